@@ -0,0 +1,134 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Command edge-proxy is a reverse proxy routing the requests by service key to the services discovered in the
+// Registry, i.e. /core-data/api/v3/ping to the /api/v3/ping route of an instance of core-data:
+//
+//	edge-proxy [--type keeper] [--host localhost] [--port 59890] [--listen 127.0.0.1:59700] [--balancer round-robin] [--cache-ttl 5s] [--watch core-data,core-command]
+//
+// It is an example of the registry client features working together: the endpoints are looked up through the
+// endpoint cache, invalidated as soon as the Registry reports them changed, and the instances of each service are
+// balanced with the given balancer. The watched services are logged each time they move, and /healthz reports
+// whether the Registry is reachable. The REGISTRY_ACCESS_TOKEN environment variable provides the registry access token.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry"
+)
+
+// balancers are the balancers selectable with the --balancer flag
+var balancers = map[string]func() types.Balancer{
+	"round-robin":         types.RoundRobin,
+	"random":              types.Random,
+	"least-recently-used": types.LeastRecentlyUsed,
+	"weighted":            types.Weighted,
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run serves the proxy until ctx is cancelled and returns the exit code: 0 once stopped, 1 on failure and 2 on invalid
+// usage
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("edge-proxy", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	registryType := flags.String("type", "keeper", "registry type, i.e. keeper or consul")
+	host := flags.String("host", "localhost", "registry host")
+	port := flags.Int("port", 59890, "registry port")
+	listen := flags.String("listen", "127.0.0.1:59700", "address the proxy listens on")
+	balancer := flags.String("balancer", "round-robin", "balancer of the instances of each service, i.e. round-robin, random, least-recently-used or weighted")
+	cacheTTL := flags.String("cache-ttl", "5s", "how long the endpoints are served from memory")
+	watch := flags.String("watch", "", "comma separated service keys whose endpoints are logged each time they change")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	newBalancer, found := balancers[*balancer]
+	if !found {
+		fmt.Fprintf(stderr, "unknown balancer '%s'\n", *balancer)
+		return 2
+	}
+
+	client, err := registry.NewRegistryClient(types.Config{
+		Type:               *registryType,
+		Host:               *host,
+		Port:               *port,
+		AccessToken:        os.Getenv("REGISTRY_ACCESS_TOKEN"),
+		Balancer:           newBalancer(),
+		EndpointCacheTTL:   *cacheTTL,
+		EndpointCacheWatch: true,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *watch != "" {
+		for _, serviceKey := range strings.Split(*watch, ",") {
+			if err := logChanges(ctx, client, strings.TrimSpace(serviceKey), stdout); err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+		}
+	}
+
+	server := &http.Server{
+		Handler:           newProxy(client, stderr),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "proxying the %s services on %s\n", *registryType, listener.Addr())
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// logChanges logs the endpoint of the service each time it changes until ctx is cancelled
+func logChanges(ctx context.Context, client registry.Client, serviceKey string, stdout io.Writer) error {
+	endpoints, err := client.WatchService(ctx, serviceKey)
+	if err != nil {
+		return fmt.Errorf("unable to watch %s: %v", serviceKey, err)
+	}
+
+	go func() {
+		for endpoint := range endpoints {
+			if endpoint.IsZero() {
+				fmt.Fprintf(stdout, "%s is not registered\n", serviceKey)
+				continue
+			}
+			fmt.Fprintf(stdout, "%s is at %s\n", serviceKey, net.JoinHostPort(endpoint.Host, fmt.Sprint(endpoint.Port)))
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry"
+)
+
+// healthRoute is the route reporting whether the Registry is reachable, rather than proxied to a service
+const healthRoute = "/healthz"
+
+// proxy routes the requests to the service named by the first segment of their path, the rest of the path being the
+// route of the service
+type proxy struct {
+	client    registry.Client
+	transport http.RoundTripper
+	stderr    io.Writer
+}
+
+func newProxy(client registry.Client, stderr io.Writer) *proxy {
+	return &proxy{
+		client:    client,
+		transport: http.DefaultTransport,
+		stderr:    stderr,
+	}
+}
+
+func (p *proxy) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.URL.Path == healthRoute {
+		p.serveHealth(writer, request)
+		return
+	}
+
+	serviceKey, route, _ := strings.Cut(strings.TrimPrefix(request.URL.Path, "/"), "/")
+	if serviceKey == "" {
+		http.Error(writer, "the path must start with the key of the target service", http.StatusNotFound)
+		return
+	}
+
+	endpoint, err := p.client.GetServiceEndpointWithContext(request.Context(), serviceKey)
+	if err != nil {
+		// i.e. 404 for the services not registered
+		http.Error(writer, err.Error(), types.ToEdgeX(err).Code())
+		return
+	}
+
+	// The services which didn't register their scheme are assumed to be called with http
+	scheme := endpoint.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	target := &url.URL{Scheme: scheme, Host: net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))}
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(outbound *httputil.ProxyRequest) {
+			outbound.Out.URL.Path = "/" + route
+			outbound.Out.URL.RawPath = ""
+			outbound.SetURL(target)
+			outbound.SetXForwarded()
+		},
+		Transport: p.transport,
+		// Report the health of the instance so the BalancingClient of the process avoids it while it's failing
+		ModifyResponse: func(response *http.Response) error {
+			switch response.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				registry.SharedInstanceHealth.ReportFailure(endpoint)
+			default:
+				registry.SharedInstanceHealth.ReportSuccess(endpoint)
+			}
+			return nil
+		},
+		ErrorHandler: func(writer http.ResponseWriter, request *http.Request, err error) {
+			fmt.Fprintf(p.stderr, "unable to reach %s at %s: %v\n", serviceKey, target.Host, err)
+			registry.SharedInstanceHealth.ReportFailure(endpoint)
+			writer.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	reverseProxy.ServeHTTP(writer, request)
+}
+
+func (p *proxy) serveHealth(writer http.ResponseWriter, request *http.Request) {
+	if !p.client.IsAliveWithContext(request.Context()) {
+		http.Error(writer, "registry is unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	writer.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	dtoCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/keepertest"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry"
+)
+
+func TestProxy(t *testing.T) {
+	coreData := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = io.WriteString(writer, request.URL.Path)
+	}))
+	defer coreData.Close()
+	coreDataUrl, err := url.Parse(coreData.URL)
+	require.NoError(t, err)
+	host, portValue, err := net.SplitHostPort(coreDataUrl.Host)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portValue)
+	require.NoError(t, err)
+
+	keeper := keepertest.NewServer()
+	keeperServer := keeper.Start()
+	defer keeperServer.Close()
+	keeper.AddRegistration(dtos.Registration{ServiceId: "core-data", Host: host, Port: port, Status: string(types.StatusUp)})
+	keeperUrl, err := url.Parse(keeperServer.URL)
+	require.NoError(t, err)
+	keeperPort, err := strconv.Atoi(keeperUrl.Port())
+	require.NoError(t, err)
+
+	client, err := registry.NewRegistryClient(types.Config{
+		Type:             "keeper",
+		Host:             keeperUrl.Hostname(),
+		Port:             keeperPort,
+		Balancer:         types.RoundRobin(),
+		EndpointCacheTTL: "5s",
+	})
+	require.NoError(t, err)
+	server := httptest.NewServer(newProxy(client, io.Discard))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/core-data/api/v3/ping")
+	require.NoError(t, err)
+	body, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "/api/v3/ping", string(body), "Expected the service key to be stripped from the proxied route")
+
+	notFound := dtoCommon.BaseResponse{Message: "not found", StatusCode: http.StatusNotFound}
+	require.NoError(t, keeper.SetResponse(http.MethodGet, common.ApiRegisterRoute+"/"+common.ServiceId+"/core-command", http.StatusNotFound, notFound))
+	response, err = http.Get(server.URL + "/core-command/api/v3/ping")
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response, err = http.Get(server.URL + healthRoute)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestProxyScheme(t *testing.T) {
+	coreData := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = io.WriteString(writer, request.URL.Path)
+	}))
+	defer coreData.Close()
+	coreDataUrl, err := url.Parse(coreData.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(coreDataUrl.Port())
+	require.NoError(t, err)
+
+	client, err := registry.NewRegistryClient(types.Config{
+		Type:            "memory",
+		MemoryEndpoints: []types.ServiceEndpoint{{ServiceId: "core-data", Host: coreDataUrl.Hostname(), Port: port, Scheme: "https"}},
+	})
+	require.NoError(t, err)
+	proxy := newProxy(client, io.Discard)
+	proxy.transport = coreData.Client().Transport
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/core-data/api/v3/ping")
+	require.NoError(t, err)
+	body, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode, "Expected the service to be called with the scheme it registered")
+	assert.Equal(t, "/api/v3/ping", string(body))
+}
@@ -0,0 +1,322 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	dtoCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const serviceName = "registry-cache"
+
+// maxAuthorizations bounds the verdicts kept, the oldest being dropped first, so callers sending ever changing
+// Authorization headers can't grow them for long
+const maxAuthorizations = 1024
+
+var (
+	apiBase                       = "/api/" + common.ApiVersion
+	pingRoute                     = apiBase + "/ping"
+	allRegistrationsRoute         = apiBase + "/registry/" + common.All
+	registrationByServiceIdPrefix = apiBase + "/registry/" + common.ServiceId + "/"
+)
+
+// cache serves the discovery routes of the Keeper API, the registration of a service and all the registrations, from
+// a snapshot of all the registrations of the upstream Keeper. The snapshot is refreshed with a single upstream request
+// at most once per TTL, whatever the number of services asking, and concurrent requests arriving while it is being
+// refreshed share the refresh. As the snapshot is fetched with the access token of the cache, the Authorization of the
+// callers is checked upstream before serving them from it, and the verdict kept for the TTL. All the other requests,
+// i.e. registering, are proxied upstream as is, and invalidate the snapshot once successful so services see their own
+// changes.
+type cache struct {
+	upstream    *url.URL
+	client      *http.Client
+	proxy       *httputil.ReverseProxy
+	accessToken string
+	ttl         time.Duration
+	// maxStale is how long the snapshot keeps being served while it can't be refreshed
+	maxStale time.Duration
+	now      func() time.Time
+
+	// lock is held while refreshing, so concurrent requests wait for the refresh in flight rather than sending theirs
+	lock          sync.Mutex
+	registrations []types.KeeperRegistration
+	fetched       time.Time
+	// invalidated is set once a proxied request changed the registrations, which are refreshed on the next request
+	invalidated atomic.Bool
+
+	// upstreamRefreshes counts the snapshot refreshes sent upstream
+	upstreamRefreshes atomic.Int64
+
+	authorizationLock sync.Mutex
+	// authorizations are the status codes upstream answered the Authorization headers of the callers with
+	authorizations map[string]authorization
+	// authorizationChecks share the upstream check of an Authorization header among the concurrent callers sending it
+	authorizationChecks singleflight.Group
+}
+
+// authorization is the status code upstream answered an Authorization header with, when checked
+type authorization struct {
+	statusCode int
+	checked    time.Time
+}
+
+func newCache(upstream *url.URL, accessToken string, ttl time.Duration, maxStale time.Duration) *cache {
+	c := &cache{
+		upstream:    upstream,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		proxy:       httputil.NewSingleHostReverseProxy(upstream),
+		accessToken: accessToken,
+		ttl:         ttl,
+		maxStale:    maxStale,
+		now:         time.Now,
+
+		authorizations: make(map[string]authorization),
+	}
+	c.proxy.ModifyResponse = func(response *http.Response) error {
+		if response.Request.Method != http.MethodGet && response.StatusCode < http.StatusMultipleChoices {
+			c.invalidated.Store(true)
+		}
+		return nil
+	}
+	return c
+}
+
+func (c *cache) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		c.proxy.ServeHTTP(writer, request)
+		return
+	}
+
+	switch {
+	case request.URL.Path == pingRoute:
+		writeJSON(writer, http.StatusOK, dtoCommon.NewPingResponse(serviceName))
+	case request.URL.Path == allRegistrationsRoute && request.URL.Query().Get(common.Deregistered) != "true":
+		if !c.authorize(writer, request) {
+			return
+		}
+		registrations, err := c.snapshot()
+		if err != nil {
+			writeJSON(writer, http.StatusBadGateway, dtoCommon.NewBaseResponse("", err.Error(), http.StatusBadGateway))
+			return
+		}
+		writeJSON(writer, http.StatusOK, types.KeeperMultiRegistrationsResponse{
+			BaseWithTotalCountResponse: dtoCommon.NewBaseWithTotalCountResponse("", "", http.StatusOK, uint32(len(registrations))),
+			Registrations:              registrations,
+		})
+	case strings.HasPrefix(request.URL.EscapedPath(), registrationByServiceIdPrefix):
+		serviceId, err := url.PathUnescape(strings.TrimPrefix(request.URL.EscapedPath(), registrationByServiceIdPrefix))
+		if err != nil {
+			writeJSON(writer, http.StatusBadRequest, dtoCommon.NewBaseResponse("", err.Error(), http.StatusBadRequest))
+			return
+		}
+		if !c.authorize(writer, request) {
+			return
+		}
+		registrations, err := c.snapshot()
+		if err != nil {
+			writeJSON(writer, http.StatusBadGateway, dtoCommon.NewBaseResponse("", err.Error(), http.StatusBadGateway))
+			return
+		}
+		for _, registration := range registrations {
+			if registration.ServiceId == serviceId {
+				writeJSON(writer, http.StatusOK, types.KeeperRegistrationResponse{
+					BaseResponse: dtoCommon.NewBaseResponse("", "", http.StatusOK),
+					Registration: registration,
+				})
+				return
+			}
+		}
+		message := fmt.Sprintf("registration for %s not found", serviceId)
+		writeJSON(writer, http.StatusNotFound, dtoCommon.NewBaseResponse("", message, http.StatusNotFound))
+	default:
+		c.proxy.ServeHTTP(writer, request)
+	}
+}
+
+// snapshot returns all the registrations, refreshed from upstream if older than the TTL or invalidated. The previous
+// snapshot is returned while upstream fails, until older than maxStale.
+func (c *cache) snapshot() ([]types.KeeperRegistration, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	age := c.now().Sub(c.fetched)
+	invalidated := c.invalidated.Swap(false)
+	if !c.fetched.IsZero() && age < c.ttl && !invalidated {
+		return c.registrations, nil
+	}
+
+	registrations, err := c.fetch()
+	if err != nil {
+		if invalidated {
+			c.invalidated.Store(true)
+		}
+		if !c.fetched.IsZero() && age < c.maxStale {
+			return c.registrations, nil
+		}
+		return nil, err
+	}
+
+	c.registrations = registrations
+	c.fetched = c.now()
+	return registrations, nil
+}
+
+// authorize checks the Authorization of the caller upstream, unless already checked within the TTL, and answers the
+// request with the status code upstream rejected it with, if it did, returning whether the caller is authorized. Only
+// a successful status code, or not found as the registration of the cache itself may not exist, authorizes it, any
+// other status code failing closed.
+func (c *cache) authorize(writer http.ResponseWriter, request *http.Request) bool {
+	statusCode, err := c.authorization(request.Header.Get("Authorization"))
+	if err != nil {
+		writeJSON(writer, http.StatusBadGateway, dtoCommon.NewBaseResponse("", err.Error(), http.StatusBadGateway))
+		return false
+	}
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		message := fmt.Sprintf("authorization rejected upstream with status code %d", statusCode)
+		writeJSON(writer, statusCode, dtoCommon.NewBaseResponse("", message, statusCode))
+		return false
+	}
+	return true
+}
+
+// authorization returns the status code upstream answers a request with the given Authorization header with, checked
+// once for all the concurrent callers sending the same header. Only the verdicts, i.e. authorized or rejected, are
+// returned, any other status code failing the check.
+func (c *cache) authorization(header string) (int, error) {
+	c.authorizationLock.Lock()
+	checked, ok := c.authorizations[header]
+	c.authorizationLock.Unlock()
+	if ok && c.now().Sub(checked.checked) < c.ttl {
+		return checked.statusCode, nil
+	}
+
+	statusCode, err, _ := c.authorizationChecks.Do(header, func() (any, error) {
+		return c.checkAuthorization(header)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return statusCode.(int), nil
+}
+
+// checkAuthorization checks the Authorization header upstream, the registration of the cache itself being requested
+// as the cheapest request Keeper authenticates, and keeps the verdict
+func (c *cache) checkAuthorization(header string) (int, error) {
+	request, err := http.NewRequest(http.MethodGet, c.upstream.JoinPath(registrationByServiceIdPrefix, serviceName).String(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("unable to check the authorization upstream: %v", err)
+	}
+	if header != "" {
+		request.Header.Set("Authorization", header)
+	}
+
+	statusCode := 0
+	response, err := c.client.Do(request)
+	if err == nil {
+		_, _ = io.Copy(io.Discard, response.Body)
+		_ = response.Body.Close()
+		statusCode = response.StatusCode
+		if !isAuthorizationVerdict(statusCode) {
+			err = fmt.Errorf("status code %d", statusCode)
+		}
+	}
+
+	c.authorizationLock.Lock()
+	defer c.authorizationLock.Unlock()
+
+	if err != nil {
+		// Like the snapshot, the last verdict keeps being used while upstream fails, until older than maxStale
+		if checked, ok := c.authorizations[header]; ok && c.now().Sub(checked.checked) < c.maxStale {
+			return checked.statusCode, nil
+		}
+		return 0, fmt.Errorf("unable to check the authorization upstream: %v", err)
+	}
+
+	c.pruneAuthorizations()
+	c.authorizations[header] = authorization{statusCode: statusCode, checked: c.now()}
+	return statusCode, nil
+}
+
+// isAuthorizationVerdict checks if upstream either authorized or rejected the Authorization header with the status
+// code, rather than failing to handle the request
+func isAuthorizationVerdict(statusCode int) bool {
+	return (statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices) || statusCode == http.StatusNotFound ||
+		statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+}
+
+// pruneAuthorizations drops the verdicts too old to be used, then the oldest ones until there is room for another
+// one. The authorizationLock is to be held.
+func (c *cache) pruneAuthorizations() {
+	for header, checked := range c.authorizations {
+		if c.now().Sub(checked.checked) >= c.maxStale {
+			delete(c.authorizations, header)
+		}
+	}
+	for len(c.authorizations) >= maxAuthorizations {
+		var oldest string
+		var oldestChecked time.Time
+		for header, checked := range c.authorizations {
+			if oldestChecked.IsZero() || checked.checked.Before(oldestChecked) {
+				oldest, oldestChecked = header, checked.checked
+			}
+		}
+		delete(c.authorizations, oldest)
+	}
+}
+
+func (c *cache) fetch() ([]types.KeeperRegistration, error) {
+	c.upstreamRefreshes.Add(1)
+
+	request, err := http.NewRequest(http.MethodGet, c.upstream.JoinPath(allRegistrationsRoute).String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the registrations from upstream: %v", err)
+	}
+	if c.accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the registrations from upstream: %v", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the registrations from upstream: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get the registrations from upstream: status code %d", response.StatusCode)
+	}
+
+	var all types.KeeperMultiRegistrationsResponse
+	if err := json.Unmarshal(body, &all); err != nil {
+		return nil, fmt.Errorf("unable to decode the registrations from upstream: %v", err)
+	}
+	return all.Registrations, nil
+}
+
+func writeJSON(writer http.ResponseWriter, statusCode int, body any) {
+	encoded, _ := json.Marshal(body)
+	writer.Header().Set(common.ContentType, common.ContentTypeJSON)
+	writer.WriteHeader(statusCode)
+	_, _ = writer.Write(encoded)
+}
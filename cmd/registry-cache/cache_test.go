@@ -0,0 +1,200 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/keepertest"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry"
+)
+
+// startCache starts the cache of a fake Keeper, returning the fake, its server, the cache and the config of the
+// clients of the cache
+func startCache(t *testing.T) (*keepertest.Server, *httptest.Server, *cache, types.Config) {
+	keeper := keepertest.NewServer()
+	upstream := keeper.Start()
+	t.Cleanup(upstream.Close)
+	upstreamUrl, _ := url.Parse(upstream.URL)
+
+	c := newCache(upstreamUrl, "", time.Minute, time.Hour)
+	server := httptest.NewServer(c)
+	t.Cleanup(server.Close)
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+
+	return keeper, upstream, c, types.Config{
+		Type:        "keeper",
+		Host:        serverUrl.Hostname(),
+		Port:        port,
+		ServiceKey:  "device-modbus",
+		ServiceHost: "10.0.0.2",
+		ServicePort: 59901,
+		CheckType:   types.CheckTypeNone,
+	}
+}
+
+func TestCacheServesDiscoveryFromSnapshot(t *testing.T) {
+	keeper, _, c, config := startCache(t)
+	keeper.AddRegistration(dtos.Registration{ServiceId: "core-data", Host: "10.0.0.1", Port: 59880, Status: string(types.StatusUp)})
+	keeper.AddRegistration(dtos.Registration{ServiceId: "core-metadata", Host: "10.0.0.1", Port: 59881, Status: string(types.StatusUp)})
+
+	client, err := registry.NewRegistryClient(config)
+	require.NoError(t, err)
+	require.True(t, client.IsAlive())
+
+	for i := 0; i < 10; i++ {
+		endpoint, err := client.GetServiceEndpoint("core-data")
+		require.NoError(t, err)
+		assert.Equal(t, 59880, endpoint.Port)
+		available, err := client.IsServiceAvailable("core-metadata")
+		require.NoError(t, err)
+		assert.True(t, available)
+	}
+	endpoints, err := client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	assert.Len(t, endpoints, 2)
+
+	_, err = client.GetServiceEndpoint("support-scheduler")
+	assert.ErrorIs(t, err, types.ErrNotRegistered)
+
+	assert.Equal(t, int64(1), c.upstreamRefreshes.Load(), "Expected all the lookups to share a single upstream request")
+}
+
+func TestCacheProxiesRegistration(t *testing.T) {
+	_, _, _, config := startCache(t)
+
+	client, err := registry.NewRegistryClient(config)
+	require.NoError(t, err)
+	_, err = client.GetServiceEndpoint("device-modbus")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+
+	require.NoError(t, client.Register())
+	endpoint, err := client.GetServiceEndpoint("device-modbus")
+	require.NoError(t, err, "Expected the registration to be visible through the cache right away")
+	assert.Equal(t, "10.0.0.2", endpoint.Host)
+
+	require.NoError(t, client.Decommission(context.Background(), "device-modbus"))
+	_, err = client.GetServiceEndpoint("device-modbus")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+}
+
+func TestCacheServesStaleWhileUpstreamUnreachable(t *testing.T) {
+	keeper, upstream, c, config := startCache(t)
+	keeper.AddRegistration(dtos.Registration{ServiceId: "core-data", Host: "10.0.0.1", Port: 59880, Status: string(types.StatusUp)})
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	client, err := registry.NewRegistryClient(config)
+	require.NoError(t, err)
+	_, err = client.GetServiceEndpoint("core-data")
+	require.NoError(t, err)
+
+	upstream.Close()
+	now = now.Add(30 * time.Minute)
+	_, err = client.GetServiceEndpoint("core-data")
+	require.NoError(t, err, "Expected the snapshot to be served while upstream is unreachable")
+	assert.Equal(t, int64(2), c.upstreamRefreshes.Load())
+
+	now = now.Add(time.Hour)
+	_, err = client.GetServiceEndpoint("core-data")
+	require.Error(t, err, "Expected the snapshot not to be served once older than max-stale")
+}
+
+func TestCacheChecksAuthorizationUpstream(t *testing.T) {
+	keeper, _, c, config := startCache(t)
+	keeper.AddRegistration(dtos.Registration{ServiceId: "core-data", Host: "10.0.0.1", Port: 59880, Status: string(types.StatusUp)})
+	keeper.SetExpectedAuthorization("Bearer service-token")
+	c.accessToken = "service-token"
+
+	client, err := registry.NewRegistryClient(config)
+	require.NoError(t, err)
+	endpoint, err := client.GetServiceEndpointWithContext(types.WithAccessToken(context.Background(), "service-token"), "core-data")
+	require.NoError(t, err)
+	assert.Equal(t, 59880, endpoint.Port)
+
+	ctx := types.WithAccessToken(context.Background(), "stolen-token")
+	_, err = client.GetServiceEndpointWithContext(ctx, "core-data")
+	require.ErrorIs(t, err, types.ErrUnauthorized, "Expected the snapshot not to be served to callers Keeper rejects")
+	_, err = client.GetAllServiceEndpointsWithContext(ctx)
+	require.ErrorIs(t, err, types.ErrUnauthorized)
+
+	assert.Equal(t, int64(1), c.upstreamRefreshes.Load(), "Expected the snapshot to be shared by the authorized callers")
+}
+
+func TestCacheAuthorizationFailsClosed(t *testing.T) {
+	var statusCode atomic.Int32
+	var checks atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		checks.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		writer.WriteHeader(int(statusCode.Load()))
+	}))
+	t.Cleanup(upstream.Close)
+	upstreamUrl, _ := url.Parse(upstream.URL)
+	c := newCache(upstreamUrl, "", time.Minute, time.Hour)
+
+	for _, rejected := range []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusBadRequest} {
+		statusCode.Store(int32(rejected))
+		_, err := c.authorization("Bearer service-token")
+		require.Error(t, err, "Expected status code %d not to authorize the caller", rejected)
+	}
+
+	statusCode.Store(http.StatusNotFound)
+	checks.Store(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			verdict, err := c.authorization("Bearer other-token")
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusNotFound, verdict)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), checks.Load(), "Expected the concurrent callers to share the upstream check")
+
+	statusCode.Store(http.StatusInternalServerError)
+	c.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	verdict, err := c.authorization("Bearer other-token")
+	require.NoError(t, err, "Expected the last verdict to be used while upstream fails")
+	assert.Equal(t, http.StatusNotFound, verdict)
+}
+
+func TestCacheAuthorizationsBounded(t *testing.T) {
+	keeper, upstream, _, _ := startCache(t)
+	keeper.AddRegistration(dtos.Registration{ServiceId: serviceName, Host: "10.0.0.3", Port: 59890, Status: string(types.StatusUp)})
+	upstreamUrl, _ := url.Parse(upstream.URL)
+	c := newCache(upstreamUrl, "", time.Minute, time.Hour)
+
+	now := time.Now()
+	for i := 0; i < maxAuthorizations; i++ {
+		c.authorizations["Bearer token-"+strconv.Itoa(i)] = authorization{statusCode: http.StatusOK, checked: now.Add(time.Duration(i) * time.Millisecond)}
+	}
+
+	verdict, err := c.authorization("Bearer new-token")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, verdict)
+	assert.Len(t, c.authorizations, maxAuthorizations)
+	assert.NotContains(t, c.authorizations, "Bearer token-0", "Expected the oldest verdict to be dropped")
+	assert.Contains(t, c.authorizations, "Bearer new-token")
+}
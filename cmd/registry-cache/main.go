@@ -0,0 +1,83 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Command registry-cache is a read-through cache of a remote Core Keeper, for gateways whose many local services would
+// otherwise each poll Keeper over the WAN. It serves the Keeper API, so the services use it as their Registry with the
+// keeper registry type:
+//
+//	registry-cache --upstream-host keeper.example.com [--upstream-port 59890] [--listen 127.0.0.1:59890] \
+//		[--ttl 5s] [--max-stale 1m]
+//
+// The discovery requests are served from a snapshot of all the registrations refreshed at most once per TTL, and kept
+// being served while Keeper is unreachable for up to max-stale. The other requests are proxied to Keeper as is. The
+// snapshot is requested with the REGISTRY_ACCESS_TOKEN environment variable as bearer token if set, and the cached
+// responses are only served to the services whose Authorization header Keeper accepts, checked at most once per TTL.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run serves the cache until ctx is cancelled and returns the exit code: 0 once stopped, 1 on failure and 2 on invalid
+// usage
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("registry-cache", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	listen := flags.String("listen", "127.0.0.1:59890", "address the cache listens on")
+	upstreamProtocol := flags.String("upstream-protocol", "http", "protocol of the upstream Keeper")
+	upstreamHost := flags.String("upstream-host", "", "host of the upstream Keeper")
+	upstreamPort := flags.Int("upstream-port", 59890, "port of the upstream Keeper")
+	ttl := flags.Duration("ttl", 5*time.Second, "how long the registrations are served before being refreshed")
+	maxStale := flags.Duration("max-stale", time.Minute, "how long the registrations are served while Keeper is unreachable")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *upstreamHost == "" {
+		fmt.Fprintln(stderr, "the --upstream-host flag is required")
+		return 2
+	}
+
+	upstream := &url.URL{Scheme: *upstreamProtocol, Host: net.JoinHostPort(*upstreamHost, strconv.Itoa(*upstreamPort))}
+	server := &http.Server{
+		Handler:           newCache(upstream, os.Getenv("REGISTRY_ACCESS_TOKEN"), *ttl, *maxStale),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "caching %s on %s\n", upstream, listener.Addr())
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
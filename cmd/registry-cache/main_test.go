@@ -0,0 +1,32 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"--upstream-host", "keeper.example.com", "--listen", "127.0.0.1:0"}
+	assert.Equal(t, 0, run(ctx, args, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "caching http://keeper.example.com:59890 on 127.0.0.1:")
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run(context.Background(), nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "the --upstream-host flag is required")
+	assert.Equal(t, 2, run(context.Background(), []string{"--ttl", "soon"}, &stdout, &stderr))
+}
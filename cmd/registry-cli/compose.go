@@ -0,0 +1,169 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The labels of the compose services overriding the registration inferred from the service definition
+const (
+	labelPrefix = "org.edgexfoundry.registry."
+	// labelRegister set to false skips the service, i.e. because it registers itself
+	labelRegister      = labelPrefix + "register"
+	labelServiceKey    = labelPrefix + "service-key"
+	labelHost          = labelPrefix + "host"
+	labelPort          = labelPrefix + "port"
+	labelCheckRoute    = labelPrefix + "check-route"
+	labelCheckInterval = labelPrefix + "check-interval"
+)
+
+// staticEndpoint is the registration of a service which doesn't register itself
+type staticEndpoint struct {
+	ServiceKey    string
+	Host          string
+	Port          int
+	CheckRoute    string
+	CheckInterval string
+}
+
+// composeFile is the part of a docker-compose file the endpoints are inferred from
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Hostname string        `yaml:"hostname"`
+	Ports    []composePort `yaml:"ports"`
+	Labels   composeLabels `yaml:"labels"`
+}
+
+// composePort is the container port of a port mapping, in the short "[ip:][host:]container[/protocol]" or the long
+// syntax
+type composePort struct {
+	Target int
+}
+
+func (p *composePort) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var long struct {
+			Target int `yaml:"target"`
+		}
+		if err := node.Decode(&long); err != nil {
+			return err
+		}
+		p.Target = long.Target
+		return nil
+	}
+
+	var short string
+	if err := node.Decode(&short); err != nil {
+		return err
+	}
+	short, _, _ = strings.Cut(short, "/")
+	container := short[strings.LastIndex(short, ":")+1:]
+	// Ranges, i.e. 8000-8010, are registered with their first port
+	container, _, _ = strings.Cut(container, "-")
+
+	target, err := strconv.Atoi(container)
+	if err != nil {
+		return fmt.Errorf("invalid port mapping '%s' at line %d", node.Value, node.Line)
+	}
+	p.Target = target
+	return nil
+}
+
+// composeLabels are the labels of a service, in the map or the "name=value" list syntax
+type composeLabels map[string]string
+
+func (l *composeLabels) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var labels map[string]string
+		if err := node.Decode(&labels); err != nil {
+			return err
+		}
+		*l = labels
+		return nil
+	}
+
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*l = make(composeLabels, len(list))
+	for _, label := range list {
+		name, value, _ := strings.Cut(label, "=")
+		(*l)[name] = value
+	}
+	return nil
+}
+
+// loadComposeEndpoints infers the static endpoints of the services of the docker-compose file, sorted by service key.
+// Each service is registered under its name, reachable at its hostname or else its name on the compose network, on the
+// container port of its first port mapping, and health checked on the check-route label if set. Services without port
+// are skipped, as well as the ones labelled with register=false.
+func loadComposeEndpoints(path string, defaultCheckInterval string) ([]staticEndpoint, []string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read compose file: %w", err)
+	}
+
+	var compose composeFile
+	if err := yaml.Unmarshal(contents, &compose); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse compose file %s: %w", path, err)
+	}
+
+	var endpoints []staticEndpoint
+	var skipped []string
+	for name, service := range compose.Services {
+		labels := service.Labels
+		if strings.EqualFold(labels[labelRegister], "false") {
+			skipped = append(skipped, fmt.Sprintf("%s: labelled %s=false", name, labelRegister))
+			continue
+		}
+
+		endpoint := staticEndpoint{
+			ServiceKey:    firstNonEmpty(labels[labelServiceKey], name),
+			Host:          firstNonEmpty(labels[labelHost], service.Hostname, name),
+			CheckRoute:    labels[labelCheckRoute],
+			CheckInterval: firstNonEmpty(labels[labelCheckInterval], defaultCheckInterval),
+		}
+
+		switch {
+		case labels[labelPort] != "":
+			endpoint.Port, err = strconv.Atoi(labels[labelPort])
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid %s label '%s' of service %s", labelPort, labels[labelPort], name)
+			}
+		case len(service.Ports) > 0:
+			endpoint.Port = service.Ports[0].Target
+		default:
+			skipped = append(skipped, fmt.Sprintf("%s: no port", name))
+			continue
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].ServiceKey < endpoints[j].ServiceKey })
+	sort.Strings(skipped)
+	return endpoints, skipped, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCompose = `
+services:
+  database:
+    image: redis:7
+    ports:
+      - "127.0.0.1:6379:6379/tcp"
+  modbus-gateway:
+    image: vendor/gateway
+    hostname: gateway
+    ports:
+      - target: 8502
+        published: 18502
+    labels:
+      org.edgexfoundry.registry.check-route: /health
+  legacy-app:
+    image: vendor/legacy
+    labels:
+      - org.edgexfoundry.registry.service-key=app-legacy
+      - org.edgexfoundry.registry.port=9000
+      - org.edgexfoundry.registry.check-route=/status
+      - org.edgexfoundry.registry.check-interval=30s
+  core-data:
+    image: edgexfoundry/core-data
+    ports:
+      - 59880:59880
+    labels:
+      org.edgexfoundry.registry.register: "false"
+  ui-builder:
+    image: vendor/builder
+`
+
+func writeCompose(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func TestLoadComposeEndpoints(t *testing.T) {
+	endpoints, skipped, err := loadComposeEndpoints(writeCompose(t, testCompose), "10s")
+	require.NoError(t, err)
+
+	assert.Equal(t, []staticEndpoint{
+		{ServiceKey: "app-legacy", Host: "legacy-app", Port: 9000, CheckRoute: "/status", CheckInterval: "30s"},
+		{ServiceKey: "database", Host: "database", Port: 6379, CheckInterval: "10s"},
+		{ServiceKey: "modbus-gateway", Host: "gateway", Port: 8502, CheckRoute: "/health", CheckInterval: "10s"},
+	}, endpoints)
+	assert.Equal(t, []string{"core-data: labelled org.edgexfoundry.registry.register=false", "ui-builder: no port"}, skipped)
+}
+
+func TestLoadComposeEndpointsInvalid(t *testing.T) {
+	_, _, err := loadComposeEndpoints(filepath.Join(t.TempDir(), "missing.yml"), "10s")
+	require.Error(t, err)
+
+	_, _, err = loadComposeEndpoints(writeCompose(t, "services:\n  app:\n    ports:\n      - http\n"), "10s")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid port mapping 'http'")
+}
@@ -0,0 +1,133 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Command registry-cli manages the EdgeX Registry from the command line. The import command pre-registers the static
+// endpoints of the services of a docker-compose file which don't register themselves, to bring up mixed environments:
+//
+//	registry-cli import --compose docker-compose.yml [--type keeper] [--host localhost] [--port 59890] [--dry-run]
+//
+// The registrations are left behind when the command exits, so the registry type must keep registrations without their
+// client, i.e. keeper or consul: the etcd registrations expire with their lease.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry"
+)
+
+const usage = `Usage: registry-cli <command> [flags]
+
+Commands:
+  import    Pre-register the static endpoints of the services of a docker-compose file
+`
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command and returns its exit code: 0 on success, 1 on failure and 2 on invalid usage
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "import":
+		return runImport(ctx, args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command '%s'\n%s", args[0], usage)
+		return 2
+	}
+}
+
+func runImport(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	composePath := flags.String("compose", "", "docker-compose file whose services are registered")
+	registryType := flags.String("type", "keeper", "registry type, i.e. keeper or consul")
+	host := flags.String("host", "localhost", "registry host")
+	port := flags.Int("port", 59890, "registry port")
+	checkInterval := flags.String("check-interval", "10s", "health check interval of the services with a check-route label")
+	dryRun := flags.Bool("dry-run", false, "print the registrations without registering")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *composePath == "" {
+		fmt.Fprintln(stderr, "the --compose flag is required")
+		return 2
+	}
+
+	endpoints, skipped, err := loadComposeEndpoints(*composePath, *checkInterval)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	for _, reason := range skipped {
+		fmt.Fprintf(stdout, "skipped %s\n", reason)
+	}
+
+	failed := false
+	for _, endpoint := range endpoints {
+		if *dryRun {
+			fmt.Fprintf(stdout, "would register %s\n", describe(endpoint))
+			continue
+		}
+
+		if err := register(ctx, registryConfig(*registryType, *host, *port, endpoint)); err != nil {
+			fmt.Fprintf(stderr, "failed to register %s: %v\n", endpoint.ServiceKey, err)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(stdout, "registered %s\n", describe(endpoint))
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// registryConfig is the configuration registering the static endpoint as the current service of the client
+func registryConfig(registryType string, host string, port int, endpoint staticEndpoint) types.Config {
+	config := types.Config{
+		Type:        registryType,
+		Host:        host,
+		Port:        port,
+		ServiceKey:  endpoint.ServiceKey,
+		ServiceHost: endpoint.Host,
+		ServicePort: endpoint.Port,
+		AccessToken: os.Getenv("REGISTRY_ACCESS_TOKEN"),
+		CheckType:   types.CheckTypeNone,
+	}
+	if endpoint.CheckRoute != "" {
+		config.CheckType = types.CheckTypeHTTP
+		config.CheckRoute = endpoint.CheckRoute
+		config.CheckInterval = endpoint.CheckInterval
+	}
+	return config
+}
+
+func register(ctx context.Context, config types.Config) error {
+	client, err := registry.NewRegistryClient(config)
+	if err != nil {
+		return err
+	}
+	return client.RegisterWithContext(ctx)
+}
+
+func describe(endpoint staticEndpoint) string {
+	description := fmt.Sprintf("%s at %s:%d", endpoint.ServiceKey, endpoint.Host, endpoint.Port)
+	if endpoint.CheckRoute != "" {
+		description += fmt.Sprintf(" health checked on %s every %s", endpoint.CheckRoute, endpoint.CheckInterval)
+	}
+	return description
+}
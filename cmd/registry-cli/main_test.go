@@ -0,0 +1,55 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/keepertest"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry"
+)
+
+func TestRunImport(t *testing.T) {
+	server := keepertest.NewServer().Start()
+	defer server.Close()
+	serverUrl, _ := url.Parse(server.URL)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"import", "--compose", writeCompose(t, testCompose), "--host", serverUrl.Hostname(), "--port", serverUrl.Port()}
+	require.Equal(t, 0, run(context.Background(), args, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "registered modbus-gateway at gateway:8502 health checked on /health every 10s\n")
+	assert.Contains(t, stdout.String(), "skipped ui-builder: no port\n")
+
+	port, _ := strconv.Atoi(serverUrl.Port())
+	client, err := registry.NewRegistryClient(types.Config{Type: "keeper", Host: serverUrl.Hostname(), Port: port})
+	require.NoError(t, err)
+	endpoint, err := client.GetServiceEndpoint("database")
+	require.NoError(t, err)
+	assert.Equal(t, types.ServiceEndpoint{ServiceId: "database", Host: "database", Port: 6379}, endpoint)
+}
+
+func TestRunImportDryRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"import", "--compose", writeCompose(t, testCompose), "--port", "1", "--dry-run"}
+	require.Equal(t, 0, run(context.Background(), args, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "would register database at database:6379\n")
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run(context.Background(), nil, &stdout, &stderr))
+	assert.Equal(t, 2, run(context.Background(), []string{"export"}, &stdout, &stderr))
+	assert.Equal(t, 2, run(context.Background(), []string{"import"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "the --compose flag is required")
+}
@@ -0,0 +1,151 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package consul
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// externalNodeMeta marks the nodes the services register on with ConsulCatalog as external to Consul, so consul-esm
+// runs their health checks, without pinging the nodes themselves
+var externalNodeMeta = map[string]string{
+	"external-node":  "true",
+	"external-probe": "false",
+}
+
+// catalogNode returns the node of the catalog the current service registers on with ConsulCatalog, the ConsulNode if
+// set, otherwise its own node named after its instance ID
+func (client *consulClient) catalogNode() string {
+	if client.config.ConsulNode != "" {
+		return client.config.ConsulNode
+	}
+	return client.instanceId
+}
+
+// discoversCatalog indicates whether the services are discovered in the catalog rather than among the services
+// registered with the agent, with ConsulCatalog or with a ConsulDatacenter
+func (client *consulClient) discoversCatalog() bool {
+	return client.config.ConsulCatalog || client.config.ConsulDatacenter != ""
+}
+
+// catalogRegister registers the service, along with the check if not nil, on the node of the current service in the
+// catalog, retrying once with a renewed Access Token
+func (client *consulClient) catalogRegister(ctx context.Context, service *consulapi.AgentService, check *consulapi.AgentCheck) error {
+	registration := &consulapi.CatalogRegistration{
+		Node:     client.catalogNode(),
+		Address:  client.serviceAddress,
+		NodeMeta: externalNodeMeta,
+		Service:  service,
+		Check:    check,
+	}
+	if check != nil {
+		check.Node = registration.Node
+	}
+	return client.catalogWrite(ctx, func(writeOptions *consulapi.WriteOptions) error {
+		_, err := client.consulClient.Catalog().Register(registration, writeOptions)
+		return err
+	})
+}
+
+// catalogDeregister removes what the deregistration targets from the catalog, retrying once with a renewed Access
+// Token
+func (client *consulClient) catalogDeregister(ctx context.Context, deregistration *consulapi.CatalogDeregistration) error {
+	return client.catalogWrite(ctx, func(writeOptions *consulapi.WriteOptions) error {
+		_, err := client.consulClient.Catalog().Deregister(deregistration, writeOptions)
+		return err
+	})
+}
+
+// catalogWrite sends the write request to the catalog, retrying once with a renewed Access Token
+func (client *consulClient) catalogWrite(ctx context.Context, write func(writeOptions *consulapi.WriteOptions) error) error {
+	writeOptions := (&consulapi.WriteOptions{}).WithContext(ctx)
+	writeOptions.Token = client.queryOptions(ctx).Token
+	err := write(writeOptions)
+
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
+	if retry {
+		// Try again with new Access Token
+		err = write(writeOptions)
+	}
+	return unauthorized(err)
+}
+
+// catalogService returns the service of the catalog registered as the given registration with the agent
+func catalogService(registration *consulapi.AgentServiceRegistration) *consulapi.AgentService {
+	return &consulapi.AgentService{
+		ID:      registration.ID,
+		Service: registration.Name,
+		Address: registration.Address,
+		Port:    registration.Port,
+		Meta:    registration.Meta,
+		Tags:    registration.Tags,
+	}
+}
+
+// catalogCheck returns the check of the catalog registered as the given registration with the agent. Like the agent
+// does, the check is critical until it is first run.
+func catalogCheck(registration *consulapi.AgentCheckRegistration) *consulapi.AgentCheck {
+	check := &consulapi.AgentCheck{
+		CheckID:   registration.ID,
+		Name:      registration.Name,
+		Notes:     registration.Notes,
+		Status:    consulapi.HealthCritical,
+		ServiceID: registration.ServiceID,
+		Definition: consulapi.HealthCheckDefinition{
+			HTTP:   registration.HTTP,
+			Header: registration.Header,
+			Method: registration.Method,
+		},
+	}
+	// The durations of the current service were validated on registration, invalid ones are left unset otherwise
+	check.Definition.IntervalDuration, _ = time.ParseDuration(registration.Interval)
+	check.Definition.DeregisterCriticalServiceAfterDuration, _ = time.ParseDuration(registration.DeregisterCriticalServiceAfter)
+	return check
+}
+
+// catalogUnregister removes the current service from the catalog along with its checks, and its node unless shared
+// with the ConsulNode
+func (client *consulClient) catalogUnregister(ctx context.Context) error {
+	deregistration := &consulapi.CatalogDeregistration{Node: client.catalogNode()}
+	if client.config.ConsulNode != "" {
+		deregistration.ServiceID = client.instanceId
+	}
+	return client.catalogDeregister(ctx, deregistration)
+}
+
+// catalogDecommission removes the instances of the target service from the catalog, with their checks. There is no
+// agent to put them into maintenance mode first.
+func (client *consulClient) catalogDecommission(ctx context.Context, serviceKey string) error {
+	if serviceKey == client.serviceKey {
+		return client.registration.Unregister(func() error {
+			if err := client.catalogUnregister(ctx); err != nil {
+				return fmt.Errorf("unable to de-register service %s with consul: %w", client.instanceId, err)
+			}
+			return nil
+		})
+	}
+
+	instances, _, err := client.catalogInstances(ctx, serviceKey, "", 0, 0)
+	if err != nil {
+		return fmt.Errorf("unable to decommission service %s: %w", serviceKey, err)
+	}
+	if len(instances) == 0 {
+		return types.Errorf(types.ErrNotRegistered, "unable to decommission %s: service is not registered", serviceKey)
+	}
+	for _, instance := range instances {
+		deregistration := &consulapi.CatalogDeregistration{Node: instance.Node, ServiceID: instance.ServiceID}
+		if err := client.catalogDeregister(ctx, deregistration); err != nil {
+			return fmt.Errorf("unable to de-register service %s with consul: %w", instance.ServiceID, err)
+		}
+	}
+	return nil
+}
@@ -17,20 +17,33 @@
 package consul
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	consulapi "github.com/hashicorp/consul/api"
 
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/health"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/lifecycle"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/transport"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/watch"
 	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
 )
 
 const (
-	consulStatusPath  = "/v1/status/leader"
-	serviceStatusPass = "passing"
-	aclError          = "Unexpected response code: 403"
+	consulStatusPath     = "/v1/status/leader"
+	defaultStatusTimeout = time.Second * 10
+	aclError             = "Unexpected response code: 403"
+	// tombstonesPrefix is the prefix of the keys of the KV store holding the tombstones of the decommissioned
+	// services, by service key
+	tombstonesPrefix = "edgex/registry/tombstones/"
 )
 
 type consulClient struct {
@@ -39,12 +52,22 @@ type consulClient struct {
 	consulClient        *consulapi.Client
 	consulConfig        *consulapi.Config
 	serviceKey          string
+	instanceId          string
 	serviceAddress      string
 	servicePort         int
 	healthCheckRoute    string
 	healthCheckInterval string
 	registeredChecks    []string
 	getAccessToken      types.GetAccessTokenCallback
+	// tokenFile is the file holding the ACL token, if any, in place of the Access Token
+	tokenFile    *tokenFile
+	statusClient *http.Client
+	registration lifecycle.Registration
+	// reportLock guards reporting, set while the client reports the status of the health check it runs itself
+	reportLock sync.Mutex
+	reporting  bool
+	// serverClock is the time of Consul, estimated from its responses
+	serverClock *transport.ServerClock
 }
 
 // Create new Consul Client. Service details are optional, not needed just for configuration, but required if registering
@@ -53,6 +76,7 @@ func NewConsulClient(registryConfig types.Config) (*consulClient, error) {
 	client := consulClient{
 		config:         &registryConfig,
 		serviceKey:     registryConfig.ServiceKey,
+		instanceId:     registryConfig.GetServiceInstanceId(),
 		consulUrl:      registryConfig.GetRegistryUrl(),
 		getAccessToken: registryConfig.GetAccessToken,
 	}
@@ -65,28 +89,71 @@ func NewConsulClient(registryConfig types.Config) (*consulClient, error) {
 		client.healthCheckInterval = registryConfig.CheckInterval
 	}
 
-	var err error
+	// All requests to Consul share the same pooled connections
+	httpClient, err := transport.NewClient(registryConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable for create new Consul Client for %s: %w", client.consulUrl, err)
+	}
+	httpClient.Transport = transport.WithLogging(httpClient.Transport, registryConfig.GetLoggingClient(), "Consul")
+	client.serverClock = transport.NewServerClock()
+	httpClient.Transport = client.serverClock.Wrap(httpClient.Transport)
+	tokenFilePath := registryConfig.AccessTokenFile
+	if tokenFilePath == "" && registryConfig.AccessToken == "" {
+		tokenFilePath = os.Getenv(consulapi.HTTPTokenFileEnvName)
+	}
+	if tokenFilePath != "" {
+		client.tokenFile, err = newTokenFile(tokenFilePath, registryConfig.GetLoggingClient())
+		if err != nil {
+			return nil, fmt.Errorf("unable for create new Consul Client for %s: %w", client.consulUrl, err)
+		}
+		httpClient.Transport = &tokenFileTransport{next: httpClient.Transport, file: client.tokenFile}
+	}
+	client.statusClient = &http.Client{Timeout: defaultStatusTimeout, Transport: httpClient.Transport}
+	if httpClient.Timeout > 0 {
+		client.statusClient.Timeout = httpClient.Timeout
+	}
 
 	client.consulConfig = consulapi.DefaultConfig()
 	client.consulConfig.Token = registryConfig.AccessToken
+	if client.tokenFile != nil {
+		// The token is read from the file for each request rather than once by the Consul client
+		client.consulConfig.TokenFile = ""
+	}
 	client.consulConfig.Address = client.consulUrl
+	client.consulConfig.Namespace = registryConfig.ConsulNamespace
+	client.consulConfig.Partition = registryConfig.ConsulPartition
+	client.consulConfig.HttpClient = httpClient
 	client.consulClient, err = consulapi.NewClient(client.consulConfig)
 	if err != nil {
-		return nil, fmt.Errorf("unable for create new Consul Client for %s: %v", client.consulUrl, err)
+		return nil, fmt.Errorf("unable for create new Consul Client for %s: %w", client.consulUrl, err)
 	}
 
 	return &client, nil
 }
 
+// ServerClock returns the time of Consul, estimated from the Date of its responses
+func (client *consulClient) ServerClock() types.Clock {
+	return client.serverClock
+}
+
 // Simply checks if Consul is up and running at the configured URL
 func (client *consulClient) IsAlive() bool {
-	netClient := http.Client{Timeout: time.Second * 10}
+	return client.IsAliveWithContext(context.Background())
+}
+
+// IsAliveWithContext simply checks if Consul is up and running at the configured URL, giving up once ctx is done
+func (client *consulClient) IsAliveWithContext(ctx context.Context) bool {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, client.consulUrl+consulStatusPath, nil)
+	if err != nil {
+		return false
+	}
 
 	// This REST endpoint doesn't require Access Token, so no need to handle Auth Error.
-	resp, err := netClient.Get(client.consulUrl + consulStatusPath)
+	resp, err := client.statusClient.Do(request)
 	if err != nil {
 		return false
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
 		return true
@@ -97,34 +164,99 @@ func (client *consulClient) IsAlive() bool {
 
 // Registers the current service with Consul for discover and health check
 func (client *consulClient) Register() error {
+	return client.RegisterWithContext(context.Background())
+}
+
+// RegisterWithContext registers the current service with Consul for discover and health check, aborting once ctx is
+// done
+func (client *consulClient) RegisterWithContext(ctx context.Context) error {
+	err := client.registration.Register(func() error {
+		return client.register(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	client.startReportingHealth()
+	return nil
+}
+
+func (client *consulClient) register(ctx context.Context) error {
+	checkType := client.config.GetCheckType()
 	if client.serviceKey == "" || client.serviceAddress == "" || client.servicePort == 0 ||
-		client.healthCheckRoute == "" || client.healthCheckInterval == "" {
+		(checkType == types.CheckTypeHTTP && (client.healthCheckRoute == "" || client.healthCheckInterval == "")) ||
+		(checkType == types.CheckTypeTTL && client.healthCheckInterval == "") {
 		return fmt.Errorf("unable to register service with consul: Service information not set")
 	}
 
+	options := client.config.GetCheckOptions(client.serviceKey)
+	if checkType == types.CheckTypeHTTP {
+		if err := options.Validate(); err != nil {
+			return fmt.Errorf("unable to register service with consul: %w", err)
+		}
+	}
+	if client.clientChecked() {
+		if interval, err := time.ParseDuration(client.healthCheckInterval); err != nil || interval <= 0 {
+			return fmt.Errorf("unable to register service with consul: invalid check interval '%s'", client.healthCheckInterval)
+		}
+	}
+	if _, err := client.config.GetDeregisterCriticalAfter(); err != nil {
+		return fmt.Errorf("unable to register service with consul: %w", err)
+	}
+	// Without agent, nothing would expire the TTL checks, including those of the checks the client runs itself
+	if client.config.ConsulCatalog && (checkType == types.CheckTypeTTL || client.clientChecked()) {
+		return types.Errorf(types.ErrNotSupported, "unable to register service with consul: ttl checks and http checks with CheckHeaders aren't supported with ConsulCatalog")
+	}
+
+	if client.config.GetProbeBeforeRegister() && checkType == types.CheckTypeHTTP {
+		if err := health.Probe(ctx, client.config.GetHealthCheckUrl(), options); err != nil {
+			return fmt.Errorf("unable to register service with consul: %w", err)
+		}
+	}
+
+	// Replicas register their own instance ID under the service key as name, so they don't overwrite each other
 	registration := &consulapi.AgentServiceRegistration{
+		ID:      client.instanceId,
 		Name:    client.serviceKey,
 		Address: client.serviceAddress,
 		Port:    client.servicePort,
+		Meta:    client.config.GetServiceMetadata(),
+		Tags:    client.config.ServiceTags,
 	}
 
 	// Register for service discovery
-	err := client.consulClient.Agent().ServiceRegister(registration)
+	var err error
+	if client.config.ConsulCatalog {
+		err = client.catalogRegister(ctx, catalogService(registration), nil)
+	} else {
+		opts := consulapi.ServiceRegisterOpts{}.WithContext(ctx)
+		opts.Token = client.queryOptions(ctx).Token
+		err = client.consulClient.Agent().ServiceRegisterOpts(registration, opts)
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		err = client.consulClient.Agent().ServiceRegister(registration)
+		var retry bool
+		retry, err = client.reloadAccessTokenOnAuthError(ctx, err)
+		if retry {
+			// Try again with new Access Token
+			err = client.consulClient.Agent().ServiceRegisterOpts(registration, opts)
+		}
+		err = unauthorized(err)
 	}
 
 	if err != nil {
 		return err
 	}
 
+	if checkType == types.CheckTypeNone {
+		return nil
+	}
+	if checkType == types.CheckTypeTTL || client.clientChecked() {
+		return client.registerTTLCheck(ctx)
+	}
+
 	// Register for Health Check
-	name := "Health Check: " + client.serviceKey
+	name := "Health Check: " + client.instanceId
 	notes := "Check the health of the API"
-	err = client.RegisterCheck(client.serviceKey, name, notes, client.healthCheckRoute, client.healthCheckInterval)
+	err = client.RegisterCheckWithContext(ctx, client.instanceId, name, notes, client.healthCheckRoute, client.healthCheckInterval)
 
 	if err != nil {
 		return err
@@ -135,24 +267,42 @@ func (client *consulClient) Register() error {
 
 // Register check with consul
 func (client *consulClient) RegisterCheck(id string, name string, notes string, route string, interval string) error {
+	return client.RegisterCheckWithContext(context.Background(), id, name, notes, route, interval)
+}
+
+// RegisterCheckWithContext registers check with consul, aborting once ctx is done. The health check of the current
+// service, whose id is the instance ID of the service, is called with the CheckMethod, and has the service
+// deregistered once critical for DeregisterCriticalAfter. The checks with CheckHeaders are run by the client instead.
+func (client *consulClient) RegisterCheckWithContext(ctx context.Context, id string, name string, notes string, route string, interval string) error {
 	registration := &consulapi.AgentCheckRegistration{
 		ID:        id,
 		Name:      name,
 		Notes:     notes,
-		ServiceID: client.serviceKey,
+		ServiceID: client.instanceId,
 		AgentServiceCheck: consulapi.AgentServiceCheck{
 			HTTP:     client.config.GetExpandedRoute(route),
 			Interval: interval,
 		},
 	}
+	if id == client.instanceId {
+		options := client.config.GetCheckOptions(client.serviceKey)
+		registration.Method = options.Method
+		registration.DeregisterCriticalServiceAfter = client.config.DeregisterCriticalAfter
+	}
+	if client.config.ConsulCatalog {
+		// The checks of the current service are deregistered from the catalog along with it
+		return client.catalogRegister(ctx, nil, catalogCheck(registration))
+	}
+	queryOptions := client.queryOptions(ctx)
 
-	err := client.consulClient.Agent().CheckRegister(registration)
+	err := client.consulClient.Agent().CheckRegisterOpts(registration, queryOptions)
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
 	if retry {
 		// Try again with new Access Token
-		err = client.consulClient.Agent().CheckRegister(registration)
+		err = client.consulClient.Agent().CheckRegisterOpts(registration, queryOptions)
 	}
+	err = unauthorized(err)
 
 	if err != nil {
 		client.registeredChecks = append(client.registeredChecks, id)
@@ -161,36 +311,147 @@ func (client *consulClient) RegisterCheck(id string, name string, notes string,
 	return err
 }
 
-func (client *consulClient) UnregisterCheck(checkId string) error {
-	err := client.consulClient.Agent().CheckDeregister(checkId)
+// registerTTLCheck registers the TTL check of the current service, which Consul reports critical unless the service
+// passes it within each CheckInterval. Its ID is the instance ID of the service, like the HTTP check.
+func (client *consulClient) registerTTLCheck(ctx context.Context) error {
+	registration := client.ttlCheckRegistration()
+	queryOptions := client.queryOptions(ctx)
+	err := client.consulClient.Agent().CheckRegisterOpts(registration, queryOptions)
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
 	if retry {
 		// Try again with new Access Token
-		err = client.consulClient.Agent().CheckDeregister(checkId)
+		err = client.consulClient.Agent().CheckRegisterOpts(registration, queryOptions)
+	}
+	err = unauthorized(err)
+
+	if err != nil {
+		return fmt.Errorf("unable to register TTL health check with consul: %w", err)
+	}
+	return nil
+}
+
+// ttlCheckRegistration returns the registration of the TTL check of the current service, or of the http check the
+// client runs itself, which expires once a few reports have been missed
+func (client *consulClient) ttlCheckRegistration() *consulapi.AgentCheckRegistration {
+	registration := &consulapi.AgentCheckRegistration{
+		ID:        client.instanceId,
+		Name:      "TTL Health Check: " + client.instanceId,
+		Notes:     "Health reported by the service",
+		ServiceID: client.instanceId,
+		AgentServiceCheck: consulapi.AgentServiceCheck{
+			TTL:                            client.healthCheckInterval,
+			DeregisterCriticalServiceAfter: client.config.DeregisterCriticalAfter,
+		},
+	}
+	if client.clientChecked() {
+		// The check interval has been validated when registering
+		interval, _ := time.ParseDuration(client.healthCheckInterval)
+		registration.Name = "Health Check: " + client.instanceId
+		registration.Notes = "Health checked by the service with its check headers"
+		registration.TTL = (clientCheckTTLFactor * interval).String()
+	}
+	return registration
+}
+
+// PassTTL reports the current service, registered with the ttl check type, healthy for the next CheckInterval
+func (client *consulClient) PassTTL(ctx context.Context) error {
+	return client.updateTTL(ctx, "", consulapi.HealthPassing)
+}
+
+// FailTTL reports the current service, registered with the ttl check type, unhealthy right away
+func (client *consulClient) FailTTL(ctx context.Context, output string) error {
+	return client.updateTTL(ctx, output, consulapi.HealthCritical)
+}
+
+func (client *consulClient) updateTTL(ctx context.Context, output string, status string) error {
+	if client.config.GetCheckType() != types.CheckTypeTTL {
+		return fmt.Errorf("unable to update TTL health check of %s: registered with %s check type", client.serviceKey, client.config.GetCheckType())
+	}
+
+	return client.writeTTL(ctx, output, status)
+}
+
+// writeTTL writes the status of the TTL check of the current service, with the given output
+func (client *consulClient) writeTTL(ctx context.Context, output string, status string) error {
+	queryOptions := client.queryOptions(ctx)
+	err := client.consulClient.Agent().UpdateTTLOpts(client.instanceId, output, status, queryOptions)
+
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
+	if retry {
+		// Try again with new Access Token
+		err = client.consulClient.Agent().UpdateTTLOpts(client.instanceId, output, status, queryOptions)
+	}
+	err = unauthorized(err)
+
+	if err != nil {
+		return fmt.Errorf("unable to update TTL health check of %s with consul: %w", client.serviceKey, transport.Unavailable(err))
+	}
+	return nil
+}
+
+func (client *consulClient) UnregisterCheck(checkId string) error {
+	return client.unregisterCheck(context.Background(), checkId)
+}
+
+func (client *consulClient) unregisterCheck(ctx context.Context, checkId string) error {
+	var err error
+	if client.config.ConsulCatalog {
+		err = client.catalogDeregister(ctx, &consulapi.CatalogDeregistration{Node: client.catalogNode(), CheckID: checkId})
+	} else {
+		queryOptions := client.queryOptions(ctx)
+		err = client.consulClient.Agent().CheckDeregisterOpts(checkId, queryOptions)
+
+		var retry bool
+		retry, err = client.reloadAccessTokenOnAuthError(ctx, err)
+		if retry {
+			// Try again with new Access Token
+			err = client.consulClient.Agent().CheckDeregisterOpts(checkId, queryOptions)
+		}
+		err = unauthorized(err)
 	}
 
 	if err != nil {
-		return fmt.Errorf("unable to de-register service health check with consul: %v", err)
+		return fmt.Errorf("unable to de-register service health check with consul: %w", err)
 	}
 	return nil
 }
 
 func (client *consulClient) Unregister() error {
-	err := client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+	return client.UnregisterWithContext(context.Background())
+}
+
+// UnregisterWithContext de-registers the current service from Consul, aborting once ctx is done
+func (client *consulClient) UnregisterWithContext(ctx context.Context) error {
+	return client.registration.Unregister(func() error {
+		return client.unregister(ctx)
+	})
+}
+
+func (client *consulClient) unregister(ctx context.Context) error {
+	if client.config.ConsulCatalog {
+		if err := client.catalogUnregister(ctx); err != nil {
+			return fmt.Errorf("unable to de-register service with consul: %w", err)
+		}
+		return nil
+	}
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
+	queryOptions := client.queryOptions(ctx)
+	err := client.consulClient.Agent().ServiceDeregisterOpts(client.instanceId, queryOptions)
+
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
 	if retry {
 		// Try again with new Access Token
-		err = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		err = client.consulClient.Agent().ServiceDeregisterOpts(client.instanceId, queryOptions)
 	}
+	err = unauthorized(err)
 
 	if err != nil {
-		return fmt.Errorf("unable to de-register service with consul: %v", err)
+		return fmt.Errorf("unable to de-register service with consul: %w", err)
 	}
 
 	for _, checkId := range client.registeredChecks {
-		if err := client.UnregisterCheck(checkId); err != nil {
+		if err := client.unregisterCheck(ctx, checkId); err != nil {
 			return err
 		}
 	}
@@ -198,100 +459,642 @@ func (client *consulClient) Unregister() error {
 	return nil
 }
 
-// GetServiceEndpoint retrieves the port, service ID and host of a known endpoint from Consul.
-// If this operation is successful and a known endpoint is found, it is returned. Otherwise, an error is returned.
-func (client *consulClient) GetServiceEndpoint(serviceID string) (types.ServiceEndpoint, error) {
-	services, err := client.consulClient.Agent().Services()
+// Decommission permanently retires the target service from Consul. Each instance of the service is first put into
+// maintenance mode so it is immediately reported as critical, then de-registered. Only the instance of the current
+// service is decommissioned when the target service is the current one, leaving its replicas registered. With
+// ConsulCatalog, the instances are removed from the catalog right away.
+func (client *consulClient) Decommission(ctx context.Context, serviceKey string) error {
+	if client.config.ConsulCatalog {
+		return client.catalogDecommission(ctx, serviceKey)
+	}
+
+	// Decommissioning the current service must not be undone by restoring its registration
+	if serviceKey == client.serviceKey {
+		return client.registration.Unregister(func() error {
+			return client.decommission(ctx, serviceKey, client.instanceId)
+		})
+	}
+
+	// Only the instances registered with the agent can be deregistered from it
+	instances, err := client.agentInstances(ctx, serviceKey)
+	if err != nil {
+		return fmt.Errorf("unable to decommission service %s: %w", serviceKey, err)
+	}
+	if len(instances) == 0 {
+		return types.Errorf(types.ErrNotRegistered, "unable to decommission %s: service is not registered", serviceKey)
+	}
+	for _, instance := range instances {
+		if err := client.decommission(ctx, serviceKey, instance.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (client *consulClient) decommission(ctx context.Context, serviceKey string, instanceId string) error {
+	if err := client.enableMaintenance(ctx, serviceKey, instanceId); err != nil {
+		return err
+	}
+
+	// Consul removes the maintenance and health checks associated with the service along with it
+	queryOptions := client.queryOptions(ctx)
+	err := client.consulClient.Agent().ServiceDeregisterOpts(instanceId, queryOptions)
+
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
+	if retry {
+		// Try again with new Access Token
+		err = client.consulClient.Agent().ServiceDeregisterOpts(instanceId, queryOptions)
+	}
+	err = unauthorized(err)
+
+	if err != nil {
+		return fmt.Errorf("unable to de-register service %s with consul: %w", instanceId, err)
+	}
+
+	return nil
+}
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
+// Drain puts the instances of the target service into maintenance mode, so they are reported as critical while still
+// registered, only the instance of the current service when the target service is the current one. With
+// ConsulCatalog, there is no agent to put them into maintenance mode.
+func (client *consulClient) Drain(ctx context.Context, serviceKey string) error {
+	if client.config.ConsulCatalog {
+		return types.Errorf(types.ErrNotSupported, "unable to drain %s: services can't be put into maintenance mode with ConsulCatalog", serviceKey)
+	}
+
+	if serviceKey == client.serviceKey {
+		return client.enableMaintenance(ctx, serviceKey, client.instanceId)
+	}
+
+	instances, err := client.agentInstances(ctx, serviceKey)
+	if err != nil {
+		return fmt.Errorf("unable to drain service %s: %w", serviceKey, err)
+	}
+	if len(instances) == 0 {
+		return types.Errorf(types.ErrNotRegistered, "unable to drain %s: service is not registered", serviceKey)
+	}
+	for _, instance := range instances {
+		if err := client.enableMaintenance(ctx, serviceKey, instance.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enableMaintenance puts the instance of the target service into maintenance mode, so it is immediately reported as
+// critical
+func (client *consulClient) enableMaintenance(ctx context.Context, serviceKey string, instanceId string) error {
+	reason := "Service " + serviceKey + " is being decommissioned"
+	queryOptions := client.queryOptions(ctx)
+	err := client.consulClient.Agent().EnableServiceMaintenanceOpts(instanceId, reason, queryOptions)
+
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
 	if retry {
 		// Try again with new Access Token
-		services, err = client.consulClient.Agent().Services()
+		err = client.consulClient.Agent().EnableServiceMaintenanceOpts(instanceId, reason, queryOptions)
+	}
+	err = unauthorized(err)
+
+	if err != nil {
+		return fmt.Errorf("unable to put service %s into maintenance mode: %w", instanceId, err)
+	}
+
+	return nil
+}
+
+// PutTombstone keeps the tombstone of the decommissioned service in the KV store of Consul
+func (client *consulClient) PutTombstone(ctx context.Context, tombstone types.Tombstone) error {
+	value, err := json.Marshal(tombstone)
+	if err != nil {
+		return fmt.Errorf("unable to encode the tombstone of service %s: %w", tombstone.ServiceKey, err)
+	}
+
+	pair := &consulapi.KVPair{Key: tombstonesPrefix + tombstone.ServiceKey, Value: value}
+	writeOptions := client.writeOptions(ctx)
+	_, err = client.consulClient.KV().Put(pair, writeOptions)
+
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
+	if retry {
+		// Try again with new Access Token
+		_, err = client.consulClient.KV().Put(pair, writeOptions)
+	}
+	err = unauthorized(err)
+
+	if err != nil {
+		return fmt.Errorf("unable to put the tombstone of service %s: %w", tombstone.ServiceKey, err)
+	}
+
+	return nil
+}
+
+// GetTombstones retrieves the tombstones of all the decommissioned services from the KV store of Consul
+func (client *consulClient) GetTombstones(ctx context.Context) ([]types.Tombstone, error) {
+	queryOptions := client.queryOptions(ctx)
+	pairs, _, err := client.consulClient.KV().List(tombstonesPrefix, queryOptions)
+
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
+	if retry {
+		// Try again with new Access Token
+		pairs, _, err = client.consulClient.KV().List(tombstonesPrefix, queryOptions)
+	}
+	err = unauthorized(err)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the service tombstones: %w", err)
+	}
+
+	tombstones := make([]types.Tombstone, 0, len(pairs))
+	for _, pair := range pairs {
+		var tombstone types.Tombstone
+		if err := json.Unmarshal(pair.Value, &tombstone); err != nil {
+			return nil, fmt.Errorf("unable to decode the tombstone %s: %w", pair.Key, transport.Malformed(err))
+		}
+		tombstones = append(tombstones, tombstone)
 	}
 
+	return tombstones, nil
+}
+
+// WatchSelf polls Consul for the registration of the current service and notifies the caller each time it has been
+// modified or deleted by someone else. Watching stops and the returned channel is closed once ctx is cancelled.
+func (client *consulClient) WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error) {
+	if client.serviceKey == "" || client.serviceAddress == "" || client.servicePort == 0 {
+		return nil, fmt.Errorf("unable to watch service registration with consul: Service information not set")
+	}
+
+	interval, waitTime, err := client.watchTimes()
 	if err != nil {
+		return nil, err
+	}
+
+	expected := types.ServiceEndpoint{
+		ServiceId: client.serviceKey,
+		Host:      client.serviceAddress,
+		Port:      client.servicePort,
+		Scheme:    client.config.GetServiceMetadata()[types.SchemeMetadataKey],
+		Metadata:  client.config.GetServiceMetadata(),
+		Tags:      client.config.ServiceTags,
+	}
+
+	endpoints := watch.BlockingEndpoint(ctx, interval, func(index uint64) (types.ServiceEndpoint, uint64, error) {
+		// The current service is registered with the agent, so in its datacenter
+		instances, index, err := client.catalogInstances(ctx, client.serviceKey, "", index, waitTime)
+		if err != nil {
+			return types.ServiceEndpoint{}, 0, err
+		}
+		// Only the instance of the current service is watched, not its replicas
+		for _, instance := range instances {
+			if instance.ServiceID == client.instanceId {
+				return catalogServiceEndpoint(instance), index, nil
+			}
+		}
+		return types.ServiceEndpoint{}, index, nil
+	})
+	return watch.SelfEvents(ctx, endpoints, expected), nil
+}
+
+// WatchService watches the endpoint of the target service with blocking queries of the Consul catalog and sends it
+// each time it changes, starting with the current one. An empty endpoint is sent when the service isn't registered
+// (anymore). The queries failing are sent again every watch interval until they succeed. Watching stops and the
+// returned channel is closed once ctx is cancelled.
+func (client *consulClient) WatchService(ctx context.Context, serviceKey string) (<-chan types.ServiceEndpoint, error) {
+	interval, waitTime, err := client.watchTimes()
+	if err != nil {
+		return nil, err
+	}
+
+	// The agent services endpoint used for discovery doesn't support blocking queries, unlike the catalog the agent
+	// syncs its services to
+	return watch.BlockingEndpoint(ctx, interval, func(index uint64) (types.ServiceEndpoint, uint64, error) {
+		instances, index, err := client.catalogInstances(ctx, serviceKey, client.config.ConsulDatacenter, index, waitTime)
+		if err != nil || len(instances) == 0 {
+			return types.ServiceEndpoint{}, index, err
+		}
+		return catalogServiceEndpoint(instances[0]), index, nil
+	}), nil
+}
+
+// watchTimes returns the interval at which the failed blocking queries of the watches are retried and how long they
+// wait for a change
+func (client *consulClient) watchTimes() (time.Duration, time.Duration, error) {
+	interval, err := client.config.GetWatchInterval()
+	if err != nil {
+		return 0, 0, err
+	}
+	waitTime, err := client.config.GetWatchWaitTime()
+	if err != nil {
+		return 0, 0, err
+	}
+	return interval, waitTime, nil
+}
+
+// catalogInstances retrieves the instances of the target service from the Consul catalog of the datacenter, the one
+// of the agent if empty, in the order of their IDs, once its index is past the given one or the wait time elapsed,
+// along with its new index. The query doesn't block when the index is 0. It is retried once with a renewed Access
+// Token.
+func (client *consulClient) catalogInstances(ctx context.Context, serviceKey string, datacenter string, index uint64, waitTime time.Duration) ([]*consulapi.CatalogService, uint64, error) {
+	queryOptions := client.queryOptions(ctx)
+	queryOptions.Datacenter = datacenter
+	queryOptions.WaitIndex = index
+	queryOptions.WaitTime = waitTime
+	instances, meta, err := client.consulClient.Catalog().Service(serviceKey, "", queryOptions)
+
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
+	if retry {
+		// Try again with new Access Token
+		instances, meta, err = client.consulClient.Catalog().Service(serviceKey, "", queryOptions)
+	}
+	if err != nil {
+		return nil, 0, transport.Malformed(transport.Unavailable(unauthorized(err)))
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ServiceID < instances[j].ServiceID
+	})
+	return instances, meta.LastIndex, nil
+}
+
+// registeredEndpoint retrieves the endpoint of the first instance of the target service from Consul, which is empty
+// when the service isn't registered
+func (client *consulClient) registeredEndpoint(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	instances, err := client.instances(ctx, serviceKey)
+	if err != nil || len(instances) == 0 {
 		return types.ServiceEndpoint{}, err
 	}
 
-	endpoint := types.ServiceEndpoint{}
-	if service, ok := services[serviceID]; ok {
-		endpoint.Port = service.Port
-		endpoint.ServiceId = serviceID
-		endpoint.Host = service.Address
-	} else {
-		return types.ServiceEndpoint{}, fmt.Errorf("no matching service endpoint found")
+	return serviceEndpoint(instances[0]), nil
+}
+
+// instances retrieves the instances of the target service discovered, in the catalog of the ConsulDatacenter with
+// either it or ConsulCatalog set, otherwise registered with the Consul agent, in the order of their IDs
+func (client *consulClient) instances(ctx context.Context, serviceKey string) ([]*consulapi.AgentService, error) {
+	if !client.discoversCatalog() {
+		return client.agentInstances(ctx, serviceKey)
+	}
+
+	catalogInstances, _, err := client.catalogInstances(ctx, serviceKey, client.config.ConsulDatacenter, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	instances := make([]*consulapi.AgentService, 0, len(catalogInstances))
+	for _, instance := range catalogInstances {
+		instances = append(instances, agentService(instance))
+	}
+	return instances, nil
+}
+
+// agentInstances retrieves the instances of the target service registered with the Consul agent, which are the
+// services named after the service key, in the order of their IDs. The instance registered with the service key as ID,
+// as before instance IDs, comes first.
+func (client *consulClient) agentInstances(ctx context.Context, serviceKey string) ([]*consulapi.AgentService, error) {
+	services, err := client.agentServices(ctx, fmt.Sprintf("Service == %s", strconv.Quote(serviceKey)))
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []*consulapi.AgentService
+	for _, service := range services {
+		// Agents too old to filter return all the services
+		if service.Service == serviceKey {
+			instances = append(instances, service)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ID < instances[j].ID
+	})
+	return instances, nil
+}
+
+// serviceEndpoint returns the endpoint of the given service instance, with its metadata and tags
+func serviceEndpoint(service *consulapi.AgentService) types.ServiceEndpoint {
+	return types.ServiceEndpoint{
+		ServiceId: service.Service,
+		Host:      service.Address,
+		Port:      service.Port,
+		Scheme:    service.Meta[types.SchemeMetadataKey],
+		Metadata:  service.Meta,
+		Tags:      service.Tags,
+	}
+}
+
+// catalogServiceEndpoint returns the endpoint of the given service instance of the catalog, with its metadata and tags
+func catalogServiceEndpoint(service *consulapi.CatalogService) types.ServiceEndpoint {
+	return serviceEndpoint(agentService(service))
+}
+
+// agentService returns the service instance of the catalog as registered with its agent, the address of its node
+// being the address of the services registered without one
+func agentService(service *consulapi.CatalogService) *consulapi.AgentService {
+	address := service.ServiceAddress
+	if address == "" {
+		address = service.Address
+	}
+	return &consulapi.AgentService{
+		ID:      service.ServiceID,
+		Service: service.ServiceName,
+		Address: address,
+		Port:    service.ServicePort,
+		Meta:    service.ServiceMeta,
+		Tags:    service.ServiceTags,
+	}
+}
+
+// TriggerHealthCheck runs the HTTP health checks of the target service right away, rather than waiting for Consul's
+// next scheduled check. Consul only accepts check results for TTL checks, so the client calls the registered health
+// check URLs itself and the result isn't reflected in Consul until its next check.
+func (client *consulClient) TriggerHealthCheck(ctx context.Context, serviceKey string) (types.HealthCheckResult, error) {
+	endpoint, err := client.registeredEndpoint(ctx, serviceKey)
+	if err != nil {
+		return types.HealthCheckResult{}, fmt.Errorf("unable to health check %s: %w", serviceKey, err)
+	}
+	if endpoint.IsZero() {
+		return types.HealthCheckResult{}, types.Errorf(types.ErrNotRegistered, "unable to health check %s: service is not registered", serviceKey)
+	}
+
+	checks, _, err := client.consulClient.Health().Checks(serviceKey, client.discoveryQueryOptions(ctx))
+	if err != nil {
+		return types.HealthCheckResult{}, fmt.Errorf("unable to get health checks of service %s: %w", serviceKey, transport.Unavailable(err))
+	}
+
+	var urls []string
+	// The http check the client runs itself is registered without its URL
+	if serviceKey == client.serviceKey && client.clientChecked() {
+		urls = append(urls, client.config.GetHealthCheckUrl())
+	}
+	for _, check := range checks {
+		if check.Definition.HTTP != "" {
+			urls = append(urls, check.Definition.HTTP)
+		}
+	}
+	if len(urls) == 0 {
+		return types.HealthCheckResult{Healthy: true, Output: "service registered without HTTP health checks"}, nil
+	}
+
+	return health.Check(ctx, client.config.GetCheckOptions(serviceKey), urls...), nil
+}
+
+// GetServiceEndpoint retrieves the port, service ID and host of a known endpoint from Consul.
+// If this operation is successful and a known endpoint is found, it is returned. Otherwise, an error is returned.
+func (client *consulClient) GetServiceEndpoint(serviceID string) (types.ServiceEndpoint, error) {
+	return client.GetServiceEndpointWithContext(context.Background(), serviceID)
+}
+
+// GetServiceEndpointWithContext retrieves the port, service ID and host of a known endpoint from Consul, aborting once
+// ctx is done
+func (client *consulClient) GetServiceEndpointWithContext(ctx context.Context, serviceID string) (types.ServiceEndpoint, error) {
+	endpoint, err := client.registeredEndpoint(ctx, serviceID)
+	if err != nil {
+		return types.ServiceEndpoint{}, err
+	}
+	if endpoint.IsZero() {
+		return types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+	if err := endpoint.Validate(); err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceID, err)
 	}
 
 	return endpoint, nil
 }
 
+// GetServiceEndpoints retrieves the endpoints of all the instances of the target service registered with the Consul
+// agent, i.e. the services with different IDs registered under the same name
+func (client *consulClient) GetServiceEndpoints(serviceKey string) ([]types.ServiceEndpoint, error) {
+	return client.GetServiceEndpointsWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointsWithContext retrieves the endpoints of all the instances of the target service from Consul,
+// aborting once ctx is done
+func (client *consulClient) GetServiceEndpointsWithContext(ctx context.Context, serviceKey string) ([]types.ServiceEndpoint, error) {
+	instances, err := client.instances(ctx, serviceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []types.ServiceEndpoint
+	for _, instance := range instances {
+		endpoint := serviceEndpoint(instance)
+		endpoint.InstanceId = instance.ID
+		if err := endpoint.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to get service %s endpoints: %w", serviceKey, err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if len(endpoints) == 0 {
+		return nil, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+	types.SortServiceEndpoints(endpoints, client.config.GetEndpointOrder())
+
+	return endpoints, nil
+}
+
 // GetAllServiceEndpoints retrieves all registered endpoints from Consul.
 func (client *consulClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
-	services, err := client.consulClient.Agent().Services()
+	return client.GetAllServiceEndpointsWithContext(context.Background())
+}
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		services, err = client.consulClient.Agent().Services()
+// GetAllServiceEndpointsWithContext retrieves all registered endpoints from Consul, aborting once ctx is done
+func (client *consulClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	services, err := client.services(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []types.ServiceEndpoint
+	for _, service := range services {
+		endpoints = append(endpoints, serviceEndpoint(service))
 	}
+	types.SortServiceEndpoints(endpoints, client.config.GetEndpointOrder())
+
+	return endpoints, nil
+}
 
+// GetServiceEndpointsMatchingWithContext retrieves the endpoints of the services with the tags and metadata of the
+// selector, which Consul selects with a filter expression, aborting once ctx is done
+func (client *consulClient) GetServiceEndpointsMatchingWithContext(ctx context.Context, selector types.EndpointSelector) ([]types.ServiceEndpoint, error) {
+	services, err := client.filteredServices(ctx, selectorFilter(selector))
 	if err != nil {
 		return nil, err
 	}
 
 	var endpoints []types.ServiceEndpoint
 	for _, service := range services {
-		svc := types.ServiceEndpoint{}
-		svc.Port = service.Port
-		svc.ServiceId = service.ID
-		svc.Host = service.Address
-		endpoints = append(endpoints, svc)
+		endpoints = append(endpoints, serviceEndpoint(service))
 	}
+	types.SortServiceEndpoints(endpoints, client.config.GetEndpointOrder())
 
-	return endpoints, nil
+	// Agents too old to filter return all the services
+	return selector.Filter(endpoints), nil
+}
+
+// selectorFilter builds the Consul filter expression selecting the services with the tags and metadata of the
+// selector, i.e. "modbus" in Tags and Meta["region"] == "eu"
+func selectorFilter(selector types.EndpointSelector) string {
+	var terms []string
+	for _, tag := range selector.Tags {
+		terms = append(terms, fmt.Sprintf("%s in Tags", strconv.Quote(tag)))
+	}
+	keys := make([]string, 0, len(selector.Metadata))
+	for key := range selector.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		terms = append(terms, fmt.Sprintf("Meta[%s] == %s", strconv.Quote(key), strconv.Quote(selector.Metadata[key])))
+	}
+	return strings.Join(terms, " and ")
 }
 
 // Checks with Consul if the target service is registered and healthy
 func (client *consulClient) IsServiceAvailable(serviceKey string) (bool, error) {
-	services, err := client.consulClient.Agent().Services()
+	return client.IsServiceAvailableWithContext(context.Background(), serviceKey)
+}
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		services, err = client.consulClient.Agent().Services()
+// IsServiceAvailableWithContext checks with Consul if the target service is registered and healthy, aborting once ctx
+// is done
+func (client *consulClient) IsServiceAvailableWithContext(ctx context.Context, serviceKey string) (bool, error) {
+	instances, err := client.instances(ctx, serviceKey)
+	if err != nil {
+		return false, fmt.Errorf("unable to check if service %s is available: %w", serviceKey, err)
 	}
 
+	if len(instances) == 0 {
+		return false, types.Errorf(types.ErrNotRegistered, "%s service is not registered. Might not have started... ", serviceKey)
+	}
+
+	healthChecks, _, err := client.consulClient.Health().Checks(serviceKey, client.discoveryQueryOptions(ctx))
 	if err != nil {
-		return false, fmt.Errorf("unable to check if service %s is available: %v", serviceKey, err)
+		return false, fmt.Errorf("unable to check health of service %s: %w", serviceKey, transport.Unavailable(err))
 	}
 
-	if _, ok := services[serviceKey]; !ok {
-		return false, fmt.Errorf("%s service is not registered. Might not have started... ", serviceKey)
+	// The service is available as long as one of its instances is healthy
+	instanceChecks := make(map[string]consulapi.HealthChecks, len(instances))
+	for _, check := range healthChecks {
+		instanceChecks[check.ServiceID] = append(instanceChecks[check.ServiceID], check)
+	}
+	for _, instance := range instances {
+		checks, ok := instanceChecks[instance.ID]
+		// Consul treats services registered without health checks as passing, which is followed unless opted out
+		if !ok && client.config.GetUncheckedServicesAvailable() || ok && types.ParseStatus(checks.AggregatedStatus()).IsUp() {
+			return true, nil
+		}
 	}
 
-	healthCheck, _, err := client.consulClient.Health().Checks(serviceKey, nil)
+	if len(healthChecks) == 0 {
+		return false, types.Errorf(types.ErrUnhealthy, "no health checks for service %s", serviceKey)
+	}
+	return false, types.Errorf(types.ErrUnhealthy, " %s service not healthy...", serviceKey)
+}
+
+// services retrieves the services registered with the Consul agent, retrying once with a renewed Access Token
+func (client *consulClient) services(ctx context.Context) (map[string]*consulapi.AgentService, error) {
+	return client.filteredServices(ctx, "")
+}
+
+// filteredServices retrieves the services discovered matching the given filter expression, all of them if empty, in
+// the catalog of the ConsulDatacenter with either it or ConsulCatalog set, otherwise registered with the Consul agent.
+// The filter isn't applied to the catalog, whose fields are named differently, so the services are to be filtered by
+// the caller as well.
+func (client *consulClient) filteredServices(ctx context.Context, filter string) (map[string]*consulapi.AgentService, error) {
+	if !client.discoversCatalog() {
+		return client.agentServices(ctx, filter)
+	}
+
+	queryOptions := client.queryOptions(ctx)
+	queryOptions.Datacenter = client.config.ConsulDatacenter
+	names, _, err := client.consulClient.Catalog().Services(queryOptions)
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
+	if retry {
+		// Try again with new Access Token
+		names, _, err = client.consulClient.Catalog().Services(queryOptions)
+	}
 	if err != nil {
-		return false, fmt.Errorf("unable to check health of service %s: %v", serviceKey, err)
+		return nil, transport.Malformed(transport.Unavailable(unauthorized(err)))
 	}
 
-	if len(healthCheck) == 0 {
-		return false, fmt.Errorf("no health checks for service %s: %v", serviceKey, err)
+	services := make(map[string]*consulapi.AgentService)
+	for name := range names {
+		// Consul servers register themselves in the catalog, but not with the agent
+		if name == "consul" {
+			continue
+		}
+		instances, _, err := client.catalogInstances(ctx, name, client.config.ConsulDatacenter, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		// The IDs are unique per node only
+		for _, instance := range instances {
+			services[instance.Node+"/"+instance.ServiceID] = agentService(instance)
+		}
+	}
+	return services, nil
+}
+
+// agentServices retrieves the services registered with the Consul agent matching the given filter expression, all
+// of them if empty, retrying once with a renewed Access Token
+func (client *consulClient) agentServices(ctx context.Context, filter string) (map[string]*consulapi.AgentService, error) {
+	queryOptions := client.queryOptions(ctx)
+	services, err := client.consulClient.Agent().ServicesWithFilterOpts(filter, queryOptions)
+
+	retry, err := client.reloadAccessTokenOnAuthError(ctx, err)
+	if retry {
+		// Try again with new Access Token
+		services, err = client.consulClient.Agent().ServicesWithFilterOpts(filter, queryOptions)
+	}
+	err = unauthorized(err)
+
+	return services, transport.Malformed(transport.Unavailable(err))
+}
+
+// unauthorized returns the ACL errors Consul kept rejecting the request with as types.ErrUnauthorized
+func unauthorized(err error) error {
+	if err != nil && strings.Contains(err.Error(), aclError) {
+		return types.Errorf(types.ErrUnauthorized, "%w", err)
+	}
+	return err
+}
+
+// queryOptions creates the options of a request bound to ctx, authenticated with the access token carried by ctx if
+// any instead of the one of the client
+func (client *consulClient) queryOptions(ctx context.Context) *consulapi.QueryOptions {
+	queryOptions := (&consulapi.QueryOptions{}).WithContext(ctx)
+	if accessToken, ok := types.AccessTokenFromContext(ctx); ok {
+		queryOptions.Token = accessToken
 	}
+	return queryOptions
+}
 
-	status := healthCheck.AggregatedStatus()
-	if status != serviceStatusPass {
-		return false, fmt.Errorf(" %s service not healthy...", serviceKey)
+// writeOptions creates the options of a write request bound to ctx, authenticated with the access token carried by
+// ctx if any instead of the one of the client
+func (client *consulClient) writeOptions(ctx context.Context) *consulapi.WriteOptions {
+	writeOptions := (&consulapi.WriteOptions{}).WithContext(ctx)
+	if accessToken, ok := types.AccessTokenFromContext(ctx); ok {
+		writeOptions.Token = accessToken
 	}
+	return writeOptions
+}
 
-	return true, nil
+// discoveryQueryOptions creates the options of a request about the services discovered, bound to ctx, sent to the
+// ConsulDatacenter if set
+func (client *consulClient) discoveryQueryOptions(ctx context.Context) *consulapi.QueryOptions {
+	queryOptions := client.queryOptions(ctx)
+	queryOptions.Datacenter = client.config.ConsulDatacenter
+	return queryOptions
 }
 
-func (client *consulClient) reloadAccessTokenOnAuthError(err error) (bool, error) {
+// reloadAccessTokenOnAuthError renews the Access Token of the client when the request failed with an ACL error, so it
+// can be retried. The token is kept when the request was authenticated with the one carried by ctx.
+func (client *consulClient) reloadAccessTokenOnAuthError(ctx context.Context, err error) (bool, error) {
 	if err == nil {
 		return false, nil
 	}
 
+	// The token of the token file is read again when it changes, rather than renewed
+	if _, ok := types.AccessTokenFromContext(ctx); ok || client.tokenFile != nil {
+		return false, err
+	}
+
 	if strings.Contains(err.Error(), aclError) && client.getAccessToken != nil {
+		client.config.GetLoggingClient().Debugf("Consul rejected the request with an ACL error, renewing the access token: %v", err)
 		newToken, err := client.getAccessToken()
 		if err != nil {
 			err = fmt.Errorf("failed to renew access token: %s", err.Error())
@@ -303,7 +1106,7 @@ func (client *consulClient) reloadAccessTokenOnAuthError(err error) (bool, error
 		// Have to recreate the consul client with the new Access Token
 		client.consulClient, err = consulapi.NewClient(client.consulConfig)
 		if err != nil {
-			return false, fmt.Errorf("unable for create new Consul Client for %s: %v", client.consulUrl, err)
+			return false, fmt.Errorf("unable for create new Consul Client for %s: %w", client.consulUrl, err)
 		}
 
 		return true, nil
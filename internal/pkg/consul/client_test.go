@@ -17,19 +17,25 @@
 package consul
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	consulapi "github.com/hashicorp/consul/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/hostile"
 	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
 )
 
@@ -82,6 +88,59 @@ func TestRegisterNoServiceInfoError(t *testing.T) {
 	require.Error(t, err, "Expected error due to no service info")
 }
 
+func TestRegisterWithoutHealthCheck(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.CheckType = types.CheckTypeNone
+	client.healthCheckRoute = ""
+	client.healthCheckInterval = ""
+
+	// Try to clean-up after test
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+	}(client)
+
+	err := client.Register()
+	require.NoError(t, err)
+
+	actual, err := client.IsServiceAvailable(client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, actual, "Expected service without health check to be available once registered")
+
+	uncheckedServicesAvailable := false
+	client.config.UncheckedServicesAvailable = &uncheckedServicesAvailable
+	actual, err = client.IsServiceAvailable(client.serviceKey)
+	require.ErrorIs(t, err, types.ErrUnhealthy, "Expected service without health check to be unhealthy once opted out")
+	require.False(t, actual)
+}
+
+func TestRegisterProbeFailure(t *testing.T) {
+	// Nothing is serving the health check route of the service
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	probeBeforeRegister := true
+	client.config.ProbeBeforeRegister = &probeBeforeRegister
+
+	err := client.Register()
+	require.Error(t, err, "Expected error due to failed health check probe")
+
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.Error(t, err, "Expected service not to be registered")
+}
+
+func TestRegisterWithContextCancelled(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.RegisterWithContext(ctx)
+	require.Error(t, err, "Expected error registering service with cancelled context")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, client.IsAliveWithContext(ctx))
+
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.Error(t, err, "Expected service not to be registered")
+}
+
 func TestRegisterWithPingCallback(t *testing.T) {
 	doneChan := make(chan bool)
 	receivedPing := false
@@ -203,6 +262,167 @@ func TestUnregister(t *testing.T) {
 	require.Error(t, err, "Expected error getting service endpoint")
 }
 
+func TestDecommission(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+
+	// Make sure service is not already registered.
+	_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+	_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+
+	err := client.Decommission(context.Background(), client.serviceKey)
+	require.Error(t, err, "Expected error decommissioning service that isn't registered")
+
+	err = client.Register()
+	require.NoError(t, err, "Error registering service")
+
+	err = client.Decommission(context.Background(), client.serviceKey)
+	require.NoError(t, err, "Error decommissioning service")
+
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.Error(t, err, "Expected error getting service endpoint")
+}
+
+func TestDrain(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+	}(client)
+
+	err := client.Register()
+	require.NoError(t, err, "Error registering service")
+
+	err = client.Drain(context.Background(), client.serviceKey)
+	require.NoError(t, err, "Error draining service")
+
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err, "Expected the drained service to stay registered")
+
+	client.config.ConsulCatalog = true
+	err = client.Drain(context.Background(), client.serviceKey)
+	assert.ErrorIs(t, err, types.ErrNotSupported)
+}
+
+func TestTombstones(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	tombstone := types.Tombstone{ServiceKey: client.serviceKey, Decommissioned: time.Now().UTC().Truncate(time.Second)}
+
+	require.NoError(t, client.PutTombstone(context.Background(), tombstone))
+	tombstones, err := client.GetTombstones(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, tombstones, tombstone)
+}
+
+func TestWatchSelf(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.WatchInterval = "50ms"
+
+	// Try to clean-up after test
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+	}(client)
+
+	err := client.Register()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.WatchSelf(ctx)
+	require.NoError(t, err)
+
+	// Another instance overwrites the registration with a different port
+	other := makeConsulClient(t, client.serviceKey, defaultServicePort+1, true, "", nil)
+	err = other.Register()
+	require.NoError(t, err)
+
+	event := receiveEvent(t, events)
+	require.Equal(t, types.RegistrationModified, event.Type)
+	require.Equal(t, defaultServicePort+1, event.Endpoint.Port)
+
+	err = other.Unregister()
+	require.NoError(t, err)
+
+	event = receiveEvent(t, events)
+	require.Equal(t, types.RegistrationDeleted, event.Type)
+}
+
+func TestWatchService(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.WatchInterval = "50ms"
+
+	// Try to clean-up after test
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+	}(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	endpoints, err := client.WatchService(ctx, client.serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint for service not registered yet")
+
+	err = client.Register()
+	require.NoError(t, err)
+
+	endpoint := receiveEndpoint(t, endpoints)
+	require.Equal(t, client.serviceKey, endpoint.ServiceId)
+	require.Equal(t, defaultServicePort, endpoint.Port)
+
+	// Another instance overwrites the registration with a different port
+	other := makeConsulClient(t, client.serviceKey, defaultServicePort+1, true, "", nil)
+	err = other.Register()
+	require.NoError(t, err)
+
+	endpoint = receiveEndpoint(t, endpoints)
+	require.Equal(t, defaultServicePort+1, endpoint.Port)
+
+	err = other.Unregister()
+	require.NoError(t, err)
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints))
+
+	cancel()
+	select {
+	case _, ok := <-endpoints:
+		require.False(t, ok, "Expected channel to be closed once the context is cancelled")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Channel not closed once the context is cancelled")
+	}
+}
+
+func TestWatchServiceBlockingQueries(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	// The changes are sent as soon as they are made, rather than on the next poll
+	client.config.WatchInterval = "1h"
+
+	// Try to clean-up after test
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+	}(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoints, err := client.WatchService(ctx, client.serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint for service not registered yet")
+
+	err = client.Register()
+	require.NoError(t, err)
+
+	endpoint := receiveEndpoint(t, endpoints)
+	require.Equal(t, client.serviceKey, endpoint.ServiceId)
+	require.Equal(t, defaultServicePort, endpoint.Port)
+
+	err = client.Unregister()
+	require.NoError(t, err)
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints))
+}
+
 func TestUnregisterCheck(t *testing.T) {
 	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
 
@@ -252,6 +472,90 @@ func TestAccessToken(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAccessTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "consul-token")
+	require.NoError(t, os.WriteFile(path, []byte("FirstAccessToken\n"), 0600))
+	mockConsul.SetExpectedAccessToken("FirstAccessToken")
+	defer mockConsul.ClearExpectedAccessToken()
+
+	makeClient := func() (*consulClient, error) {
+		return NewConsulClient(types.Config{
+			Host:            testHost,
+			Port:            port,
+			CheckInterval:   "1s",
+			CheckRoute:      "/api/v1/ping",
+			ServiceKey:      getUniqueServiceName(),
+			ServiceHost:     serviceHost,
+			ServicePort:     defaultServicePort,
+			AccessToken:     "IgnoredAccessToken",
+			AccessTokenFile: path,
+		})
+	}
+	client, err := makeClient()
+	require.NoError(t, err)
+	require.NoError(t, client.Register())
+	defer func() { _ = client.Unregister() }()
+
+	// The Vault agent rotates the token
+	require.NoError(t, os.WriteFile(path, []byte("SecondAccessToken"), 0600))
+	mockConsul.SetExpectedAccessToken("SecondAccessToken")
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err, "Expected the rotated token to be used")
+
+	// The last token is kept while the file is rewritten
+	require.NoError(t, os.WriteFile(path, []byte{}, 0600))
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(path))
+	_, err = makeClient()
+	require.Error(t, err, "Expected the client not to be created without token file")
+}
+
+func TestRequestAccessToken(t *testing.T) {
+	renewCalled := false
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "ClientAccessToken", func() (string, error) {
+		renewCalled = true
+		return "RenewedAccessToken", nil
+	})
+
+	expectedToken := "RequestAccessToken"
+	mockConsul.SetExpectedAccessToken(expectedToken)
+	defer mockConsul.ClearExpectedAccessToken()
+
+	ctx := types.WithAccessToken(context.Background(), expectedToken)
+	require.NoError(t, client.RegisterWithContext(ctx))
+	_, err := client.GetServiceEndpointWithContext(ctx, client.serviceKey)
+	require.NoError(t, err)
+	_, err = client.GetAllServiceEndpointsWithContext(ctx)
+	require.NoError(t, err)
+	require.NoError(t, client.UnregisterWithContext(ctx))
+
+	_, err = client.GetAllServiceEndpointsWithContext(types.WithAccessToken(context.Background(), "WrongAccessToken"))
+	require.Error(t, err)
+	assert.False(t, renewCalled, "Expected the token of the client not to be renewed for a request overriding it")
+}
+
+func TestGetAllServiceEndpointsOrder(t *testing.T) {
+	prefix := getUniqueServiceName()
+	for i, name := range []string{prefix + "-c", prefix + "-a", prefix + "-b"} {
+		port := defaultServicePort - i
+		client := makeConsulClient(t, name, port, true, "", nil)
+		require.NoError(t, client.Register())
+		defer func() { _ = client.Unregister() }()
+	}
+
+	client := makeConsulClient(t, prefix, defaultServicePort, true, "", nil)
+	endpoints, err := client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	require.True(t, slices.IsSortedFunc(endpoints, types.ByServiceId), "Expected endpoints sorted by service id")
+
+	client.config.EndpointOrder = types.ByAddress
+	endpoints, err = client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	require.True(t, slices.IsSortedFunc(endpoints, types.ByAddress), "Expected endpoints sorted by address")
+}
+
 func TestRenewAccessToken(t *testing.T) {
 	goodToken := "bfb78dc5-c6a3-33d9-88b5-e3a4b63dda77" // nolint: gosec
 	badToken := "badToken-c6a3-33d9-88b5-e3a4b63dda77"  // nolint: gosec
@@ -321,6 +625,15 @@ func TestRenewAccessToken(t *testing.T) {
 		assert.True(t, renewCalled)
 	})
 
+	t.Run("Decommission", func(t *testing.T) {
+		require.NoError(t, makeConsulClient(t, serviceName, defaultServicePort, true, goodToken, nil).Register())
+		client := createClient()
+
+		err := client.Decommission(context.Background(), serviceName)
+		require.NoError(t, err)
+		assert.True(t, renewCalled)
+	})
+
 	t.Run("UnregisterCheck", func(t *testing.T) {
 		client := createClient()
 
@@ -368,6 +681,199 @@ func TestGetServiceEndpoint(t *testing.T) {
 	require.Equal(t, expectedFoundEndpoint, actualEndpoint, "Test for endpoint found result not as expected")
 }
 
+func TestGetServiceEndpointMetadataAndTags(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.ServiceMetadata = map[string]string{"version": "3.1", "region": "eu"}
+	client.config.ServiceTags = []string{"modbus-tcp"}
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+	}(client)
+
+	require.NoError(t, client.Register())
+
+	endpoint, err := client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, client.config.ServiceMetadata, endpoint.Metadata)
+	assert.Equal(t, client.config.ServiceTags, endpoint.Tags)
+
+	endpoints, err := client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	assert.Contains(t, endpoints, endpoint)
+}
+
+func TestGetServiceEndpointScheme(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.ServiceProtocol = "https"
+	defer func() { _ = client.Unregister() }()
+
+	require.NoError(t, client.Register())
+
+	endpoint, err := client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, "https", endpoint.Scheme)
+	assert.Equal(t, "https", endpoint.Metadata[types.SchemeMetadataKey])
+}
+
+func TestGetServiceEndpoints(t *testing.T) {
+	name := getUniqueServiceName()
+	client := makeConsulClient(t, name, defaultServicePort, true, "", nil)
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+	}(client)
+	require.NoError(t, client.Register())
+
+	replica := &consulapi.AgentServiceRegistration{ID: name + "-2", Name: name, Address: serviceHost, Port: defaultServicePort + 1}
+	require.NoError(t, client.consulClient.Agent().ServiceRegister(replica))
+	defer func() { _ = client.consulClient.Agent().ServiceDeregister(replica.ID) }()
+
+	endpoints, err := client.GetServiceEndpoints(name)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, name, endpoints[0].InstanceId)
+	assert.Equal(t, defaultServicePort, endpoints[0].Port)
+	assert.Equal(t, replica.ID, endpoints[1].InstanceId)
+	assert.Equal(t, defaultServicePort+1, endpoints[1].Port)
+
+	_, err = client.GetServiceEndpoints(getUniqueServiceName())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, types.ErrNotRegistered)
+}
+
+func TestRegisterReplicas(t *testing.T) {
+	name := getUniqueServiceName()
+	replicas := []*consulClient{
+		makeReplicaClient(t, name, defaultServicePort),
+		makeReplicaClient(t, name, defaultServicePort+1),
+	}
+	for _, replica := range replicas {
+		require.NoError(t, replica.Register())
+		defer func(replica *consulClient) { _ = replica.Unregister() }(replica)
+	}
+
+	endpoints, err := replicas[0].GetServiceEndpoints(name)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2, "Expected the second replica not to overwrite the first")
+	assert.Equal(t, name+"-"+serviceHost+":8000", endpoints[0].InstanceId)
+	assert.Equal(t, name+"-"+serviceHost+":8001", endpoints[1].InstanceId)
+
+	endpoint, err := replicas[1].GetServiceEndpoint(name)
+	require.NoError(t, err)
+	assert.Equal(t, name, endpoint.ServiceId, "Expected the replicas to be looked up by service key")
+
+	require.NoError(t, replicas[0].Unregister())
+	endpoints, err = replicas[1].GetServiceEndpoints(name)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, replicas[1].instanceId, endpoints[0].InstanceId)
+}
+
+func TestIsServiceAvailableReplicas(t *testing.T) {
+	name := getUniqueServiceName()
+	replicas := []*consulClient{
+		makeReplicaClient(t, name, defaultServicePort),
+		makeReplicaClient(t, name, defaultServicePort+1),
+	}
+	for _, replica := range replicas {
+		replica.config.CheckType = types.CheckTypeTTL
+		require.NoError(t, replica.Register())
+		defer func(replica *consulClient) { _ = replica.Unregister() }(replica)
+	}
+
+	_, err := replicas[0].IsServiceAvailable(name)
+	require.ErrorIs(t, err, types.ErrUnhealthy, "Expected the TTL checks of both replicas to be critical")
+
+	require.NoError(t, replicas[1].PassTTL(context.Background()))
+	available, err := replicas[0].IsServiceAvailable(name)
+	require.NoError(t, err)
+	assert.True(t, available, "Expected the service to be available with one healthy replica")
+}
+
+func TestGetServiceEndpointsMatching(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.ServiceMetadata = map[string]string{"protocol": "modbus"}
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+	}(client)
+	require.NoError(t, client.Register())
+
+	endpoints, err := client.GetServiceEndpointsMatchingWithContext(context.Background(), types.EndpointSelector{Metadata: map[string]string{"protocol": "modbus"}})
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, client.serviceKey, endpoints[0].ServiceId)
+
+	endpoints, err = client.GetServiceEndpointsMatchingWithContext(context.Background(), types.EndpointSelector{Metadata: map[string]string{"protocol": "onvif"}})
+	require.NoError(t, err)
+	assert.Empty(t, endpoints)
+}
+
+func TestConsulDatacenter(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.ConsulDatacenter = "dc2"
+	client.config.ServiceTags = []string{"gpu"}
+	require.NoError(t, client.Register())
+	defer func() { _ = client.Unregister() }()
+	mockConsul.CatalogDatacenters()
+
+	endpoint, err := client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, client.serviceKey, endpoint.ServiceId)
+	assert.Equal(t, defaultServicePort, endpoint.Port)
+	assert.Equal(t, []string{"dc2"}, mockConsul.CatalogDatacenters(), "Expected the service to be looked up in the catalog of the datacenter")
+
+	endpoints, err := client.GetServiceEndpointsMatchingWithContext(context.Background(), types.EndpointSelector{Tags: []string{"gpu"}})
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1, "Expected the services of the catalog to be filtered")
+	assert.Equal(t, client.serviceKey, endpoints[0].ServiceId)
+
+	endpoints, err = client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	for _, endpoint := range endpoints {
+		assert.NotEqual(t, "consul", endpoint.ServiceId, "Expected the Consul servers not to be discovered")
+	}
+	assert.NotContains(t, mockConsul.CatalogDatacenters(), "")
+}
+
+func TestConsulCatalog(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.ConsulCatalog = true
+	client.config.CheckType = types.CheckTypeTTL
+	require.ErrorIs(t, client.Register(), types.ErrNotSupported, "Expected TTL checks not to be supported without agent")
+	client.config.CheckType = types.CheckTypeHTTP
+	client.config.CheckHeaders = map[string]string{"Authorization": "Bearer secret"}
+	require.ErrorIs(t, client.Register(), types.ErrNotSupported, "Expected the checks run by the client not to be supported without agent")
+	client.config.CheckHeaders = nil
+
+	require.NoError(t, client.Register())
+	defer func() { _ = client.Unregister() }()
+
+	mockConsul.serviceLock.Lock()
+	node := mockConsul.serviceNodes[client.instanceId]
+	mockConsul.serviceLock.Unlock()
+	assert.Equal(t, client.instanceId, node, "Expected the service to be registered on its own node of the catalog")
+
+	endpoint, err := client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, defaultServicePort, endpoint.Port)
+
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.ErrorIs(t, err, types.ErrUnhealthy, "Expected the check to be critical until consul-esm runs it")
+
+	require.NoError(t, client.Unregister())
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.ErrorIs(t, err, types.ErrNotRegistered, "Expected the node to be deregistered along with the service")
+}
+
+func TestSelectorFilter(t *testing.T) {
+	assert.Empty(t, selectorFilter(types.EndpointSelector{}))
+	assert.Equal(t, `"gpu" in Tags and Meta["protocol"] == "modbus" and Meta["region"] == "eu"`, selectorFilter(types.EndpointSelector{
+		Tags:     []string{"gpu"},
+		Metadata: map[string]string{"region": "eu", "protocol": "modbus"},
+	}))
+}
+
 func TestIsServiceAvailableNotRegistered(t *testing.T) {
 
 	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
@@ -466,6 +972,161 @@ func TestIsServiceAvailableHealthy(t *testing.T) {
 	require.True(t, actual, "IsServiceAvailable result not as expected")
 }
 
+func TestTriggerHealthCheck(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+
+	// Setup a server to simulate the service for the health check callback
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !healthy.Load() {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = writer.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	// Figure out which port the simulated service is running on.
+	serverUrl, _ := url.Parse(server.URL)
+	serverPort, _ := strconv.Atoi(serverUrl.Port())
+
+	client := makeConsulClient(t, getUniqueServiceName(), serverPort, true, "", nil)
+
+	// Try to clean-up after test
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+	}(client)
+
+	_, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.Error(t, err, "Expected error health checking service that isn't registered")
+
+	err = client.Register()
+	require.NoError(t, err)
+
+	// Wait for the health check to be registered
+	require.Eventually(t, func() bool {
+		checks, _, err := client.consulClient.Health().Checks(client.serviceKey, nil)
+		return err == nil && len(checks) > 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	result, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, result.Healthy, result.Output)
+
+	healthy.Store(false)
+	result, err = client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.False(t, result.Healthy)
+	require.Contains(t, result.Output, "unexpected status code 503")
+}
+
+func TestRegisterTTLHealthCheck(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.CheckType = types.CheckTypeTTL
+	client.config.DeregisterCriticalAfter = "30m"
+	client.healthCheckRoute = ""
+
+	// Try to clean-up after test
+	defer func(client *consulClient) {
+		_ = client.consulClient.Agent().ServiceDeregister(client.serviceKey)
+		_ = client.consulClient.Agent().CheckDeregister(client.serviceKey)
+	}(client)
+
+	err := client.Register()
+	require.NoError(t, err)
+
+	// Critical until passed
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.ErrorIs(t, err, types.ErrUnhealthy)
+
+	err = client.PassTTL(context.Background())
+	require.NoError(t, err)
+	available, err := client.IsServiceAvailable(client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, available)
+
+	err = client.FailTTL(context.Background(), "database unreachable")
+	require.NoError(t, err)
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.ErrorIs(t, err, types.ErrUnhealthy)
+	checks, _, err := client.consulClient.Health().Checks(client.serviceKey, nil)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	require.Equal(t, "database unreachable", checks[0].Output)
+	require.Equal(t, 30*time.Minute, time.Duration(checks[0].Definition.DeregisterCriticalServiceAfter))
+}
+
+func TestRegisterCheckHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "Bearer secret" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = writer.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	serverPort, _ := strconv.Atoi(serverUrl.Port())
+	client := makeConsulClient(t, getUniqueServiceName(), serverPort, true, "", nil)
+	client.config.CheckHeaders = map[string]string{"Authorization": "Bearer secret"}
+	client.healthCheckInterval = "10ms"
+	defer func() { _ = client.Unregister() }()
+
+	require.NoError(t, client.Register())
+	require.Eventually(t, func() bool {
+		available, _ := client.IsServiceAvailable(client.serviceKey)
+		return available
+	}, time.Second, 10*time.Millisecond, "Expected the client to health check the service with its headers")
+
+	checks, _, err := client.consulClient.Health().Checks(client.serviceKey, nil)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	require.Empty(t, checks[0].Definition.Header, "Expected the token of the check not to be registered")
+	require.Empty(t, checks[0].Definition.HTTP, "Expected Consul not to run the check itself")
+
+	result, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, result.Healthy, result.Output)
+}
+
+func TestRegisterInvalidDeregisterCriticalAfter(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+	client.config.DeregisterCriticalAfter = "bogus"
+
+	err := client.Register()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid deregister critical after")
+}
+
+func TestPassTTLWrongCheckType(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), defaultServicePort, true, "", nil)
+
+	err := client.PassTTL(context.Background())
+	require.Error(t, err, "Expected error passing TTL health check of service registered with http check type")
+}
+
+func receiveEvent(t *testing.T, events <-chan types.RegistrationEvent) types.RegistrationEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Timed out waiting for registration event")
+		return types.RegistrationEvent{}
+	}
+}
+
+func receiveEndpoint(t *testing.T, endpoints <-chan types.ServiceEndpoint) types.ServiceEndpoint {
+	select {
+	case endpoint := <-endpoints:
+		return endpoint
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Timed out waiting for service endpoint")
+		return types.ServiceEndpoint{}
+	}
+}
+
 func makeConsulClient(t *testing.T, serviceName string, servicePort int, setServiceInfo bool, accessToken string, tokenCallback types.GetAccessTokenCallback) *consulClient {
 	registryConfig := types.Config{
 		Host:           testHost,
@@ -490,6 +1151,37 @@ func makeConsulClient(t *testing.T, serviceName string, servicePort int, setServ
 	return client
 }
 
+// makeReplicaClient makes a client registering its own instance of the target service, with a generated instance ID
+func makeReplicaClient(t *testing.T, serviceName string, servicePort int) *consulClient {
+	client := makeConsulClient(t, serviceName, servicePort, true, "", nil)
+	client.config.GenerateInstanceId = true
+	client.instanceId = client.config.GetServiceInstanceId()
+	return client
+}
+
 func getUniqueServiceName() string {
 	return serviceName + strconv.Itoa(time.Now().Nanosecond())
 }
+
+func TestGetServiceEndpointHostileResponses(t *testing.T) {
+	for _, registration := range hostile.Registrations() {
+		t.Run(registration.Name, func(t *testing.T) {
+			server, host, port := hostile.Serve(func(*http.Request) string {
+				return fmt.Sprintf(`{%q:{"ID":%q,"Service":%q,%s}}`, serviceName, serviceName, serviceName, registration.Fields("Address", "Port"))
+			})
+			defer server.Close()
+
+			client, err := NewConsulClient(types.Config{Host: host, Port: port})
+			require.NoError(t, err)
+
+			endpoint, err := client.GetServiceEndpoint(serviceName)
+			if !registration.Valid {
+				require.ErrorIs(t, err, types.ErrMalformedResponse)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, hostile.ValidHost, endpoint.Host)
+			assert.Equal(t, hostile.ValidPort, endpoint.Port)
+		})
+	}
+}
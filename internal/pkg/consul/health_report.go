@@ -0,0 +1,84 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package consul
+
+import (
+	"context"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/health"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/lifecycle"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// clientCheckTTLFactor is the number of check intervals the TTL check of a service checked by the client survives
+// without being reported
+const clientCheckTTLFactor = 3
+
+// clientChecked tells whether the client runs the http health check of the current service itself and reports its
+// status with a TTL check, for the checks sending CheckHeaders. Consul returns the check definitions to every service
+// reading the health of the service, so the headers, which may carry a token, mustn't be registered.
+func (client *consulClient) clientChecked() bool {
+	return client.config.GetCheckType() == types.CheckTypeHTTP && len(client.config.CheckHeaders) > 0
+}
+
+// startReportingHealth starts checking the current service and reporting its status to Consul every check interval in
+// the background, unless Consul runs its health check or it is already being reported
+func (client *consulClient) startReportingHealth() {
+	if !client.clientChecked() {
+		return
+	}
+
+	client.reportLock.Lock()
+	defer client.reportLock.Unlock()
+
+	if client.reporting {
+		return
+	}
+	client.reporting = true
+	go client.reportHealth()
+}
+
+// reportHealth reports the status of the current service right away and then every check interval, until the service
+// is unregistered. Failures to report are retried on the next check.
+func (client *consulClient) reportHealth() {
+	// The check interval has been validated when registering
+	interval, _ := time.ParseDuration(client.healthCheckInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !client.stillReporting() {
+			return
+		}
+
+		ctx := context.Background()
+		status, output := consulapi.HealthPassing, ""
+		if err := health.Probe(ctx, client.config.GetHealthCheckUrl(), client.config.GetCheckOptions(client.serviceKey)); err != nil {
+			status, output = consulapi.HealthCritical, err.Error()
+		}
+		if err := client.writeTTL(ctx, output, status); err != nil {
+			client.config.GetLoggingClient().Debugf("Failed to report the status of the %s service to Consul, retrying on the next check: %v", client.serviceKey, err)
+		}
+
+		<-ticker.C
+	}
+}
+
+// stillReporting tells whether the status of the current service is still to be reported, which stops once it is
+// unregistered
+func (client *consulClient) stillReporting() bool {
+	client.reportLock.Lock()
+	defer client.reportLock.Unlock()
+
+	if client.registration.Desired() != lifecycle.Registered {
+		client.reporting = false
+		return false
+	}
+	return true
+}
@@ -23,8 +23,10 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	consulapi "github.com/hashicorp/consul/api"
 )
@@ -40,6 +42,14 @@ type MockConsul struct {
 	serviceCheckStore   map[string]consulapi.AgentCheck
 	serviceLock         sync.Mutex
 	expectedAccessToken string
+	// index is the Raft index of the services, increased and changed closed each time a service is registered or
+	// deregistered, for the blocking queries of the catalog
+	index   uint64
+	changed chan struct{}
+	// serviceNodes are the nodes of the services registered in the catalog, by service ID
+	serviceNodes map[string]string
+	// datacenters are the datacenters the catalog requests were sent to, the empty one for the agent's
+	datacenters []string
 }
 
 func NewMockConsul() *MockConsul {
@@ -47,6 +57,9 @@ func NewMockConsul() *MockConsul {
 		keyValueStore:     make(map[string]*consulapi.KVPair),
 		serviceStore:      make(map[string]consulapi.AgentService),
 		serviceCheckStore: make(map[string]consulapi.AgentCheck),
+		serviceNodes:      make(map[string]string),
+		index:             1,
+		changed:           make(chan struct{}),
 	}
 
 	return &mock
@@ -83,12 +96,19 @@ func (mock *MockConsul) Start() *httptest.Server {
 				}
 
 				// Copying over basic fields required for current test cases.
-				mockService.ID = mockServiceRegister.Name
+				// Like Consul, the ID defaults to the name
+				mockService.ID = mockServiceRegister.ID
+				if mockService.ID == "" {
+					mockService.ID = mockServiceRegister.Name
+				}
 				mockService.Service = mockServiceRegister.Name
 				mockService.Address = mockServiceRegister.Address
 				mockService.Port = mockServiceRegister.Port
+				mockService.Meta = mockServiceRegister.Meta
+				mockService.Tags = mockServiceRegister.Tags
 
 				mock.serviceStore[mockService.ID] = mockService
+				mock.servicesChanged()
 				writer.Header().Set("Content-Type", "application/json")
 				writer.WriteHeader(http.StatusOK)
 
@@ -118,6 +138,7 @@ func (mock *MockConsul) Start() *httptest.Server {
 				_, ok := mock.serviceStore[key]
 				if ok {
 					delete(mock.serviceStore, key)
+					mock.servicesChanged()
 				}
 
 				_, ok = mock.serviceCheckStore[key]
@@ -128,6 +149,26 @@ func (mock *MockConsul) Start() *httptest.Server {
 				writer.WriteHeader(http.StatusOK)
 
 			}
+		} else if strings.Contains(request.URL.Path, "/v1/agent/service/maintenance/") {
+			key := strings.Replace(request.URL.Path, "/v1/agent/service/maintenance/", "", 1)
+			switch request.Method {
+			case "PUT":
+				mock.serviceLock.Lock()
+				defer mock.serviceLock.Unlock()
+
+				if _, ok := mock.serviceStore[key]; !ok {
+					writer.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				if check, ok := mock.serviceCheckStore[key]; ok && request.URL.Query().Get("enable") == "true" {
+					check.Status = "critical"
+					check.Output = request.URL.Query().Get("reason")
+					mock.serviceCheckStore[key] = check
+				}
+				writer.Header().Set("Content-Type", "application/json")
+				writer.WriteHeader(http.StatusOK)
+			}
 		} else if strings.Contains(request.URL.Path, "/v1/status/leader") {
 			switch request.Method {
 			case "GET":
@@ -162,7 +203,11 @@ func (mock *MockConsul) Start() *httptest.Server {
 							Status:      "TBD",
 							Output:      "TBD",
 							ServiceID:   healthCheck.ServiceID,
-							ServiceName: healthCheck.ServiceID,
+							ServiceName: mock.serviceName(healthCheck.ServiceID),
+							Definition: consulapi.HealthCheckDefinition{
+								HTTP:                           healthCheck.AgentServiceCheck.HTTP,
+								DeregisterCriticalServiceAfter: readableDuration(healthCheck.DeregisterCriticalServiceAfter),
+							},
 						}
 
 						response, err := http.Get(healthCheck.AgentServiceCheck.HTTP)
@@ -189,10 +234,52 @@ func (mock *MockConsul) Start() *httptest.Server {
 					}()
 
 				}
+				// TTL health checks are critical until updated by the service
+				if healthCheck.AgentServiceCheck.TTL != "" {
+					mock.serviceLock.Lock()
+					mock.serviceCheckStore[healthCheck.ID] = consulapi.AgentCheck{
+						Node:        "Mock Consul server",
+						CheckID:     healthCheck.ID,
+						Name:        healthCheck.Name,
+						Status:      consulapi.HealthCritical,
+						ServiceID:   healthCheck.ServiceID,
+						ServiceName: mock.serviceName(healthCheck.ServiceID),
+						Type:        "ttl",
+						Definition: consulapi.HealthCheckDefinition{
+							DeregisterCriticalServiceAfter: readableDuration(healthCheck.DeregisterCriticalServiceAfter),
+						},
+					}
+					mock.serviceLock.Unlock()
+				}
 
 				writer.Header().Set("Content-Type", "application/json")
 				writer.WriteHeader(http.StatusOK)
 			}
+		} else if strings.Contains(request.URL.Path, "/v1/agent/check/update/") {
+			key := strings.Replace(request.URL.Path, "/v1/agent/check/update/", "", 1)
+			switch request.Method {
+			case "PUT":
+				mock.serviceLock.Lock()
+				defer mock.serviceLock.Unlock()
+
+				var update struct {
+					Status string
+					Output string
+				}
+				if err := json.NewDecoder(request.Body).Decode(&update); err != nil {
+					log.Printf("error reading request body: %s", err.Error())
+				}
+
+				check, ok := mock.serviceCheckStore[key]
+				if !ok || check.Type != "ttl" {
+					writer.WriteHeader(http.StatusNotFound)
+					return
+				}
+				check.Status = update.Status
+				check.Output = update.Output
+				mock.serviceCheckStore[key] = check
+				writer.WriteHeader(http.StatusOK)
+			}
 		} else if strings.Contains(request.URL.Path, "/agent/check/deregister/") {
 			key := strings.Replace(request.URL.Path, "/v1/agent/check/deregister/", "", 1)
 			switch request.Method {
@@ -210,17 +297,41 @@ func (mock *MockConsul) Start() *httptest.Server {
 					writer.WriteHeader(http.StatusBadRequest)
 				}
 			}
+		} else if strings.HasSuffix(request.URL.Path, "/v1/catalog/register") {
+			switch request.Method {
+			case "PUT":
+				mock.catalogRegister(writer, request)
+			}
+		} else if strings.HasSuffix(request.URL.Path, "/v1/catalog/deregister") {
+			switch request.Method {
+			case "PUT":
+				mock.catalogDeregister(writer, request)
+			}
+		} else if strings.HasSuffix(request.URL.Path, "/v1/catalog/services") {
+			switch request.Method {
+			case "GET":
+				mock.catalogServices(writer, request)
+			}
+		} else if strings.Contains(request.URL.Path, "/v1/catalog/service/") {
+			switch request.Method {
+			case "GET":
+				mock.catalogService(writer, request, strings.Replace(request.URL.Path, "/v1/catalog/service/", "", 1))
+			}
+		} else if strings.HasPrefix(request.URL.Path, "/v1/kv/") {
+			mock.keyValue(writer, request, strings.TrimPrefix(request.URL.Path, "/v1/kv/"))
 		} else if strings.Contains(request.URL.Path, "/v1/health/checks") {
 			switch request.Method {
 			case "GET":
 				mock.serviceLock.Lock()
 				defer mock.serviceLock.Unlock()
 
+				// The checks of all the instances of the service named after the key
 				agentChecks := make([]consulapi.AgentCheck, 0)
 				key := strings.Replace(request.URL.Path, "/v1/health/checks/", "", 1)
-				check, ok := mock.serviceCheckStore[key]
-				if ok {
-					agentChecks = append(agentChecks, check)
+				for _, check := range mock.serviceCheckStore {
+					if check.ServiceName == key {
+						agentChecks = append(agentChecks, check)
+					}
 				}
 
 				jsonData, _ := json.MarshalIndent(&agentChecks, "", "  ")
@@ -237,6 +348,205 @@ func (mock *MockConsul) Start() *httptest.Server {
 	return testMockServer
 }
 
+// keyValue puts the value of the key in the KV store, or gets the pairs whose key starts with it with the recurse
+// parameter, as the KV store does
+func (mock *MockConsul) keyValue(writer http.ResponseWriter, request *http.Request, key string) {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	switch request.Method {
+	case http.MethodPut:
+		value, err := io.ReadAll(request.Body)
+		if err != nil {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mock.keyValueStore[key] = &consulapi.KVPair{Key: key, Value: value}
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = writer.Write([]byte("true"))
+	case http.MethodGet:
+		pairs := make(consulapi.KVPairs, 0)
+		for _, pair := range mock.keyValueStore {
+			if pair.Key == key || (request.URL.Query().Has("recurse") && strings.HasPrefix(pair.Key, key)) {
+				pairs = append(pairs, pair)
+			}
+		}
+		if len(pairs) == 0 {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(pairs)
+	}
+}
+
+// catalogService writes the instances of the service with the given name as the catalog does. Like Consul, the request
+// blocks until the index is past the index parameter, or the wait time elapsed, when the index parameter is set.
+func (mock *MockConsul) catalogService(writer http.ResponseWriter, request *http.Request, name string) {
+	waitIndex, _ := strconv.ParseUint(request.URL.Query().Get("index"), 10, 64)
+	waitTime, err := time.ParseDuration(request.URL.Query().Get("wait"))
+	if err != nil {
+		waitTime = 5 * time.Minute
+	}
+
+	timeout := time.After(waitTime)
+	mock.serviceLock.Lock()
+	mock.datacenters = append(mock.datacenters, request.URL.Query().Get("dc"))
+	for waiting := waitIndex > 0; waiting && mock.index <= waitIndex; {
+		changed := mock.changed
+		mock.serviceLock.Unlock()
+		select {
+		case <-changed:
+		case <-timeout:
+			waiting = false
+		case <-request.Context().Done():
+			return
+		}
+		mock.serviceLock.Lock()
+	}
+
+	instances := make([]consulapi.CatalogService, 0)
+	for _, service := range mock.serviceStore {
+		if service.Service == name {
+			instances = append(instances, consulapi.CatalogService{
+				Node:           mock.serviceNodes[service.ID],
+				ServiceID:      service.ID,
+				ServiceName:    service.Service,
+				ServiceAddress: service.Address,
+				ServicePort:    service.Port,
+				ServiceMeta:    service.Meta,
+				ServiceTags:    service.Tags,
+			})
+		}
+	}
+	index := mock.index
+	mock.serviceLock.Unlock()
+
+	jsonData, _ := json.MarshalIndent(&instances, "", "  ")
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("X-Consul-Index", strconv.FormatUint(index, 10))
+	writer.WriteHeader(http.StatusOK)
+	if _, err := writer.Write(jsonData); err != nil {
+		log.Printf("error writing data response: %s", err.Error())
+	}
+}
+
+// catalogRegister registers the service and check of the request on its node, as the catalog does
+func (mock *MockConsul) catalogRegister(writer http.ResponseWriter, request *http.Request) {
+	var registration consulapi.CatalogRegistration
+	if err := json.NewDecoder(request.Body).Decode(&registration); err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	if registration.Service != nil {
+		service := *registration.Service
+		if service.ID == "" {
+			service.ID = service.Service
+		}
+		mock.serviceStore[service.ID] = service
+		mock.serviceNodes[service.ID] = registration.Node
+		mock.servicesChanged()
+	}
+	if registration.Check != nil {
+		check := *registration.Check
+		check.Node = registration.Node
+		check.ServiceName = mock.serviceName(check.ServiceID)
+		mock.serviceCheckStore[check.CheckID] = check
+	}
+	writer.WriteHeader(http.StatusOK)
+}
+
+// catalogDeregister removes the check, otherwise the service along with its checks, otherwise the node along with its
+// services of the request, as the catalog does
+func (mock *MockConsul) catalogDeregister(writer http.ResponseWriter, request *http.Request) {
+	var deregistration consulapi.CatalogDeregistration
+	if err := json.NewDecoder(request.Body).Decode(&deregistration); err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	if deregistration.CheckID != "" {
+		delete(mock.serviceCheckStore, deregistration.CheckID)
+		writer.WriteHeader(http.StatusOK)
+		return
+	}
+	for id, node := range mock.serviceNodes {
+		if node == deregistration.Node && (deregistration.ServiceID == "" || deregistration.ServiceID == id) {
+			delete(mock.serviceStore, id)
+			delete(mock.serviceNodes, id)
+			for checkId, check := range mock.serviceCheckStore {
+				if check.ServiceID == id {
+					delete(mock.serviceCheckStore, checkId)
+				}
+			}
+			mock.servicesChanged()
+		}
+	}
+	writer.WriteHeader(http.StatusOK)
+}
+
+// catalogServices writes the names of the services registered along with their tags as the catalog does
+func (mock *MockConsul) catalogServices(writer http.ResponseWriter, request *http.Request) {
+	mock.serviceLock.Lock()
+	mock.datacenters = append(mock.datacenters, request.URL.Query().Get("dc"))
+	services := map[string][]string{"consul": {}}
+	for _, service := range mock.serviceStore {
+		services[service.Service] = append(services[service.Service], service.Tags...)
+	}
+	index := mock.index
+	mock.serviceLock.Unlock()
+
+	jsonData, _ := json.MarshalIndent(&services, "", "  ")
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("X-Consul-Index", strconv.FormatUint(index, 10))
+	writer.WriteHeader(http.StatusOK)
+	if _, err := writer.Write(jsonData); err != nil {
+		log.Printf("error writing data response: %s", err.Error())
+	}
+}
+
+// CatalogDatacenters returns the datacenters the catalog requests were sent to since the last call, the empty one for
+// the datacenter of the agent
+func (mock *MockConsul) CatalogDatacenters() []string {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	datacenters := mock.datacenters
+	mock.datacenters = nil
+	return datacenters
+}
+
+// servicesChanged increases the index of the services and wakes the blocking queries up. The serviceLock must be held.
+func (mock *MockConsul) servicesChanged() {
+	mock.index++
+	close(mock.changed)
+	mock.changed = make(chan struct{})
+}
+
+// readableDuration parses the duration of a check registration, zero if not set
+// serviceName returns the name of the service registered with the given ID, the ID if it isn't registered. The
+// serviceLock must be held.
+func (mock *MockConsul) serviceName(serviceId string) string {
+	if service, ok := mock.serviceStore[serviceId]; ok {
+		return service.Service
+	}
+	return serviceId
+}
+
+func readableDuration(duration string) consulapi.ReadableDuration {
+	parsed, _ := time.ParseDuration(duration)
+	return consulapi.ReadableDuration(parsed)
+}
+
 func (mock *MockConsul) SetExpectedAccessToken(token string) {
 	mock.expectedAccessToken = token
 }
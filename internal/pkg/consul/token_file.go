@@ -0,0 +1,102 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package consul
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// tokenFile is the file holding the ACL token, read again each time it changed, i.e. once the Vault agent rendered the
+// rotated token. The last token read is kept while the file can't be read or is empty, i.e. while being rewritten.
+type tokenFile struct {
+	path string
+	lc   logger.LoggingClient
+
+	lock    sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+	failing bool
+}
+
+// newTokenFile reads the ACL token from the file at path, failing if it can't be read
+func newTokenFile(path string, lc logger.LoggingClient) (*tokenFile, error) {
+	file := &tokenFile{path: path, lc: lc}
+	if err := file.reload(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Token returns the current ACL token, read again from the file if it changed since last read
+func (f *tokenFile) Token() string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if err := f.reload(); err != nil {
+		if !f.failing {
+			f.lc.Warnf("Keeping the last Consul ACL token: %v", err)
+		}
+		f.failing = true
+	} else {
+		f.failing = false
+	}
+	return f.token
+}
+
+// reload reads the token from the file if its modification time or size changed. The lock must be held once created.
+func (f *tokenFile) reload() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("unable to read ACL token file: %w", err)
+	}
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return nil
+	}
+
+	contents, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("unable to read ACL token file: %w", err)
+	}
+	token := string(bytes.TrimSpace(contents))
+	if token == "" {
+		return fmt.Errorf("ACL token file %s is empty", f.path)
+	}
+
+	if f.token != "" && token != f.token {
+		f.lc.Infof("Consul ACL token reloaded from %s", f.path)
+	}
+	f.token = token
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return nil
+}
+
+// tokenFileTransport authenticates the requests sent to Consul with the current token of the token file, but the
+// requests carrying their own access token in their context
+type tokenFileTransport struct {
+	next http.RoundTripper
+	file *tokenFile
+}
+
+func (t *tokenFileTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if _, ok := types.AccessTokenFromContext(request.Context()); ok {
+		return t.next.RoundTrip(request)
+	}
+
+	authenticated := request.Clone(request.Context())
+	authenticated.Header.Set(TokenKey, t.file.Token())
+	return t.next.RoundTrip(authenticated)
+}
@@ -0,0 +1,281 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dnssrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/transport"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/watch"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	defaultDNSPort     = 53
+	defaultDNSProtocol = "tcp"
+)
+
+// dnsClient discovers services from DNS SRV records, i.e. _edgex-core-data._tcp.cluster.local, for environments which
+// expose discovery purely through DNS. The records are maintained by whatever serves the DNS zone, so registering
+// does nothing and a service is available as long as it has SRV records.
+type dnsClient struct {
+	config         *types.Config
+	scheduler      *watch.Scheduler
+	serviceKey     string
+	requestTimeout time.Duration
+	resolver       *net.Resolver
+}
+
+// NewDNSClient creates new DNS SRV Client. The DNS server is the one at Host and Port if set, otherwise the system
+// resolver is used. Service details are optional since registering does nothing.
+func NewDNSClient(registryConfig types.Config) (*dnsClient, error) {
+	requestTimeout, err := registryConfig.GetRequestTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new DNS Client: %w", err)
+	}
+	dialTimeout, err := registryConfig.GetDialTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new DNS Client: %w", err)
+	}
+
+	client := dnsClient{
+		config:         &registryConfig,
+		scheduler:      watch.NewScheduler(registryConfig.WatchConcurrency),
+		serviceKey:     registryConfig.ServiceKey,
+		requestTimeout: requestTimeout,
+		resolver:       net.DefaultResolver,
+	}
+
+	if registryConfig.Host != "" {
+		port := registryConfig.Port
+		if port == 0 {
+			port = defaultDNSPort
+		}
+		server := net.JoinHostPort(registryConfig.Host, strconv.Itoa(port))
+		dialer := net.Dialer{Timeout: dialTimeout}
+		client.resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	return &client, nil
+}
+
+// IsAlive simply checks if the DNS server answers queries
+func (c *dnsClient) IsAlive() bool {
+	return c.IsAliveWithContext(context.Background())
+}
+
+// IsAliveWithContext simply checks if the DNS server answers queries, giving up once ctx is done
+func (c *dnsClient) IsAliveWithContext(ctx context.Context) bool {
+	// A service without records still gets an answer from a live server
+	_, _, err := c.lookup(ctx, c.serviceKey)
+	return err == nil
+}
+
+// Register does nothing, the SRV records are maintained by whatever serves the DNS zone
+func (c *dnsClient) Register() error {
+	return c.RegisterWithContext(context.Background())
+}
+
+// RegisterWithContext does nothing, the SRV records are maintained by whatever serves the DNS zone
+func (c *dnsClient) RegisterWithContext(_ context.Context) error {
+	return nil
+}
+
+// RegisterCheck does nothing, DNS doesn't health check services
+func (c *dnsClient) RegisterCheck(id string, name string, notes string, url string, interval string) error {
+	return c.RegisterCheckWithContext(context.Background(), id, name, notes, url, interval)
+}
+
+// RegisterCheckWithContext does nothing, DNS doesn't health check services
+func (c *dnsClient) RegisterCheckWithContext(_ context.Context, id string, name string, notes string, url string, interval string) error {
+	return nil
+}
+
+// Unregister does nothing, the SRV records are maintained by whatever serves the DNS zone
+func (c *dnsClient) Unregister() error {
+	return c.UnregisterWithContext(context.Background())
+}
+
+// UnregisterWithContext does nothing, the SRV records are maintained by whatever serves the DNS zone
+func (c *dnsClient) UnregisterWithContext(_ context.Context) error {
+	return nil
+}
+
+// Decommission isn't supported, the SRV records are to be removed from the DNS zone by its owner
+func (c *dnsClient) Decommission(_ context.Context, serviceKey string) error {
+	return fmt.Errorf("unable to decommission %s: DNS SRV records can't be removed through the registry client", serviceKey)
+}
+
+// WatchSelf isn't supported since the current service isn't registered by the registry client
+func (c *dnsClient) WatchSelf(_ context.Context) (<-chan types.RegistrationEvent, error) {
+	return nil, fmt.Errorf("unable to watch service registration with DNS: services aren't registered by the registry client")
+}
+
+// WatchService polls DNS for the endpoint of the target service and sends it each time it changes, starting with the
+// current one. An empty endpoint is sent when the service has no SRV records (anymore). Watching stops and the
+// returned channel is closed once ctx is cancelled.
+func (c *dnsClient) WatchService(ctx context.Context, serviceKey string) (<-chan types.ServiceEndpoint, error) {
+	interval, err := c.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Endpoint(ctx, c.scheduler, interval, func() (types.ServiceEndpoint, error) {
+		records, _, err := c.lookup(ctx, serviceKey)
+		if err != nil || len(records) == 0 {
+			return types.ServiceEndpoint{}, err
+		}
+		return endpoint(serviceKey, records[0]), nil
+	}), nil
+}
+
+// TriggerHealthCheck reports the SRV records of the target service. DNS doesn't health check services, the records
+// are expected to only cover healthy instances.
+func (c *dnsClient) TriggerHealthCheck(ctx context.Context, serviceKey string) (types.HealthCheckResult, error) {
+	records, found, err := c.lookup(ctx, serviceKey)
+	if err != nil {
+		return types.HealthCheckResult{}, err
+	}
+	if !found {
+		return types.HealthCheckResult{}, types.Errorf(types.ErrNotRegistered, "unable to health check %s: service is not registered", serviceKey)
+	}
+
+	return types.HealthCheckResult{Healthy: true, Output: fmt.Sprintf("%d SRV records", len(records))}, nil
+}
+
+// GetServiceEndpoint resolves the SRV records of the target service, returning the target and port of the record to
+// use first according to their priority and weight.
+// If this operation is successful and a known endpoint is found, it is returned. Otherwise, an error is returned.
+func (c *dnsClient) GetServiceEndpoint(serviceKey string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointWithContext resolves the SRV records of the target service, aborting once ctx is done
+func (c *dnsClient) GetServiceEndpointWithContext(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	records, found, err := c.lookup(ctx, serviceKey)
+	if err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
+	}
+	if !found {
+		return types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+
+	endpoint := endpoint(serviceKey, records[0])
+	if err := endpoint.Validate(); err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
+	}
+	return endpoint, nil
+}
+
+// GetServiceEndpoints resolves the SRV records of the target service, returning an endpoint per record in the order
+// they are to be used, each identified by its target and port
+func (c *dnsClient) GetServiceEndpoints(serviceKey string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointsWithContext resolves the SRV records of the target service, aborting once ctx is done
+func (c *dnsClient) GetServiceEndpointsWithContext(ctx context.Context, serviceKey string) ([]types.ServiceEndpoint, error) {
+	records, found, err := c.lookup(ctx, serviceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s endpoints: %w", serviceKey, err)
+	}
+	if !found {
+		return nil, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+
+	endpoints := make([]types.ServiceEndpoint, 0, len(records))
+	for _, record := range records {
+		endpoint := endpoint(serviceKey, record)
+		endpoint.InstanceId = net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))
+		if err := endpoint.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to get service %s endpoints: %w", serviceKey, err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+// GetAllServiceEndpoints isn't supported, DNS can't enumerate the services of a domain
+func (c *dnsClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+// GetAllServiceEndpointsWithContext isn't supported, DNS can't enumerate the services of a domain
+func (c *dnsClient) GetAllServiceEndpointsWithContext(_ context.Context) ([]types.ServiceEndpoint, error) {
+	return nil, fmt.Errorf("failed to get all service endpoints: DNS SRV records can't be enumerated")
+}
+
+// IsServiceAvailable checks with DNS if the target service has SRV records
+func (c *dnsClient) IsServiceAvailable(serviceKey string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceKey)
+}
+
+// IsServiceAvailableWithContext checks with DNS if the target service has SRV records, aborting once ctx is done
+func (c *dnsClient) IsServiceAvailableWithContext(ctx context.Context, serviceKey string) (bool, error) {
+	_, found, err := c.lookup(ctx, serviceKey)
+	if err != nil {
+		return false, fmt.Errorf("unable to check if service %s is available: %w", serviceKey, err)
+	}
+	if !found {
+		return false, types.Errorf(types.ErrNotRegistered, "%s service is not registered. Might not have started... ", serviceKey)
+	}
+
+	return true, nil
+}
+
+// lookup resolves the SRV records of the target service, reporting whether it has any
+func (c *dnsClient) lookup(ctx context.Context, serviceKey string) ([]*net.SRV, bool, error) {
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	_, records, err := c.resolver.LookupSRV(ctx, "", "", c.recordName(serviceKey))
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, transport.Unavailable(err)
+	}
+
+	return records, len(records) > 0, nil
+}
+
+// recordName returns the name of the SRV records of the target service, i.e. _edgex-core-data._tcp.cluster.local.
+// The name is fully qualified when the domain is set, so the search domains of the resolver don't apply.
+func (c *dnsClient) recordName(serviceKey string) string {
+	protocol := c.config.DNSProtocol
+	if protocol == "" {
+		protocol = defaultDNSProtocol
+	}
+
+	name := "_" + c.config.DNSServicePrefix + serviceKey + "._" + protocol
+	if c.config.DNSDomain != "" {
+		name += "." + strings.TrimSuffix(c.config.DNSDomain, ".") + "."
+	}
+	return name
+}
+
+func endpoint(serviceKey string, record *net.SRV) types.ServiceEndpoint {
+	return types.ServiceEndpoint{
+		ServiceId: serviceKey,
+		Host:      strings.TrimSuffix(record.Target, "."),
+		Port:      int(record.Port),
+	}
+}
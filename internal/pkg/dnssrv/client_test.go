@@ -0,0 +1,210 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dnssrv
+
+import (
+	"context"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/hostile"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	testDomain        = "cluster.local"
+	testServicePrefix = "edgex-"
+)
+
+var (
+	testRegistryHost string
+	testRegistryPort int
+	mockDNS          *MockDNS
+)
+
+func TestMain(m *testing.M) {
+	mockDNS = NewMockDNS()
+	conn, err := mockDNS.Start()
+	if err != nil {
+		panic(err)
+	}
+
+	host, port, _ := net.SplitHostPort(conn.LocalAddr().String())
+	testRegistryHost = host
+	testRegistryPort, _ = strconv.Atoi(port)
+
+	exitCode := m.Run()
+	_ = conn.Close()
+	os.Exit(exitCode)
+}
+
+func TestIsAlive(t *testing.T) {
+	client := makeDNSClient(t)
+	require.True(t, client.IsAlive(), "DNS server not running")
+
+	client, err := NewDNSClient(types.Config{Host: testRegistryHost, Port: getClosedPort(t), RequestTimeout: "200ms"})
+	require.NoError(t, err)
+	require.False(t, client.IsAlive(), "Expected DNS server on closed port not to be alive")
+}
+
+func TestGetServiceEndpoint(t *testing.T) {
+	mockDNS.SetRecords("_edgex-core-data._tcp.cluster.local",
+		net.SRV{Target: "core-data-1.edgex.cluster.local.", Port: 59880, Priority: 10, Weight: 1},
+		net.SRV{Target: "core-data-0.edgex.cluster.local.", Port: 59880, Priority: 0, Weight: 1})
+	defer mockDNS.SetRecords("_edgex-core-data._tcp.cluster.local")
+	client := makeDNSClient(t)
+
+	endpoint, err := client.GetServiceEndpoint("core-data")
+	require.NoError(t, err)
+	assert.Equal(t, types.ServiceEndpoint{ServiceId: "core-data", Host: "core-data-0.edgex.cluster.local", Port: 59880}, endpoint,
+		"Expected the record with the lowest priority")
+
+	available, err := client.IsServiceAvailable("core-data")
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	result, err := client.TriggerHealthCheck(context.Background(), "core-data")
+	require.NoError(t, err)
+	assert.Equal(t, types.HealthCheckResult{Healthy: true, Output: "2 SRV records"}, result)
+}
+
+func TestGetServiceEndpoints(t *testing.T) {
+	mockDNS.SetRecords("_edgex-core-data._tcp.cluster.local",
+		net.SRV{Target: "core-data-0.edgex.cluster.local.", Port: 59880, Priority: 0, Weight: 1},
+		net.SRV{Target: "core-data-1.edgex.cluster.local.", Port: 59880, Priority: 10, Weight: 1})
+	defer mockDNS.SetRecords("_edgex-core-data._tcp.cluster.local")
+	client := makeDNSClient(t)
+
+	endpoints, err := client.GetServiceEndpoints("core-data")
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{
+		{ServiceId: "core-data", InstanceId: "core-data-0.edgex.cluster.local:59880", Host: "core-data-0.edgex.cluster.local", Port: 59880},
+		{ServiceId: "core-data", InstanceId: "core-data-1.edgex.cluster.local:59880", Host: "core-data-1.edgex.cluster.local", Port: 59880},
+	}, endpoints)
+
+	_, err = client.GetServiceEndpoints("core-command")
+	require.EqualError(t, err, "no matching service endpoint found")
+}
+
+func TestGetServiceEndpointHostileRecords(t *testing.T) {
+	for _, registration := range hostile.Registrations() {
+		t.Run(registration.Name, func(t *testing.T) {
+			host, port, ok := registration.Address()
+			if !ok || port < 0 || port > math.MaxUint16 || len(host) > types.MaxHostLength {
+				t.Skip("not representable as SRV record")
+			}
+			mockDNS.SetRecords("_edgex-core-data._tcp.cluster.local", net.SRV{Target: host + ".", Port: uint16(port)})
+			defer mockDNS.SetRecords("_edgex-core-data._tcp.cluster.local")
+			client := makeDNSClient(t)
+
+			endpoint, err := client.GetServiceEndpoint("core-data")
+			endpoints, endpointsErr := client.GetServiceEndpoints("core-data")
+			if !registration.Valid {
+				require.ErrorIs(t, err, types.ErrMalformedResponse)
+				require.ErrorIs(t, endpointsErr, types.ErrMalformedResponse)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, hostile.ValidHost, endpoint.Host)
+			assert.Equal(t, hostile.ValidPort, endpoint.Port)
+			require.NoError(t, endpointsErr)
+			require.Len(t, endpoints, 1)
+			assert.Equal(t, hostile.ValidPort, endpoints[0].Port)
+		})
+	}
+}
+
+func TestServiceNotFound(t *testing.T) {
+	client := makeDNSClient(t)
+
+	_, err := client.GetServiceEndpoint("core-command")
+	require.EqualError(t, err, "no matching service endpoint found")
+
+	_, err = client.IsServiceAvailable("core-command")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service is not registered")
+
+	_, err = client.TriggerHealthCheck(context.Background(), "core-command")
+	require.Error(t, err)
+}
+
+func TestRecordName(t *testing.T) {
+	client := makeDNSClient(t)
+	assert.Equal(t, "_edgex-core-data._tcp.cluster.local.", client.recordName("core-data"))
+
+	client.config.DNSProtocol = "udp"
+	client.config.DNSDomain = ""
+	client.config.DNSServicePrefix = ""
+	assert.Equal(t, "_core-data._udp", client.recordName("core-data"))
+}
+
+func TestRegistrationNoOp(t *testing.T) {
+	client := makeDNSClient(t)
+	require.NoError(t, client.Register())
+	require.NoError(t, client.RegisterCheck("id", "name", "notes", "http://localhost/ping", "10s"))
+	require.NoError(t, client.Unregister())
+
+	require.Error(t, client.Decommission(context.Background(), "core-data"))
+	_, err := client.WatchSelf(context.Background())
+	require.Error(t, err)
+	_, err = client.GetAllServiceEndpoints()
+	require.Error(t, err)
+}
+
+func TestWatchService(t *testing.T) {
+	client := makeDNSClient(t)
+	client.config.WatchInterval = "10ms"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	endpoints, err := client.WatchService(ctx, "support-notifications")
+	require.NoError(t, err)
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint before records exist")
+
+	mockDNS.SetRecords("_edgex-support-notifications._tcp.cluster.local", net.SRV{Target: "notifications.edgex.", Port: 59860})
+	require.Equal(t, types.ServiceEndpoint{ServiceId: "support-notifications", Host: "notifications.edgex", Port: 59860}, receiveEndpoint(t, endpoints))
+
+	mockDNS.SetRecords("_edgex-support-notifications._tcp.cluster.local")
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint once records are removed")
+}
+
+func receiveEndpoint(t *testing.T, endpoints <-chan types.ServiceEndpoint) types.ServiceEndpoint {
+	select {
+	case endpoint := <-endpoints:
+		return endpoint
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for service endpoint")
+		return types.ServiceEndpoint{}
+	}
+}
+
+func getClosedPort(t *testing.T) int {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	require.NoError(t, conn.Close())
+	return port
+}
+
+func makeDNSClient(t *testing.T) *dnsClient {
+	client, err := NewDNSClient(types.Config{
+		Host:             testRegistryHost,
+		Port:             testRegistryPort,
+		Type:             "dns",
+		DNSDomain:        testDomain,
+		DNSServicePrefix: testServicePrefix,
+	})
+	require.NoError(t, err)
+
+	return client
+}
@@ -0,0 +1,112 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dnssrv
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// MockDNS emulates a DNS server answering SRV queries over UDP
+type MockDNS struct {
+	records map[string][]net.SRV
+	lock    sync.Mutex
+}
+
+func NewMockDNS() *MockDNS {
+	return &MockDNS{records: make(map[string][]net.SRV)}
+}
+
+// SetRecords replaces the SRV records of the fully qualified name, i.e. _edgex-core-data._tcp.cluster.local. The
+// name isn't found anymore once its records are cleared.
+func (mock *MockDNS) SetRecords(name string, records ...net.SRV) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
+	name = strings.ToLower(strings.TrimSuffix(name, ".") + ".")
+	if len(records) == 0 {
+		delete(mock.records, name)
+		return
+	}
+	mock.records[name] = records
+}
+
+// Start listens on a random local UDP port, returning the connection to close once done
+func (mock *MockDNS) Start() (net.PacketConn, error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		buffer := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			response, err := mock.answer(buffer[:n])
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(response, addr)
+		}
+	}()
+
+	return conn, nil
+}
+
+func (mock *MockDNS) answer(query []byte) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil, err
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	mock.lock.Lock()
+	records, found := mock.records[strings.ToLower(question.Name.String())]
+	mock.lock.Unlock()
+
+	responseHeader := dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true, RecursionDesired: header.RecursionDesired}
+	if !found {
+		responseHeader.RCode = dnsmessage.RCodeNameError
+	}
+
+	builder := dnsmessage.NewBuilder(nil, responseHeader)
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(question); err != nil {
+		return nil, err
+	}
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+	if question.Type == dnsmessage.TypeSRV {
+		for _, record := range records {
+			target, err := dnsmessage.NewName(strings.TrimSuffix(record.Target, ".") + ".")
+			if err != nil {
+				return nil, err
+			}
+			err = builder.SRVResource(
+				dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 30},
+				dnsmessage.SRVResource{Priority: record.Priority, Weight: record.Weight, Port: record.Port, Target: target})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return builder.Finish()
+}
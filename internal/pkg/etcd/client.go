@@ -0,0 +1,701 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/health"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/lifecycle"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/transport"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/watch"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	// servicesPrefix is the prefix of the keys holding the registrations of the services, each instance of a service
+	// being registered under servicesPrefix + serviceKey + "/" + instanceId
+	servicesPrefix = "/edgex/registry/services/"
+	// tombstonesPrefix is the prefix of the keys holding the tombstones of the decommissioned services, by service key
+	tombstonesPrefix = "/edgex/registry/tombstones/"
+	// defaultKeepAliveInterval is the interval at which the lease of a service registered without health check
+	// interval is kept alive
+	defaultKeepAliveInterval = 10 * time.Second
+	// leaseTTLFactor is the number of keep alive intervals a lease survives without being kept alive
+	leaseTTLFactor = 3
+)
+
+// registration is the value stored in etcd for each registered service
+type registration struct {
+	ServiceId     string `json:"serviceId"`
+	InstanceId    string `json:"instanceId,omitempty"`
+	Host          string `json:"host"`
+	Port          int    `json:"port"`
+	CheckType     string `json:"checkType"`
+	CheckRoute    string `json:"checkRoute,omitempty"`
+	CheckInterval string `json:"checkInterval,omitempty"`
+	// CheckOptions are the HTTP health check options, if not the default ones, without the headers as the registrations
+	// are readable by every service and the headers may carry a token
+	CheckOptions *types.HealthCheckOptions `json:"checkOptions,omitempty"`
+	Metadata     map[string]string         `json:"metadata,omitempty"`
+	Tags         []string                  `json:"tags,omitempty"`
+}
+
+// etcdClient implements the registry on top of etcd. Each instance of a service is registered as a key of its own
+// attached to a lease the client keeps alive as long as the health check of the instance passes, so etcd removes the
+// registration of an instance which stopped or became unhealthy once its lease expires. Registered instances are
+// therefore always available.
+type etcdClient struct {
+	config              *types.Config
+	scheduler           *watch.Scheduler
+	etcdUrl             string
+	serviceKey          string
+	instanceId          string
+	serviceHost         string
+	servicePort         int
+	healthCheckRoute    string
+	healthCheckInterval string
+
+	restClient        *restClient
+	registration      lifecycle.Registration
+	keepAliveInterval time.Duration
+	leaseLock         sync.Mutex
+	leaseId           int64
+	keepingAlive      bool
+	// serverClock is the time of etcd, estimated from its responses
+	serverClock *transport.ServerClock
+}
+
+// NewEtcdClient creates new etcd Client. Service details are optional, not needed just for configuration, but required if registering
+func NewEtcdClient(registryConfig types.Config) (*etcdClient, error) {
+	client := etcdClient{
+		config:            &registryConfig,
+		scheduler:         watch.NewScheduler(registryConfig.WatchConcurrency),
+		serviceKey:        registryConfig.ServiceKey,
+		instanceId:        registryConfig.GetServiceInstanceId(),
+		etcdUrl:           registryConfig.GetRegistryUrl(),
+		keepAliveInterval: defaultKeepAliveInterval,
+	}
+
+	// ServiceHost will be empty when client isn't registering the service
+	if registryConfig.ServiceHost != "" {
+		client.servicePort = registryConfig.ServicePort
+		client.serviceHost = registryConfig.ServiceHost
+		client.healthCheckRoute = registryConfig.CheckRoute
+		client.healthCheckInterval = registryConfig.CheckInterval
+	}
+
+	if client.healthCheckInterval != "" {
+		interval, err := time.ParseDuration(client.healthCheckInterval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("unable to create new etcd Client for %s: invalid check interval '%s'", client.etcdUrl, client.healthCheckInterval)
+		}
+		client.keepAliveInterval = interval
+	}
+
+	httpClient, err := transport.NewClient(registryConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new etcd Client for %s: %w", client.etcdUrl, err)
+	}
+	client.serverClock = transport.NewServerClock()
+	httpClient.Transport = client.serverClock.Wrap(httpClient.Transport)
+	client.restClient = newRestClient(client.etcdUrl, httpClient, registryConfig.AccessToken, registryConfig.GetAccessToken)
+
+	return &client, nil
+}
+
+// ServerClock returns the time of etcd, estimated from the Date of its responses
+func (c *etcdClient) ServerClock() types.Clock {
+	return c.serverClock
+}
+
+// IsAlive simply checks if etcd is up and running at the configured URL
+func (c *etcdClient) IsAlive() bool {
+	return c.IsAliveWithContext(context.Background())
+}
+
+// IsAliveWithContext simply checks if etcd is up and running at the configured URL, giving up once ctx is done
+func (c *etcdClient) IsAliveWithContext(ctx context.Context) bool {
+	return c.restClient.Health(ctx) == nil
+}
+
+// Register registers the current service with etcd for discovery and health check
+func (c *etcdClient) Register() error {
+	return c.RegisterWithContext(context.Background())
+}
+
+// RegisterWithContext registers the current service with etcd for discovery and health check, aborting once ctx is
+// done. The lease of the registration is then kept alive in the background until the service is unregistered.
+func (c *etcdClient) RegisterWithContext(ctx context.Context) error {
+	err := c.registration.Register(func() error {
+		return c.register(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.startKeepingAlive()
+	return nil
+}
+
+func (c *etcdClient) register(ctx context.Context) error {
+	checkType := c.config.GetCheckType()
+	if c.serviceKey == "" || c.serviceHost == "" || c.servicePort == 0 ||
+		(checkType == types.CheckTypeHTTP && (c.healthCheckRoute == "" || c.healthCheckInterval == "")) {
+		return fmt.Errorf("unable to register service with etcd: Service information not set")
+	}
+
+	// There is no TTL for the service to report its health with, its lease being kept alive by the client
+	if checkType == types.CheckTypeTTL {
+		return types.Errorf(types.ErrNotSupported, "unable to register service with etcd: ttl health checks aren't supported")
+	}
+
+	options := c.config.GetCheckOptions(c.serviceKey)
+	if checkType == types.CheckTypeHTTP {
+		if err := options.Validate(); err != nil {
+			return fmt.Errorf("unable to register service with etcd: %w", err)
+		}
+	}
+
+	if c.config.GetProbeBeforeRegister() && checkType == types.CheckTypeHTTP {
+		if err := health.Probe(ctx, c.config.GetHealthCheckUrl(), options); err != nil {
+			return fmt.Errorf("unable to register service with etcd: %w", err)
+		}
+	}
+
+	r := registration{
+		ServiceId:     c.serviceKey,
+		InstanceId:    c.instanceId,
+		Host:          c.serviceHost,
+		Port:          c.servicePort,
+		CheckType:     checkType,
+		CheckRoute:    c.healthCheckRoute,
+		CheckInterval: c.healthCheckInterval,
+		Metadata:      c.config.GetServiceMetadata(),
+		Tags:          c.config.ServiceTags,
+	}
+	registered := options
+	registered.Headers = nil
+	if checkType == types.CheckTypeHTTP && !registered.IsZero() {
+		r.CheckOptions = &registered
+	}
+	value, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode the %s service registration: %w", c.serviceKey, err)
+	}
+
+	ttl := int64(math.Ceil(leaseTTLFactor * c.keepAliveInterval.Seconds()))
+	leaseId, err := c.restClient.GrantLease(ctx, ttl)
+	if err != nil {
+		return fmt.Errorf("failed to grant the lease of the %s service: %w", c.serviceKey, err)
+	}
+
+	if err := c.restClient.Put(ctx, instanceKeyPath(c.serviceKey, c.instanceId), value, leaseId); err != nil {
+		return fmt.Errorf("failed to register the %s service: %w", c.serviceKey, err)
+	}
+
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+
+	// The registration now lives with the new lease, so the previous one, if any, is left to expire
+	c.leaseId = leaseId
+
+	return nil
+}
+
+// startKeepingAlive starts keeping the lease of the current service alive in the background, unless it is already
+// being kept alive
+func (c *etcdClient) startKeepingAlive() {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+
+	if c.keepingAlive {
+		return
+	}
+	c.keepingAlive = true
+	go c.keepAlive()
+}
+
+// keepAlive keeps the lease of the current service alive every keepAliveInterval while its health check passes, until
+// the service is unregistered. The registration is restored when its lease expired, i.e. after the service has been
+// unhealthy for a while or etcd lost it.
+func (c *etcdClient) keepAlive() {
+	ticker := time.NewTicker(c.keepAliveInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.leaseLock.Lock()
+		if c.registration.Desired() != lifecycle.Registered {
+			c.keepingAlive = false
+			c.leaseLock.Unlock()
+			return
+		}
+		leaseId := c.leaseId
+		c.leaseLock.Unlock()
+
+		ctx := context.Background()
+		if err := c.check(ctx); err != nil {
+			// Let the lease expire so the unhealthy service is no longer discovered
+			continue
+		}
+
+		ttl, err := c.restClient.KeepAlive(ctx, leaseId)
+		if err != nil || ttl > 0 {
+			continue
+		}
+
+		// Restore doesn't register again if the service has been unregistered in the meantime
+		_, _ = c.registration.Restore(func() error {
+			return c.register(ctx)
+		})
+	}
+}
+
+// check runs the health check of the current service once, passing for the none check type
+func (c *etcdClient) check(ctx context.Context) error {
+	switch checkType := c.config.GetCheckType(); checkType {
+	case types.CheckTypeNone:
+		return nil
+	case types.CheckTypeHTTP:
+		return health.Probe(ctx, c.config.GetHealthCheckUrl(), c.config.GetCheckOptions(c.config.ServiceKey))
+	default:
+		address := net.JoinHostPort(c.serviceHost, strconv.Itoa(c.servicePort))
+		return health.ProbeService(ctx, checkType, address, c.healthCheckRoute, types.HealthCheckOptions{})
+	}
+}
+
+// RegisterCheck registers a health check with etcd
+func (c *etcdClient) RegisterCheck(id string, name string, notes string, url string, interval string) error {
+	return c.RegisterCheckWithContext(context.Background(), id, name, notes, url, interval)
+}
+
+// RegisterCheckWithContext registers a health check with etcd
+func (c *etcdClient) RegisterCheckWithContext(_ context.Context, id string, name string, notes string, url string, interval string) error {
+	// the health of the service is reflected by the lease of its registration, which covers the health check
+	return nil
+}
+
+// Unregister de-registers the current service from etcd
+func (c *etcdClient) Unregister() error {
+	return c.UnregisterWithContext(context.Background())
+}
+
+// UnregisterWithContext de-registers the current service from etcd, aborting once ctx is done
+func (c *etcdClient) UnregisterWithContext(ctx context.Context) error {
+	return c.registration.Unregister(func() error {
+		return c.unregister(ctx)
+	})
+}
+
+func (c *etcdClient) unregister(ctx context.Context) error {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+
+	// Without lease, the current instance isn't registered by the client, so the registration, if any, belongs to
+	// another process registering the same instance ID
+	if c.leaseId == 0 {
+		return nil
+	}
+
+	// Revoking the lease deletes the registration attached to it. When revoking fails, the registration is deleted
+	// only if still attached to the lease, so the registration of another process registered in the meantime, i.e.
+	// after the lease expired, is kept.
+	if err := c.restClient.RevokeLease(ctx, c.leaseId); err != nil {
+		if _, err := c.restClient.DeleteIfLease(ctx, instanceKeyPath(c.serviceKey, c.instanceId), c.leaseId); err != nil {
+			return fmt.Errorf("failed to de-register %s: %w", c.serviceKey, err)
+		}
+	}
+	c.leaseId = 0
+
+	return nil
+}
+
+// Decommission permanently retires the target service from etcd by deleting the registrations of all its instances
+func (c *etcdClient) Decommission(ctx context.Context, serviceKey string) error {
+	// Decommissioning the current service must not be undone by restoring its registration
+	if serviceKey == c.serviceKey {
+		return c.registration.Unregister(func() error {
+			return c.decommission(ctx, serviceKey)
+		})
+	}
+
+	return c.decommission(ctx, serviceKey)
+}
+
+func (c *etcdClient) decommission(ctx context.Context, serviceKey string) error {
+	instances, err := c.getInstances(ctx, serviceKey)
+	if err != nil {
+		return fmt.Errorf("failed to delete the %s service registration: %w", serviceKey, err)
+	}
+
+	deleted := false
+	for _, instance := range instances {
+		instanceDeleted, err := c.restClient.Delete(ctx, instance.key)
+		if err != nil {
+			return fmt.Errorf("failed to delete the %s service registration: %w", serviceKey, err)
+		}
+		deleted = deleted || instanceDeleted
+	}
+	if !deleted {
+		return types.Errorf(types.ErrNotRegistered, "unable to decommission %s: service is not registered", serviceKey)
+	}
+
+	return nil
+}
+
+// PromoteStandby makes the standby instance of the target service active and its active instances standby, in a single
+// transaction failing if any of their registrations changed meanwhile, i.e. as promoted by someone else. The roles
+// last until the instances register again, as they register with the role of their ServiceMetadata.
+func (c *etcdClient) PromoteStandby(ctx context.Context, serviceKey string, instanceId string) error {
+	instances, err := c.getInstances(ctx, serviceKey)
+	if err != nil {
+		return fmt.Errorf("unable to promote the standby instance %s of %s: %w", instanceId, serviceKey, err)
+	}
+
+	var kvs, values []keyValue
+	promoted := false
+	for _, instance := range instances {
+		r := instance.registration
+		role := ""
+		switch {
+		case r.InstanceId == instanceId:
+			if !r.endpoint().IsStandby() {
+				return fmt.Errorf("unable to promote the instance %s of %s: it isn't a standby instance", instanceId, serviceKey)
+			}
+			role, promoted = types.RoleActive, true
+		case !r.endpoint().IsStandby():
+			role = types.RoleStandby
+		default:
+			continue
+		}
+
+		r.Metadata = maps.Clone(r.Metadata)
+		if r.Metadata == nil {
+			r.Metadata = make(map[string]string, 1)
+		}
+		r.Metadata[types.RoleMetadataKey] = role
+		value, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("unable to promote the standby instance %s of %s: %w", instanceId, serviceKey, err)
+		}
+		kvs = append(kvs, instance.kv)
+		values = append(values, keyValue{Key: instance.kv.Key, Value: value, Lease: instance.kv.Lease})
+	}
+	if !promoted {
+		return types.Errorf(types.ErrNotRegistered, "unable to promote the standby instance %s of %s: instance is not registered", instanceId, serviceKey)
+	}
+
+	updated, err := c.restClient.PutIfUnmodified(ctx, kvs, values)
+	if err != nil {
+		return fmt.Errorf("unable to promote the standby instance %s of %s: %w", instanceId, serviceKey, err)
+	}
+	if !updated {
+		return fmt.Errorf("unable to promote the standby instance %s of %s: the instances were updated meanwhile", instanceId, serviceKey)
+	}
+
+	return nil
+}
+
+// PutTombstone keeps the tombstone of the decommissioned service in etcd, without lease so it outlives the clients
+func (c *etcdClient) PutTombstone(ctx context.Context, tombstone types.Tombstone) error {
+	value, err := json.Marshal(tombstone)
+	if err != nil {
+		return fmt.Errorf("failed to encode the %s service tombstone: %w", tombstone.ServiceKey, err)
+	}
+	if err := c.restClient.Put(ctx, tombstonesPrefix+tombstone.ServiceKey, value, 0); err != nil {
+		return fmt.Errorf("failed to put the %s service tombstone: %w", tombstone.ServiceKey, err)
+	}
+
+	return nil
+}
+
+// GetTombstones retrieves the tombstones of all the decommissioned services from etcd
+func (c *etcdClient) GetTombstones(ctx context.Context) ([]types.Tombstone, error) {
+	kvs, err := c.restClient.GetPrefix(ctx, tombstonesPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the service tombstones: %w", err)
+	}
+
+	tombstones := make([]types.Tombstone, 0, len(kvs))
+	for _, kv := range kvs {
+		var tombstone types.Tombstone
+		if err := json.Unmarshal(kv.Value, &tombstone); err != nil {
+			return nil, fmt.Errorf("failed to decode the %s service tombstone: %w", kv.Key, transport.Malformed(err))
+		}
+		tombstones = append(tombstones, tombstone)
+	}
+
+	return tombstones, nil
+}
+
+// WatchSelf polls etcd for the registration of the current instance and notifies the caller each time it has been
+// modified or deleted by someone else. Watching stops and the returned channel is closed once ctx is cancelled.
+func (c *etcdClient) WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error) {
+	if c.serviceKey == "" || c.serviceHost == "" || c.servicePort == 0 {
+		return nil, fmt.Errorf("unable to watch service registration with etcd: Service information not set")
+	}
+
+	interval, err := c.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	expected := types.ServiceEndpoint{
+		ServiceId:  c.serviceKey,
+		InstanceId: c.instanceId,
+		Host:       c.serviceHost,
+		Port:       c.servicePort,
+		Scheme:     c.config.GetServiceMetadata()[types.SchemeMetadataKey],
+		Metadata:   c.config.GetServiceMetadata(),
+		Tags:       c.config.ServiceTags,
+	}
+
+	return watch.Self(ctx, c.scheduler, interval, expected, func() (types.ServiceEndpoint, error) {
+		value, found, err := c.restClient.Get(ctx, instanceKeyPath(c.serviceKey, c.instanceId))
+		if err != nil || !found {
+			return types.ServiceEndpoint{}, err
+		}
+		var r registration
+		if err := json.Unmarshal(value, &r); err != nil {
+			return types.ServiceEndpoint{}, fmt.Errorf("failed to decode %s service registration: %w", c.serviceKey, transport.Malformed(err))
+		}
+		return r.endpoint(), nil
+	}), nil
+}
+
+// WatchService polls etcd for the endpoint of the target service, the first of its instances in the endpoint order,
+// and sends it each time it changes, starting with
+// the current one. An empty endpoint is sent when the service isn't registered (anymore). Watching stops and the
+// returned channel is closed once ctx is cancelled.
+func (c *etcdClient) WatchService(ctx context.Context, serviceKey string) (<-chan types.ServiceEndpoint, error) {
+	interval, err := c.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Endpoint(ctx, c.scheduler, interval, func() (types.ServiceEndpoint, error) {
+		return c.registeredEndpoint(ctx, serviceKey)
+	}), nil
+}
+
+// registeredEndpoint retrieves the endpoint of the target service from etcd, which is empty when the service isn't
+// registered
+func (c *etcdClient) registeredEndpoint(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	registration, found, err := c.getRegistration(ctx, serviceKey)
+	if err != nil || !found {
+		return types.ServiceEndpoint{}, err
+	}
+
+	return registration.endpoint(), nil
+}
+
+// TriggerHealthCheck runs the health check of the target service right away. etcd doesn't health check services
+// itself, so the client runs the registered health check.
+func (c *etcdClient) TriggerHealthCheck(ctx context.Context, serviceKey string) (types.HealthCheckResult, error) {
+	registration, found, err := c.getRegistration(ctx, serviceKey)
+	if err != nil {
+		return types.HealthCheckResult{}, err
+	}
+	if !found {
+		return types.HealthCheckResult{}, types.Errorf(types.ErrNotRegistered, "unable to health check %s: service is not registered", serviceKey)
+	}
+
+	if strings.EqualFold(registration.CheckType, types.CheckTypeNone) {
+		return types.HealthCheckResult{Healthy: true, Output: "service registered without health check"}, nil
+	}
+
+	// Only the current service knows its headers, which aren't registered
+	options := c.config.GetCheckOptions(serviceKey)
+	if registration.CheckOptions != nil && serviceKey != c.serviceKey {
+		options = *registration.CheckOptions
+	}
+	address := net.JoinHostPort(registration.Host, strconv.Itoa(registration.Port))
+	return health.CheckService(ctx, registration.CheckType, address, registration.CheckRoute, options), nil
+}
+
+// GetServiceEndpoint retrieves the port, service ID and host of a known endpoint from etcd.
+// If this operation is successful and a known endpoint is found, it is returned. Otherwise, an error is returned.
+func (c *etcdClient) GetServiceEndpoint(serviceKey string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointWithContext retrieves the port, service ID and host of a known endpoint from etcd, aborting once
+// ctx is done
+func (c *etcdClient) GetServiceEndpointWithContext(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	registration, found, err := c.getRegistration(ctx, serviceKey)
+	if err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
+	}
+	if !found {
+		return types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+
+	endpoint := registration.endpoint()
+	if err := endpoint.Validate(); err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
+	}
+	return endpoint, nil
+}
+
+// GetServiceEndpoints retrieves the endpoints of all the instances of the target service from etcd
+func (c *etcdClient) GetServiceEndpoints(serviceKey string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointsWithContext retrieves the endpoints of all the instances of the target service from etcd,
+// aborting once ctx is done
+func (c *etcdClient) GetServiceEndpointsWithContext(ctx context.Context, serviceKey string) ([]types.ServiceEndpoint, error) {
+	instances, err := c.getInstances(ctx, serviceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s endpoints: %w", serviceKey, err)
+	}
+	if len(instances) == 0 {
+		return nil, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+
+	endpoints := make([]types.ServiceEndpoint, 0, len(instances))
+	for _, instance := range instances {
+		endpoint := instance.registration.endpoint()
+		if err := endpoint.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to get service %s endpoints: %w", serviceKey, err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	types.SortServiceEndpoints(endpoints, c.config.GetEndpointOrder())
+
+	return endpoints, nil
+}
+
+// GetAllServiceEndpoints retrieves all registered endpoints from etcd.
+func (c *etcdClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+// GetAllServiceEndpointsWithContext retrieves all registered endpoints from etcd, aborting once ctx is done
+func (c *etcdClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	kvs, err := c.restClient.GetPrefix(ctx, servicesPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all service endpoints: %w", err)
+	}
+
+	endpoints := make([]types.ServiceEndpoint, 0, len(kvs))
+	for _, kv := range kvs {
+		var r registration
+		if err := json.Unmarshal(kv.Value, &r); err != nil {
+			return nil, fmt.Errorf("failed to decode the registration of %s: %w", string(kv.Key), transport.Malformed(err))
+		}
+		endpoint := r.endpoint()
+		if err := endpoint.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to get all service endpoints: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	types.SortServiceEndpoints(endpoints, c.config.GetEndpointOrder())
+
+	return endpoints, nil
+}
+
+// IsServiceAvailable checks with etcd if the target service is registered and healthy
+func (c *etcdClient) IsServiceAvailable(serviceKey string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceKey)
+}
+
+// IsServiceAvailableWithContext checks with etcd if the target service is registered and healthy, aborting once ctx
+// is done. Unhealthy services are removed from etcd once their lease expires, so registered services are healthy.
+func (c *etcdClient) IsServiceAvailableWithContext(ctx context.Context, serviceKey string) (bool, error) {
+	_, found, err := c.getRegistration(ctx, serviceKey)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, types.Errorf(types.ErrNotRegistered, "%s service is not registered. Might not have started... ", serviceKey)
+	}
+
+	return true, nil
+}
+
+// getRegistration retrieves the registration of the first instance of the target service in the endpoint order from
+// etcd, reporting whether any instance is registered
+func (c *etcdClient) getRegistration(ctx context.Context, serviceKey string) (registration, bool, error) {
+	instances, err := c.getInstances(ctx, serviceKey)
+	if err != nil || len(instances) == 0 {
+		return registration{}, false, err
+	}
+
+	endpoints := make([]types.ServiceEndpoint, len(instances))
+	for i, instance := range instances {
+		endpoints[i] = instance.registration.endpoint()
+	}
+	types.SortServiceEndpoints(endpoints, c.config.GetEndpointOrder())
+	for _, instance := range instances {
+		if instance.registration.InstanceId == endpoints[0].InstanceId {
+			return instance.registration, true, nil
+		}
+	}
+	return instances[0].registration, true, nil
+}
+
+// instance is the registration of an instance of a service along with the key it is stored at, as read from etcd
+type instance struct {
+	key          string
+	registration registration
+	kv           keyValue
+}
+
+// getInstances retrieves the registrations of all the instances of the target service from etcd. The registration
+// stored under the service key itself by the versions registering a single instance per service key is included, so
+// they are still discovered while being upgraded. The keys under the service key registered by another service, whose
+// service key extends it with a /, are left out.
+func (c *etcdClient) getInstances(ctx context.Context, serviceKey string) ([]instance, error) {
+	serviceKeyPath := servicesPrefix + serviceKey
+	kvs, err := c.restClient.GetPrefix(ctx, serviceKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s service registration: %w", serviceKey, err)
+	}
+
+	var instances []instance
+	for _, kv := range kvs {
+		key := string(kv.Key)
+		if key != serviceKeyPath && !strings.HasPrefix(key, serviceKeyPath+"/") {
+			continue
+		}
+		var r registration
+		if err := json.Unmarshal(kv.Value, &r); err != nil {
+			return nil, fmt.Errorf("failed to decode %s service registration: %w", serviceKey, transport.Malformed(err))
+		}
+		if r.ServiceId != serviceKey {
+			continue
+		}
+		instances = append(instances, instance{key: key, registration: r, kv: kv})
+	}
+
+	return instances, nil
+}
+
+func (r registration) endpoint() types.ServiceEndpoint {
+	return types.ServiceEndpoint{
+		ServiceId:  r.ServiceId,
+		InstanceId: r.InstanceId,
+		Host:       r.Host,
+		Port:       r.Port,
+		Scheme:     r.Metadata[types.SchemeMetadataKey],
+		Metadata:   r.Metadata,
+		Tags:       r.Tags,
+	}
+}
+
+func instanceKeyPath(serviceKey string, instanceId string) string {
+	return servicesPrefix + serviceKey + "/" + instanceId
+}
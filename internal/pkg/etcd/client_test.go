@@ -0,0 +1,553 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package etcd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/hostile"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	serviceName        = "etcdUnitTest"
+	defaultServiceHost = "localhost"
+	defaultServicePort = 8000
+	testCheckRoute     = "/api/v3/ping"
+)
+
+var (
+	testRegistryHost string
+	testRegistryPort int
+	mockEtcd         *MockEtcd
+)
+
+func TestMain(m *testing.M) {
+	mockEtcd = NewMockEtcd()
+	testMockServer := mockEtcd.Start()
+
+	URL, _ := url.Parse(testMockServer.URL)
+	testRegistryHost = URL.Hostname()
+	testRegistryPort, _ = strconv.Atoi(URL.Port())
+
+	exitCode := m.Run()
+	testMockServer.Close()
+	os.Exit(exitCode)
+}
+
+func TestIsAlive(t *testing.T) {
+	client := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+	require.True(t, client.IsAlive(), "etcd not running")
+
+	client, err := NewEtcdClient(types.Config{Host: testRegistryHost, Port: 1})
+	require.NoError(t, err)
+	require.False(t, client.IsAlive())
+}
+
+func TestNewEtcdClientInvalidCheckInterval(t *testing.T) {
+	_, err := NewEtcdClient(types.Config{
+		Host:          testRegistryHost,
+		Port:          testRegistryPort,
+		ServiceHost:   defaultServiceHost,
+		CheckInterval: "bogus",
+	})
+	require.Error(t, err)
+}
+
+func TestRegisterNoServiceInfoError(t *testing.T) {
+	client, err := NewEtcdClient(types.Config{Host: testRegistryHost, Port: testRegistryPort, ServiceKey: getUniqueServiceName()})
+	require.NoError(t, err)
+
+	err = client.Register()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Service information not set")
+}
+
+func TestRegister(t *testing.T) {
+	client := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+	require.NoError(t, client.Register())
+	defer func() { _ = client.Unregister() }()
+
+	endpoint, err := client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, types.ServiceEndpoint{ServiceId: client.serviceKey, InstanceId: client.serviceKey, Host: defaultServiceHost, Port: defaultServicePort}, endpoint)
+
+	available, err := client.IsServiceAvailable(client.serviceKey)
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	// Registering again replaces the registration
+	require.NoError(t, client.Register())
+	endpoints, err := client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	assert.Contains(t, endpoints, endpoint)
+}
+
+func TestUnregister(t *testing.T) {
+	client := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+	require.NoError(t, client.Register())
+	require.NoError(t, client.Unregister())
+
+	_, err := client.GetServiceEndpoint(client.serviceKey)
+	require.EqualError(t, err, "no matching service endpoint found")
+
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service is not registered")
+}
+
+func TestUnregisterAfterFailedRevoke(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == leaseRevokeRoute {
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		mockEtcd.Handler().ServeHTTP(writer, request)
+	}))
+	defer server.Close()
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+
+	makeClient := func(serviceKey string) *etcdClient {
+		client, err := NewEtcdClient(types.Config{Host: serverUrl.Hostname(), Port: port, ServiceKey: serviceKey,
+			ServiceHost: defaultServiceHost, ServicePort: defaultServicePort, CheckType: types.CheckTypeNone})
+		require.NoError(t, err)
+		return client
+	}
+	registered := func(serviceKey string) bool {
+		_, found, err := makeClient(serviceKey).getRegistration(context.Background(), serviceKey)
+		require.NoError(t, err)
+		return found
+	}
+
+	t.Run("registration attached to lease", func(t *testing.T) {
+		client := makeClient(getUniqueServiceName())
+		require.NoError(t, client.Register())
+
+		require.NoError(t, client.Unregister())
+		assert.False(t, registered(client.serviceKey), "Expected registration deleted despite failed revoke")
+	})
+
+	t.Run("registration of another instance", func(t *testing.T) {
+		serviceKey := getUniqueServiceName()
+		client := makeClient(serviceKey)
+		require.NoError(t, client.Register())
+		// The lease of the client expires, and another instance registers the service meanwhile
+		mockEtcd.Expire(serviceKey)
+		other := makeClient(serviceKey)
+		require.NoError(t, other.Register())
+		defer func() { _ = other.Unregister() }()
+
+		require.NoError(t, client.Unregister())
+		assert.True(t, registered(serviceKey), "Expected registration of the other instance to be kept")
+	})
+}
+
+func TestDecommission(t *testing.T) {
+	client := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+	other := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+	require.NoError(t, other.Register())
+	defer func() { _ = other.Unregister() }()
+
+	require.NoError(t, client.Decommission(context.Background(), other.serviceKey))
+	_, err := client.GetServiceEndpoint(other.serviceKey)
+	require.Error(t, err)
+
+	err = client.Decommission(context.Background(), other.serviceKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service is not registered")
+}
+
+func TestTombstones(t *testing.T) {
+	client := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+	tombstone := types.Tombstone{ServiceKey: client.serviceKey, Decommissioned: time.Now().UTC().Truncate(time.Second)}
+
+	require.NoError(t, client.PutTombstone(context.Background(), tombstone))
+	tombstones, err := client.GetTombstones(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, tombstones, tombstone)
+
+	// The tombstones aren't mistaken for registrations
+	endpoints, err := client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	for _, endpoint := range endpoints {
+		assert.NotEqual(t, client.serviceKey, endpoint.ServiceId)
+	}
+}
+
+func TestLeaseExpiryRestoresRegistration(t *testing.T) {
+	client := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+	client.keepAliveInterval = 10 * time.Millisecond
+	require.NoError(t, client.Register())
+
+	registered := func() bool {
+		_, found, err := client.getRegistration(context.Background(), client.serviceKey)
+		return err == nil && found
+	}
+
+	mockEtcd.Expire(client.serviceKey)
+	require.Eventually(t, registered, time.Second, 10*time.Millisecond, "Expected expired registration to be restored")
+
+	require.NoError(t, client.Unregister())
+	require.False(t, registered())
+	time.Sleep(50 * time.Millisecond)
+	require.False(t, registered(), "Expected unregistered service not to be registered again")
+}
+
+func TestUnhealthyServiceLeaseExpires(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !healthy.Load() {
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	URL, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(URL.Port())
+	client := makeEtcdClient(t, getUniqueServiceName(), port, types.CheckTypeHTTP)
+	client.keepAliveInterval = 100 * time.Millisecond
+	require.NoError(t, client.Register())
+	defer func() { _ = client.Unregister() }()
+
+	available := func() bool {
+		ok, _ := client.IsServiceAvailable(client.serviceKey)
+		return ok
+	}
+
+	healthy.Store(false)
+	require.Eventually(t, func() bool { return !available() }, 3*time.Second, 50*time.Millisecond, "Expected unhealthy service lease to expire")
+
+	healthy.Store(true)
+	require.Eventually(t, available, time.Second, 50*time.Millisecond, "Expected recovered service to be registered again")
+}
+
+func TestTCPServiceLeaseExpires(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	client := makeEtcdClient(t, getUniqueServiceName(), port, types.CheckTypeTCP)
+	client.keepAliveInterval = 100 * time.Millisecond
+	require.NoError(t, client.Register())
+	defer func() { _ = client.Unregister() }()
+
+	result, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Output)
+
+	_ = listener.Close()
+	require.Eventually(t, func() bool {
+		available, _ := client.IsServiceAvailable(client.serviceKey)
+		return !available
+	}, 3*time.Second, 50*time.Millisecond, "Expected the lease of the service refusing connections to expire")
+}
+
+func TestRegisterTTLNotSupported(t *testing.T) {
+	client := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeTTL)
+	require.ErrorIs(t, client.Register(), types.ErrNotSupported)
+}
+
+func TestRegisterInstances(t *testing.T) {
+	serviceKey := getUniqueServiceName()
+	first := makeEtcdClient(t, serviceKey, defaultServicePort, types.CheckTypeNone)
+	first.instanceId = serviceKey + "-1"
+	second := makeEtcdClient(t, serviceKey, defaultServicePort+1, types.CheckTypeNone)
+	second.instanceId = serviceKey + "-2"
+	// Registered under the instance keys of serviceKey, which mustn't be mistaken for its instances
+	nested := makeEtcdClient(t, serviceKey+"/"+first.instanceId, defaultServicePort+2, types.CheckTypeNone)
+
+	for _, client := range []*etcdClient{first, second, nested} {
+		require.NoError(t, client.Register())
+		defer func(client *etcdClient) { _ = client.Unregister() }(client)
+	}
+
+	endpoints, err := first.GetServiceEndpoints(serviceKey)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2, "Expected both instances to be registered")
+	assert.Equal(t, []string{first.instanceId, second.instanceId}, []string{endpoints[0].InstanceId, endpoints[1].InstanceId})
+
+	require.NoError(t, first.Unregister())
+	endpoints, err = second.GetServiceEndpoints(serviceKey)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1, "Expected unregistering an instance to keep the other one")
+	assert.Equal(t, second.instanceId, endpoints[0].InstanceId)
+
+	endpoint, err := second.GetServiceEndpoint(serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, defaultServicePort+1, endpoint.Port)
+
+	require.NoError(t, first.Register())
+	require.NoError(t, second.Decommission(context.Background(), serviceKey))
+	_, err = second.GetServiceEndpoints(serviceKey)
+	require.ErrorIs(t, err, types.ErrNotRegistered, "Expected decommissioning to delete all the instances")
+	available, err := nested.IsServiceAvailable(nested.serviceKey)
+	require.NoError(t, err)
+	assert.True(t, available, "Expected the nested service to be kept")
+}
+
+func TestPromoteStandby(t *testing.T) {
+	serviceKey := getUniqueServiceName()
+	active := makeEtcdClient(t, serviceKey, defaultServicePort, types.CheckTypeNone)
+	active.instanceId = serviceKey + "-1"
+	standby := makeEtcdClient(t, serviceKey, defaultServicePort+1, types.CheckTypeNone)
+	standby.instanceId = serviceKey + "-2"
+	standby.config.ServiceMetadata = map[string]string{types.RoleMetadataKey: types.RoleStandby}
+	for _, client := range []*etcdClient{active, standby} {
+		require.NoError(t, client.Register())
+		defer func(client *etcdClient) { _ = client.Unregister() }(client)
+	}
+
+	require.NoError(t, active.PromoteStandby(context.Background(), serviceKey, standby.instanceId))
+	endpoints, err := active.GetServiceEndpoints(serviceKey)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	assert.True(t, endpoints[0].IsStandby(), "Expected the active instance to become standby")
+	assert.False(t, endpoints[1].IsStandby(), "Expected the standby instance to become active")
+	assert.Equal(t, types.RoleActive, endpoints[1].Metadata[types.RoleMetadataKey])
+
+	err = active.PromoteStandby(context.Background(), serviceKey, standby.instanceId)
+	assert.Error(t, err, "Expected the active instance not to be promoted")
+	err = active.PromoteStandby(context.Background(), serviceKey, "unknown")
+	assert.ErrorIs(t, err, types.ErrNotRegistered)
+
+	// The registrations stay attached to the leases of their instances
+	require.NoError(t, active.Unregister())
+	endpoints, err = active.GetServiceEndpoints(serviceKey)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, standby.instanceId, endpoints[0].InstanceId)
+}
+
+func TestGetServiceEndpointsSingleInstanceRegistration(t *testing.T) {
+	serviceKey := getUniqueServiceName()
+	client := makeEtcdClient(t, serviceKey, defaultServicePort, types.CheckTypeNone)
+	value, err := json.Marshal(registration{ServiceId: serviceKey, Host: defaultServiceHost, Port: defaultServicePort})
+	require.NoError(t, err)
+	// Registered under the service key itself, as by the versions registering a single instance per service key
+	require.NoError(t, client.restClient.Put(context.Background(), servicesPrefix+serviceKey, value, 0))
+	defer func() { _ = client.Decommission(context.Background(), serviceKey) }()
+
+	endpoint, err := client.GetServiceEndpoint(serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, defaultServicePort, endpoint.Port)
+}
+
+func TestGetAllServiceEndpointsOrder(t *testing.T) {
+	prefix := getUniqueServiceName()
+	for i, name := range []string{prefix + "-c", prefix + "-a", prefix + "-b"} {
+		client := makeEtcdClient(t, name, defaultServicePort-i, types.CheckTypeNone)
+		require.NoError(t, client.Register())
+		defer func() { _ = client.Unregister() }()
+	}
+
+	client := makeEtcdClient(t, prefix, defaultServicePort, types.CheckTypeNone)
+	endpoints, err := client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(endpoints), 3)
+	require.True(t, slices.IsSortedFunc(endpoints, types.ByServiceId), "Expected endpoints sorted by service id")
+
+	client.config.EndpointOrder = types.ByAddress
+	endpoints, err = client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	require.True(t, slices.IsSortedFunc(endpoints, types.ByAddress), "Expected endpoints sorted by address")
+}
+
+func TestWatchService(t *testing.T) {
+	client := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+	client.config.WatchInterval = "10ms"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	endpoints, err := client.WatchService(ctx, client.serviceKey)
+	require.NoError(t, err)
+
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint before registering")
+
+	require.NoError(t, client.Register())
+	require.Equal(t, client.serviceKey, receiveEndpoint(t, endpoints).ServiceId)
+
+	require.NoError(t, client.Unregister())
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint once unregistered")
+}
+
+func TestTriggerHealthCheck(t *testing.T) {
+	client := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+
+	_, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.Error(t, err, "Expected error for service not registered")
+
+	require.NoError(t, client.Register())
+	defer func() { _ = client.Unregister() }()
+
+	result, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, "Expected service registered without health check to be healthy")
+}
+
+func TestTriggerHealthCheckExpectation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`{"status":"DOWN"}`))
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	client, err := NewEtcdClient(types.Config{
+		Host:             testRegistryHost,
+		Port:             testRegistryPort,
+		ServiceKey:       getUniqueServiceName(),
+		ServiceHost:      serverUrl.Hostname(),
+		ServicePort:      port,
+		CheckRoute:       testCheckRoute,
+		CheckInterval:    "1s",
+		CheckExpectation: types.HealthCheckExpectation{JSONPath: "status", JSONValue: "UP"},
+		CheckStatusCodes: []int{http.StatusOK},
+		CheckHeaders:     map[string]string{"Authorization": "Bearer secret"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Register())
+	defer func() { _ = client.Unregister() }()
+
+	other := makeEtcdClient(t, getUniqueServiceName(), defaultServicePort, types.CheckTypeNone)
+	result, err := other.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	assert.False(t, result.Healthy, "Expected the options stored in the registration to be verified")
+	assert.Contains(t, result.Output, "is 'DOWN' instead of 'UP'")
+
+	registration, _, err := other.getRegistration(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	assert.Empty(t, registration.CheckOptions.Headers, "Expected the token of the check not to be registered")
+}
+
+func TestAccessToken(t *testing.T) {
+	mockEtcd.SetExpectedAccessToken("fresh-token")
+	defer mockEtcd.ClearExpectedAccessToken()
+
+	client, err := NewEtcdClient(types.Config{Host: testRegistryHost, Port: testRegistryPort, AccessToken: "expired-token"})
+	require.NoError(t, err)
+	require.False(t, client.IsAlive(), "Expected request with the wrong token to be rejected")
+
+	renewals := 0
+	client, err = NewEtcdClient(types.Config{
+		Host:        testRegistryHost,
+		Port:        testRegistryPort,
+		AccessToken: "expired-token",
+		GetAccessToken: func() (string, error) {
+			renewals++
+			return "fresh-token", nil
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, client.IsAlive(), "Expected request to be retried with the renewed token")
+	require.True(t, client.IsAlive())
+	require.Equal(t, 1, renewals, "Expected the renewed token to be kept for subsequent requests")
+
+	mockEtcd.SetExpectedAccessToken("request-token")
+	require.True(t, client.IsAliveWithContext(types.WithAccessToken(context.Background(), "request-token")))
+	require.Equal(t, 1, renewals)
+}
+
+func receiveEndpoint(t *testing.T, endpoints <-chan types.ServiceEndpoint) types.ServiceEndpoint {
+	select {
+	case endpoint := <-endpoints:
+		return endpoint
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for service endpoint")
+		return types.ServiceEndpoint{}
+	}
+}
+
+func makeEtcdClient(t *testing.T, serviceName string, servicePort int, checkType string) *etcdClient {
+	client, err := NewEtcdClient(types.Config{
+		Host:          testRegistryHost,
+		Port:          testRegistryPort,
+		ServiceKey:    serviceName,
+		ServiceHost:   defaultServiceHost,
+		ServicePort:   servicePort,
+		CheckType:     checkType,
+		CheckRoute:    testCheckRoute,
+		CheckInterval: "1s",
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+func getUniqueServiceName() string {
+	return serviceName + strconv.Itoa(time.Now().Nanosecond())
+}
+
+func TestGetServiceEndpointHostileResponses(t *testing.T) {
+	for _, registration := range hostile.Registrations() {
+		t.Run(registration.Name, func(t *testing.T) {
+			value := fmt.Sprintf(`{"serviceId":%q,%s}`, serviceName, registration.Fields("host", "port"))
+			server, host, port := hostile.Serve(func(*http.Request) string {
+				// etcd encodes the keys and values in base64
+				return fmt.Sprintf(`{"kvs":[{"key":%q,"value":%q}]}`,
+					base64.StdEncoding.EncodeToString([]byte(instanceKeyPath(serviceName, serviceName))), base64.StdEncoding.EncodeToString([]byte(value)))
+			})
+			defer server.Close()
+
+			client, err := NewEtcdClient(types.Config{Host: host, Port: port})
+			require.NoError(t, err)
+
+			endpoint, err := client.GetServiceEndpoint(serviceName)
+			if !registration.Valid {
+				require.ErrorIs(t, err, types.ErrMalformedResponse)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, hostile.ValidHost, endpoint.Host)
+			assert.Equal(t, hostile.ValidPort, endpoint.Port)
+		})
+	}
+}
+
+func TestGetAllServiceEndpointsHostileResponses(t *testing.T) {
+	for _, registration := range hostile.Registrations() {
+		t.Run(registration.Name, func(t *testing.T) {
+			value := fmt.Sprintf(`{"serviceId":%q,%s}`, serviceName, registration.Fields("host", "port"))
+			server, host, port := hostile.Serve(func(*http.Request) string {
+				return fmt.Sprintf(`{"kvs":[{"key":%q,"value":%q}]}`,
+					base64.StdEncoding.EncodeToString([]byte(instanceKeyPath(serviceName, serviceName))), base64.StdEncoding.EncodeToString([]byte(value)))
+			})
+			defer server.Close()
+
+			client, err := NewEtcdClient(types.Config{Host: host, Port: port})
+			require.NoError(t, err)
+
+			endpoints, err := client.GetAllServiceEndpoints()
+			if !registration.Valid {
+				require.ErrorIs(t, err, types.ErrMalformedResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, endpoints, 1)
+			assert.Equal(t, hostile.ValidHost, endpoints[0].Host)
+			assert.Equal(t, hostile.ValidPort, endpoints[0].Port)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package etcd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type mockLease struct {
+	ttl       time.Duration
+	expiresAt time.Time
+}
+
+// MockEtcd emulates the JSON gateway of the etcd v3 API for the key and lease operations used by the etcd client
+type MockEtcd struct {
+	keyValues     map[string]keyValue
+	revision      int64
+	leases        map[int64]*mockLease
+	nextLeaseId   int64
+	expectedToken string
+	lock          sync.Mutex
+}
+
+func NewMockEtcd() *MockEtcd {
+	return &MockEtcd{
+		keyValues:   make(map[string]keyValue),
+		leases:      make(map[int64]*mockLease),
+		nextLeaseId: 1,
+	}
+}
+
+// SetExpectedAccessToken has every request without the given token rejected with 401 Unauthorized
+func (mock *MockEtcd) SetExpectedAccessToken(token string) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
+	mock.expectedToken = token
+}
+
+func (mock *MockEtcd) ClearExpectedAccessToken() {
+	mock.SetExpectedAccessToken("")
+}
+
+// Expire expires the leases of the instances of the target service right away, as etcd does when the leases aren't
+// kept alive in time
+func (mock *MockEtcd) Expire(serviceKey string) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
+	for key, kv := range mock.keyValues {
+		if strings.HasPrefix(key, servicesPrefix+serviceKey+"/") {
+			mock.revoke(kv.Lease)
+		}
+	}
+}
+
+// put stores the key with the next revision. Callers must hold lock.
+func (mock *MockEtcd) put(kv keyValue) {
+	mock.revision++
+	kv.ModRevision = mock.revision
+	mock.keyValues[string(kv.Key)] = kv
+}
+
+// revoke deletes the lease and the keys attached to it. Callers must hold lock.
+func (mock *MockEtcd) revoke(leaseId int64) {
+	delete(mock.leases, leaseId)
+	for key, kv := range mock.keyValues {
+		if kv.Lease == leaseId {
+			delete(mock.keyValues, key)
+		}
+	}
+}
+
+// expireLeases revokes the leases which haven't been kept alive in time. Callers must hold lock.
+func (mock *MockEtcd) expireLeases() {
+	now := time.Now()
+	for id, lease := range mock.leases {
+		if now.After(lease.expiresAt) {
+			mock.revoke(id)
+		}
+	}
+}
+
+func (mock *MockEtcd) Start() *httptest.Server {
+	return httptest.NewServer(mock.Handler())
+}
+
+// Handler returns the handler serving the JSON gateway, for the tests to put faults in front of it
+func (mock *MockEtcd) Handler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mock.lock.Lock()
+		defer mock.lock.Unlock()
+
+		if mock.expectedToken != "" && request.Header.Get(authorizationHeader) != mock.expectedToken {
+			writeJSON(writer, http.StatusUnauthorized, errorResponse{Error: "etcdserver: invalid auth token", Message: "etcdserver: invalid auth token"})
+			return
+		}
+
+		mock.expireLeases()
+
+		switch request.URL.Path {
+		case healthRoute:
+			writeJSON(writer, http.StatusOK, map[string]string{"health": "true"})
+		case putRoute:
+			var req keyValue
+			if !decodeJSON(writer, request, &req) {
+				return
+			}
+			if _, ok := mock.leases[req.Lease]; req.Lease != 0 && !ok {
+				writeJSON(writer, http.StatusNotFound, errorResponse{Error: "etcdserver: requested lease not found", Message: "etcdserver: requested lease not found"})
+				return
+			}
+			mock.put(req)
+			writeJSON(writer, http.StatusOK, struct{}{})
+		case rangeRoute:
+			var req rangeRequest
+			if !decodeJSON(writer, request, &req) {
+				return
+			}
+			res := rangeResponse{}
+			for key, kv := range mock.keyValues {
+				if key == string(req.Key) || (req.RangeEnd != nil && bytes.Compare([]byte(key), req.Key) >= 0 && bytes.Compare([]byte(key), req.RangeEnd) < 0) {
+					res.Kvs = append(res.Kvs, kv)
+				}
+			}
+			// etcd returns the keys in lexical order
+			sort.Slice(res.Kvs, func(i, j int) bool { return bytes.Compare(res.Kvs[i].Key, res.Kvs[j].Key) < 0 })
+			writeJSON(writer, http.StatusOK, res)
+		case deleteRoute:
+			var req rangeRequest
+			if !decodeJSON(writer, request, &req) {
+				return
+			}
+			res := deleteResponse{}
+			if _, ok := mock.keyValues[string(req.Key)]; ok {
+				delete(mock.keyValues, string(req.Key))
+				res.Deleted = 1
+			}
+			writeJSON(writer, http.StatusOK, res)
+		case txnRoute:
+			var req txnRequest
+			if !decodeJSON(writer, request, &req) {
+				return
+			}
+			res := txnResponse{Succeeded: true}
+			for _, c := range req.Compare {
+				kv, ok := mock.keyValues[string(c.Key)]
+				switch {
+				case c.Result != "EQUAL":
+					writeJSON(writer, http.StatusBadRequest, errorResponse{Error: "unsupported comparison", Message: "unsupported comparison"})
+					return
+				case c.Target == "LEASE":
+					res.Succeeded = res.Succeeded && ok && kv.Lease == c.Lease
+				case c.Target == "MOD":
+					res.Succeeded = res.Succeeded && ok && kv.ModRevision == c.ModRevision
+				default:
+					writeJSON(writer, http.StatusBadRequest, errorResponse{Error: "unsupported comparison", Message: "unsupported comparison"})
+					return
+				}
+			}
+			if res.Succeeded {
+				for _, op := range req.Success {
+					if op.RequestPut != nil {
+						mock.put(*op.RequestPut)
+					}
+					if op.RequestDeleteRange != nil {
+						delete(mock.keyValues, string(op.RequestDeleteRange.Key))
+					}
+				}
+			}
+			writeJSON(writer, http.StatusOK, res)
+		case leaseGrantRoute:
+			var req lease
+			if !decodeJSON(writer, request, &req) {
+				return
+			}
+			id := mock.nextLeaseId
+			mock.nextLeaseId++
+			ttl := time.Duration(req.TTL) * time.Second
+			mock.leases[id] = &mockLease{ttl: ttl, expiresAt: time.Now().Add(ttl)}
+			writeJSON(writer, http.StatusOK, lease{ID: id, TTL: req.TTL})
+		case keepAliveRoute:
+			var req lease
+			if !decodeJSON(writer, request, &req) {
+				return
+			}
+			res := keepAliveResponse{Result: lease{ID: req.ID}}
+			if l, ok := mock.leases[req.ID]; ok {
+				l.expiresAt = time.Now().Add(l.ttl)
+				res.Result.TTL = int64(l.ttl.Seconds())
+			}
+			writeJSON(writer, http.StatusOK, res)
+		case leaseRevokeRoute:
+			var req lease
+			if !decodeJSON(writer, request, &req) {
+				return
+			}
+			if _, ok := mock.leases[req.ID]; !ok {
+				writeJSON(writer, http.StatusNotFound, errorResponse{Error: "etcdserver: requested lease not found", Message: "etcdserver: requested lease not found"})
+				return
+			}
+			mock.revoke(req.ID)
+			writeJSON(writer, http.StatusOK, struct{}{})
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func decodeJSON(writer http.ResponseWriter, request *http.Request, v any) bool {
+	if err := json.NewDecoder(request.Body).Decode(v); err != nil {
+		writeJSON(writer, http.StatusBadRequest, errorResponse{Error: err.Error(), Message: err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeJSON(writer http.ResponseWriter, statusCode int, v any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	_ = json.NewEncoder(writer).Encode(v)
+}
@@ -0,0 +1,332 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/transport"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	authorizationHeader = "Authorization"
+
+	healthRoute      = "/health"
+	putRoute         = "/v3/kv/put"
+	rangeRoute       = "/v3/kv/range"
+	deleteRoute      = "/v3/kv/deleterange"
+	leaseGrantRoute  = "/v3/lease/grant"
+	keepAliveRoute   = "/v3/lease/keepalive"
+	leaseRevokeRoute = "/v3/lease/revoke"
+	txnRoute         = "/v3/kv/txn"
+)
+
+// requestError is the error of a request rejected by etcd
+type requestError struct {
+	statusCode int
+	message    string
+}
+
+func (e *requestError) Error() string {
+	return fmt.Sprintf("request failed, status code: %d, err: %s", e.statusCode, e.message)
+}
+
+// Is reports whether the request was rejected for its access token, as types.ErrUnauthorized
+func (e *requestError) Is(target error) bool {
+	return target == types.ErrUnauthorized && (e.statusCode == http.StatusUnauthorized || e.statusCode == http.StatusForbidden)
+}
+
+// keyValue is a key of the etcd key space with its value and the lease it is attached to, if any, along with the
+// revision it was last modified at
+type keyValue struct {
+	Key         []byte `json:"key"`
+	Value       []byte `json:"value,omitempty"`
+	Lease       int64  `json:"lease,omitempty,string"`
+	ModRevision int64  `json:"mod_revision,omitempty,string"`
+}
+
+type rangeRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end,omitempty"`
+}
+
+type rangeResponse struct {
+	Kvs []keyValue `json:"kvs"`
+}
+
+type deleteResponse struct {
+	Deleted int64 `json:"deleted,omitempty,string"`
+}
+
+// compare is a condition of a transaction, comparing either the lease or the revision the key was last modified at
+type compare struct {
+	Key         []byte `json:"key"`
+	Target      string `json:"target"`
+	Result      string `json:"result"`
+	Lease       int64  `json:"lease,omitempty,string"`
+	ModRevision int64  `json:"mod_revision,omitempty,string"`
+}
+
+type requestOp struct {
+	RequestPut         *keyValue     `json:"request_put,omitempty"`
+	RequestDeleteRange *rangeRequest `json:"request_delete_range,omitempty"`
+}
+
+type txnRequest struct {
+	Compare []compare   `json:"compare"`
+	Success []requestOp `json:"success"`
+}
+
+type txnResponse struct {
+	Succeeded bool `json:"succeeded,omitempty"`
+}
+
+type lease struct {
+	ID  int64 `json:"ID,omitempty,string"`
+	TTL int64 `json:"TTL,omitempty,string"`
+}
+
+type keepAliveResponse struct {
+	Result lease `json:"result"`
+}
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// restClient invokes the JSON gateway of the etcd v3 API, which etcd serves on its client port alongside gRPC, so
+// the registry doesn't depend on the gRPC etcd client. Keys and values are base64 encoded by the JSON encoding of
+// their []byte type as the gateway expects.
+type restClient struct {
+	baseUrl        string
+	httpClient     *http.Client
+	getAccessToken types.GetAccessTokenCallback
+	accessToken    string
+	tokenLock      sync.RWMutex
+}
+
+func newRestClient(baseUrl string, httpClient *http.Client, accessToken string, getAccessToken types.GetAccessTokenCallback) *restClient {
+	return &restClient{
+		baseUrl:        baseUrl,
+		httpClient:     httpClient,
+		accessToken:    accessToken,
+		getAccessToken: getAccessToken,
+	}
+}
+
+// Health checks that etcd is up and has a leader
+func (rc *restClient) Health(ctx context.Context) error {
+	return rc.sendRequest(ctx, http.MethodGet, healthRoute, nil, nil)
+}
+
+// Put stores the value of the key, attached to the lease unless zero
+func (rc *restClient) Put(ctx context.Context, key string, value []byte, leaseId int64) error {
+	return rc.sendRequest(ctx, http.MethodPost, putRoute, keyValue{Key: []byte(key), Value: value, Lease: leaseId}, nil)
+}
+
+// Get returns the value of the key, reporting whether the key exists
+func (rc *restClient) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	res := rangeResponse{}
+	if err := rc.sendRequest(ctx, http.MethodPost, rangeRoute, rangeRequest{Key: []byte(key)}, &res); err != nil {
+		return nil, false, err
+	}
+	if len(res.Kvs) == 0 {
+		return nil, false, nil
+	}
+	return res.Kvs[0].Value, true, nil
+}
+
+// GetPrefix returns the keys starting with prefix and their values
+func (rc *restClient) GetPrefix(ctx context.Context, prefix string) ([]keyValue, error) {
+	res := rangeResponse{}
+	err := rc.sendRequest(ctx, http.MethodPost, rangeRoute, rangeRequest{Key: []byte(prefix), RangeEnd: prefixRangeEnd(prefix)}, &res)
+	return res.Kvs, err
+}
+
+// Delete deletes the key, reporting whether it existed
+func (rc *restClient) Delete(ctx context.Context, key string) (bool, error) {
+	res := deleteResponse{}
+	err := rc.sendRequest(ctx, http.MethodPost, deleteRoute, rangeRequest{Key: []byte(key)}, &res)
+	return res.Deleted > 0, err
+}
+
+// DeleteIfLease deletes the key in a transaction, only if it is attached to the given lease, reporting whether it was
+func (rc *restClient) DeleteIfLease(ctx context.Context, key string, leaseId int64) (bool, error) {
+	req := txnRequest{
+		Compare: []compare{{Key: []byte(key), Target: "LEASE", Result: "EQUAL", Lease: leaseId}},
+		Success: []requestOp{{RequestDeleteRange: &rangeRequest{Key: []byte(key)}}},
+	}
+	res := txnResponse{}
+	err := rc.sendRequest(ctx, http.MethodPost, txnRoute, req, &res)
+	return res.Succeeded, err
+}
+
+// PutIfUnmodified puts the keys in a transaction, only if none was modified since the revisions of kvs, reporting
+// whether they were. Each key is attached to the lease of its new value.
+func (rc *restClient) PutIfUnmodified(ctx context.Context, kvs []keyValue, values []keyValue) (bool, error) {
+	req := txnRequest{}
+	for _, kv := range kvs {
+		req.Compare = append(req.Compare, compare{Key: kv.Key, Target: "MOD", Result: "EQUAL", ModRevision: kv.ModRevision})
+	}
+	for _, value := range values {
+		req.Success = append(req.Success, requestOp{RequestPut: &keyValue{Key: value.Key, Value: value.Value, Lease: value.Lease}})
+	}
+	res := txnResponse{}
+	err := rc.sendRequest(ctx, http.MethodPost, txnRoute, req, &res)
+	return res.Succeeded, err
+}
+
+// GrantLease creates a lease expiring after ttlSeconds unless kept alive and returns its ID
+func (rc *restClient) GrantLease(ctx context.Context, ttlSeconds int64) (int64, error) {
+	res := lease{}
+	err := rc.sendRequest(ctx, http.MethodPost, leaseGrantRoute, lease{TTL: ttlSeconds}, &res)
+	return res.ID, err
+}
+
+// KeepAlive renews the lease and returns its remaining TTL, which is zero when the lease has already expired
+func (rc *restClient) KeepAlive(ctx context.Context, leaseId int64) (int64, error) {
+	res := keepAliveResponse{}
+	err := rc.sendRequest(ctx, http.MethodPost, keepAliveRoute, lease{ID: leaseId}, &res)
+	if requestErr, ok := err.(*requestError); ok && requestErr.statusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	return res.Result.TTL, err
+}
+
+// RevokeLease revokes the lease, deleting all the keys attached to it
+func (rc *restClient) RevokeLease(ctx context.Context, leaseId int64) error {
+	return rc.sendRequest(ctx, http.MethodPost, leaseRevokeRoute, lease{ID: leaseId}, nil)
+}
+
+// sendRequest sends the request with the optional JSON encoded data to etcd and decodes the JSON response into result
+// when it is not nil. A request rejected with 401, i.e. due to an expired token, is retried once with a token renewed
+// by the GetAccessToken callback, unless the token is overridden by the context.
+func (rc *restClient) sendRequest(ctx context.Context, method string, requestPath string, data any, result any) error {
+	requestUrl, err := url.JoinPath(rc.baseUrl, requestPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse baseUrl and requestPath: %w", err)
+	}
+
+	var jsonEncodedData []byte
+	if data != nil {
+		jsonEncodedData, err = json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to encode input data to JSON: %w", err)
+		}
+	}
+
+	statusCode, bodyBytes, err := rc.send(ctx, method, requestUrl, jsonEncodedData)
+	_, hasRequestToken := types.AccessTokenFromContext(ctx)
+	if err == nil && statusCode == http.StatusUnauthorized && rc.getAccessToken != nil && !hasRequestToken {
+		if err = rc.renewAccessToken(); err == nil {
+			statusCode, bodyBytes, err = rc.send(ctx, method, requestUrl, jsonEncodedData)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if statusCode > http.StatusMultiStatus {
+		res := errorResponse{}
+		message := string(bodyBytes)
+		if json.Unmarshal(bodyBytes, &res) == nil && (res.Message != "" || res.Error != "") {
+			message = res.Message
+			if message == "" {
+				message = res.Error
+			}
+		}
+		return &requestError{statusCode: statusCode, message: message}
+	}
+
+	if result != nil && len(bodyBytes) > 0 {
+		if err := json.Unmarshal(bodyBytes, result); err != nil {
+			return fmt.Errorf("failed to parse the response body: %w", transport.Malformed(err))
+		}
+	}
+
+	return nil
+}
+
+// send sends a single attempt of the request and returns the status code and body of the response
+func (rc *restClient) send(ctx context.Context, method string, requestUrl string, jsonEncodedData []byte) (int, []byte, error) {
+	var body io.Reader
+	if jsonEncodedData != nil {
+		body = bytes.NewReader(jsonEncodedData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestUrl, body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create a http request: %w", err)
+	}
+	if jsonEncodedData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	accessToken, ok := types.AccessTokenFromContext(ctx)
+	if !ok {
+		accessToken = rc.currentAccessToken()
+	}
+	if accessToken != "" {
+		// etcd expects the token issued by its authenticate API as is, without scheme
+		req.Header.Set(authorizationHeader, accessToken)
+	}
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to send a http request: %w", transport.Unavailable(err))
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get the body from the response: %w", err)
+	}
+
+	return resp.StatusCode, bodyBytes, nil
+}
+
+func (rc *restClient) renewAccessToken() error {
+	accessToken, err := rc.getAccessToken()
+	if err != nil {
+		return fmt.Errorf("failed to renew access token: %w", err)
+	}
+
+	rc.tokenLock.Lock()
+	defer rc.tokenLock.Unlock()
+	rc.accessToken = accessToken
+
+	return nil
+}
+
+func (rc *restClient) currentAccessToken() string {
+	rc.tokenLock.RLock()
+	defer rc.tokenLock.RUnlock()
+
+	return rc.accessToken
+}
+
+// prefixRangeEnd returns the end of the range of the keys starting with prefix, i.e. the prefix with its last byte
+// incremented
+func prefixRangeEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// The prefix consists of 0xff bytes only, so the range goes up to the end of the key space
+	return []byte{0}
+}
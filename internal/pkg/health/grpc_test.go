@@ -0,0 +1,57 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestProbeGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	healthServer := grpchealth.NewServer()
+	healthServer.SetServingStatus("edgex.CoreData", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("edgex.CoreCommand", healthpb.HealthCheckResponse_NOT_SERVING)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	address := listener.Addr().String()
+	require.NoError(t, ProbeGRPC(context.Background(), address, ""), "Expected overall server health to be SERVING")
+	require.NoError(t, ProbeGRPC(context.Background(), address, "edgex.CoreData"))
+
+	err = ProbeGRPC(context.Background(), address, "edgex.CoreCommand")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NOT_SERVING")
+
+	err = ProbeGRPC(context.Background(), address, "edgex.CoreMetadata")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NotFound", "Expected NOT_FOUND for unknown service")
+
+	server.Stop()
+	require.Error(t, ProbeGRPC(context.Background(), address, ""))
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	require.NoError(t, ProbeTCP(context.Background(), address))
+
+	_ = listener.Close()
+	require.Error(t, ProbeTCP(context.Background(), address))
+}
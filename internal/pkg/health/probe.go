@@ -0,0 +1,130 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	probeTimeout = 5 * time.Second
+	// maxBodySize bounds the health check response body kept for verifying the expected content
+	maxBodySize = 1 << 20
+)
+
+// Probe calls the health check URL of a service once with the given options and returns an error unless it responds
+// with a healthy status code and the expected content
+func Probe(ctx context.Context, url string, options types.HealthCheckOptions) error {
+	if err := options.Validate(); err != nil {
+		return fmt.Errorf("health check %s failed: %v", url, err)
+	}
+
+	netClient := http.Client{Timeout: probeTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, options.GetMethod(), url, nil)
+	if err != nil {
+		return fmt.Errorf("health check %s failed: %v", url, err)
+	}
+	for name, value := range options.Headers {
+		req.Header.Set(name, value)
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	resp, err := netClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	var body []byte
+	if !options.HealthCheckExpectation.IsZero() {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			return fmt.Errorf("health check %s failed: %v", url, err)
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if !options.IsHealthyStatusCode(resp.StatusCode) {
+		return fmt.Errorf("health check %s failed: unexpected status code %d", url, resp.StatusCode)
+	}
+	if err := options.Verify(body); err != nil {
+		return fmt.Errorf("health check %s failed: %v", url, err)
+	}
+
+	return nil
+}
+
+// ProbeTCP opens a TCP connection to the given address once, i.e. the host and port of a service, and returns an
+// error unless it is accepted
+func ProbeTCP(ctx context.Context, address string) error {
+	dialer := net.Dialer{Timeout: probeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("TCP health check %s failed: %v", address, err)
+	}
+	_ = conn.Close()
+
+	return nil
+}
+
+// Check probes each of the health check URLs of a service once with the given options, reporting the service healthy
+// when all of them pass
+func Check(ctx context.Context, options types.HealthCheckOptions, urls ...string) types.HealthCheckResult {
+	result := types.HealthCheckResult{Healthy: true}
+
+	var outputs []string
+	for _, url := range urls {
+		if err := Probe(ctx, url, options); err != nil {
+			result.Healthy = false
+			outputs = append(outputs, err.Error())
+			continue
+		}
+		outputs = append(outputs, fmt.Sprintf("health check %s passed", url))
+	}
+	result.Output = strings.Join(outputs, "; ")
+
+	return result
+}
+
+// ProbeService runs the health check of the given type of the service listening at the given address once: calls
+// the route of the http check, connects for the tcp check or calls the gRPC health service named by the route for the
+// grpc check, and returns an error unless it passes. The ttl and none checks aren't run by probing the service.
+func ProbeService(ctx context.Context, checkType string, address string, route string, options types.HealthCheckOptions) error {
+	switch strings.ToLower(checkType) {
+	case types.CheckTypeHTTP:
+		return Probe(ctx, "http://"+address+route, options)
+	case types.CheckTypeTCP:
+		return ProbeTCP(ctx, address)
+	case types.CheckTypeGRPC:
+		return ProbeGRPC(ctx, address, route)
+	default:
+		return fmt.Errorf("unable to probe %s: %s health checks aren't run by probing the service", address, checkType)
+	}
+}
+
+// CheckService probes the service listening at the given address once with the health check of the given type, like
+// ProbeService, reporting the result
+func CheckService(ctx context.Context, checkType string, address string, route string, options types.HealthCheckOptions) types.HealthCheckResult {
+	if strings.EqualFold(checkType, types.CheckTypeHTTP) {
+		return Check(ctx, options, "http://"+address+route)
+	}
+
+	if err := ProbeService(ctx, checkType, address, route, options); err != nil {
+		return types.HealthCheckResult{Healthy: false, Output: err.Error()}
+	}
+	return types.HealthCheckResult{Healthy: true, Output: fmt.Sprintf("%s health check %s passed", checkType, address)}
+}
@@ -0,0 +1,133 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+func TestProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/api/v3/ping" {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = writer.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	err := Probe(context.Background(), server.URL+"/api/v3/ping", types.HealthCheckOptions{})
+	require.NoError(t, err)
+
+	err = Probe(context.Background(), server.URL+"/unhealthy", types.HealthCheckOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code 503")
+
+	server.Close()
+	err = Probe(context.Background(), server.URL+"/api/v3/ping", types.HealthCheckOptions{})
+	require.Error(t, err)
+}
+
+func TestProbeExpectation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`{"apiVersion":"v3","status":"DEGRADED"}`))
+	}))
+	defer server.Close()
+
+	err := Probe(context.Background(), server.URL, types.HealthCheckOptions{HealthCheckExpectation: types.HealthCheckExpectation{BodyContains: `"apiVersion":"v3"`}})
+	require.NoError(t, err)
+
+	err = Probe(context.Background(), server.URL, types.HealthCheckOptions{HealthCheckExpectation: types.HealthCheckExpectation{JSONPath: "status", JSONValue: "UP"}})
+	require.Error(t, err, "Expected service responding 200 while broken to fail its health check")
+	assert.Contains(t, err.Error(), "is 'DEGRADED' instead of 'UP'")
+}
+
+func TestProbeOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "Bearer secret" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if request.Method == http.MethodHead {
+			writer.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = writer.Write([]byte(`{"status":"UP","version":"3.1"}`))
+	}))
+	defer server.Close()
+
+	err := Probe(context.Background(), server.URL, types.HealthCheckOptions{})
+	require.Error(t, err, "Expected check route requiring a bearer token to fail without it")
+	assert.Contains(t, err.Error(), "unexpected status code 401")
+
+	headers := map[string]string{"Authorization": "Bearer secret"}
+	err = Probe(context.Background(), server.URL, types.HealthCheckOptions{
+		Headers:                headers,
+		HealthCheckExpectation: types.HealthCheckExpectation{JSONFragment: `{"status":"UP"}`},
+	})
+	require.NoError(t, err)
+
+	err = Probe(context.Background(), server.URL, types.HealthCheckOptions{Method: http.MethodHead, Headers: headers})
+	require.Error(t, err, "Expected 204 to be unhealthy by default")
+	err = Probe(context.Background(), server.URL, types.HealthCheckOptions{Method: http.MethodHead, Headers: headers, StatusCodes: []int{200, 204}})
+	require.NoError(t, err)
+
+	err = Probe(context.Background(), server.URL, types.HealthCheckOptions{Method: http.MethodPost})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported method 'POST'")
+}
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/api/v3/ping" {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = writer.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	result := Check(context.Background(), types.HealthCheckOptions{}, server.URL+"/api/v3/ping")
+	assert.True(t, result.Healthy)
+	assert.Contains(t, result.Output, "passed")
+
+	result = Check(context.Background(), types.HealthCheckOptions{}, server.URL+"/api/v3/ping", server.URL+"/unhealthy")
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Output, "unexpected status code 503")
+
+	result = Check(context.Background(), types.HealthCheckOptions{})
+	assert.True(t, result.Healthy, "Expected service without health checks to be healthy")
+}
+
+func TestCheckService(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte("pong"))
+	}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	result := CheckService(context.Background(), types.CheckTypeHTTP, address, "/api/v3/ping", types.HealthCheckOptions{})
+	assert.True(t, result.Healthy, result.Output)
+
+	result = CheckService(context.Background(), types.CheckTypeTCP, address, "", types.HealthCheckOptions{})
+	assert.True(t, result.Healthy, result.Output)
+
+	result = CheckService(context.Background(), types.CheckTypeGRPC, address, "", types.HealthCheckOptions{})
+	assert.False(t, result.Healthy, "Expected the HTTP server to fail the gRPC health check")
+
+	result = CheckService(context.Background(), types.CheckTypeTTL, address, "", types.HealthCheckOptions{})
+	assert.False(t, result.Healthy)
+	assert.Contains(t, result.Output, "aren't run by probing the service")
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package hostile provides a corpus of hostile and edge-case registrations, as a misbehaving Registry or client may
+// store them, for the tests of the decoders of all the registry types
+package hostile
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ValidHost and ValidPort are the address of the registrations of the corpus the clients are expected to decode
+	ValidHost = "localhost"
+	ValidPort = 59880
+)
+
+// Registration is a registration of the corpus, given as the raw JSON values of its host and port so each registry
+// type can render it in its own documents
+type Registration struct {
+	Name string
+	// Host and Port are the raw JSON values of the host and port of the registration, left out when empty
+	Host string
+	Port string
+	// DuplicatePort is the raw JSON value of a port field repeated before the one of Port, if not empty
+	DuplicatePort string
+	// Valid tells whether the registration is expected to be decoded, with ValidHost and ValidPort, all the others
+	// being expected to fail with types.ErrMalformedResponse
+	Valid bool
+}
+
+// Registrations returns the corpus
+func Registrations() []Registration {
+	validHost := strconv.Quote(ValidHost)
+	validPort := strconv.Itoa(ValidPort)
+	return []Registration{
+		{Name: "valid", Host: validHost, Port: validPort, Valid: true},
+		// encoding/json keeps the last of the duplicate keys, like most JSON decoders
+		{Name: "duplicate port", Host: validHost, Port: validPort, DuplicatePort: "1", Valid: true},
+		{Name: "huge host", Host: strconv.Quote(strings.Repeat("a", 1<<20)), Port: validPort},
+		{Name: "host of wrong type", Host: "42", Port: validPort},
+		{Name: "port of wrong type", Host: validHost, Port: strconv.Quote(validPort)},
+		{Name: "fractional port", Host: validHost, Port: "59880.5"},
+		{Name: "NaN port", Host: validHost, Port: "NaN"},
+		{Name: "infinite port", Host: validHost, Port: "1e400"},
+		{Name: "port out of range", Host: validHost, Port: "65536"},
+		{Name: "negative port", Host: validHost, Port: "-1"},
+		{Name: "null port", Host: validHost, Port: "null"},
+		{Name: "missing port", Host: validHost},
+	}
+}
+
+// Fields renders the host and port of the registration as the members of a JSON object, without the braces, under
+// the given field names
+func (r Registration) Fields(hostField string, portField string) string {
+	var members []string
+	if r.Host != "" {
+		members = append(members, strconv.Quote(hostField)+":"+r.Host)
+	}
+	if r.DuplicatePort != "" {
+		members = append(members, strconv.Quote(portField)+":"+r.DuplicatePort)
+	}
+	if r.Port != "" {
+		members = append(members, strconv.Quote(portField)+":"+r.Port)
+	}
+	return strings.Join(members, ",")
+}
+
+// Address returns the host and port of the registration as registry types not storing JSON documents carry them, i.e.
+// as DNS records or in memory, a missing or null port being 0. ok is false when they can't be carried as a string and
+// an integer, i.e. with a port of wrong type.
+func (r Registration) Address() (host string, port int, ok bool) {
+	if r.Host != "" && json.Unmarshal([]byte(r.Host), &host) != nil {
+		return "", 0, false
+	}
+	if r.Port != "" && json.Unmarshal([]byte(r.Port), &port) != nil {
+		return "", 0, false
+	}
+	return host, port, true
+}
+
+// Serve starts a Registry answering each request with the JSON document rendered by respond for it, and returns its
+// host and port. The caller closes the returned server once done.
+func Serve(respond func(request *http.Request) string) (*httptest.Server, string, int) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = writer.Write([]byte(respond(request)))
+	}))
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	return server, serverUrl.Hostname(), port
+}
@@ -7,6 +7,7 @@ package keeper
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/interfaces"
 )
@@ -28,3 +29,47 @@ func (_ *emptyAuthenticationInjector) RoundTripper() http.RoundTripper {
 	// Do nothing to the request; used for unit tests
 	return nil
 }
+
+type tokenAuthenticationInjector struct {
+	token string
+}
+
+// NewTokenAuthenticationInjector creates an instance of AuthenticationInjector adding the given bearer token to requests
+func NewTokenAuthenticationInjector(token string) interfaces.AuthenticationInjector {
+	return &tokenAuthenticationInjector{token: token}
+}
+
+func (i *tokenAuthenticationInjector) AddAuthenticationData(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+i.token)
+	return nil
+}
+
+func (_ *tokenAuthenticationInjector) RoundTripper() http.RoundTripper {
+	return nil
+}
+
+type rotatingAuthenticationInjector struct {
+	tokens []string
+	lock   sync.Mutex
+}
+
+// NewRotatingAuthenticationInjector creates an instance of AuthenticationInjector adding the given bearer tokens in
+// turn, the last one being kept once all others have been used, i.e. to simulate an expired token being replaced
+func NewRotatingAuthenticationInjector(tokens ...string) interfaces.AuthenticationInjector {
+	return &rotatingAuthenticationInjector{tokens: tokens}
+}
+
+func (i *rotatingAuthenticationInjector) AddAuthenticationData(req *http.Request) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	req.Header.Set("Authorization", "Bearer "+i.tokens[0])
+	if len(i.tokens) > 1 {
+		i.tokens = i.tokens[1:]
+	}
+	return nil
+}
+
+func (_ *rotatingAuthenticationInjector) RoundTripper() http.RoundTripper {
+	return nil
+}
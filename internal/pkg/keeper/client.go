@@ -8,22 +8,31 @@ package keeper
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	httpClient "github.com/edgexfoundry/go-mod-core-contracts/v3/clients/http"
-	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/interfaces"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
 	dtoCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
-	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/requests"
-	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
 
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/health"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/lifecycle"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/transport"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/watch"
 	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
 )
 
+// noCheckInterval is the interval registered for the services without health check, which Keeper doesn't check
+const noCheckInterval = "10s"
+
 type keeperClient struct {
 	config              *types.Config
+	scheduler           *watch.Scheduler
 	keeperUrl           string
 	serviceKey          string
 	serviceHost         string
@@ -31,14 +40,25 @@ type keeperClient struct {
 	healthCheckRoute    string
 	healthCheckInterval string
 
-	commonClient   interfaces.CommonClient
-	registryClient interfaces.RegistryClient
+	restClient     *restClient
+	registration   lifecycle.Registration
+	verifyInterval time.Duration
+	verifyLock     sync.Mutex
+	verifying      bool
+	// patchUnsupported is set once Keeper rejected a PATCH of a registration, so the next updates are read-modify-PUT
+	patchUnsupported atomic.Bool
+	health           healthReport
+	// serverClock is the time of Keeper, estimated from its responses
+	serverClock *transport.ServerClock
+	// watched is the listing of the registrations shared by the watches of the services
+	watched *watch.Shared[map[string]types.KeeperRegistration]
 }
 
 // NewKeeperClient creates new Keeper Client. Service details are optional, not needed just for configuration, but required if registering
 func NewKeeperClient(registryConfig types.Config) (*keeperClient, error) {
 	client := keeperClient{
 		config:     &registryConfig,
+		scheduler:  watch.NewScheduler(registryConfig.WatchConcurrency),
 		serviceKey: registryConfig.ServiceKey,
 		keeperUrl:  registryConfig.GetRegistryUrl(),
 	}
@@ -51,69 +71,279 @@ func NewKeeperClient(registryConfig types.Config) (*keeperClient, error) {
 		client.healthCheckInterval = registryConfig.CheckInterval
 	}
 
-	// Create the common and registry http clients for invoking APIs from Keeper
-	client.commonClient = httpClient.NewCommonClient(client.keeperUrl, registryConfig.AuthInjector)
-	client.registryClient = httpClient.NewRegistryClient(client.keeperUrl, registryConfig.AuthInjector, registryConfig.EnableNameFieldEscape)
+	// Create the http client for invoking the ping and registry APIs from Keeper, reusing pooled connections across calls
+	httpClient, err := transport.NewClient(registryConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new Keeper Client for %s: %w", client.keeperUrl, err)
+	}
+	client.serverClock = transport.NewServerClock()
+	httpClient.Transport = client.serverClock.Wrap(httpClient.Transport)
+	client.verifyInterval, err = registryConfig.GetRegistrationVerifyInterval()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new Keeper Client for %s: %w", client.keeperUrl, err)
+	}
+
+	retryPolicy, err := newRetryPolicy(registryConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new Keeper Client for %s: %w", client.keeperUrl, err)
+	}
+	client.restClient = newRestClient(client.keeperUrl, httpClient, registryConfig.AuthInjector, registryConfig.GetAccessToken, retryPolicy, registryConfig.EnableNameFieldEscape, registryConfig.GetLoggingClient(), registryConfig.GetTextMapPropagator())
+
+	client.watched = watch.NewShared(client.registrations)
 
 	return &client, nil
 }
 
+// ServerClock returns the time of Keeper, estimated from the Date of its responses
+func (k *keeperClient) ServerClock() types.Clock {
+	return k.serverClock
+}
+
 // IsAlive simply checks if Keeper is up and running at the configured URL
 func (k *keeperClient) IsAlive() bool {
-	if _, err := k.commonClient.Ping(context.Background()); err != nil {
+	return k.IsAliveWithContext(context.Background())
+}
+
+// IsAliveWithContext simply checks if Keeper is up and running at the configured URL, giving up once ctx is done
+func (k *keeperClient) IsAliveWithContext(ctx context.Context) bool {
+	if _, err := k.restClient.Ping(ctx); err != nil {
 		return false
 	}
 	return true
 }
 
-// Register registers the current service with Keeper for discovery and health check
+// Register registers the current service with Keeper for discovery and health check. Keeper doesn't health check
+// services registered with the none check type, and only runs the http checks: the client runs the tcp, grpc and ttl
+// checks of the current service itself and reports its status with the registration.
 func (k *keeperClient) Register() error {
+	return k.RegisterWithContext(context.Background())
+}
+
+// RegisterWithContext registers the current service with Keeper for discovery and health check, aborting once ctx is
+// done. The registration is then verified in the background if a RegistrationVerifyInterval is set.
+func (k *keeperClient) RegisterWithContext(ctx context.Context) error {
+	err := k.registration.Register(func() error {
+		return k.register(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	k.startVerifyingRegistration()
+	k.startReportingHealth()
+	return nil
+}
+
+// startVerifyingRegistration starts verifying the registration of the current service in the background, unless it is
+// already being verified or verifying is disabled
+func (k *keeperClient) startVerifyingRegistration() {
+	if k.verifyInterval <= 0 {
+		return
+	}
+
+	k.verifyLock.Lock()
+	defer k.verifyLock.Unlock()
+
+	if k.verifying {
+		return
+	}
+	k.verifying = true
+	go k.verifyRegistration()
+}
+
+// verifyRegistration checks every verifyInterval that Keeper still has the registration of the current service and
+// restores it when missing, until the service is unregistered. Failures are retried on the next check.
+func (k *keeperClient) verifyRegistration() {
+	ticker := time.NewTicker(k.verifyInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		k.verifyLock.Lock()
+		if k.registration.Desired() != lifecycle.Registered {
+			k.verifying = false
+			k.verifyLock.Unlock()
+			return
+		}
+		k.verifyLock.Unlock()
+
+		ctx := context.Background()
+		_, found, err := k.getRegistration(ctx, k.serviceKey)
+		if err != nil || found {
+			continue
+		}
+
+		// Restore doesn't register again if the service has been unregistered since the check
+		lc := k.config.GetLoggingClient()
+		restored, err := k.registration.Restore(func() error {
+			return k.register(ctx)
+		})
+		switch {
+		case err != nil:
+			lc.Debugf("Failed to restore the %s service registration missing from Keeper, retrying on the next check: %v", k.serviceKey, err)
+		case restored:
+			lc.Debugf("Restored the %s service registration missing from Keeper", k.serviceKey)
+		}
+	}
+}
+
+func (k *keeperClient) register(ctx context.Context) error {
+	checkType := k.config.GetCheckType()
 	if k.serviceKey == "" || k.serviceHost == "" || k.servicePort == 0 ||
-		k.healthCheckRoute == "" || k.healthCheckInterval == "" {
+		(checkType == types.CheckTypeHTTP && (k.healthCheckRoute == "" || k.healthCheckInterval == "")) {
 		return fmt.Errorf("unable to register service with keeper: Service information not set")
 	}
+	if k.clientChecked() {
+		if interval, err := time.ParseDuration(k.healthCheckInterval); err != nil || interval <= 0 {
+			return fmt.Errorf("unable to register service with keeper: invalid check interval '%s' for %s health check", k.healthCheckInterval, checkType)
+		}
+	}
 
-	registrationReq := requests.AddRegistrationRequest{
-		BaseRequest: dtoCommon.BaseRequest{
-			Versionable: dtoCommon.Versionable{ApiVersion: common.ApiVersion},
-		},
-		Registration: dtos.Registration{
-			ServiceId: k.serviceKey,
-			Host:      k.serviceHost,
-			Port:      k.servicePort,
-			HealthCheck: dtos.HealthCheck{
-				Interval: k.healthCheckInterval,
-				Path:     k.healthCheckRoute,
-				Type:     "http",
-			},
-		},
+	if checkType == types.CheckTypeHTTP {
+		if err := k.config.GetCheckOptions(k.serviceKey).Validate(); err != nil {
+			return fmt.Errorf("unable to register service with keeper: %w", err)
+		}
+	}
+	if _, err := k.config.GetDeregisterCriticalAfter(); err != nil {
+		return fmt.Errorf("unable to register service with keeper: %w", err)
+	}
+	// Keeper keys the registrations by service key, so replicas would overwrite each other's registration
+	if k.config.GetServiceInstanceId() != k.serviceKey {
+		return types.Errorf(types.ErrNotSupported, "unable to register service with keeper: Keeper registers a single instance per service key, ServiceInstanceId and GenerateInstanceId aren't supported")
+	}
+	// Keeper drops the fields it doesn't know of, so the metadata would silently be lost
+	if len(k.config.ServiceMetadata) > 0 || len(k.config.ServiceTags) > 0 || len(k.config.ServiceNamedEndpoints) > 0 ||
+		k.config.ServiceZone != "" || k.config.ServiceWeight != 0 {
+		return types.Errorf(types.ErrNotSupported, "unable to register service with keeper: Keeper doesn't store service metadata, tags, named endpoints, zone nor weight")
+	}
+
+	if k.config.GetProbeBeforeRegister() && checkType == types.CheckTypeHTTP {
+		if err := health.Probe(ctx, k.config.GetHealthCheckUrl(), k.config.GetCheckOptions(k.serviceKey)); err != nil {
+			return fmt.Errorf("unable to register service with keeper: %w", err)
+		}
+	}
+
+	registrationReq, err := k.registrationRequest("")
+	if err != nil {
+		return fmt.Errorf("unable to register service with keeper: %w", err)
 	}
 
 	// check if the service registry exists first
-	resp, err := k.registryClient.RegistrationByServiceId(context.Background(), k.serviceKey)
-	if err != nil && resp.StatusCode != http.StatusNotFound {
-		return fmt.Errorf("failed to check the %s service registry status: %v", k.serviceKey, err)
+	existing, found, err := k.getRegistration(ctx, k.serviceKey)
+	if err != nil {
+		return fmt.Errorf("failed to check the %s service registry status: %w", k.serviceKey, err)
 	}
 
 	// call the UpdateRegister to update the registry if the service already exists
 	// otherwise, call Register to create a new registry
-	if resp.StatusCode == http.StatusOK {
-		err := k.registryClient.UpdateRegister(context.Background(), registrationReq)
+	if found {
+		err := k.restClient.UpdateRegister(ctx, registrationReq)
 		if err != nil {
-			return fmt.Errorf("failed to update the %s service registry: %v", k.serviceKey, err)
+			return fmt.Errorf("failed to update the %s service registry: %w", k.serviceKey, err)
 		}
+		k.reportUpdate(existing, registrationReq.Registration)
 	} else {
-		err := k.registryClient.Register(context.Background(), registrationReq)
+		err := k.restClient.Register(ctx, registrationReq)
 		if err != nil {
-			return fmt.Errorf("failed to register the %s service: %v", k.serviceKey, err)
+			return fmt.Errorf("failed to register the %s service: %w", k.serviceKey, err)
 		}
 	}
 
+	// The status of the new registration is left to Keeper, so it is reported on the next check
+	k.resetReportedStatus()
+
 	return nil
 }
 
+// reportUpdate logs the fields of the registration of the current service replaced by Register, and passes them to
+// the OnRegistrationUpdate hook
+func (k *keeperClient) reportUpdate(existing types.KeeperRegistration, registered types.KeeperRegistration) {
+	update := types.DiffRegistrations(k.serviceKey, registrationFields(existing), registrationFields(registered))
+
+	lc := k.config.GetLoggingClient()
+	if len(update.Changes) == 0 {
+		lc.Debugf("Replaced the %s service registration in Keeper, unchanged", k.serviceKey)
+	} else {
+		args := make([]interface{}, 0, 2*len(update.Changes))
+		for _, change := range update.Changes {
+			args = append(args, change.Field, fmt.Sprintf("%s -> %s", change.Old, change.New))
+		}
+		lc.Info(fmt.Sprintf("Updated the %s service registration in Keeper", k.serviceKey), args...)
+	}
+
+	if k.config.OnRegistrationUpdate != nil {
+		k.config.OnRegistrationUpdate(update)
+	}
+}
+
+func registrationFields(registration types.KeeperRegistration) types.RegistrationFields {
+	return types.RegistrationFields{
+		Host:          registration.Host,
+		Port:          registration.Port,
+		CheckType:     registration.HealthCheck.Type,
+		CheckRoute:    registration.HealthCheck.Path,
+		CheckInterval: registration.HealthCheck.Interval,
+	}
+}
+
+// registrationRequest builds the request registering the current service with the given status, left to Keeper if
+// empty, as adjusted by the RegistrationMutator
+func (k *keeperClient) registrationRequest(status types.Status) (types.KeeperRegistrationRequest, error) {
+	registration := types.KeeperRegistration{
+		Registration: dtos.Registration{
+			ServiceId: k.serviceKey,
+			Host:      k.serviceHost,
+			Port:      k.servicePort,
+			HealthCheck: dtos.HealthCheck{
+				Interval: k.registeredCheckInterval(),
+				Path:     k.registeredCheckPath(),
+				Type:     k.registeredCheckType(),
+			},
+			Status: string(status),
+		},
+	}
+	if k.config.GetCheckType() != types.CheckTypeNone {
+		registration.DeregisterCriticalAfter = k.config.DeregisterCriticalAfter
+	}
+	if k.config.RegistrationMutator != nil {
+		if err := k.config.RegistrationMutator(&registration); err != nil {
+			return types.KeeperRegistrationRequest{}, fmt.Errorf("registration mutator failed: %w", err)
+		}
+	}
+
+	return types.KeeperRegistrationRequest{
+		BaseRequest: dtoCommon.BaseRequest{
+			Versionable: dtoCommon.Versionable{ApiVersion: common.ApiVersion},
+		},
+		Registration: registration,
+	}, nil
+}
+
+// registeredCheckPath returns the path of the health check registered for the current service. Keeper requires one for
+// every check type, so the other types than http register the root path, followed by the gRPC service of grpc checks.
+func (k *keeperClient) registeredCheckPath() string {
+	if k.config.GetCheckType() == types.CheckTypeHTTP {
+		return k.healthCheckRoute
+	}
+	return "/" + strings.TrimPrefix(k.healthCheckRoute, "/")
+}
+
+// registeredCheckInterval returns the interval of the health check registered for the current service. Keeper
+// requires one for every check type, so services without health check nor CheckInterval register the
+// noCheckInterval.
+func (k *keeperClient) registeredCheckInterval() string {
+	if k.healthCheckInterval == "" && k.config.GetCheckType() == types.CheckTypeNone {
+		return noCheckInterval
+	}
+	return k.healthCheckInterval
+}
+
 // RegisterCheck registers a health check with Keeper
 func (k *keeperClient) RegisterCheck(id string, name string, notes string, url string, interval string) error {
+	return k.RegisterCheckWithContext(context.Background(), id, name, notes, url, interval)
+}
+
+// RegisterCheckWithContext registers a health check with Keeper
+func (k *keeperClient) RegisterCheckWithContext(_ context.Context, id string, name string, notes string, url string, interval string) error {
 	// keeper combines service discovery and health check into one single register request
 	return nil
 }
@@ -125,89 +355,416 @@ func (k *keeperClient) UnregisterCheck(id string) error {
 
 // Unregister de-registers the current service from Keeper
 func (k *keeperClient) Unregister() error {
-	registrationReq := requests.AddRegistrationRequest{
+	return k.UnregisterWithContext(context.Background())
+}
+
+// UnregisterWithContext de-registers the current service from Keeper, aborting once ctx is done
+func (k *keeperClient) UnregisterWithContext(ctx context.Context) error {
+	return k.registration.Unregister(func() error {
+		return k.unregister(ctx)
+	})
+}
+
+func (k *keeperClient) unregister(ctx context.Context) error {
+	registrationReq, err := k.registrationRequest(types.StatusHalt)
+	if err != nil {
+		return fmt.Errorf("failed to de-register %s: %w", k.serviceKey, err)
+	}
+
+	err = k.restClient.UpdateRegister(ctx, registrationReq)
+	if err != nil {
+		return fmt.Errorf("failed to de-register %s: %w", k.serviceKey, err)
+	}
+
+	return nil
+}
+
+// Decommission permanently retires the target service from Keeper. The registration is first set to HALT so Keeper
+// stops health checking it and discovery stops treating it as available, then it is deleted from the registry.
+func (k *keeperClient) Decommission(ctx context.Context, serviceKey string) error {
+	// Decommissioning the current service must not be undone by restoring its registration
+	if serviceKey == k.serviceKey {
+		return k.registration.Unregister(func() error {
+			return k.decommission(ctx, serviceKey)
+		})
+	}
+
+	return k.decommission(ctx, serviceKey)
+}
+
+func (k *keeperClient) decommission(ctx context.Context, serviceKey string) error {
+	if err := k.halt(ctx, serviceKey); err != nil {
+		return err
+	}
+
+	err := k.restClient.Deregister(ctx, serviceKey)
+	if err != nil {
+		return fmt.Errorf("failed to delete the %s service registry: %w", serviceKey, err)
+	}
+
+	return nil
+}
+
+// Drain sets the registration of the target service to HALT, so Keeper stops health checking it and discovery stops
+// treating it as available, keeping it registered until it is decommissioned
+func (k *keeperClient) Drain(ctx context.Context, serviceKey string) error {
+	return k.halt(ctx, serviceKey)
+}
+
+// halt sets the registration of the target service to HALT, unless already halted
+func (k *keeperClient) halt(ctx context.Context, serviceKey string) error {
+	registration, found, err := k.getRegistration(ctx, serviceKey)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return types.Errorf(types.ErrNotRegistered, "unable to decommission %s: service is not registered", serviceKey)
+	}
+	if types.ParseStatus(registration.Status).IsHalted() {
+		return nil
+	}
+
+	registration.Status = string(types.StatusHalt)
+	registrationReq := types.KeeperRegistrationRequest{
 		BaseRequest: dtoCommon.BaseRequest{
 			Versionable: dtoCommon.Versionable{ApiVersion: common.ApiVersion},
 		},
-		Registration: dtos.Registration{
-			ServiceId: k.serviceKey,
-			Host:      k.serviceHost,
-			Port:      k.servicePort,
-			HealthCheck: dtos.HealthCheck{
-				Interval: k.healthCheckInterval,
-				Path:     k.healthCheckRoute,
-				Type:     "http",
-			},
-			Status: models.Halt,
-		},
+		Registration: registration,
+	}
+
+	err = k.restClient.UpdateRegister(ctx, registrationReq)
+	if err != nil {
+		return fmt.Errorf("failed to halt %s before decommissioning: %w", serviceKey, err)
+	}
+
+	return nil
+}
+
+// UpdateRegistrationWithContext updates only the fields of the patch in the registration of the target service, with
+// a PATCH of the registration when Keeper supports it, otherwise reading the registration, updating it and replacing
+// it, which may undo the updates made by others in between. The registration of the current service is restored from
+// the configuration when it goes missing, so its patched fields only last until then.
+func (k *keeperClient) UpdateRegistrationWithContext(ctx context.Context, serviceKey string, patch types.RegistrationPatch) error {
+	if err := patch.Validate(); err != nil {
+		return fmt.Errorf("unable to update the %s service registration: %w", serviceKey, err)
 	}
 
-	err := k.registryClient.UpdateRegister(context.Background(), registrationReq)
+	if !k.patchUnsupported.Load() {
+		err := k.restClient.PatchRegister(ctx, types.NewKeeperRegistrationPatchRequest(serviceKey, patch))
+		if err == nil {
+			return nil
+		}
+		// Keeper versions without PATCH of the registrations reject it as an unknown route or method. As a 404 may also
+		// be the registration missing, reading it tells which.
+		switch err.Code() {
+		case http.StatusNotFound:
+		case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			k.patchUnsupported.Store(true)
+			k.config.GetLoggingClient().Debugf("Keeper doesn't support updating only some fields of the registrations, replacing them instead")
+		default:
+			return fmt.Errorf("failed to update the %s service registry: %w", serviceKey, err)
+		}
+	}
+
+	registration, found, err := k.getRegistration(ctx, serviceKey)
 	if err != nil {
-		return fmt.Errorf("failed to de-register %s: %v", k.serviceKey, err)
+		return err
+	}
+	if !found {
+		return types.Errorf(types.ErrNotRegistered, "unable to update the %s service registration: service is not registered", serviceKey)
+	}
+
+	registrationReq := types.KeeperRegistrationRequest{
+		BaseRequest: dtoCommon.BaseRequest{
+			Versionable: dtoCommon.Versionable{ApiVersion: common.ApiVersion},
+		},
+		Registration: patch.Apply(registration),
+	}
+	if err := k.restClient.UpdateRegister(ctx, registrationReq); err != nil {
+		return fmt.Errorf("failed to update the %s service registry: %w", serviceKey, err)
 	}
 
 	return nil
 }
 
+// WatchSelf polls Keeper for the registration of the current service and notifies the caller each time it has been
+// modified or deleted by someone else. Watching stops and the returned channel is closed once ctx is cancelled.
+func (k *keeperClient) WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error) {
+	if k.serviceKey == "" || k.serviceHost == "" || k.servicePort == 0 {
+		return nil, fmt.Errorf("unable to watch service registration with keeper: Service information not set")
+	}
+
+	interval, err := k.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	expected := types.ServiceEndpoint{
+		ServiceId: k.serviceKey,
+		Host:      k.serviceHost,
+		Port:      k.servicePort,
+	}
+
+	return watch.Self(ctx, k.scheduler, interval, expected, func() (types.ServiceEndpoint, error) {
+		return k.registeredEndpoint(ctx, k.serviceKey)
+	}), nil
+}
+
+// WatchService polls Keeper for the endpoint of the target service and sends it each time it changes, starting with
+// the current one. An empty endpoint is sent when the service isn't registered (anymore). The watches share a listing
+// of all the registrations made at most every half interval, so watching many services doesn't multiply the requests,
+// unless ctx carries its own authentication. Watching stops and the returned channel is closed once ctx is cancelled.
+func (k *keeperClient) WatchService(ctx context.Context, serviceKey string) (<-chan types.ServiceEndpoint, error) {
+	interval, err := k.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Endpoint(ctx, k.scheduler, interval, func() (types.ServiceEndpoint, error) {
+		if hasRequestAuth(ctx) {
+			return k.registeredEndpoint(ctx, serviceKey)
+		}
+
+		registrations, err := k.watched.Get(ctx, interval/2)
+		if err != nil {
+			return types.ServiceEndpoint{}, err
+		}
+		registration, found := registrations[serviceKey]
+		if !found || types.ParseStatus(registration.Status).IsHalted() {
+			return types.ServiceEndpoint{}, nil
+		}
+		return serviceEndpoint(registration), nil
+	}), nil
+}
+
+// registrations retrieves the registrations of all the services from Keeper, but the de-registered ones, by service key
+func (k *keeperClient) registrations(ctx context.Context) (map[string]types.KeeperRegistration, error) {
+	resp, err := k.restClient.AllRegistry(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all service registrations: %w", err)
+	}
+
+	registrations := make(map[string]types.KeeperRegistration, len(resp.Registrations))
+	for _, registration := range resp.Registrations {
+		registrations[registration.ServiceId] = registration
+	}
+	return registrations, nil
+}
+
+// registeredEndpoint retrieves the endpoint of the target service from Keeper, which is empty when the service isn't
+// registered or has been de-registered
+func (k *keeperClient) registeredEndpoint(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	registration, found, err := k.getRegistration(ctx, serviceKey)
+	if err != nil || !found || types.ParseStatus(registration.Status).IsHalted() {
+		return types.ServiceEndpoint{}, err
+	}
+
+	return serviceEndpoint(registration), nil
+}
+
+// serviceEndpoint returns the endpoint of the registered service, without metadata nor tags as Keeper doesn't store
+// them
+func serviceEndpoint(registration types.KeeperRegistration) types.ServiceEndpoint {
+	return types.ServiceEndpoint{
+		ServiceId: registration.ServiceId,
+		Host:      registration.Host,
+		Port:      registration.Port,
+	}
+}
+
+// TriggerHealthCheck runs the health check of the target service right away, rather than waiting for Keeper's next
+// scheduled check. Keeper doesn't offer to run checks on demand, so the client runs the registered health check itself
+// and the result isn't reflected in the registry until the next scheduled check. TTL checks, and the http checks other
+// services run themselves with their own options, can't be run on demand, so the status they last reported is returned
+// instead.
+func (k *keeperClient) TriggerHealthCheck(ctx context.Context, serviceKey string) (types.HealthCheckResult, error) {
+	registration, found, err := k.getRegistration(ctx, serviceKey)
+	if err != nil {
+		return types.HealthCheckResult{}, err
+	}
+	if !found || types.ParseStatus(registration.Status).IsHalted() {
+		return types.HealthCheckResult{}, types.Errorf(types.ErrNotRegistered, "unable to health check %s: service is not registered", serviceKey)
+	}
+
+	if strings.EqualFold(registration.HealthCheck.Type, types.CheckTypeNone) {
+		return types.HealthCheckResult{Healthy: true, Output: "service registered without health check"}, nil
+	}
+
+	checkType := strings.ToLower(registration.HealthCheck.Type)
+	// The options of the http checks run by the client are only known to the current service
+	if checkType == clientHTTPCheckType && serviceKey == k.serviceKey {
+		checkType = types.CheckTypeHTTP
+	}
+	if checkType == types.CheckTypeTTL || checkType == clientHTTPCheckType {
+		status := types.ParseStatus(registration.Status)
+		return types.HealthCheckResult{Healthy: status.IsUp(), Output: fmt.Sprintf("%s health check last reported %s", checkType, status)}, nil
+	}
+
+	route := registration.HealthCheck.Path
+	if checkType == types.CheckTypeGRPC {
+		route = strings.TrimPrefix(route, "/")
+	}
+	address := net.JoinHostPort(registration.Host, strconv.Itoa(registration.Port))
+	return health.CheckService(ctx, checkType, address, route, k.config.GetCheckOptions(serviceKey)), nil
+}
+
 // GetServiceEndpoint retrieves the port, service ID and host of a known endpoint from Keeper.
 // If this operation is successful and a known endpoint is found, it is returned. Otherwise, an error is returned.
 func (k *keeperClient) GetServiceEndpoint(serviceKey string) (types.ServiceEndpoint, error) {
-	resp, err := k.registryClient.RegistrationByServiceId(context.Background(), serviceKey)
+	return k.GetServiceEndpointWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointWithContext retrieves the port, service ID and host of a known endpoint from Keeper, aborting once
+// ctx is done
+func (k *keeperClient) GetServiceEndpointWithContext(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	resp, err := k.restClient.RegistrationByServiceId(ctx, serviceKey)
 	if err != nil {
-		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %v", serviceKey, err)
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
 	}
 
-	endpoint := types.ServiceEndpoint{
-		ServiceId: serviceKey,
-		Host:      resp.Registration.Host,
-		Port:      resp.Registration.Port,
+	endpoint := serviceEndpoint(resp.Registration)
+	endpoint.ServiceId = serviceKey
+	if err := endpoint.Validate(); err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
 	}
 
 	return endpoint, nil
 }
 
+// GetServiceEndpoints retrieves the endpoint of the target service from Keeper, which registers a single instance per
+// service key
+func (k *keeperClient) GetServiceEndpoints(serviceKey string) ([]types.ServiceEndpoint, error) {
+	return k.GetServiceEndpointsWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointsWithContext retrieves the endpoint of the target service from Keeper, aborting once ctx is done
+func (k *keeperClient) GetServiceEndpointsWithContext(ctx context.Context, serviceKey string) ([]types.ServiceEndpoint, error) {
+	endpoint, err := k.GetServiceEndpointWithContext(ctx, serviceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.ServiceEndpoint{endpoint}, nil
+}
+
 // GetAllServiceEndpoints retrieves all registered endpoints from Keeper.
 func (k *keeperClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return k.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+// GetAllServiceEndpointsWithContext retrieves all registered endpoints from Keeper, aborting once ctx is done
+func (k *keeperClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
 	// filter out registrations with status is HALT which have been deregistered
-	resp, err := k.registryClient.AllRegistry(context.Background(), false)
+	resp, err := k.restClient.AllRegistry(ctx, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all service endpoints: %v", err)
+		return nil, fmt.Errorf("failed to get all service endpoints: %w", err)
 	}
 
 	endpoints := make([]types.ServiceEndpoint, len(resp.Registrations))
 	for idx, r := range resp.Registrations {
-		endpoint := types.ServiceEndpoint{
-			ServiceId: r.ServiceId,
-			Host:      r.Host,
-			Port:      r.Port,
+		endpoints[idx] = serviceEndpoint(r)
+		if err := endpoints[idx].Validate(); err != nil {
+			return nil, fmt.Errorf("failed to get all service endpoints: %w", err)
 		}
-		endpoints[idx] = endpoint
 	}
+	types.SortServiceEndpoints(endpoints, k.config.GetEndpointOrder())
 
 	return endpoints, nil
 }
 
 // IsServiceAvailable checks with Keeper if the target service is registered and healthy
 func (k *keeperClient) IsServiceAvailable(serviceKey string) (bool, error) {
-	resp, err := k.registryClient.RegistrationByServiceId(context.Background(), serviceKey)
-	if err != nil && resp.StatusCode != http.StatusNotFound {
-		return false, fmt.Errorf("failed to get %s service registry: %v", serviceKey, err)
+	return k.IsServiceAvailableWithContext(context.Background(), serviceKey)
+}
+
+// IsServiceAvailableWithContext checks with Keeper if the target service is registered and healthy, aborting once ctx
+// is done
+func (k *keeperClient) IsServiceAvailableWithContext(ctx context.Context, serviceKey string) (bool, error) {
+	registration, found, err := k.getRegistration(ctx, serviceKey)
+	if err != nil {
+		return false, err
 	}
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		if strings.EqualFold(resp.Registration.Status, models.Halt) {
-			return false, fmt.Errorf(" %s service has been unregistered", serviceKey)
-		}
-		if !strings.EqualFold(resp.Registration.Status, "up") {
-			return false, fmt.Errorf(" %s service not healthy...", serviceKey)
+	return k.availability(serviceKey, registration, found)
+}
+
+// IsServicesAvailableWithContext checks the availability of all the target services with a single request for all
+// the registrations, aborting once ctx is done
+func (k *keeperClient) IsServicesAvailableWithContext(ctx context.Context, serviceKeys []string) (map[string]types.ServiceAvailability, error) {
+	resp, err := k.restClient.AllRegistry(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all service registrations: %w", err)
+	}
+
+	registrations := make(map[string]types.KeeperRegistration, len(resp.Registrations))
+	for _, registration := range resp.Registrations {
+		registrations[registration.ServiceId] = registration
+	}
+	availabilities := make(map[string]types.ServiceAvailability, len(serviceKeys))
+	for _, serviceKey := range serviceKeys {
+		registration, found := registrations[serviceKey]
+		serviceAvailability := types.NewServiceAvailability(k.availability(serviceKey, registration, found))
+		// The reported status tells the de-registered services apart from those which never registered
+		if found && !serviceAvailability.Available {
+			serviceAvailability.Status = types.ParseStatus(registration.Status)
 		}
+		availabilities[serviceKey] = serviceAvailability
+	}
+
+	return availabilities, nil
+}
 
+// availability tells whether the service with the given registration, if found, is available, or why it isn't
+func (k *keeperClient) availability(serviceKey string, registration types.KeeperRegistration, found bool) (bool, error) {
+	if !found {
+		return false, types.Errorf(types.ErrNotRegistered, "%s service is not registered. Might not have started... ", serviceKey)
+	}
+
+	if types.ParseStatus(registration.Status).IsHalted() {
+		return false, types.Errorf(types.ErrNotRegistered, " %s service has been unregistered", serviceKey)
+	}
+	// services registered without health check are available as long as they are registered, unless opted out
+	if k.config.GetUncheckedServicesAvailable() && strings.EqualFold(registration.HealthCheck.Type, types.CheckTypeNone) {
 		return true, nil
-	case http.StatusNotFound:
-		return false, fmt.Errorf("%s service is not registered. Might not have started... ", serviceKey)
-	default:
-		return false, fmt.Errorf("failed to check service availability: %s", resp.Message)
 	}
+	if !types.ParseStatus(registration.Status).IsUp() {
+		return false, types.Errorf(types.ErrUnhealthy, " %s service not healthy...", serviceKey)
+	}
+
+	return true, nil
+}
+
+// GetRegistrationWithContext retrieves the complete registration of the target service from Keeper, as is, i.e. the
+// HALT status of the de-registered services, aborting once ctx is done
+func (k *keeperClient) GetRegistrationWithContext(ctx context.Context, serviceKey string) (types.KeeperRegistration, error) {
+	registration, found, err := k.getRegistration(ctx, serviceKey)
+	if err != nil {
+		return types.KeeperRegistration{}, err
+	}
+	if !found {
+		return types.KeeperRegistration{}, types.Errorf(types.ErrNotRegistered, "%s service is not registered", serviceKey)
+	}
+
+	return registration, nil
+}
+
+// getRegistration retrieves the registration of the target service from Keeper, reporting whether it exists.
+// Keeper may signal a missing registration either with a 404 response or with a 404 status code in the response body.
+func (k *keeperClient) getRegistration(ctx context.Context, serviceKey string) (types.KeeperRegistration, bool, error) {
+	resp, err := k.restClient.RegistrationByServiceId(ctx, serviceKey)
+	if err != nil {
+		if err.Code() == http.StatusNotFound {
+			return types.KeeperRegistration{}, false, nil
+		}
+		return types.KeeperRegistration{}, false, fmt.Errorf("failed to get %s service registry: %w", serviceKey, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return types.KeeperRegistration{}, false, nil
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		return types.KeeperRegistration{}, false, fmt.Errorf("failed to get %s service registry: %w", serviceKey, newResponseError(resp.BaseResponse))
+	}
+
+	return resp.Registration, true, nil
 }
@@ -6,19 +6,36 @@
 package keeper
 
 import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	dtoCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
 
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/hostile"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/keepertest"
 	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
 )
 
@@ -34,12 +51,12 @@ var (
 	testRegistryPort = 0
 )
 
-var mockKeeper *MockKeeper
+var mockKeeper *keepertest.Server
 
 func TestMain(m *testing.M) {
 	var testMockServer *httptest.Server
 	if testRegistryHost == "" || testRegistryPort != 59883 {
-		mockKeeper = NewMockKeeper()
+		mockKeeper = keepertest.NewServer()
 		testMockServer = mockKeeper.Start()
 
 		URL, _ := url.Parse(testMockServer.URL)
@@ -61,6 +78,490 @@ func TestIsAlive(t *testing.T) {
 	}
 }
 
+func TestIsAliveReusesConnections(t *testing.T) {
+	connections := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set(common.ContentType, common.ContentTypeJSON)
+		_, _ = writer.Write([]byte("{}"))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections++
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	client, err := NewKeeperClient(types.Config{Host: serverUrl.Hostname(), Port: port, MaxIdleConnsPerHost: 1})
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		require.True(t, client.IsAlive())
+	}
+	require.Equal(t, 1, connections, "Expected all calls to share a single keep-alive connection")
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		traceparent = request.Header.Get("traceparent")
+		writer.Header().Set(common.ContentType, common.ContentTypeJSON)
+		_, _ = writer.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	client, err := NewKeeperClient(types.Config{Host: serverUrl.Hostname(), Port: port, TextMapPropagator: propagation.TraceContext{}})
+	require.NoError(t, err)
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	require.True(t, client.IsAliveWithContext(trace.ContextWithSpanContext(context.Background(), spanContext)))
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceparent)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("response delay can only be scripted against the mock keeper")
+	}
+
+	client, err := NewKeeperClient(types.Config{Host: testRegistryHost, Port: testRegistryPort, RequestTimeout: "100ms"})
+	require.NoError(t, err)
+
+	mockKeeper.Delay(time.Second)
+	defer mockKeeper.Delay(0)
+	require.False(t, client.IsAlive(), "Expected IsAlive to time out before the delayed response")
+
+	mockKeeper.Delay(0)
+	require.True(t, client.IsAlive())
+}
+
+func TestRegistryUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	client, err := NewKeeperClient(types.Config{Host: "127.0.0.1", Port: port})
+	require.NoError(t, err)
+
+	_, err = client.GetServiceEndpoint("core-data")
+	require.ErrorIs(t, err, types.ErrRegistryUnavailable)
+	require.NotErrorIs(t, err, types.ErrNotRegistered)
+}
+
+func TestKeeperError(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("error responses can only be scripted against the mock keeper")
+	}
+
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	failure := dtoCommon.BaseResponse{RequestId: "test-request", Message: "keeper is shutting down", StatusCode: http.StatusServiceUnavailable}
+	require.NoError(t, mockKeeper.SetResponse(http.MethodGet, client.restClient.routes.allRegistrations(), http.StatusServiceUnavailable, failure))
+	defer mockKeeper.ClearResponses()
+
+	ctx := context.WithValue(context.Background(), common.CorrelationHeader, "test-correlation") // nolint: staticcheck
+	_, err := client.GetAllServiceEndpointsWithContext(ctx)
+	require.ErrorIs(t, err, types.ErrRegistryUnavailable)
+
+	var keeperErr *types.KeeperError
+	require.ErrorAs(t, err, &keeperErr)
+	require.Equal(t, types.KeeperError{
+		StatusCode:    http.StatusServiceUnavailable,
+		ErrorCode:     "ServiceUnavailable",
+		RequestId:     "test-request",
+		CorrelationId: "test-correlation",
+		Message:       "keeper is shutting down",
+	}, *keeperErr)
+}
+
+func TestNewKeeperClientInvalidIdleConnTimeout(t *testing.T) {
+	_, err := NewKeeperClient(types.Config{Host: testRegistryHost, Port: testRegistryPort, IdleConnTimeout: "bogus"})
+	require.Error(t, err)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("failures can only be scripted against the mock keeper")
+	}
+
+	client, err := NewKeeperClient(types.Config{
+		Host:             testRegistryHost,
+		Port:             testRegistryPort,
+		RetryMaxAttempts: 3,
+		RetryBaseDelay:   "10ms",
+		RetryJitter:      0.5,
+	})
+	require.NoError(t, err)
+
+	mockKeeper.Fail(2)
+	defer mockKeeper.Fail(0)
+	_, err = client.GetAllServiceEndpoints()
+	require.NoError(t, err, "Expected request to be retried until Keeper recovers")
+
+	mockKeeper.Fail(3)
+	_, err = client.GetAllServiceEndpoints()
+	require.Error(t, err, "Expected request to fail once the attempts are exhausted")
+	require.Contains(t, err.Error(), "503")
+
+	mockKeeper.Fail(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.False(t, client.IsAliveWithContext(ctx), "Expected no retry once the context is done")
+
+	// Requests aren't retried by default
+	client = makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	mockKeeper.Fail(1)
+	require.False(t, client.IsAlive())
+	require.True(t, client.IsAlive())
+}
+
+func TestRetryPolicyPost(t *testing.T) {
+	tests := []struct {
+		name             string
+		response         func() (*http.Response, error)
+		expectedAttempts int
+	}{
+		{"connection refused", func() (*http.Response, error) {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+		}, 3},
+		{"connection reset", func() (*http.Response, error) {
+			return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+		}, 1},
+		{"5xx", func() (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+		}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var posts atomic.Int32
+			client, err := NewKeeperClient(types.Config{
+				Host:                            "edgex-core-keeper",
+				Port:                            59890,
+				ServiceKey:                      getUniqueServiceName(),
+				ServiceHost:                     defaultServiceHost,
+				ServicePort:                     defaultServicePort,
+				CheckType:                       types.CheckTypeNone,
+				RetryMaxAttempts:                3,
+				RetryConnectionRefusedBaseDelay: "1ms",
+				RetryBaseDelay:                  "1ms",
+				RoundTripper: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+					// The service isn't registered yet, so it is registered with a POST request
+					if request.Method != http.MethodPost {
+						return &http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody}, nil
+					}
+					posts.Add(1)
+					return test.response()
+				}),
+			})
+			require.NoError(t, err)
+
+			require.Error(t, client.Register())
+			require.Equal(t, int32(test.expectedAttempts), posts.Load(),
+				"Expected POST to be retried only when it failed before being sent")
+		})
+	}
+}
+
+func TestLoggingClient(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("failures can only be scripted against the mock keeper")
+	}
+
+	lc := &recordingLogger{}
+	client, err := NewKeeperClient(types.Config{
+		Host:             testRegistryHost,
+		Port:             testRegistryPort,
+		RetryMaxAttempts: 2,
+		RetryBaseDelay:   "10ms",
+		LoggingClient:    lc,
+	})
+	require.NoError(t, err)
+
+	mockKeeper.Fail(1)
+	defer mockKeeper.Fail(0)
+	require.True(t, client.IsAlive())
+
+	messages := lc.messages()
+	require.Len(t, messages, 5)
+	require.Contains(t, messages[0], "Sending Keeper request GET")
+	require.Contains(t, messages[1], "with status code 503")
+	require.Contains(t, messages[2], "Retrying Keeper request GET")
+	require.Contains(t, messages[2], "after attempt 1 of 2")
+	require.Contains(t, messages[4], "with status code 200")
+}
+
+// recordingLogger records the debug and trace messages it is asked to log
+type recordingLogger struct {
+	logger.MockLogger
+	lock   sync.Mutex
+	logged []string
+}
+
+func (l *recordingLogger) Debugf(msg string, args ...any) {
+	l.record(msg, args...)
+}
+
+func (l *recordingLogger) Tracef(msg string, args ...any) {
+	l.record(msg, args...)
+}
+
+func (l *recordingLogger) record(msg string, args ...any) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.logged = append(l.logged, fmt.Sprintf(msg, args...))
+}
+
+func (l *recordingLogger) messages() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return append([]string(nil), l.logged...)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestRetryPolicyTransportFailures(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              error
+		expectedAttempts int
+	}{
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, 3},
+		{"DNS temporary failure", &net.DNSError{Err: "server misbehaving", Name: "edgex-core-keeper", IsTemporary: true}, 3},
+		{"DNS not found", &net.DNSError{Err: "no such host", Name: "edgex-core-keeper", IsNotFound: true}, 1},
+		{"TLS", x509.UnknownAuthorityError{}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attempts := 0
+			client, err := NewKeeperClient(types.Config{
+				Host:                            "edgex-core-keeper",
+				Port:                            59890,
+				RetryMaxAttempts:                3,
+				RetryConnectionRefusedBaseDelay: "1ms",
+				RetryBaseDelay:                  "1ms",
+				RoundTripper: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+					attempts++
+					return nil, test.err
+				}),
+			})
+			require.NoError(t, err)
+
+			_, err = client.GetAllServiceEndpoints()
+			require.ErrorIs(t, err, types.ErrRegistryUnavailable)
+			require.Equal(t, test.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestNewKeeperClientInvalidRetryPolicy(t *testing.T) {
+	_, err := NewKeeperClient(types.Config{Host: testRegistryHost, Port: testRegistryPort, RetryBaseDelay: "bogus"})
+	require.Error(t, err)
+
+	_, err = NewKeeperClient(types.Config{Host: testRegistryHost, Port: testRegistryPort, RetryConnectionRefusedBaseDelay: "bogus"})
+	require.Error(t, err)
+
+	_, err = NewKeeperClient(types.Config{Host: testRegistryHost, Port: testRegistryPort, RetryJitter: 1.5})
+	require.Error(t, err)
+}
+
+func TestRegistrationVerify(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("registrations can only be dropped by the mock keeper")
+	}
+
+	client, err := NewKeeperClient(types.Config{
+		Host:                       testRegistryHost,
+		Port:                       testRegistryPort,
+		CheckInterval:              "1s",
+		CheckRoute:                 common.ApiPingRoute,
+		ServiceKey:                 getUniqueServiceName(),
+		ServiceHost:                defaultServiceHost,
+		ServicePort:                defaultServicePort,
+		RegistrationVerifyInterval: "10ms",
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Register())
+
+	registered := func() bool {
+		_, found, err := client.getRegistration(context.Background(), client.serviceKey)
+		return err == nil && found
+	}
+
+	mockKeeper.Forget(client.serviceKey)
+	require.Eventually(t, registered, time.Second, 10*time.Millisecond, "Expected lost registration to be restored")
+
+	require.NoError(t, client.Unregister())
+	mockKeeper.Forget(client.serviceKey)
+	time.Sleep(50 * time.Millisecond)
+	require.False(t, registered(), "Expected unregistered service not to be registered again")
+}
+
+func TestNewKeeperClientInvalidRegistrationVerifyInterval(t *testing.T) {
+	_, err := NewKeeperClient(types.Config{Host: testRegistryHost, Port: testRegistryPort, RegistrationVerifyInterval: "bogus"})
+	require.Error(t, err)
+}
+
+func TestGetAllServiceEndpointsOrder(t *testing.T) {
+	prefix := getUniqueServiceName()
+	for i, name := range []string{prefix + "-c", prefix + "-a", prefix + "-b"} {
+		port := defaultServicePort - i
+		client := makeKeeperClient(t, name, defaultServiceHost, port, true)
+		require.NoError(t, client.Register())
+		defer func() { _ = client.Unregister() }()
+	}
+
+	client := makeKeeperClient(t, prefix, defaultServiceHost, defaultServicePort, true)
+	endpoints, err := client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	require.True(t, slices.IsSortedFunc(endpoints, types.ByServiceId), "Expected endpoints sorted by service id")
+
+	client.config.EndpointOrder = types.ByAddress
+	endpoints, err = client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	require.True(t, slices.IsSortedFunc(endpoints, types.ByAddress), "Expected endpoints sorted by address")
+}
+
+func TestAuthInjector(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("authorization can only be enforced by the mock keeper")
+	}
+
+	mockKeeper.SetExpectedAuthorization("Bearer test-token")
+	defer mockKeeper.ClearExpectedAuthorization()
+
+	unauthorized := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	require.False(t, unauthorized.IsAlive(), "Expected requests without token to be rejected")
+	require.Error(t, unauthorized.Register(), "Expected requests without token to be rejected")
+
+	client, err := NewKeeperClient(types.Config{
+		Host:          testRegistryHost,
+		Port:          testRegistryPort,
+		CheckInterval: "1s",
+		CheckRoute:    common.ApiPingRoute,
+		ServiceKey:    getUniqueServiceName(),
+		ServiceHost:   defaultServiceHost,
+		ServicePort:   defaultServicePort,
+		AuthInjector:  NewTokenAuthenticationInjector("test-token"),
+	})
+	require.NoError(t, err)
+
+	require.True(t, client.IsAlive())
+	require.NoError(t, client.Register())
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+	_, err = client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.Error(t, err, "Expected service without health check server to be unhealthy")
+	require.Contains(t, err.Error(), "service not healthy", "Expected request to be authorized")
+	require.NoError(t, client.Unregister())
+	require.NoError(t, client.Decommission(context.Background(), client.serviceKey))
+}
+
+func TestRenewAccessToken(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("authorization can only be enforced by the mock keeper")
+	}
+
+	mockKeeper.SetExpectedAuthorization("Bearer fresh-token")
+	defer mockKeeper.ClearExpectedAuthorization()
+
+	renewals := 0
+	client, err := NewKeeperClient(types.Config{
+		Host:         testRegistryHost,
+		Port:         testRegistryPort,
+		AuthInjector: NewTokenAuthenticationInjector("expired-token"),
+		GetAccessToken: func() (string, error) {
+			renewals++
+			return "fresh-token", nil
+		},
+	})
+	require.NoError(t, err)
+
+	require.True(t, client.IsAlive(), "Expected request to be retried with the renewed token")
+	require.True(t, client.IsAlive())
+	require.Equal(t, 1, renewals, "Expected the renewed token to be kept for subsequent requests")
+
+	// Without callback, the retry gets fresh authentication data from the AuthInjector
+	client, err = NewKeeperClient(types.Config{
+		Host:         testRegistryHost,
+		Port:         testRegistryPort,
+		AuthInjector: NewRotatingAuthenticationInjector("expired-token", "fresh-token"),
+	})
+	require.NoError(t, err)
+	require.True(t, client.IsAlive(), "Expected request to be retried with the rotated token")
+
+	client, err = NewKeeperClient(types.Config{
+		Host:         testRegistryHost,
+		Port:         testRegistryPort,
+		AuthInjector: NewTokenAuthenticationInjector("expired-token"),
+		GetAccessToken: func() (string, error) {
+			return "", errors.New("secret store unavailable")
+		},
+	})
+	require.NoError(t, err)
+	_, err = client.GetAllServiceEndpoints()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to renew access token")
+}
+
+func TestRequestAuthOverride(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("authorization can only be enforced by the mock keeper")
+	}
+
+	mockKeeper.SetExpectedAuthorization("Bearer request-token")
+	defer mockKeeper.ClearExpectedAuthorization()
+
+	renewals := 0
+	client, err := NewKeeperClient(types.Config{
+		Host:         testRegistryHost,
+		Port:         testRegistryPort,
+		AuthInjector: NewTokenAuthenticationInjector("client-token"),
+		GetAccessToken: func() (string, error) {
+			renewals++
+			return "renewed-token", nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := types.WithAccessToken(context.Background(), "request-token")
+	_, err = client.GetAllServiceEndpointsWithContext(ctx)
+	require.NoError(t, err, "Expected the token from the context to override the AuthInjector")
+
+	ctx = types.WithAuthInjector(context.Background(), NewTokenAuthenticationInjector("request-token"))
+	_, err = client.GetAllServiceEndpointsWithContext(ctx)
+	require.NoError(t, err, "Expected the AuthInjector from the context to override the one of the client")
+
+	ctx = types.WithAccessToken(context.Background(), "wrong-token")
+	_, err = client.GetAllServiceEndpointsWithContext(ctx)
+	require.Error(t, err)
+	require.Zero(t, renewals, "Expected the token of the client not to be renewed for a request overriding it")
+
+	require.False(t, client.IsAlive(), "Expected the override to apply to a single call only")
+}
+
+func TestNilAuthInjector(t *testing.T) {
+	client, err := NewKeeperClient(types.Config{Host: testRegistryHost, Port: testRegistryPort})
+	require.NoError(t, err)
+	require.True(t, client.IsAlive(), "Expected client without AuthInjector to send unauthenticated requests")
+}
+
 func TestRegisterNoServiceInfoError(t *testing.T) {
 	// Don't set the service info so check for info results in error
 	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, false)
@@ -69,6 +570,281 @@ func TestRegisterNoServiceInfoError(t *testing.T) {
 	require.Error(t, err, "Expected error due to no service info")
 }
 
+func TestRegisterWithoutHealthCheck(t *testing.T) {
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	client.config.CheckType = types.CheckTypeNone
+	client.healthCheckRoute = ""
+	client.healthCheckInterval = ""
+
+	// Try to clean-up after test
+	defer func() {
+		_ = client.Unregister()
+	}()
+
+	err := client.Register()
+	require.NoError(t, err)
+
+	actual, err := client.IsServiceAvailable(client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, actual, "Expected service without health check to be available once registered")
+
+	uncheckedServicesAvailable := false
+	client.config.UncheckedServicesAvailable = &uncheckedServicesAvailable
+	actual, err = client.IsServiceAvailable(client.serviceKey)
+	require.ErrorIs(t, err, types.ErrUnhealthy, "Expected service without health check to be unhealthy once opted out")
+	require.False(t, actual)
+}
+
+func TestRegisterTCPHealthCheck(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("Keeper only runs http health checks, the client reported status is only checked against the mock keeper")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	client := makeKeeperClient(t, getUniqueServiceName(), "127.0.0.1", port, true)
+	client.config.CheckType = types.CheckTypeTCP
+	client.healthCheckRoute = ""
+	client.healthCheckInterval = "10ms"
+	defer func() { _ = client.Unregister() }()
+
+	require.NoError(t, client.Register())
+	require.Eventually(t, func() bool {
+		available, _ := client.IsServiceAvailable(client.serviceKey)
+		return available
+	}, time.Second, 10*time.Millisecond, "Expected service accepting connections to be reported healthy")
+
+	registration, err := client.GetRegistrationWithContext(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, "/", registration.HealthCheck.Path, "Expected the root path registered, as Keeper requires one")
+	result, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, result.Healthy, result.Output)
+
+	_ = listener.Close()
+	require.Eventually(t, func() bool {
+		_, err := client.IsServiceAvailable(client.serviceKey)
+		return errors.Is(err, types.ErrUnhealthy)
+	}, time.Second, 10*time.Millisecond, "Expected service refusing connections to be reported unhealthy")
+}
+
+func TestRegisterTTLHealthCheck(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("Keeper only runs http health checks, the client reported status is only checked against the mock keeper")
+	}
+
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	client.config.CheckType = types.CheckTypeTTL
+	client.healthCheckRoute = ""
+	client.healthCheckInterval = "50ms"
+	defer func() { _ = client.Unregister() }()
+
+	require.Error(t, client.PassTTL(context.Background()), "Expected error for service not registered")
+	require.NoError(t, client.Register())
+	require.Eventually(t, func() bool {
+		_, err := client.IsServiceAvailable(client.serviceKey)
+		return errors.Is(err, types.ErrUnhealthy)
+	}, time.Second, 10*time.Millisecond, "Expected service to be unhealthy until it passes its TTL")
+
+	require.NoError(t, client.PassTTL(context.Background()))
+	available, err := client.IsServiceAvailable(client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, available, "Expected service to be healthy right after passing its TTL")
+
+	require.Eventually(t, func() bool {
+		_, err := client.IsServiceAvailable(client.serviceKey)
+		return errors.Is(err, types.ErrUnhealthy)
+	}, time.Second, 10*time.Millisecond, "Expected service to be unhealthy once its TTL expired")
+
+	require.NoError(t, client.PassTTL(context.Background()))
+	require.NoError(t, client.FailTTL(context.Background(), "database unreachable"))
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.ErrorIs(t, err, types.ErrUnhealthy, "Expected service to be unhealthy right after failing its TTL")
+}
+
+func TestRegisterHealthCheckOptions(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("Keeper only runs http health checks, the client reported status is only checked against the mock keeper")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodHead || request.Header.Get("Authorization") != "Bearer secret" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	client := makeKeeperClient(t, getUniqueServiceName(), serverUrl.Hostname(), port, true)
+	require.NoError(t, client.Register())
+	_, err := client.IsServiceAvailable(client.serviceKey)
+	require.ErrorIs(t, err, types.ErrUnhealthy, "Expected check route requiring a bearer token to fail without it")
+	require.NoError(t, client.Unregister())
+
+	client = makeKeeperClient(t, getUniqueServiceName(), serverUrl.Hostname(), port, true)
+	defer func() { _ = client.Unregister() }()
+	client.config.CheckMethod = http.MethodHead
+	client.config.CheckHeaders = map[string]string{"Authorization": "Bearer secret"}
+	client.config.CheckStatusCodes = []int{http.StatusNoContent}
+	client.healthCheckInterval = "10ms"
+	require.NoError(t, client.Register())
+	require.Eventually(t, func() bool {
+		available, _ := client.IsServiceAvailable(client.serviceKey)
+		return available
+	}, time.Second, 10*time.Millisecond, "Expected the client to health check the service with its options")
+
+	registration, err := client.GetRegistrationWithContext(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, clientHTTPCheckType, registration.HealthCheck.Type, "Expected Keeper not to run the check itself")
+	encoded, err := json.Marshal(registration)
+	require.NoError(t, err)
+	require.NotContains(t, string(encoded), "secret", "Expected the token of the check not to be registered")
+
+	result, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, result.Healthy, result.Output)
+}
+
+func TestRegisterUpdate(t *testing.T) {
+	var updates []types.RegistrationUpdate
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	client.config.OnRegistrationUpdate = func(update types.RegistrationUpdate) {
+		updates = append(updates, update)
+	}
+	defer func() { _ = client.Unregister() }()
+
+	require.NoError(t, client.Register())
+	require.Empty(t, updates, "Expected no update for a new registration")
+
+	require.NoError(t, client.Register())
+	require.Len(t, updates, 1)
+	require.Empty(t, updates[0].Changes, "Expected registration replaced as is to have no change")
+
+	client.servicePort = defaultServicePort + 1
+	client.healthCheckInterval = "5s"
+	require.NoError(t, client.Register())
+	require.Len(t, updates, 2)
+	require.Equal(t, client.serviceKey, updates[1].ServiceKey)
+	require.Equal(t, []types.RegistrationFieldChange{
+		{Field: "port", Old: strconv.Itoa(defaultServicePort), New: strconv.Itoa(defaultServicePort + 1)},
+		{Field: "checkInterval", Old: "1s", New: "5s"},
+	}, updates[1].Changes)
+}
+
+func TestRegisterDeregisterCriticalAfter(t *testing.T) {
+	var registration types.KeeperRegistrationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			writer.WriteHeader(http.StatusNotFound)
+		case http.MethodPost:
+			_ = json.NewDecoder(request.Body).Decode(&registration)
+			writer.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	client, err := NewKeeperClient(types.Config{
+		Host:                    serverUrl.Hostname(),
+		Port:                    port,
+		ServiceKey:              getUniqueServiceName(),
+		ServiceHost:             defaultServiceHost,
+		ServicePort:             defaultServicePort,
+		CheckRoute:              common.ApiPingRoute,
+		CheckInterval:           "1s",
+		DeregisterCriticalAfter: "30m",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, client.Register())
+	require.Equal(t, "30m", registration.Registration.DeregisterCriticalAfter)
+}
+
+func TestRegisterRegistrationMutator(t *testing.T) {
+	var registration map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			writer.WriteHeader(http.StatusNotFound)
+		case http.MethodPost:
+			var body map[string]map[string]any
+			_ = json.NewDecoder(request.Body).Decode(&body)
+			registration = body["registration"]
+			writer.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	config := types.Config{
+		Host:          serverUrl.Hostname(),
+		Port:          port,
+		ServiceKey:    getUniqueServiceName(),
+		ServiceHost:   defaultServiceHost,
+		ServicePort:   defaultServicePort,
+		CheckRoute:    common.ApiPingRoute,
+		CheckInterval: "1s",
+		RegistrationMutator: func(registration *types.KeeperRegistration) error {
+			registration.Host = "edgex-" + registration.Host
+			registration.Extensions = map[string]any{"site": "plant-7"}
+			return nil
+		},
+	}
+	client, err := NewKeeperClient(config)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Register())
+	require.Equal(t, "edgex-"+defaultServiceHost, registration["host"])
+	require.Equal(t, "plant-7", registration["site"])
+
+	config.RegistrationMutator = func(*types.KeeperRegistration) error { return errors.New("site unknown") }
+	client, err = NewKeeperClient(config)
+	require.NoError(t, err)
+	require.ErrorContains(t, client.Register(), "registration mutator failed: site unknown")
+}
+
+func TestRegisterInvalidHealthCheckOptions(t *testing.T) {
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	client.config.CheckMethod = http.MethodPost
+
+	err := client.Register()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported method 'POST'")
+}
+
+func TestRegisterInvalidCheckInterval(t *testing.T) {
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	client.config.CheckType = types.CheckTypeGRPC
+	client.healthCheckInterval = ""
+
+	err := client.Register()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid check interval")
+}
+
+func TestRegisterProbeFailure(t *testing.T) {
+	// Nothing is serving the health check route of the service
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	probeBeforeRegister := true
+	client.config.ProbeBeforeRegister = &probeBeforeRegister
+
+	err := client.Register()
+	require.Error(t, err, "Expected error due to failed health check probe")
+
+	actual, err := client.IsServiceAvailable(client.serviceKey)
+	require.False(t, actual)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "service is not registered", "Expected service not to be registered")
+}
+
 func TestRegisterWithPingCallback(t *testing.T) {
 	doneChan := make(chan bool, 1)
 	receivedPing := false
@@ -135,6 +911,22 @@ func TestDuplicateRegister(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRegisterWithContextCancelled(t *testing.T) {
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.RegisterWithContext(ctx)
+	require.Error(t, err, "Expected error registering service with cancelled context")
+	require.Contains(t, err.Error(), context.Canceled.Error())
+	require.False(t, client.IsAliveWithContext(ctx))
+
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.Error(t, err, "Expected service not to be registered")
+	require.Contains(t, err.Error(), "service is not registered", "Wrong error")
+}
+
 func TestUnregister(t *testing.T) {
 	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
 
@@ -151,6 +943,130 @@ func TestUnregister(t *testing.T) {
 	require.NoError(t, err, "Expected no error since service registry still exists after un-registering")
 }
 
+func TestDecommission(t *testing.T) {
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+
+	err := client.Decommission(context.Background(), client.serviceKey)
+	require.Error(t, err, "Expected error decommissioning service that isn't registered")
+
+	err = client.Register()
+	require.NoError(t, err, "Error registering service")
+
+	err = client.Decommission(context.Background(), client.serviceKey)
+	require.NoError(t, err, "Error decommissioning service")
+
+	actual, err := client.IsServiceAvailable(client.serviceKey)
+	require.False(t, actual)
+	require.Error(t, err, "expected error")
+	require.Contains(t, err.Error(), "service is not registered", "Wrong error")
+}
+
+func TestDrain(t *testing.T) {
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	defer func() {
+		_ = client.Unregister()
+	}()
+
+	err := client.Drain(context.Background(), client.serviceKey)
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+
+	err = client.Register()
+	require.NoError(t, err, "Error registering service")
+
+	err = client.Drain(context.Background(), client.serviceKey)
+	require.NoError(t, err, "Error draining service")
+
+	registration, found, err := client.getRegistration(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, found, "Expected the drained service to stay registered")
+	require.True(t, types.ParseStatus(registration.Status).IsHalted())
+}
+
+func TestWatchSelf(t *testing.T) {
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	client.config.WatchInterval = "50ms"
+
+	// Try to clean-up after test
+	defer func() {
+		_ = client.Unregister()
+	}()
+
+	err := client.Register()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.WatchSelf(ctx)
+	require.NoError(t, err)
+
+	// Another instance overwrites the registration with a different port
+	other := makeKeeperClient(t, client.serviceKey, defaultServiceHost, defaultServicePort+1, true)
+	err = other.Register()
+	require.NoError(t, err)
+
+	event := receiveEvent(t, events)
+	require.Equal(t, types.RegistrationModified, event.Type)
+	require.Equal(t, defaultServicePort+1, event.Endpoint.Port)
+
+	err = other.Unregister()
+	require.NoError(t, err)
+
+	event = receiveEvent(t, events)
+	require.Equal(t, types.RegistrationDeleted, event.Type)
+}
+
+func TestWatchService(t *testing.T) {
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	client.config.WatchInterval = "50ms"
+
+	// Try to clean-up after test
+	defer func() {
+		_ = client.Unregister()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	endpoints, err := client.WatchService(ctx, client.serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint for service not registered yet")
+
+	err = client.Register()
+	require.NoError(t, err)
+
+	endpoint := receiveEndpoint(t, endpoints)
+	require.Equal(t, client.serviceKey, endpoint.ServiceId)
+	require.Equal(t, defaultServicePort, endpoint.Port)
+
+	// Another instance overwrites the registration with a different port
+	other := makeKeeperClient(t, client.serviceKey, defaultServiceHost, defaultServicePort+1, true)
+	err = other.Register()
+	require.NoError(t, err)
+
+	endpoint = receiveEndpoint(t, endpoints)
+	require.Equal(t, defaultServicePort+1, endpoint.Port)
+
+	err = other.Unregister()
+	require.NoError(t, err)
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints))
+
+	cancel()
+	select {
+	case _, ok := <-endpoints:
+		require.False(t, ok, "Expected channel to be closed once the context is cancelled")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Channel not closed once the context is cancelled")
+	}
+}
+
+func TestWatchSelfNoServiceInfoError(t *testing.T) {
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, false)
+
+	_, err := client.WatchSelf(context.Background())
+	require.Error(t, err, "Expected error due to no service info")
+}
+
 func TestGetServiceEndpoint(t *testing.T) {
 	uniqueServiceName := getUniqueServiceName()
 	expectedFoundEndpoint := types.ServiceEndpoint{
@@ -185,6 +1101,142 @@ func TestGetServiceEndpoint(t *testing.T) {
 	require.Equal(t, expectedFoundEndpoint, actualEndpoint, "Test for endpoint found result not as expected")
 }
 
+func TestRegisterMetadataNotSupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(config *types.Config)
+	}{
+		{"metadata", func(config *types.Config) { config.ServiceMetadata = map[string]string{"version": "3.1"} }},
+		{"tags", func(config *types.Config) { config.ServiceTags = []string{"modbus-tcp"} }},
+		{"zone", func(config *types.Config) { config.ServiceZone = "zone-a" }},
+		{"weight", func(config *types.Config) { config.ServiceWeight = 9 }},
+		{"named endpoints", func(config *types.Config) {
+			config.ServiceNamedEndpoints = map[string]types.NamedEndpoint{"opcua": {Port: 4840, Scheme: "opc.tcp"}}
+		}},
+		{"instance ID", func(config *types.Config) { config.ServiceInstanceId = config.ServiceKey + "-1" }},
+		{"generated instance ID", func(config *types.Config) { config.GenerateInstanceId = true }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+			test.config(client.config)
+
+			err := client.Register()
+			require.ErrorIs(t, err, types.ErrNotSupported)
+			_, found, err := client.getRegistration(context.Background(), client.serviceKey)
+			require.NoError(t, err)
+			require.False(t, found, "Expected the service not to be registered without its metadata")
+		})
+	}
+}
+
+func TestServiceKeyWithReservedCharacters(t *testing.T) {
+	client := makeKeeperClient(t, "device/onvif?camera#"+getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+	client.config.CheckType = types.CheckTypeNone
+	require.NoError(t, client.Register())
+
+	available, err := client.IsServiceAvailable(client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, available)
+
+	require.NoError(t, client.Unregister())
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.Error(t, err, "Expected service to be unregistered")
+}
+
+func TestIsServicesAvailable(t *testing.T) {
+	prefix := getUniqueServiceName()
+	mockKeeper.AddRegistration(dtos.Registration{ServiceId: prefix + "-up", Host: defaultServiceHost, Port: defaultServicePort, Status: string(types.StatusUp)})
+	mockKeeper.AddRegistration(dtos.Registration{ServiceId: prefix + "-down", Host: defaultServiceHost, Port: defaultServicePort, Status: string(types.StatusDown)})
+
+	var requests atomic.Int32
+	client, err := NewKeeperClient(types.Config{
+		Host: testRegistryHost,
+		Port: testRegistryPort,
+		RoundTripper: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			return http.DefaultTransport.RoundTrip(request)
+		}),
+	})
+	require.NoError(t, err)
+
+	availabilities, err := client.IsServicesAvailableWithContext(context.Background(), []string{prefix + "-up", prefix + "-down", prefix + "-missing"})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load(), "Expected all the services to be checked with a single request")
+
+	require.Len(t, availabilities, 3)
+	require.True(t, availabilities[prefix+"-up"].Available)
+	require.NoError(t, availabilities[prefix+"-up"].Err)
+	require.False(t, availabilities[prefix+"-down"].Available)
+	require.ErrorIs(t, availabilities[prefix+"-down"].Err, types.ErrUnhealthy)
+	require.Equal(t, types.StatusDown, availabilities[prefix+"-down"].Status)
+	require.False(t, availabilities[prefix+"-missing"].Available)
+	require.ErrorIs(t, availabilities[prefix+"-missing"].Err, types.ErrNotRegistered)
+	require.Equal(t, types.StatusUnknown, availabilities[prefix+"-missing"].Status)
+}
+
+func TestGetRegistration(t *testing.T) {
+	serviceKey := getUniqueServiceName()
+	mockKeeper.AddRegistration(dtos.Registration{
+		DBTimestamp: dtos.DBTimestamp{Created: 1712000000000, Modified: 1712000060000},
+		ServiceId:   serviceKey,
+		Host:        defaultServiceHost,
+		Port:        defaultServicePort,
+		Status:      string(types.StatusUp),
+		HealthCheck: dtos.HealthCheck{Interval: "10s", Path: "/api/v3/ping", Type: types.CheckTypeHTTP},
+	})
+	client := makeKeeperClient(t, serviceKey, defaultServiceHost, defaultServicePort, true)
+
+	registration, err := client.GetRegistrationWithContext(context.Background(), serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, "/api/v3/ping", registration.HealthCheck.Path)
+	require.Equal(t, string(types.StatusUp), registration.Status)
+	require.Equal(t, time.UnixMilli(1712000060000), registration.ModifiedAt())
+
+	_, err = client.GetRegistrationWithContext(context.Background(), serviceKey+"-missing")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+}
+
+func TestUpdateRegistration(t *testing.T) {
+	serviceKey := getUniqueServiceName()
+	mockKeeper.AddRegistration(dtos.Registration{
+		ServiceId:   serviceKey,
+		Host:        defaultServiceHost,
+		Port:        defaultServicePort,
+		Status:      string(types.StatusUp),
+		HealthCheck: dtos.HealthCheck{Interval: "10s", Path: "/api/v3/ping", Type: types.CheckTypeHTTP},
+	})
+	client := makeKeeperClient(t, serviceKey, defaultServiceHost, defaultServicePort, true)
+
+	interval := "30s"
+	route := "/api/v3/health"
+	err := client.UpdateRegistrationWithContext(context.Background(), serviceKey, types.RegistrationPatch{CheckInterval: &interval})
+	require.NoError(t, err)
+	registration, err := client.GetRegistrationWithContext(context.Background(), serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, "30s", registration.HealthCheck.Interval)
+	require.Equal(t, "/api/v3/ping", registration.HealthCheck.Path, "Expected the fields left out to be left as registered")
+	require.Equal(t, defaultServicePort, registration.Port)
+	require.False(t, client.patchUnsupported.Load())
+
+	// Keeper versions without PATCH have the registration read, updated and replaced instead
+	require.NoError(t, mockKeeper.SetResponse(http.MethodPatch, client.restClient.routes.registry(), http.StatusMethodNotAllowed, nil))
+	defer mockKeeper.ClearResponses()
+	err = client.UpdateRegistrationWithContext(context.Background(), serviceKey, types.RegistrationPatch{CheckRoute: &route})
+	require.NoError(t, err)
+	registration, err = client.GetRegistrationWithContext(context.Background(), serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, route, registration.HealthCheck.Path)
+	require.Equal(t, "30s", registration.HealthCheck.Interval)
+	require.True(t, client.patchUnsupported.Load())
+
+	err = client.UpdateRegistrationWithContext(context.Background(), serviceKey+"-missing", types.RegistrationPatch{CheckInterval: &interval})
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+
+	err = client.UpdateRegistrationWithContext(context.Background(), serviceKey, types.RegistrationPatch{})
+	require.Error(t, err, "Expected the patch updating no field to be rejected")
+}
+
 func TestIsServiceAvailableNotRegistered(t *testing.T) {
 
 	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
@@ -197,6 +1249,7 @@ func TestIsServiceAvailableNotRegistered(t *testing.T) {
 	require.False(t, actual)
 	require.Error(t, err, "expected error")
 	require.Contains(t, err.Error(), "service has been unregistered", "Wrong error")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
 }
 
 func TestIsServiceAvailableNotHealthy(t *testing.T) {
@@ -222,6 +1275,7 @@ func TestIsServiceAvailableNotHealthy(t *testing.T) {
 	require.False(t, actual)
 	require.Error(t, err, "expected error")
 	require.Contains(t, err.Error(), "service not healthy", "Wrong error")
+	require.ErrorIs(t, err, types.ErrUnhealthy)
 }
 
 func TestIsServiceAvailableHealthy(t *testing.T) {
@@ -271,6 +1325,152 @@ func TestIsServiceAvailableHealthy(t *testing.T) {
 	require.True(t, actual, "IsServiceAvailable result not as expected")
 }
 
+func TestIsServiceAvailableMarkedDown(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("health state can only be scripted against the mock keeper")
+	}
+
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+
+	// Try to clean-up after test
+	defer func() {
+		_ = client.Unregister()
+	}()
+
+	err := client.Register()
+	require.NoError(t, err)
+
+	mockKeeper.MarkUp(client.serviceKey)
+	actual, err := client.IsServiceAvailable(client.serviceKey)
+	require.NoError(t, err, "IsServiceAvailable result not as expected")
+	require.True(t, actual, "IsServiceAvailable result not as expected")
+
+	mockKeeper.MarkDown(client.serviceKey)
+	actual, err = client.IsServiceAvailable(client.serviceKey)
+	require.False(t, actual)
+	require.Error(t, err, "expected error")
+	require.Contains(t, err.Error(), "service not healthy", "Wrong error")
+
+	// Scripted health must not override a de-registered service
+	err = client.Unregister()
+	require.NoError(t, err)
+	mockKeeper.MarkUp(client.serviceKey)
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.Error(t, err, "expected error")
+	require.Contains(t, err.Error(), "service has been unregistered", "Wrong error")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+}
+
+func TestIsServiceAvailableFlapping(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("health state can only be scripted against the mock keeper")
+	}
+
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+
+	// Try to clean-up after test
+	defer func() {
+		_ = client.Unregister()
+	}()
+
+	err := client.Register()
+	require.NoError(t, err)
+
+	period := 200 * time.Millisecond
+	mockKeeper.Flap(client.serviceKey, period)
+
+	actual, _ := client.IsServiceAvailable(client.serviceKey)
+	require.False(t, actual, "Expected service to start flapping DOWN")
+
+	available := func() bool {
+		actual, _ := client.IsServiceAvailable(client.serviceKey)
+		return actual
+	}
+	require.Eventually(t, available, 5*period, period/10, "Expected service to have flapped UP")
+	require.Eventually(t, func() bool { return !available() }, 5*period, period/10, "Expected service to have flapped DOWN again")
+}
+
+func TestDelay(t *testing.T) {
+	if mockKeeper == nil {
+		t.Skip("response delay can only be scripted against the mock keeper")
+	}
+
+	client := makeKeeperClient(t, getUniqueServiceName(), defaultServiceHost, defaultServicePort, true)
+
+	mockKeeper.Delay(time.Second)
+	defer mockKeeper.Delay(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.False(t, client.IsAliveWithContext(ctx), "Expected IsAlive to give up before the delayed response")
+
+	mockKeeper.Delay(0)
+	require.True(t, client.IsAlive())
+}
+
+func TestTriggerHealthCheck(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+
+	// Setup a server to simulate the service for the health check callback
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !healthy.Load() {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = writer.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	// Figure out which port the simulated service is running on.
+	serverUrl, _ := url.Parse(server.URL)
+	serverHost := serverUrl.Hostname()
+	serverPort, _ := strconv.Atoi(serverUrl.Port())
+
+	client := makeKeeperClient(t, getUniqueServiceName(), serverHost, serverPort, true)
+
+	// Try to clean-up after test
+	defer func() {
+		_ = client.Unregister()
+	}()
+
+	_, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.Error(t, err, "Expected error health checking service that isn't registered")
+
+	err = client.Register()
+	require.NoError(t, err)
+
+	result, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.True(t, result.Healthy, result.Output)
+
+	healthy.Store(false)
+	result, err = client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	require.False(t, result.Healthy)
+	require.Contains(t, result.Output, "unexpected status code 503")
+}
+
+func receiveEvent(t *testing.T, events <-chan types.RegistrationEvent) types.RegistrationEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Timed out waiting for registration event")
+		return types.RegistrationEvent{}
+	}
+}
+
+func receiveEndpoint(t *testing.T, endpoints <-chan types.ServiceEndpoint) types.ServiceEndpoint {
+	select {
+	case endpoint := <-endpoints:
+		return endpoint
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Timed out waiting for service endpoint")
+		return types.ServiceEndpoint{}
+	}
+}
+
 func makeKeeperClient(t *testing.T, serviceName string, serviceHost string, servicePort int, setServiceInfo bool) *keeperClient {
 	registryConfig := types.Config{
 		Host:          testRegistryHost,
@@ -295,3 +1495,50 @@ func makeKeeperClient(t *testing.T, serviceName string, serviceHost string, serv
 func getUniqueServiceName() string {
 	return serviceName + strconv.Itoa(time.Now().Nanosecond())
 }
+
+func TestGetServiceEndpointHostileResponses(t *testing.T) {
+	for _, registration := range hostile.Registrations() {
+		t.Run(registration.Name, func(t *testing.T) {
+			server, host, port := hostile.Serve(func(*http.Request) string {
+				return fmt.Sprintf(`{"apiVersion":"v3","statusCode":200,"registration":{"serviceId":%q,%s}}`, serviceName, registration.Fields("host", "port"))
+			})
+			defer server.Close()
+
+			client, err := NewKeeperClient(types.Config{Host: host, Port: port, AuthInjector: NewNullAuthenticationInjector()})
+			require.NoError(t, err)
+
+			endpoint, err := client.GetServiceEndpoint(serviceName)
+			if !registration.Valid {
+				require.ErrorIs(t, err, types.ErrMalformedResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, hostile.ValidHost, endpoint.Host)
+			require.Equal(t, hostile.ValidPort, endpoint.Port)
+		})
+	}
+}
+
+func TestGetAllServiceEndpointsHostileResponses(t *testing.T) {
+	for _, registration := range hostile.Registrations() {
+		t.Run(registration.Name, func(t *testing.T) {
+			server, host, port := hostile.Serve(func(*http.Request) string {
+				return fmt.Sprintf(`{"apiVersion":"v3","statusCode":200,"registrations":[{"serviceId":%q,%s}]}`, serviceName, registration.Fields("host", "port"))
+			})
+			defer server.Close()
+
+			client, err := NewKeeperClient(types.Config{Host: host, Port: port, AuthInjector: NewNullAuthenticationInjector()})
+			require.NoError(t, err)
+
+			endpoints, err := client.GetAllServiceEndpoints()
+			if !registration.Valid {
+				require.ErrorIs(t, err, types.ErrMalformedResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, endpoints, 1)
+			require.Equal(t, hostile.ValidHost, endpoints[0].Host)
+			require.Equal(t, hostile.ValidPort, endpoints[0].Port)
+		})
+	}
+}
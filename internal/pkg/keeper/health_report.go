@@ -0,0 +1,190 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/health"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/lifecycle"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// clientCheckTypes are the health check types Keeper doesn't run itself, so the client checks the current service and
+// reports its status to Keeper
+var clientCheckTypes = map[string]bool{
+	types.CheckTypeTCP:  true,
+	types.CheckTypeGRPC: true,
+	types.CheckTypeTTL:  true,
+}
+
+// clientHTTPCheckType is the check type registered for the http health checks the client runs itself, as Keeper only
+// calls the check route with a plain GET, and its registrations are readable by every service, so the headers
+// carrying a token mustn't be registered
+const clientHTTPCheckType = "http-client"
+
+// clientChecked tells whether the client checks the current service itself rather than Keeper, for the check types
+// Keeper doesn't run and the http checks with other options than the default ones
+func (k *keeperClient) clientChecked() bool {
+	checkType := k.config.GetCheckType()
+	return clientCheckTypes[checkType] || (checkType == types.CheckTypeHTTP && !k.config.GetCheckOptions(k.serviceKey).IsZero())
+}
+
+// registeredCheckType returns the check type registered for the current service
+func (k *keeperClient) registeredCheckType() string {
+	if checkType := k.config.GetCheckType(); checkType != types.CheckTypeHTTP || !k.clientChecked() {
+		return checkType
+	}
+	return clientHTTPCheckType
+}
+
+// healthReport is the state of the health checks the client runs for the current service
+type healthReport struct {
+	// lock serializes the reports, so they reach Keeper in order
+	lock      sync.Mutex
+	reporting bool
+	// reported is the status last reported to Keeper, empty until reported for the current registration
+	reported types.Status
+	// lastPass is the last time the service reported itself healthy with PassTTL
+	lastPass time.Time
+}
+
+// startReportingHealth starts checking the current service and reporting its status to Keeper every check interval in
+// the background, unless Keeper runs its health check or it is already being reported
+func (k *keeperClient) startReportingHealth() {
+	if !k.clientChecked() {
+		return
+	}
+
+	k.health.lock.Lock()
+	defer k.health.lock.Unlock()
+
+	if k.health.reporting {
+		return
+	}
+	k.health.reporting = true
+	go k.reportHealth()
+}
+
+// reportHealth reports the status of the current service right away and then every check interval, until the service is
+// unregistered. Failures to report are retried on the next check.
+func (k *keeperClient) reportHealth() {
+	// The check interval has been validated when registering
+	interval, _ := time.ParseDuration(k.healthCheckInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !k.stillReporting() {
+			return
+		}
+
+		err := k.report(context.Background(), interval)
+		if err != nil {
+			k.config.GetLoggingClient().Debugf("Failed to report the status of the %s service to Keeper, retrying on the next check: %v", k.serviceKey, err)
+		}
+
+		<-ticker.C
+	}
+}
+
+// stillReporting tells whether the status of the current service is still to be reported, which stops once it is
+// unregistered
+func (k *keeperClient) stillReporting() bool {
+	k.health.lock.Lock()
+	defer k.health.lock.Unlock()
+
+	if k.registration.Desired() != lifecycle.Registered {
+		k.health.reporting = false
+		return false
+	}
+	return true
+}
+
+// report checks the current service and reports its status to Keeper if it changed
+func (k *keeperClient) report(ctx context.Context, interval time.Duration) error {
+	k.health.lock.Lock()
+	defer k.health.lock.Unlock()
+
+	status := types.StatusUp
+	if err := k.check(ctx, interval); err != nil {
+		status = types.StatusDown
+	}
+	if status == k.health.reported {
+		return nil
+	}
+
+	registrationReq, err := k.registrationRequest(status)
+	if err != nil {
+		return fmt.Errorf("failed to report the %s service %s: %w", k.serviceKey, status, err)
+	}
+	if err := k.restClient.UpdateRegister(ctx, registrationReq); err != nil {
+		return fmt.Errorf("failed to report the %s service %s: %w", k.serviceKey, status, err)
+	}
+	k.health.reported = status
+	return nil
+}
+
+// check runs the health check of the current service once. Called with the lock held.
+func (k *keeperClient) check(ctx context.Context, interval time.Duration) error {
+	address := net.JoinHostPort(k.serviceHost, strconv.Itoa(k.servicePort))
+
+	switch k.config.GetCheckType() {
+	case types.CheckTypeTTL:
+		if time.Since(k.health.lastPass) > interval {
+			return fmt.Errorf("TTL health check failed: no pass within %s", interval)
+		}
+		return nil
+	case types.CheckTypeHTTP:
+		return health.Probe(ctx, k.config.GetHealthCheckUrl(), k.config.GetCheckOptions(k.serviceKey))
+	default:
+		return health.ProbeService(ctx, k.config.GetCheckType(), address, k.healthCheckRoute, types.HealthCheckOptions{})
+	}
+}
+
+// resetReportedStatus has the status reported again on the next check, i.e. once the registration has been replaced
+func (k *keeperClient) resetReportedStatus() {
+	k.health.lock.Lock()
+	defer k.health.lock.Unlock()
+
+	k.health.reported = ""
+}
+
+// PassTTL reports the current service, registered with the ttl check type, healthy for the next check interval. The
+// status is reported to Keeper right away if it changed.
+func (k *keeperClient) PassTTL(ctx context.Context) error {
+	return k.updateTTL(ctx, time.Now())
+}
+
+// FailTTL reports the current service, registered with the ttl check type, unhealthy right away. Keeper registrations
+// have no health check output, so the output is only logged.
+func (k *keeperClient) FailTTL(ctx context.Context, output string) error {
+	k.config.GetLoggingClient().Debugf("Reporting the %s service unhealthy to Keeper: %s", k.serviceKey, output)
+	return k.updateTTL(ctx, time.Time{})
+}
+
+// updateTTL records the last time the current service passed its TTL health check, zero if it failed it
+func (k *keeperClient) updateTTL(ctx context.Context, lastPass time.Time) error {
+	if k.config.GetCheckType() != types.CheckTypeTTL {
+		return fmt.Errorf("unable to update TTL health check of %s: registered with %s check type", k.serviceKey, k.config.GetCheckType())
+	}
+	if k.registration.Desired() != lifecycle.Registered {
+		return types.Errorf(types.ErrNotRegistered, "unable to update TTL health check of %s: service is not registered", k.serviceKey)
+	}
+
+	k.health.lock.Lock()
+	k.health.lastPass = lastPass
+	k.health.lock.Unlock()
+
+	// The check interval has been validated when registering
+	interval, _ := time.ParseDuration(k.healthCheckInterval)
+	return k.report(ctx, interval)
+}
@@ -0,0 +1,340 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/http/utils"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	dtoCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/errors"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/transport"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+)
+
+// restClient is the REST client for invoking the ping and registry APIs from Core Keeper. It mirrors the
+// go-mod-core-contracts registry client, but binds the caller's context to every request so in-flight calls can be
+// cancelled or bounded by a deadline.
+type restClient struct {
+	baseUrl        string
+	httpClient     *http.Client
+	authInjector   interfaces.AuthenticationInjector
+	getAccessToken types.GetAccessTokenCallback
+	accessToken    string
+	tokenLock      sync.RWMutex
+	retryPolicy    retryPolicy
+	routes         routeBuilder
+	lc             logger.LoggingClient
+	propagator     propagation.TextMapPropagator
+}
+
+// newRestClient creates the REST client sending all its requests through the given shared http.Client, whose transport
+// is built on the secure transport of the authInjector when provided
+func newRestClient(baseUrl string, httpClient *http.Client, authInjector interfaces.AuthenticationInjector, getAccessToken types.GetAccessTokenCallback, retryPolicy retryPolicy, enableNameFieldEscape bool, lc logger.LoggingClient, propagator propagation.TextMapPropagator) *restClient {
+	client := restClient{
+		baseUrl:        baseUrl,
+		httpClient:     httpClient,
+		authInjector:   authInjector,
+		getAccessToken: getAccessToken,
+		retryPolicy:    retryPolicy,
+		routes:         newRouteBuilder(common.ApiVersion, enableNameFieldEscape),
+		lc:             lc,
+		propagator:     propagator,
+	}
+
+	return &client
+}
+
+// Ping checks that Core Keeper is up and responding
+func (rc *restClient) Ping(ctx context.Context) (dtoCommon.PingResponse, errors.EdgeX) {
+	res := dtoCommon.PingResponse{}
+	err := rc.sendRequest(ctx, http.MethodGet, rc.routes.ping(), nil, nil, &res)
+	return res, err
+}
+
+// Register registers a service instance
+func (rc *restClient) Register(ctx context.Context, req types.KeeperRegistrationRequest) errors.EdgeX {
+	return rc.sendRequest(ctx, http.MethodPost, rc.routes.registry(), nil, req, nil)
+}
+
+// UpdateRegister updates the registration data of the service
+func (rc *restClient) UpdateRegister(ctx context.Context, req types.KeeperRegistrationRequest) errors.EdgeX {
+	return rc.sendRequest(ctx, http.MethodPut, rc.routes.registry(), nil, req, nil)
+}
+
+// PatchRegister updates only the given fields of the registration data of the service
+func (rc *restClient) PatchRegister(ctx context.Context, req types.KeeperRegistrationPatchRequest) errors.EdgeX {
+	return rc.sendRequest(ctx, http.MethodPatch, rc.routes.registry(), nil, req, nil)
+}
+
+// RegistrationByServiceId returns the registration data by service id
+func (rc *restClient) RegistrationByServiceId(ctx context.Context, serviceId string) (types.KeeperRegistrationResponse, errors.EdgeX) {
+	res := types.KeeperRegistrationResponse{}
+	err := rc.sendRequest(ctx, http.MethodGet, rc.routes.registrationByServiceId(serviceId), nil, nil, &res)
+	return res, err
+}
+
+// AllRegistry returns the registration data of all registered service
+func (rc *restClient) AllRegistry(ctx context.Context, deregistered bool) (types.KeeperMultiRegistrationsResponse, errors.EdgeX) {
+	requestParams := url.Values{}
+	requestParams.Set(common.Deregistered, strconv.FormatBool(deregistered))
+
+	res := types.KeeperMultiRegistrationsResponse{}
+	err := rc.sendRequest(ctx, http.MethodGet, rc.routes.allRegistrations(), requestParams, nil, &res)
+	return res, err
+}
+
+// Deregister deregisters a service by service id
+func (rc *restClient) Deregister(ctx context.Context, serviceId string) errors.EdgeX {
+	return rc.sendRequest(ctx, http.MethodDelete, rc.routes.registrationByServiceId(serviceId), nil, nil, nil)
+}
+
+// sendRequest sends the request with the optional JSON encoded data to Core Keeper and decodes the JSON response into
+// result when it is not nil. Non 2xx responses are returned as errors of the kind matching the status code. A request
+// rejected with 401 or 403, i.e. due to an expired token, is retried once with renewed authentication data, unless
+// its authentication data is overridden by the context. Requests failing with a transient error are retried according
+// to the retryPolicy.
+func (rc *restClient) sendRequest(ctx context.Context, method string, requestPath string, requestParams url.Values, data any, result any) errors.EdgeX {
+	fullPath, err := url.JoinPath(rc.baseUrl, requestPath)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, "failed to parse baseUrl and requestPath", err)
+	}
+	u, err := url.Parse(fullPath)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, "failed to parse baseUrl and requestPath", err)
+	}
+	if requestParams != nil {
+		u.RawQuery = requestParams.Encode()
+	}
+
+	var jsonEncodedData []byte
+	if data != nil {
+		jsonEncodedData, err = json.Marshal(data)
+		if err != nil {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to encode input data to JSON", err)
+		}
+	}
+
+	// All the attempts of the request share the same Correlation ID, so Keeper logs them along with each other
+	correlation := correlationId(ctx)
+	statusCode, bodyBytes, edgexErr := rc.sendWithRetries(ctx, method, u.String(), correlation, jsonEncodedData)
+	if edgexErr == nil && (statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden) && !hasRequestAuth(ctx) {
+		rc.lc.Debugf("Keeper rejected %s %s with status code %d, renewing authentication data (correlation ID %s)", method, requestPath, statusCode, correlation)
+		if edgexErr = rc.renewAccessToken(); edgexErr == nil {
+			statusCode, bodyBytes, edgexErr = rc.sendWithRetries(ctx, method, u.String(), correlation, jsonEncodedData)
+		}
+	}
+	if edgexErr != nil {
+		return edgexErr
+	}
+
+	if statusCode > http.StatusMultiStatus {
+		return errors.NewCommonEdgeX(errors.KindMapping(statusCode), "", newKeeperError(statusCode, correlation, bodyBytes))
+	}
+
+	if result != nil && len(bodyBytes) > 0 {
+		if err := json.Unmarshal(bodyBytes, result); err != nil {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to parse the response body", transport.Malformed(err))
+		}
+	}
+
+	return nil
+}
+
+// sendWithRetries sends the request until it succeeds, fails with an error which isn't transient, the attempts of the
+// retryPolicy are exhausted or ctx is done, and returns the status code and body of the last response. POST requests
+// aren't idempotent, so they are only retried when they failed before being sent.
+func (rc *restClient) sendWithRetries(ctx context.Context, method string, requestUrl string, correlation string, jsonEncodedData []byte) (int, []byte, errors.EdgeX) {
+	for retry := 1; ; retry++ {
+		statusCode, bodyBytes, edgexErr := rc.send(ctx, method, requestUrl, correlation, jsonEncodedData)
+		failure := types.ClassifyTransportFailure(edgexErr)
+		if retry >= rc.retryPolicy.maxAttempts || !isTransient(statusCode, edgexErr, failure) ||
+			(method == http.MethodPost && !isUnsent(failure)) {
+			return statusCode, bodyBytes, edgexErr
+		}
+
+		rc.lc.Debugf("Retrying Keeper request %s %s after attempt %d of %d failed (correlation ID %s)", method, requestUrl, retry, rc.retryPolicy.maxAttempts, correlation)
+		if !rc.retryPolicy.wait(ctx, retry, failure) {
+			return statusCode, bodyBytes, edgexErr
+		}
+	}
+}
+
+// isTransient tells whether the request failed because Keeper is unreachable or unable to handle it at the moment,
+// i.e. while restarting, so the request may succeed if retried. Host names which don't exist and failed TLS handshakes
+// aren't transient, they stay so until the configuration is fixed, and neither is the circuit breaker being open,
+// which is for failing fast.
+func isTransient(statusCode int, edgexErr errors.EdgeX, failure types.TransportFailure) bool {
+	if edgexErr != nil {
+		return errors.Kind(edgexErr) == errors.KindServiceUnavailable &&
+			failure != types.TransportFailureDNSNotFound && failure != types.TransportFailureTLS &&
+			failure != types.TransportFailureCircuitOpen
+	}
+	return statusCode >= http.StatusInternalServerError
+}
+
+// isUnsent tells whether the request failed to connect to Keeper, so it wasn't sent. Requests timing out or whose
+// connection was reset may have been handled by Keeper nonetheless.
+func isUnsent(failure types.TransportFailure) bool {
+	return failure == types.TransportFailureConnectionRefused || failure == types.TransportFailureDNS
+}
+
+// send sends a single attempt of the request and returns the status code and body of the response
+func (rc *restClient) send(ctx context.Context, method string, requestUrl string, correlation string, jsonEncodedData []byte) (int, []byte, errors.EdgeX) {
+	var body io.Reader
+	if jsonEncodedData != nil {
+		body = bytes.NewReader(jsonEncodedData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestUrl, body)
+	if err != nil {
+		return 0, nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to create a http request", err)
+	}
+	if jsonEncodedData != nil {
+		req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	}
+	req.Header.Set(common.CorrelationHeader, correlation)
+	rc.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	if edgexErr := rc.addAuthenticationData(ctx, req); edgexErr != nil {
+		return 0, nil, edgexErr
+	}
+
+	rc.lc.Tracef("Sending Keeper request %s %s (correlation ID %s)", method, requestUrl, correlation)
+	start := time.Now()
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		rc.lc.Debugf("Keeper request %s %s failed after %s: %v (correlation ID %s)", method, requestUrl, time.Since(start), err, correlation)
+		return 0, nil, errors.NewCommonEdgeX(errors.KindServiceUnavailable, "failed to send a http request", transport.Unavailable(err))
+	}
+	defer resp.Body.Close()
+	rc.lc.Debugf("Keeper responded to %s %s with status code %d in %s (correlation ID %s)", method, requestUrl, resp.StatusCode, time.Since(start), correlation)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, errors.NewCommonEdgeX(errors.KindIOError, "failed to get the body from the response", err)
+	}
+
+	return resp.StatusCode, bodyBytes, nil
+}
+
+// addAuthenticationData authenticates the request with the access token or AuthenticationInjector carried by ctx if
+// any, otherwise with the authInjector and access token of the client
+func (rc *restClient) addAuthenticationData(ctx context.Context, req *http.Request) errors.EdgeX {
+	if accessToken, ok := types.AccessTokenFromContext(ctx); ok {
+		req.Header.Set(authorizationHeader, bearerPrefix+accessToken)
+		return nil
+	}
+
+	if authInjector, ok := types.AuthInjectorFromContext(ctx); ok {
+		if err := authInjector.AddAuthenticationData(req); err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+		return nil
+	}
+
+	if rc.authInjector != nil {
+		if err := rc.authInjector.AddAuthenticationData(req); err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+	}
+
+	// A token renewed after a rejected request supersedes the one from the authInjector
+	if accessToken := rc.currentAccessToken(); accessToken != "" {
+		req.Header.Set(authorizationHeader, bearerPrefix+accessToken)
+	}
+	return nil
+}
+
+// hasRequestAuth tells whether the authentication data of the requests made with ctx is overridden by the context
+func hasRequestAuth(ctx context.Context) bool {
+	_, hasAccessToken := types.AccessTokenFromContext(ctx)
+	_, hasAuthInjector := types.AuthInjectorFromContext(ctx)
+	return hasAccessToken || hasAuthInjector
+}
+
+// renewAccessToken gets a new access token from the GetAccessToken callback for the retry of a rejected request. The
+// retry gets fresh authentication data from the authInjector as well, so there is nothing to renew without callback.
+func (rc *restClient) renewAccessToken() errors.EdgeX {
+	if rc.getAccessToken == nil {
+		return nil
+	}
+
+	accessToken, err := rc.getAccessToken()
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindNotAllowed, "failed to renew access token", err)
+	}
+
+	rc.tokenLock.Lock()
+	defer rc.tokenLock.Unlock()
+	rc.accessToken = accessToken
+
+	return nil
+}
+
+func (rc *restClient) currentAccessToken() string {
+	rc.tokenLock.RLock()
+	defer rc.tokenLock.RUnlock()
+
+	return rc.accessToken
+}
+
+// newKeeperError creates the error of a non 2xx response from its status code and body, whose message and request ID
+// are only known when it is a Keeper response
+func newKeeperError(statusCode int, correlation string, bodyBytes []byte) *types.KeeperError {
+	keeperErr := types.KeeperError{
+		StatusCode:    statusCode,
+		ErrorCode:     string(errors.KindMapping(statusCode)),
+		CorrelationId: correlation,
+		Message:       string(bodyBytes),
+	}
+
+	var resp dtoCommon.BaseResponse
+	if err := json.Unmarshal(bodyBytes, &resp); err == nil && resp.Message != "" {
+		keeperErr.Message = resp.Message
+		keeperErr.RequestId = resp.RequestId
+	}
+
+	return &keeperErr
+}
+
+// newResponseError creates the error of a 2xx response whose body reports a non 2xx status code
+func newResponseError(resp dtoCommon.BaseResponse) *types.KeeperError {
+	return &types.KeeperError{
+		StatusCode: resp.StatusCode,
+		ErrorCode:  string(errors.KindMapping(resp.StatusCode)),
+		RequestId:  resp.RequestId,
+		Message:    resp.Message,
+	}
+}
+
+// correlationId gets the Correlation ID from the context, creating a new one if the context doesn't carry any
+func correlationId(ctx context.Context) string {
+	correlation := utils.FromContext(ctx, common.CorrelationHeader)
+	if len(correlation) == 0 {
+		correlation = uuid.New().String()
+	}
+	return correlation
+}
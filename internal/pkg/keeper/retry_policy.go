@@ -0,0 +1,79 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// retryPolicy defines how requests failing with a transient error are retried, with exponential backoff between the
+// attempts. Requests whose connection was refused back off from a shorter delay.
+type retryPolicy struct {
+	maxAttempts            int
+	baseDelay              time.Duration
+	connectionRefusedDelay time.Duration
+	jitter                 float64
+}
+
+// newRetryPolicy creates the retry policy from the retry settings of the registry config
+func newRetryPolicy(config types.Config) (retryPolicy, error) {
+	baseDelay, err := config.GetRetryBaseDelay()
+	if err != nil {
+		return retryPolicy{}, err
+	}
+	connectionRefusedDelay, err := config.GetRetryConnectionRefusedBaseDelay()
+	if err != nil {
+		return retryPolicy{}, err
+	}
+	if config.RetryJitter < 0 || config.RetryJitter > 1 {
+		return retryPolicy{}, fmt.Errorf("invalid retry jitter '%v': must be between 0 and 1", config.RetryJitter)
+	}
+
+	return retryPolicy{
+		maxAttempts:            config.RetryMaxAttempts,
+		baseDelay:              baseDelay,
+		connectionRefusedDelay: connectionRefusedDelay,
+		jitter:                 config.RetryJitter,
+	}, nil
+}
+
+// delay returns the delay before the given retry, starting from 1, of a request which failed with the given transport
+// failure, if any
+func (p retryPolicy) delay(retry int, failure types.TransportFailure) time.Duration {
+	baseDelay := p.baseDelay
+	if failure == types.TransportFailureConnectionRefused {
+		baseDelay = p.connectionRefusedDelay
+	}
+
+	delay := baseDelay << (retry - 1)
+	if delay <= 0 {
+		// The shift overflowed
+		delay = baseDelay
+	}
+	if p.jitter > 0 {
+		// Randomize the delay within [delay * (1 - jitter), delay]
+		delay -= time.Duration(p.jitter * rand.Float64() * float64(delay)) // nolint: gosec
+	}
+	return delay
+}
+
+// wait waits for the delay before the given retry, returning false without waiting any longer once ctx is done
+func (p retryPolicy) wait(ctx context.Context, retry int, failure types.TransportFailure) bool {
+	timer := time.NewTimer(p.delay(retry, failure))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
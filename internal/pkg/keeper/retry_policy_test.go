@@ -0,0 +1,36 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 4, baseDelay: 100 * time.Millisecond}
+	require.Equal(t, 100*time.Millisecond, policy.delay(1, ""))
+	require.Equal(t, 200*time.Millisecond, policy.delay(2, ""))
+	require.Equal(t, 400*time.Millisecond, policy.delay(3, ""))
+
+	policy.jitter = 0.25
+	for i := 0; i < 100; i++ {
+		delay := policy.delay(2, "")
+		require.GreaterOrEqual(t, delay, 150*time.Millisecond)
+		require.LessOrEqual(t, delay, 200*time.Millisecond)
+	}
+}
+
+func TestRetryPolicyConnectionRefusedDelay(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 4, baseDelay: 100 * time.Millisecond, connectionRefusedDelay: 10 * time.Millisecond}
+	require.Equal(t, 10*time.Millisecond, policy.delay(1, types.TransportFailureConnectionRefused))
+	require.Equal(t, 20*time.Millisecond, policy.delay(2, types.TransportFailureConnectionRefused))
+	require.Equal(t, 200*time.Millisecond, policy.delay(2, types.TransportFailureTimeout))
+}
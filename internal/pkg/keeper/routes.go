@@ -0,0 +1,61 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keeper
+
+import (
+	"net/url"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+)
+
+// routeBuilder builds the paths of the Core Keeper API routes for an API version. Service keys are always escaped, so
+// keys containing reserved characters such as / or ? address the right registration. The EdgeX name field escaping,
+// which also escapes -, ., _ and ~, is applied instead when enabled, as expected by Keeper configured with it.
+type routeBuilder struct {
+	apiBase               string
+	enableNameFieldEscape bool
+}
+
+func newRouteBuilder(apiVersion string, enableNameFieldEscape bool) routeBuilder {
+	return routeBuilder{
+		apiBase:               "/api/" + apiVersion,
+		enableNameFieldEscape: enableNameFieldEscape,
+	}
+}
+
+// ping returns the path of the ping route, i.e. /api/v3/ping
+func (b routeBuilder) ping() string {
+	return b.apiBase + "/ping"
+}
+
+// registry returns the path of the route registering and updating registrations, i.e. /api/v3/registry
+func (b routeBuilder) registry() string {
+	return b.apiBase + "/registry"
+}
+
+// allRegistrations returns the path of the route listing all registrations, i.e. /api/v3/registry/all
+func (b routeBuilder) allRegistrations() string {
+	return b.registry() + "/" + common.All
+}
+
+// registrationByServiceIdPrefix returns the path of the routes of the registrations by service ID, without the
+// service ID, i.e. /api/v3/registry/serviceId/
+func (b routeBuilder) registrationByServiceIdPrefix() string {
+	return b.registry() + "/" + common.ServiceId + "/"
+}
+
+// registrationByServiceId returns the path of the route of the registration of the target service, i.e.
+// /api/v3/registry/serviceId/core-data
+func (b routeBuilder) registrationByServiceId(serviceId string) string {
+	return b.registrationByServiceIdPrefix() + b.escape(serviceId)
+}
+
+func (b routeBuilder) escape(name string) string {
+	if b.enableNameFieldEscape {
+		return common.URLEncode(name)
+	}
+	return url.PathEscape(name)
+}
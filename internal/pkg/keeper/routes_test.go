@@ -0,0 +1,25 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteBuilder(t *testing.T) {
+	routes := newRouteBuilder("v3", false)
+	assert.Equal(t, "/api/v3/ping", routes.ping())
+	assert.Equal(t, "/api/v3/registry", routes.registry())
+	assert.Equal(t, "/api/v3/registry/all", routes.allRegistrations())
+	assert.Equal(t, "/api/v3/registry/serviceId/core-data", routes.registrationByServiceId("core-data"))
+	assert.Equal(t, "/api/v3/registry/serviceId/device%2Fonvif%3Fcamera%231", routes.registrationByServiceId("device/onvif?camera#1"))
+
+	routes = newRouteBuilder("v4", true)
+	assert.Equal(t, "/api/v4/registry/serviceId/core%2Ddata", routes.registrationByServiceId("core-data"),
+		"Expected EdgeX name field escaping when enabled")
+}
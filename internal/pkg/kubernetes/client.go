@@ -0,0 +1,561 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/health"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/lifecycle"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/transport"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/watch"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// kubernetesClient implements the registry on top of the Kubernetes API. Services are discovered from the Services of
+// the namespace and are available when at least one of the endpoints of their EndpointSlices is ready, which
+// Kubernetes determines with the readiness probes of the pods. The current service is only registered by publishing
+// an EndpointSlice when KubernetesRegisterEndpoints is set, since Kubernetes registers the pods selected by Services
+// itself. Each replica publishes an EndpointSlice of its own, Kubernetes merging the EndpointSlices of the Service.
+type kubernetesClient struct {
+	config      *types.Config
+	scheduler   *watch.Scheduler
+	serverUrl   string
+	namespace   string
+	serviceKey  string
+	serviceHost string
+	servicePort int
+	sliceName   string
+
+	restClient   *restClient
+	registration lifecycle.Registration
+}
+
+// NewKubernetesClient creates new Kubernetes Client, connecting in-cluster or with a kubeconfig file. Service details
+// are optional, not needed just for discovery, but required if registering endpoints
+func NewKubernetesClient(registryConfig types.Config) (*kubernetesClient, error) {
+	conn, err := newConnection(registryConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new Kubernetes Client: %w", err)
+	}
+
+	client := kubernetesClient{
+		config:     &registryConfig,
+		scheduler:  watch.NewScheduler(registryConfig.WatchConcurrency),
+		serverUrl:  conn.server,
+		namespace:  conn.namespace,
+		serviceKey: registryConfig.ServiceKey,
+	}
+
+	// ServiceHost will be empty when client isn't registering the service
+	if registryConfig.ServiceHost != "" {
+		client.servicePort = registryConfig.ServicePort
+		client.serviceHost = registryConfig.ServiceHost
+		client.sliceName = endpointSliceName(registryConfig)
+	}
+
+	// The connection TLS is applied before the transport is wrapped, i.e. by the failover
+	roundTripper, err := transport.New(registryConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new Kubernetes Client for %s: %w", client.serverUrl, err)
+	}
+	roundTripper, err = withConnectionTLS(roundTripper, conn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new Kubernetes Client for %s: %w", client.serverUrl, err)
+	}
+	httpClient, err := transport.NewClientWithTransport(registryConfig, roundTripper)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new Kubernetes Client for %s: %w", client.serverUrl, err)
+	}
+	client.restClient = newRestClient(conn, httpClient)
+
+	return &client, nil
+}
+
+// withConnectionTLS returns a clone of the transport with the certificates of the connection which aren't covered by
+// the TLS settings of the registry configuration, i.e. the ones embedded in the kubeconfig file, applied on top of
+// them. Fails when the transport isn't an http.Transport, i.e. a RoundTripper set in the registry configuration, as
+// they couldn't be applied.
+func withConnectionTLS(roundTripper http.RoundTripper, conn connection) (http.RoundTripper, error) {
+	if conn.caData == nil && conn.certData == nil && !conn.insecure {
+		return roundTripper, nil
+	}
+
+	base, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unable to apply the TLS settings of the connection to a %T transport, only to an http.Transport", roundTripper)
+	}
+	pooled := base.Clone()
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if pooled.TLSClientConfig != nil {
+		tlsConfig = pooled.TLSClientConfig.Clone()
+	}
+
+	if conn.caData != nil && tlsConfig.RootCAs == nil {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(conn.caData) {
+			return nil, fmt.Errorf("no PEM encoded certificates found in the certificate authority")
+		}
+	}
+	if conn.certData != nil && len(tlsConfig.Certificates) == 0 {
+		certificate, err := tls.X509KeyPair(conn.certData, conn.keyData)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	if conn.insecure {
+		tlsConfig.InsecureSkipVerify = true // nolint: gosec
+	}
+
+	pooled.TLSClientConfig = tlsConfig
+	return pooled, nil
+}
+
+// IsAlive simply checks if the API server is up and running at the configured URL
+func (c *kubernetesClient) IsAlive() bool {
+	return c.IsAliveWithContext(context.Background())
+}
+
+// IsAliveWithContext simply checks if the API server is up and running at the configured URL, giving up once ctx is
+// done
+func (c *kubernetesClient) IsAliveWithContext(ctx context.Context) bool {
+	return c.restClient.sendRequest(ctx, http.MethodGet, "/version", nil, nil, nil) == nil
+}
+
+// Register publishes an EndpointSlice for the current service, creating a Service without selector for it if needed,
+// when KubernetesRegisterEndpoints is set. Kubernetes doesn't health check the published endpoint.
+func (c *kubernetesClient) Register() error {
+	return c.RegisterWithContext(context.Background())
+}
+
+// RegisterWithContext publishes an EndpointSlice for the current service when KubernetesRegisterEndpoints is set,
+// aborting once ctx is done
+func (c *kubernetesClient) RegisterWithContext(ctx context.Context) error {
+	return c.registration.Register(func() error {
+		return c.register(ctx)
+	})
+}
+
+func (c *kubernetesClient) register(ctx context.Context) error {
+	if !c.config.KubernetesRegisterEndpoints {
+		return nil
+	}
+
+	if c.serviceKey == "" || c.serviceHost == "" || c.servicePort == 0 {
+		return fmt.Errorf("unable to register service with kubernetes: Service information not set")
+	}
+
+	if c.config.GetProbeBeforeRegister() && c.config.GetCheckType() == types.CheckTypeHTTP {
+		if err := health.Probe(ctx, c.config.GetHealthCheckUrl(), c.config.GetCheckOptions(c.config.ServiceKey)); err != nil {
+			return fmt.Errorf("unable to register service with kubernetes: %w", err)
+		}
+	}
+
+	if err := c.ensureService(ctx); err != nil {
+		return err
+	}
+
+	ready := true
+	slice := endpointSlice{
+		ApiVersion: "discovery.k8s.io/v1",
+		Kind:       "EndpointSlice",
+		Metadata: objectMeta{
+			Name:      c.sliceName,
+			Namespace: c.namespace,
+			Labels:    map[string]string{serviceNameLabel: c.serviceKey, managedByLabel: managedBy},
+		},
+		AddressType: addressType(c.serviceHost),
+		Endpoints:   []endpoint{{Addresses: []string{c.serviceHost}, Conditions: endpointConditions{Ready: &ready}}},
+		Ports:       []endpointPort{{Protocol: "TCP", Port: c.servicePort}},
+	}
+
+	// Replace the EndpointSlice of a previous registration of the instance, otherwise create it
+	err := c.restClient.sendRequest(ctx, http.MethodPut, endpointSlicePath(c.namespace, slice.Metadata.Name), nil, slice, nil)
+	if isNotFound(err) {
+		err = c.restClient.sendRequest(ctx, http.MethodPost, endpointSlicesPath(c.namespace), nil, slice, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to register the %s service: %w", c.serviceKey, err)
+	}
+
+	return nil
+}
+
+// ensureService creates a Service without selector for the current service unless one already exists, so the
+// published EndpointSlice is discovered
+func (c *kubernetesClient) ensureService(ctx context.Context) error {
+	_, found, err := c.getService(ctx, c.serviceKey)
+	if err != nil || found {
+		return err
+	}
+
+	svc := service{
+		ApiVersion: "v1",
+		Kind:       "Service",
+		Metadata: objectMeta{
+			Name:      c.serviceKey,
+			Namespace: c.namespace,
+			Labels:    map[string]string{serviceManagedByLabel: managedBy},
+		},
+		Spec: serviceSpec{Ports: []servicePort{{Protocol: "TCP", Port: c.servicePort, TargetPort: c.servicePort}}},
+	}
+	if err := c.restClient.sendRequest(ctx, http.MethodPost, servicesPath(c.namespace), nil, svc, nil); err != nil {
+		return fmt.Errorf("failed to create the %s service: %w", c.serviceKey, err)
+	}
+
+	return nil
+}
+
+// RegisterCheck registers a health check with Kubernetes
+func (c *kubernetesClient) RegisterCheck(id string, name string, notes string, url string, interval string) error {
+	return c.RegisterCheckWithContext(context.Background(), id, name, notes, url, interval)
+}
+
+// RegisterCheckWithContext registers a health check with Kubernetes
+func (c *kubernetesClient) RegisterCheckWithContext(_ context.Context, id string, name string, notes string, url string, interval string) error {
+	// the health of pods is checked by their readiness probes, which are part of their deployment
+	return nil
+}
+
+// Unregister removes the EndpointSlice published for the current instance, if any, leaving the other replicas
+// registered
+func (c *kubernetesClient) Unregister() error {
+	return c.UnregisterWithContext(context.Background())
+}
+
+// UnregisterWithContext removes the EndpointSlice published for the current instance, if any, aborting once ctx is
+// done
+func (c *kubernetesClient) UnregisterWithContext(ctx context.Context) error {
+	return c.registration.Unregister(func() error {
+		return c.unregister(ctx)
+	})
+}
+
+func (c *kubernetesClient) unregister(ctx context.Context) error {
+	if !c.config.KubernetesRegisterEndpoints {
+		return nil
+	}
+
+	err := c.restClient.sendRequest(ctx, http.MethodDelete, endpointSlicePath(c.namespace, c.sliceName), nil, nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to de-register %s: %w", c.serviceKey, err)
+	}
+
+	return nil
+}
+
+// Decommission permanently retires the target service by deleting the EndpointSlices of all its instances and the
+// Service created by the registry client for it. Services created by other means are left to their owner.
+func (c *kubernetesClient) Decommission(ctx context.Context, serviceKey string) error {
+	// Decommissioning the current service must not be undone by restoring its registration
+	if serviceKey == c.serviceKey {
+		return c.registration.Unregister(func() error {
+			return c.decommission(ctx, serviceKey)
+		})
+	}
+
+	return c.decommission(ctx, serviceKey)
+}
+
+func (c *kubernetesClient) decommission(ctx context.Context, serviceKey string) error {
+	items, err := c.endpointSlices(ctx, serviceKey)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, slice := range items {
+		if slice.Metadata.Labels[managedByLabel] != managedBy {
+			continue
+		}
+		found = true
+		err = c.restClient.sendRequest(ctx, http.MethodDelete, endpointSlicePath(c.namespace, slice.Metadata.Name), nil, nil, nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to delete the %s endpoint slice: %w", slice.Metadata.Name, err)
+		}
+	}
+
+	svc, serviceFound, err := c.getService(ctx, serviceKey)
+	if err != nil {
+		return err
+	}
+	if serviceFound && svc.Metadata.Labels[serviceManagedByLabel] == managedBy {
+		found = true
+		err = c.restClient.sendRequest(ctx, http.MethodDelete, servicePath(c.namespace, serviceKey), nil, nil, nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to delete the %s service: %w", serviceKey, err)
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("unable to decommission %s: service is not registered by the registry client", serviceKey)
+	}
+
+	return nil
+}
+
+// WatchSelf polls Kubernetes for the Service of the current service and notifies the caller each time it has been
+// modified or deleted by someone else. Watching stops and the returned channel is closed once ctx is cancelled.
+func (c *kubernetesClient) WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error) {
+	if c.serviceKey == "" || c.serviceHost == "" || c.servicePort == 0 {
+		return nil, fmt.Errorf("unable to watch service registration with kubernetes: Service information not set")
+	}
+
+	interval, err := c.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	expected := c.endpoint(service{
+		Metadata: objectMeta{Name: c.serviceKey},
+		Spec:     serviceSpec{Ports: []servicePort{{Port: c.servicePort}}},
+	})
+
+	return watch.Self(ctx, c.scheduler, interval, expected, func() (types.ServiceEndpoint, error) {
+		return c.registeredEndpoint(ctx, c.serviceKey)
+	}), nil
+}
+
+// WatchService polls Kubernetes for the endpoint of the target service and sends it each time it changes, starting
+// with the current one. An empty endpoint is sent when the Service doesn't exist (anymore). Watching stops and the
+// returned channel is closed once ctx is cancelled.
+func (c *kubernetesClient) WatchService(ctx context.Context, serviceKey string) (<-chan types.ServiceEndpoint, error) {
+	interval, err := c.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Endpoint(ctx, c.scheduler, interval, func() (types.ServiceEndpoint, error) {
+		return c.registeredEndpoint(ctx, serviceKey)
+	}), nil
+}
+
+// registeredEndpoint retrieves the endpoint of the target service, which is empty when the Service doesn't exist
+func (c *kubernetesClient) registeredEndpoint(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	svc, found, err := c.getService(ctx, serviceKey)
+	if err != nil || !found {
+		return types.ServiceEndpoint{}, err
+	}
+
+	return c.endpoint(svc), nil
+}
+
+// TriggerHealthCheck reports the readiness of the endpoints of the target service. The endpoints are health checked
+// by the readiness probes of their pods, which Kubernetes doesn't offer to run on demand.
+func (c *kubernetesClient) TriggerHealthCheck(ctx context.Context, serviceKey string) (types.HealthCheckResult, error) {
+	_, found, err := c.getService(ctx, serviceKey)
+	if err != nil {
+		return types.HealthCheckResult{}, err
+	}
+	if !found {
+		return types.HealthCheckResult{}, types.Errorf(types.ErrNotRegistered, "unable to health check %s: service is not registered", serviceKey)
+	}
+
+	ready, total, err := c.endpointReadiness(ctx, serviceKey)
+	if err != nil {
+		return types.HealthCheckResult{}, err
+	}
+
+	return types.HealthCheckResult{Healthy: ready > 0, Output: fmt.Sprintf("%d of %d endpoints ready", ready, total)}, nil
+}
+
+// GetServiceEndpoint retrieves the port, service ID and host of a known Service from Kubernetes. The host is the
+// cluster DNS name of the Service.
+// If this operation is successful and a known endpoint is found, it is returned. Otherwise, an error is returned.
+func (c *kubernetesClient) GetServiceEndpoint(serviceKey string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointWithContext retrieves the port, service ID and host of a known Service from Kubernetes, aborting
+// once ctx is done
+func (c *kubernetesClient) GetServiceEndpointWithContext(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	svc, found, err := c.getService(ctx, serviceKey)
+	if err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
+	}
+	if !found {
+		return types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+
+	return c.endpoint(svc), nil
+}
+
+// GetServiceEndpoints retrieves the address and port of each ready endpoint of the EndpointSlices of the target
+// Service from Kubernetes, i.e. of each ready pod it selects, rather than the cluster DNS name of the Service
+func (c *kubernetesClient) GetServiceEndpoints(serviceKey string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointsWithContext retrieves the ready endpoints of the target Service from Kubernetes, aborting once ctx
+// is done
+func (c *kubernetesClient) GetServiceEndpointsWithContext(ctx context.Context, serviceKey string) ([]types.ServiceEndpoint, error) {
+	svc, found, err := c.getService(ctx, serviceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s endpoints: %w", serviceKey, err)
+	}
+	if !found {
+		return nil, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+
+	items, err := c.endpointSlices(ctx, serviceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s endpoints: %w", serviceKey, err)
+	}
+
+	endpoints := []types.ServiceEndpoint{}
+	for _, slice := range items {
+		// The port of a slice is the one the pods listen on, the Service port being used for slices without any
+		port := c.endpoint(svc).Port
+		if len(slice.Ports) > 0 {
+			port = slice.Ports[0].Port
+		}
+		for _, e := range slice.Endpoints {
+			if !e.isReady() || len(e.Addresses) == 0 {
+				continue
+			}
+			instanceId := e.Addresses[0]
+			if e.TargetRef != nil && e.TargetRef.Name != "" {
+				instanceId = e.TargetRef.Name
+			}
+			endpoint := types.ServiceEndpoint{ServiceId: serviceKey, InstanceId: instanceId, Host: e.Addresses[0], Port: port}
+			if err := endpoint.Validate(); err != nil {
+				return nil, fmt.Errorf("failed to get service %s endpoints: %w", serviceKey, err)
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	types.SortServiceEndpoints(endpoints, c.config.GetEndpointOrder())
+
+	return endpoints, nil
+}
+
+// GetAllServiceEndpoints retrieves the endpoints of all the Services of the namespace from Kubernetes.
+func (c *kubernetesClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+// GetAllServiceEndpointsWithContext retrieves the endpoints of all the Services of the namespace from Kubernetes,
+// aborting once ctx is done
+func (c *kubernetesClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	res := serviceList{}
+	if err := c.restClient.sendRequest(ctx, http.MethodGet, servicesPath(c.namespace), nil, nil, &res); err != nil {
+		return nil, fmt.Errorf("failed to get all service endpoints: %w", err)
+	}
+
+	endpoints := make([]types.ServiceEndpoint, 0, len(res.Items))
+	for _, svc := range res.Items {
+		endpoints = append(endpoints, c.endpoint(svc))
+	}
+	types.SortServiceEndpoints(endpoints, c.config.GetEndpointOrder())
+
+	return endpoints, nil
+}
+
+// IsServiceAvailable checks with Kubernetes if the target service exists and has ready endpoints
+func (c *kubernetesClient) IsServiceAvailable(serviceKey string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceKey)
+}
+
+// IsServiceAvailableWithContext checks with Kubernetes if the target service exists and has ready endpoints, aborting
+// once ctx is done
+func (c *kubernetesClient) IsServiceAvailableWithContext(ctx context.Context, serviceKey string) (bool, error) {
+	_, found, err := c.getService(ctx, serviceKey)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, types.Errorf(types.ErrNotRegistered, "%s service is not registered. Might not have started... ", serviceKey)
+	}
+
+	ready, _, err := c.endpointReadiness(ctx, serviceKey)
+	if err != nil {
+		return false, err
+	}
+	if ready == 0 {
+		return false, types.Errorf(types.ErrUnhealthy, " %s service not healthy...", serviceKey)
+	}
+
+	return true, nil
+}
+
+// getService retrieves the Service of the target service, reporting whether it exists
+func (c *kubernetesClient) getService(ctx context.Context, serviceKey string) (service, bool, error) {
+	svc := service{}
+	err := c.restClient.sendRequest(ctx, http.MethodGet, servicePath(c.namespace, serviceKey), nil, nil, &svc)
+	if isNotFound(err) {
+		return service{}, false, nil
+	}
+	if err != nil {
+		return service{}, false, fmt.Errorf("failed to get %s service: %w", serviceKey, err)
+	}
+
+	return svc, true, nil
+}
+
+// endpointSlices retrieves the EndpointSlices of the target service
+func (c *kubernetesClient) endpointSlices(ctx context.Context, serviceKey string) ([]endpointSlice, error) {
+	requestParams := url.Values{}
+	requestParams.Set("labelSelector", serviceNameLabel+"="+serviceKey)
+
+	res := endpointSliceList{}
+	if err := c.restClient.sendRequest(ctx, http.MethodGet, endpointSlicesPath(c.namespace), requestParams, nil, &res); err != nil {
+		return nil, fmt.Errorf("failed to get %s endpoint slices: %w", serviceKey, err)
+	}
+
+	return res.Items, nil
+}
+
+// endpointReadiness counts the ready endpoints of the EndpointSlices of the target service, and all its endpoints
+func (c *kubernetesClient) endpointReadiness(ctx context.Context, serviceKey string) (int, int, error) {
+	items, err := c.endpointSlices(ctx, serviceKey)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ready, total := 0, 0
+	for _, slice := range items {
+		for _, e := range slice.Endpoints {
+			total++
+			if e.isReady() {
+				ready++
+			}
+		}
+	}
+
+	return ready, total, nil
+}
+
+func (c *kubernetesClient) endpoint(svc service) types.ServiceEndpoint {
+	endpoint := types.ServiceEndpoint{
+		ServiceId: svc.Metadata.Name,
+		Host:      svc.Metadata.Name + "." + c.namespace + ".svc",
+	}
+	if len(svc.Spec.Ports) > 0 {
+		endpoint.Port = svc.Spec.Ports[0].Port
+	}
+	return endpoint
+}
+
+// addressType returns the EndpointSlice address type of the host
+func addressType(host string) string {
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return "FQDN"
+	case ip.To4() != nil:
+		return "IPv4"
+	default:
+		return "IPv6"
+	}
+}
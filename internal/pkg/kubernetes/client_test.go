@@ -0,0 +1,312 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/hostile"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	serviceName        = "kubernetesunittest"
+	testNamespace      = "edgex"
+	defaultServiceHost = "10.42.0.7"
+	defaultServicePort = 59880
+)
+
+var (
+	testRegistryHost string
+	testRegistryPort int
+	mockKubernetes   *MockKubernetes
+)
+
+func TestMain(m *testing.M) {
+	mockKubernetes = NewMockKubernetes()
+	testMockServer := mockKubernetes.Start()
+
+	URL, _ := url.Parse(testMockServer.URL)
+	testRegistryHost = URL.Hostname()
+	testRegistryPort, _ = strconv.Atoi(URL.Port())
+
+	exitCode := m.Run()
+	testMockServer.Close()
+	os.Exit(exitCode)
+}
+
+func TestIsAlive(t *testing.T) {
+	client := makeKubernetesClient(t, getUniqueServiceName(), false)
+	require.True(t, client.IsAlive(), "Kubernetes API server not running")
+}
+
+func TestDiscoverService(t *testing.T) {
+	name := getUniqueServiceName()
+	mockKubernetes.AddService(testNamespace, name, defaultServicePort, 1, 1)
+	client := makeKubernetesClient(t, getUniqueServiceName(), false)
+
+	endpoint, err := client.GetServiceEndpoint(name)
+	require.NoError(t, err)
+	assert.Equal(t, types.ServiceEndpoint{ServiceId: name, Host: name + ".edgex.svc", Port: defaultServicePort}, endpoint)
+
+	available, err := client.IsServiceAvailable(name)
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	result, err := client.TriggerHealthCheck(context.Background(), name)
+	require.NoError(t, err)
+	assert.True(t, result.Healthy)
+	assert.Equal(t, "1 of 2 endpoints ready", result.Output)
+
+	endpoints, err := client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	assert.Contains(t, endpoints, endpoint)
+	assert.True(t, slices.IsSortedFunc(endpoints, types.ByServiceId))
+}
+
+func TestGetServiceEndpoints(t *testing.T) {
+	name := getUniqueServiceName()
+	mockKubernetes.AddService(testNamespace, name, defaultServicePort, 2, 1)
+	client := makeKubernetesClient(t, getUniqueServiceName(), false)
+
+	endpoints, err := client.GetServiceEndpoints(name)
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{
+		{ServiceId: name, InstanceId: name + "-0", Host: "10.0.0.1", Port: defaultServicePort},
+		{ServiceId: name, InstanceId: name + "-1", Host: "10.0.0.2", Port: defaultServicePort},
+	}, endpoints, "Expected the ready pods only")
+
+	_, err = client.GetServiceEndpoints(getUniqueServiceName())
+	require.EqualError(t, err, "no matching service endpoint found")
+}
+
+func TestIsServiceAvailableNotReady(t *testing.T) {
+	name := getUniqueServiceName()
+	mockKubernetes.AddService(testNamespace, name, defaultServicePort, 0, 2)
+	client := makeKubernetesClient(t, getUniqueServiceName(), false)
+
+	_, err := client.IsServiceAvailable(name)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service not healthy")
+
+	_, err = client.IsServiceAvailable(getUniqueServiceName())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service is not registered")
+
+	_, err = client.GetServiceEndpoint(getUniqueServiceName())
+	require.EqualError(t, err, "no matching service endpoint found")
+}
+
+func TestRegisterDisabled(t *testing.T) {
+	client := makeKubernetesClient(t, getUniqueServiceName(), false)
+	require.NoError(t, client.Register())
+
+	_, err := client.GetServiceEndpoint(client.serviceKey)
+	require.Error(t, err, "Expected nothing to be registered without KubernetesRegisterEndpoints")
+	require.NoError(t, client.Unregister())
+}
+
+func TestRegisterEndpoints(t *testing.T) {
+	client := makeKubernetesClient(t, getUniqueServiceName(), true)
+	require.NoError(t, client.Register())
+	require.NoError(t, client.Register(), "Expected registering again to replace the EndpointSlice")
+
+	endpoint, err := client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, defaultServicePort, endpoint.Port)
+
+	available, err := client.IsServiceAvailable(client.serviceKey)
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	require.NoError(t, client.Unregister())
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.Error(t, err, "Expected service without endpoints to be unavailable")
+
+	require.NoError(t, client.Decommission(context.Background(), client.serviceKey))
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.Error(t, err, "Expected the Service created on registering to be deleted")
+}
+
+func TestRegisterReplicas(t *testing.T) {
+	name := getUniqueServiceName()
+	first := makeKubernetesClient(t, name, true)
+	second, err := NewKubernetesClient(types.Config{
+		Host:                        testRegistryHost,
+		Port:                        testRegistryPort,
+		ServiceKey:                  name,
+		ServiceHost:                 "10.42.0.8",
+		ServicePort:                 defaultServicePort,
+		KubernetesNamespace:         testNamespace,
+		KubernetesRegisterEndpoints: true,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, first.sliceName, second.sliceName, "Expected each replica to publish its own EndpointSlice")
+
+	require.NoError(t, first.Register())
+	require.NoError(t, second.Register())
+	ready, total, err := first.endpointReadiness(context.Background(), name)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ready)
+	assert.Equal(t, 2, total)
+
+	require.NoError(t, first.Unregister())
+	available, err := second.IsServiceAvailable(name)
+	require.NoError(t, err)
+	assert.True(t, available, "Expected the other replica to stay registered")
+
+	require.NoError(t, first.Decommission(context.Background(), name))
+	items, err := first.endpointSlices(context.Background(), name)
+	require.NoError(t, err)
+	assert.Empty(t, items, "Expected the EndpointSlices of all the replicas to be deleted")
+}
+
+func TestRegisterNoServiceInfoError(t *testing.T) {
+	client, err := NewKubernetesClient(types.Config{
+		Host:                        testRegistryHost,
+		Port:                        testRegistryPort,
+		KubernetesNamespace:         testNamespace,
+		KubernetesRegisterEndpoints: true,
+	})
+	require.NoError(t, err)
+
+	err = client.Register()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Service information not set")
+}
+
+func TestDecommissionNotManaged(t *testing.T) {
+	name := getUniqueServiceName()
+	mockKubernetes.AddService(testNamespace, name, defaultServicePort, 1, 0)
+	client := makeKubernetesClient(t, getUniqueServiceName(), true)
+
+	err := client.Decommission(context.Background(), name)
+	require.Error(t, err)
+	_, err = client.GetServiceEndpoint(name)
+	require.NoError(t, err, "Expected the Service not created by the registry client to be kept")
+}
+
+func TestWatchService(t *testing.T) {
+	client := makeKubernetesClient(t, getUniqueServiceName(), true)
+	client.config.WatchInterval = "10ms"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	endpoints, err := client.WatchService(ctx, client.serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint before registering")
+
+	require.NoError(t, client.Register())
+	require.Equal(t, client.serviceKey, receiveEndpoint(t, endpoints).ServiceId)
+
+	require.NoError(t, client.Decommission(context.Background(), client.serviceKey))
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint once decommissioned")
+}
+
+func TestAccessToken(t *testing.T) {
+	mockKubernetes.SetExpectedToken("api-token")
+	defer mockKubernetes.ClearExpectedToken()
+
+	client := makeKubernetesClient(t, getUniqueServiceName(), false)
+	require.False(t, client.IsAlive(), "Expected request without token to be rejected")
+	require.True(t, client.IsAliveWithContext(types.WithAccessToken(context.Background(), "api-token")))
+
+	client.config.AccessToken = "api-token"
+	client, err := NewKubernetesClient(*client.config)
+	require.NoError(t, err)
+	require.True(t, client.IsAlive())
+}
+
+func receiveEndpoint(t *testing.T, endpoints <-chan types.ServiceEndpoint) types.ServiceEndpoint {
+	select {
+	case endpoint := <-endpoints:
+		return endpoint
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for service endpoint")
+		return types.ServiceEndpoint{}
+	}
+}
+
+func makeKubernetesClient(t *testing.T, serviceName string, registerEndpoints bool) *kubernetesClient {
+	client, err := NewKubernetesClient(types.Config{
+		Host:                        testRegistryHost,
+		Port:                        testRegistryPort,
+		ServiceKey:                  serviceName,
+		ServiceHost:                 defaultServiceHost,
+		ServicePort:                 defaultServicePort,
+		KubernetesNamespace:         testNamespace,
+		KubernetesRegisterEndpoints: registerEndpoints,
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+func getUniqueServiceName() string {
+	return serviceName + strconv.Itoa(time.Now().Nanosecond())
+}
+
+func TestGetServiceEndpointsHostileResponses(t *testing.T) {
+	for _, registration := range hostile.Registrations() {
+		t.Run(registration.Name, func(t *testing.T) {
+			// The hosts are the addresses of the endpoints, and the ports those of the EndpointSlices
+			slicePort := registration
+			slicePort.Host = ""
+			server, host, port := hostile.Serve(func(request *http.Request) string {
+				if strings.HasSuffix(request.URL.Path, "/endpointslices") {
+					return fmt.Sprintf(`{"items":[{"endpoints":[{"addresses":[%s]}],"ports":[{%s}]}]}`, registration.Host, slicePort.Fields("", "port"))
+				}
+				return fmt.Sprintf(`{"metadata":{"name":%q},"spec":{"ports":[{"port":%d}]}}`, serviceName, hostile.ValidPort)
+			})
+			defer server.Close()
+
+			client, err := NewKubernetesClient(types.Config{Host: host, Port: port, KubernetesNamespace: testNamespace})
+			require.NoError(t, err)
+
+			endpoints, err := client.GetServiceEndpoints(serviceName)
+			if !registration.Valid {
+				require.ErrorIs(t, err, types.ErrMalformedResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, endpoints, 1)
+			assert.Equal(t, hostile.ValidHost, endpoints[0].Host)
+			assert.Equal(t, hostile.ValidPort, endpoints[0].Port)
+		})
+	}
+}
+
+func TestWithConnectionTLS(t *testing.T) {
+	pooled := &http.Transport{}
+	roundTripper, err := withConnectionTLS(pooled, connection{})
+	require.NoError(t, err)
+	assert.Same(t, pooled, roundTripper, "Expected the transport as is without connection TLS")
+
+	roundTripper, err = withConnectionTLS(pooled, connection{insecure: true})
+	require.NoError(t, err)
+	require.NotSame(t, pooled, roundTripper, "Expected the connection TLS to be applied to a clone")
+	assert.True(t, roundTripper.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+	assert.False(t, pooled.TLSClientConfig != nil && pooled.TLSClientConfig.InsecureSkipVerify, "Expected the transport to be left untouched")
+
+	_, err = withConnectionTLS(pooled, connection{caData: []byte("not a certificate")})
+	require.Error(t, err)
+
+	custom := http.RoundTripper(http.NewFileTransport(http.Dir(t.TempDir())))
+	_, err = withConnectionTLS(custom, connection{insecure: true})
+	require.Error(t, err, "Expected the connection TLS not to be silently dropped with a custom RoundTripper")
+}
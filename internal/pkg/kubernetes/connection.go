@@ -0,0 +1,210 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const defaultNamespace = "default"
+
+// serviceAccountDir is where Kubernetes mounts the credentials of the service account into the pods
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// connection is how to reach and authenticate with the Kubernetes API server
+type connection struct {
+	server    string
+	namespace string
+	// token is the bearer token, which is read from tokenFile instead when set since service account tokens are rotated
+	token     string
+	tokenFile string
+	// caData, certData and keyData are the PEM encoded CA and client certificate which aren't covered by the TLS
+	// settings of the registry configuration
+	caData   []byte
+	certData []byte
+	keyData  []byte
+	insecure bool
+}
+
+// kubeconfig is the subset of the kubeconfig file format needed to connect with the current context
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			TokenFile             string `yaml:"tokenFile"`
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// newConnection resolves the connection to the API server from the kubeconfig file when set, otherwise from the Host
+// and Port of the registry configuration if set or the in-cluster environment, authenticated with the AccessToken
+// if set or the service account
+func newConnection(config types.Config) (connection, error) {
+	var conn connection
+	if config.KubeconfigFile != "" {
+		var err error
+		if conn, err = loadKubeconfig(config.KubeconfigFile); err != nil {
+			return connection{}, err
+		}
+	} else {
+		if config.Host != "" {
+			conn.server = config.GetRegistryUrl()
+		} else {
+			host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+			if host == "" || port == "" {
+				return connection{}, fmt.Errorf("registry host not set and not running in a Kubernetes cluster")
+			}
+			conn.server = "https://" + net.JoinHostPort(host, port)
+		}
+
+		if config.AccessToken != "" {
+			conn.token = config.AccessToken
+		} else if tokenFile := filepath.Join(serviceAccountDir, "token"); fileExists(tokenFile) {
+			conn.tokenFile = tokenFile
+		}
+
+		if caFile := filepath.Join(serviceAccountDir, "ca.crt"); config.TLSCAFile == "" && fileExists(caFile) {
+			caData, err := os.ReadFile(caFile)
+			if err != nil {
+				return connection{}, fmt.Errorf("unable to read service account CA file %s: %v", caFile, err)
+			}
+			conn.caData = caData
+		}
+
+		if namespace, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace")); err == nil {
+			conn.namespace = strings.TrimSpace(string(namespace))
+		}
+	}
+
+	if config.KubernetesNamespace != "" {
+		conn.namespace = config.KubernetesNamespace
+	}
+	if conn.namespace == "" {
+		conn.namespace = defaultNamespace
+	}
+
+	return conn, nil
+}
+
+// loadKubeconfig resolves the connection of the current context of the kubeconfig file. Relative file paths are
+// relative to the directory of the kubeconfig file.
+func loadKubeconfig(path string) (connection, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return connection{}, fmt.Errorf("unable to read kubeconfig file %s: %v", path, err)
+	}
+
+	var config kubeconfig
+	if err := yaml.Unmarshal(contents, &config); err != nil {
+		return connection{}, fmt.Errorf("unable to parse kubeconfig file %s: %v", path, err)
+	}
+
+	conn := connection{}
+	found := false
+	var clusterName, userName string
+	for _, context := range config.Contexts {
+		if context.Name == config.CurrentContext {
+			clusterName, userName, conn.namespace = context.Context.Cluster, context.Context.User, context.Context.Namespace
+			found = true
+			break
+		}
+	}
+	if !found {
+		return connection{}, fmt.Errorf("current context '%s' not found in kubeconfig file %s", config.CurrentContext, path)
+	}
+
+	dir := filepath.Dir(path)
+	found = false
+	for _, cluster := range config.Clusters {
+		if cluster.Name != clusterName {
+			continue
+		}
+		found = true
+		conn.server = cluster.Cluster.Server
+		conn.insecure = cluster.Cluster.InsecureSkipTLSVerify
+		if conn.caData, err = fileOrData(dir, cluster.Cluster.CertificateAuthority, cluster.Cluster.CertificateAuthorityData); err != nil {
+			return connection{}, fmt.Errorf("invalid certificate authority of cluster '%s': %v", clusterName, err)
+		}
+		break
+	}
+	if !found || conn.server == "" {
+		return connection{}, fmt.Errorf("server of cluster '%s' not found in kubeconfig file %s", clusterName, path)
+	}
+
+	for _, user := range config.Users {
+		if user.Name != userName {
+			continue
+		}
+		conn.token = user.User.Token
+		if user.User.TokenFile != "" {
+			conn.tokenFile = resolvePath(dir, user.User.TokenFile)
+		}
+		if conn.certData, err = fileOrData(dir, user.User.ClientCertificate, user.User.ClientCertificateData); err != nil {
+			return connection{}, fmt.Errorf("invalid client certificate of user '%s': %v", userName, err)
+		}
+		if conn.keyData, err = fileOrData(dir, user.User.ClientKey, user.User.ClientKeyData); err != nil {
+			return connection{}, fmt.Errorf("invalid client key of user '%s': %v", userName, err)
+		}
+		break
+	}
+
+	return conn, nil
+}
+
+// fileOrData returns the base64 decoded data if set, otherwise the contents of the file if set
+func fileOrData(dir string, file string, data string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if file != "" {
+		return os.ReadFile(resolvePath(dir, file))
+	}
+	return nil, nil
+}
+
+func resolvePath(dir string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
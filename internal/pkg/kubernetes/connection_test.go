@@ -0,0 +1,96 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: edge
+clusters:
+- name: other
+  cluster:
+    server: https://other:6443
+- name: k3s
+  cluster:
+    server: https://k3s.local:6443
+    certificate-authority-data: %s
+contexts:
+- name: edge
+  context:
+    cluster: k3s
+    user: edgex
+    namespace: edgex
+users:
+- name: edgex
+  user:
+    token: kubeconfig-token
+`
+
+func TestLoadKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	caData := base64.StdEncoding.EncodeToString([]byte("ca"))
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(testKubeconfig, caData)), 0600))
+
+	conn, err := newConnection(types.Config{KubeconfigFile: path})
+	require.NoError(t, err)
+	assert.Equal(t, "https://k3s.local:6443", conn.server)
+	assert.Equal(t, "edgex", conn.namespace)
+	assert.Equal(t, "kubeconfig-token", conn.token)
+	assert.Equal(t, []byte("ca"), conn.caData)
+
+	conn, err = newConnection(types.Config{KubeconfigFile: path, KubernetesNamespace: "site-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "site-1", conn.namespace, "Expected the configured namespace to override the one of the context")
+
+	_, err = newConnection(types.Config{KubeconfigFile: filepath.Join(dir, "missing")})
+	require.Error(t, err)
+}
+
+func TestInClusterConnection(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("sa-token\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "namespace"), []byte("edgex"), 0600))
+
+	previous := serviceAccountDir
+	serviceAccountDir = dir
+	defer func() { serviceAccountDir = previous }()
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+	_, err := newConnection(types.Config{})
+	require.Error(t, err, "Expected error outside of a cluster without registry host")
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.43.0.1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+	conn, err := newConnection(types.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://10.43.0.1:443", conn.server)
+	assert.Equal(t, "edgex", conn.namespace)
+	assert.Equal(t, filepath.Join(dir, "token"), conn.tokenFile)
+
+	token, err := newRestClient(conn, nil).bearerToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "sa-token", token)
+
+	conn, err = newConnection(types.Config{AccessToken: "configured-token"})
+	require.NoError(t, err)
+	assert.Equal(t, "configured-token", conn.token)
+	assert.Empty(t, conn.tokenFile, "Expected the configured token to override the service account")
+}
@@ -0,0 +1,201 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MockKubernetes emulates the Services and EndpointSlices APIs of the Kubernetes API server
+type MockKubernetes struct {
+	services      map[string]service
+	slices        map[string]endpointSlice
+	expectedToken string
+	lock          sync.Mutex
+}
+
+func NewMockKubernetes() *MockKubernetes {
+	return &MockKubernetes{
+		services: make(map[string]service),
+		slices:   make(map[string]endpointSlice),
+	}
+}
+
+// AddService adds a Service to the namespace with an EndpointSlice of ready and not ready endpoints, as Kubernetes
+// maintains for the pods selected by a Service according to their readiness probes
+func (mock *MockKubernetes) AddService(namespace string, name string, port int, ready int, notReady int) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
+	mock.services[namespace+"/"+name] = service{
+		ApiVersion: "v1",
+		Kind:       "Service",
+		Metadata:   objectMeta{Name: name, Namespace: namespace},
+		Spec:       serviceSpec{Ports: []servicePort{{Port: port}}},
+	}
+
+	slice := endpointSlice{
+		Metadata:    objectMeta{Name: name + "-abcde", Namespace: namespace, Labels: map[string]string{serviceNameLabel: name}},
+		AddressType: "IPv4",
+		Ports:       []endpointPort{{Protocol: "TCP", Port: port}},
+	}
+	for i := 0; i < ready+notReady; i++ {
+		isReady := i < ready
+		slice.Endpoints = append(slice.Endpoints, endpoint{
+			Addresses:  []string{"10.0.0." + strconv.Itoa(i+1)},
+			Conditions: endpointConditions{Ready: &isReady},
+			TargetRef:  &objectReference{Kind: "Pod", Name: name + "-" + strconv.Itoa(i)},
+		})
+	}
+	mock.slices[namespace+"/"+slice.Metadata.Name] = slice
+}
+
+// SetExpectedToken has every request without the given bearer token rejected with 401 Unauthorized
+func (mock *MockKubernetes) SetExpectedToken(token string) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
+	mock.expectedToken = token
+}
+
+func (mock *MockKubernetes) ClearExpectedToken() {
+	mock.SetExpectedToken("")
+}
+
+func (mock *MockKubernetes) Start() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mock.lock.Lock()
+		defer mock.lock.Unlock()
+
+		if mock.expectedToken != "" && request.Header.Get(authorizationHeader) != bearerPrefix+mock.expectedToken {
+			writeStatus(writer, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		if request.URL.Path == "/version" {
+			writeJSON(writer, http.StatusOK, map[string]string{"major": "1", "minor": "29"})
+			return
+		}
+
+		// /api/v1/namespaces/<namespace>/services[/<name>] or
+		// /apis/discovery.k8s.io/v1/namespaces/<namespace>/endpointslices[/<name>]
+		path := strings.TrimPrefix(strings.TrimPrefix(request.URL.Path, "/api/v1/"), "/apis/discovery.k8s.io/v1/")
+		parts := strings.Split(path, "/")
+		if len(parts) < 3 || parts[0] != "namespaces" {
+			writeStatus(writer, http.StatusNotFound, "the server could not find the requested resource")
+			return
+		}
+		namespace, resource, name := parts[1], parts[2], ""
+		if len(parts) > 3 {
+			name = parts[3]
+		}
+
+		switch resource {
+		case "services":
+			handleResource(writer, request, mock.services, namespace, name, func(items []service) any { return serviceList{Items: items} },
+				func(s service) objectMeta { return s.Metadata }, func(service) bool { return true })
+		case "endpointslices":
+			selector := request.URL.Query().Get("labelSelector")
+			handleResource(writer, request, mock.slices, namespace, name, func(items []endpointSlice) any { return endpointSliceList{Items: items} },
+				func(s endpointSlice) objectMeta { return s.Metadata }, func(s endpointSlice) bool { return matchesSelector(s.Metadata.Labels, selector) })
+		default:
+			writeStatus(writer, http.StatusNotFound, "the server could not find the requested resource")
+		}
+	}))
+}
+
+// handleResource serves the get, list, create, update and delete requests of a resource type
+func handleResource[T any](writer http.ResponseWriter, request *http.Request, store map[string]T, namespace string, name string,
+	list func([]T) any, meta func(T) objectMeta, matches func(T) bool) {
+	key := namespace + "/" + name
+
+	switch {
+	case name == "" && request.Method == http.MethodGet:
+		var keys []string
+		for k, item := range store {
+			if strings.HasPrefix(k, namespace+"/") && matches(item) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		items := make([]T, 0, len(keys))
+		for _, k := range keys {
+			items = append(items, store[k])
+		}
+		writeJSON(writer, http.StatusOK, list(items))
+	case name == "" && request.Method == http.MethodPost:
+		var item T
+		if err := json.NewDecoder(request.Body).Decode(&item); err != nil {
+			writeStatus(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+		key = namespace + "/" + meta(item).Name
+		if _, ok := store[key]; ok {
+			writeStatus(writer, http.StatusConflict, "already exists")
+			return
+		}
+		store[key] = item
+		writeJSON(writer, http.StatusCreated, item)
+	case request.Method == http.MethodGet:
+		item, ok := store[key]
+		if !ok {
+			writeStatus(writer, http.StatusNotFound, name+" not found")
+			return
+		}
+		writeJSON(writer, http.StatusOK, item)
+	case request.Method == http.MethodPut:
+		if _, ok := store[key]; !ok {
+			writeStatus(writer, http.StatusNotFound, name+" not found")
+			return
+		}
+		var item T
+		if err := json.NewDecoder(request.Body).Decode(&item); err != nil {
+			writeStatus(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+		store[key] = item
+		writeJSON(writer, http.StatusOK, item)
+	case request.Method == http.MethodDelete:
+		if _, ok := store[key]; !ok {
+			writeStatus(writer, http.StatusNotFound, name+" not found")
+			return
+		}
+		delete(store, key)
+		writeJSON(writer, http.StatusOK, status{Message: "deleted"})
+	default:
+		writeStatus(writer, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// matchesSelector tells whether the labels match the equality based label selector, i.e. k1=v1,k2=v2
+func matchesSelector(labels map[string]string, selector string) bool {
+	if selector == "" {
+		return true
+	}
+	for _, requirement := range strings.Split(selector, ",") {
+		key, value, _ := strings.Cut(requirement, "=")
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func writeStatus(writer http.ResponseWriter, statusCode int, message string) {
+	writeJSON(writer, statusCode, status{Message: message})
+}
+
+func writeJSON(writer http.ResponseWriter, statusCode int, v any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	_ = json.NewEncoder(writer).Encode(v)
+}
@@ -0,0 +1,129 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	serviceNameLabel = "kubernetes.io/service-name"
+	managedByLabel   = "endpointslice.kubernetes.io/managed-by"
+	// serviceManagedByLabel is the managed-by label of the Services created by the registry client
+	serviceManagedByLabel = "app.kubernetes.io/managed-by"
+	// managedBy is the value of the managed-by labels of the resources created by the registry client
+	managedBy = "go-mod-registry"
+	// endpointSliceSuffix is appended to the service key to name the EndpointSlices registering the instances of the
+	// service, followed by the hash of the instance
+	endpointSliceSuffix = "-registry"
+)
+
+// The subsets of the Kubernetes API resources used for discovery and registration
+
+type objectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+}
+
+type servicePort struct {
+	Name       string `json:"name,omitempty"`
+	Protocol   string `json:"protocol,omitempty"`
+	Port       int    `json:"port"`
+	TargetPort int    `json:"targetPort,omitempty"`
+}
+
+type service struct {
+	ApiVersion string      `json:"apiVersion"`
+	Kind       string      `json:"kind"`
+	Metadata   objectMeta  `json:"metadata"`
+	Spec       serviceSpec `json:"spec"`
+}
+
+type serviceSpec struct {
+	Ports []servicePort `json:"ports"`
+}
+
+type serviceList struct {
+	Items []service `json:"items"`
+}
+
+type endpointConditions struct {
+	// Ready is nil when unknown, which consumers are to interpret as ready
+	Ready *bool `json:"ready,omitempty"`
+}
+
+// objectReference references the object backing an endpoint, i.e. its pod
+type objectReference struct {
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type endpoint struct {
+	Addresses  []string           `json:"addresses"`
+	Conditions endpointConditions `json:"conditions"`
+	TargetRef  *objectReference   `json:"targetRef,omitempty"`
+}
+
+// isReady tells whether the endpoint is ready, which it is when unknown
+func (e endpoint) isReady() bool {
+	return e.Conditions.Ready == nil || *e.Conditions.Ready
+}
+
+type endpointPort struct {
+	Name     string `json:"name,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Port     int    `json:"port"`
+}
+
+type endpointSlice struct {
+	ApiVersion  string         `json:"apiVersion"`
+	Kind        string         `json:"kind"`
+	Metadata    objectMeta     `json:"metadata"`
+	AddressType string         `json:"addressType"`
+	Endpoints   []endpoint     `json:"endpoints"`
+	Ports       []endpointPort `json:"ports"`
+}
+
+type endpointSliceList struct {
+	Items []endpointSlice `json:"items"`
+}
+
+func servicesPath(namespace string) string {
+	return "/api/v1/namespaces/" + url.PathEscape(namespace) + "/services"
+}
+
+func servicePath(namespace string, name string) string {
+	return servicesPath(namespace) + "/" + url.PathEscape(name)
+}
+
+func endpointSlicesPath(namespace string) string {
+	return "/apis/discovery.k8s.io/v1/namespaces/" + url.PathEscape(namespace) + "/endpointslices"
+}
+
+func endpointSlicePath(namespace string, name string) string {
+	return endpointSlicesPath(namespace) + "/" + url.PathEscape(name)
+}
+
+// endpointSliceName returns the name of the EndpointSlice registering the current instance. Each replica publishes
+// its own EndpointSlice, so they don't overwrite each other's registration: the name is suffixed with the hash of the
+// ServiceInstanceId, or of the ServiceHost and ServicePort when the instance ID is the ServiceKey, which isn't a
+// valid resource name in general.
+func endpointSliceName(config types.Config) string {
+	instance := config.GetServiceInstanceId()
+	if instance == config.ServiceKey {
+		instance = net.JoinHostPort(config.ServiceHost, strconv.Itoa(config.ServicePort))
+	}
+	hash := sha256.Sum256([]byte(instance))
+	return config.ServiceKey + endpointSliceSuffix + "-" + hex.EncodeToString(hash[:5])
+}
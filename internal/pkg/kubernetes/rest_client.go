@@ -0,0 +1,150 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/transport"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+)
+
+// statusError is the error of a request rejected by the API server with a Status
+type statusError struct {
+	statusCode int
+	message    string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("request failed, status code: %d, err: %s", e.statusCode, e.message)
+}
+
+// Is reports whether the request was rejected for its bearer token, as types.ErrUnauthorized
+func (e *statusError) Is(target error) bool {
+	return target == types.ErrUnauthorized && (e.statusCode == http.StatusUnauthorized || e.statusCode == http.StatusForbidden)
+}
+
+func isNotFound(err error) bool {
+	statusErr, ok := err.(*statusError)
+	return ok && statusErr.statusCode == http.StatusNotFound
+}
+
+type status struct {
+	Message string `json:"message"`
+}
+
+// restClient invokes the API server with the bearer token of the connection
+type restClient struct {
+	baseUrl    string
+	httpClient *http.Client
+	token      string
+	tokenFile  string
+}
+
+func newRestClient(conn connection, httpClient *http.Client) *restClient {
+	return &restClient{
+		baseUrl:    conn.server,
+		httpClient: httpClient,
+		token:      conn.token,
+		tokenFile:  conn.tokenFile,
+	}
+}
+
+// sendRequest sends the request with the optional JSON encoded data to the API server and decodes the JSON response
+// into result when it is not nil. Non 2xx responses are returned as statusError.
+func (rc *restClient) sendRequest(ctx context.Context, method string, requestPath string, requestParams url.Values, data any, result any) error {
+	requestUrl, err := url.JoinPath(rc.baseUrl, requestPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse baseUrl and requestPath: %w", err)
+	}
+	if requestParams != nil {
+		requestUrl += "?" + requestParams.Encode()
+	}
+
+	var body io.Reader
+	if data != nil {
+		jsonEncodedData, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to encode input data to JSON: %w", err)
+		}
+		body = bytes.NewReader(jsonEncodedData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestUrl, body)
+	if err != nil {
+		return fmt.Errorf("failed to create a http request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	token, err := rc.bearerToken(ctx)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set(authorizationHeader, bearerPrefix+token)
+	}
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send a http request: %w", transport.Unavailable(err))
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to get the body from the response: %w", err)
+	}
+
+	if resp.StatusCode > http.StatusMultiStatus {
+		message := string(bodyBytes)
+		res := status{}
+		if json.Unmarshal(bodyBytes, &res) == nil && res.Message != "" {
+			message = res.Message
+		}
+		return &statusError{statusCode: resp.StatusCode, message: message}
+	}
+
+	if result != nil && len(bodyBytes) > 0 {
+		if err := json.Unmarshal(bodyBytes, result); err != nil {
+			return fmt.Errorf("failed to parse the response body: %w", transport.Malformed(err))
+		}
+	}
+
+	return nil
+}
+
+// bearerToken returns the token carried by ctx if any, otherwise the token of the connection. The token file is read
+// for each request since Kubernetes rotates the tokens of service accounts.
+func (rc *restClient) bearerToken(ctx context.Context) (string, error) {
+	if token, ok := types.AccessTokenFromContext(ctx); ok {
+		return token, nil
+	}
+	if rc.tokenFile == "" {
+		return rc.token, nil
+	}
+
+	token, err := os.ReadFile(rc.tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to read token file %s: %w", rc.tokenFile, err)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
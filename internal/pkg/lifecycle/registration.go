@@ -0,0 +1,125 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package lifecycle
+
+import "sync"
+
+// State is the state of the registration of a service with the Registry
+type State int
+
+const (
+	Unregistered State = iota
+	Registering
+	Registered
+	Unregistering
+)
+
+func (s State) String() string {
+	switch s {
+	case Unregistered:
+		return "Unregistered"
+	case Registering:
+		return "Registering"
+	case Registered:
+		return "Registered"
+	case Unregistering:
+		return "Unregistering"
+	default:
+		return "Unknown"
+	}
+}
+
+// Registration sequences the operations on the registration of a service, so they never overlap, and keeps track of
+// whether the service is meant to be registered. This way a background operation restoring the registration can't
+// resurrect a registration which has been removed on purpose in the meantime. The zero value is an unregistered
+// service.
+type Registration struct {
+	// operationLock is held for the whole duration of an operation, lock only while reading or updating the states
+	operationLock sync.Mutex
+	lock          sync.Mutex
+	desired       State
+	state         State
+}
+
+// Register runs register, which registers the service with the Registry, and marks the service as meant to be
+// registered
+func (r *Registration) Register(register func() error) error {
+	r.setDesired(Registered)
+
+	r.operationLock.Lock()
+	defer r.operationLock.Unlock()
+
+	return r.run(Registering, Registered, register)
+}
+
+// Unregister runs unregister, which removes the registration of the service from the Registry, and marks the service
+// as not meant to be registered anymore. The mark is kept even if unregister fails, so the registration isn't
+// restored later on.
+func (r *Registration) Unregister(unregister func() error) error {
+	r.setDesired(Unregistered)
+
+	r.operationLock.Lock()
+	defer r.operationLock.Unlock()
+
+	return r.run(Unregistering, Unregistered, unregister)
+}
+
+// Restore runs register to restore the registration of the service, i.e. after the Registry lost it, but only while
+// the service is still meant to be registered. It reports whether register was run.
+func (r *Registration) Restore(register func() error) (bool, error) {
+	r.operationLock.Lock()
+	defer r.operationLock.Unlock()
+
+	if r.Desired() != Registered {
+		return false, nil
+	}
+
+	return true, r.run(Registering, Registered, register)
+}
+
+// State returns the current state of the registration
+func (r *Registration) State() State {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.state
+}
+
+// Desired returns whether the service is meant to be Registered or Unregistered
+func (r *Registration) Desired() State {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.desired
+}
+
+func (r *Registration) setDesired(desired State) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.desired = desired
+}
+
+// run runs the operation in the transitional state, ending up in the target state if the operation succeeds or back
+// in the state it started from otherwise. Callers must hold operationLock.
+func (r *Registration) run(transitional State, target State, operation func() error) error {
+	r.lock.Lock()
+	previous := r.state
+	r.state = transitional
+	r.lock.Unlock()
+
+	err := operation()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if err != nil {
+		r.state = previous
+	} else {
+		r.state = target
+	}
+
+	return err
+}
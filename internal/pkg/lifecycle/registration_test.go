@@ -0,0 +1,122 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package lifecycle
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrationStates(t *testing.T) {
+	var registration Registration
+	assert.Equal(t, Unregistered, registration.State())
+	assert.Equal(t, Unregistered, registration.Desired())
+
+	err := registration.Register(func() error {
+		assert.Equal(t, Registering, registration.State())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Registered, registration.State())
+	assert.Equal(t, Registered, registration.Desired())
+
+	err = registration.Unregister(func() error {
+		assert.Equal(t, Unregistering, registration.State())
+		return errors.New("registry unreachable")
+	})
+	require.Error(t, err)
+	assert.Equal(t, Registered, registration.State(), "Expected failed operation to keep the previous state")
+	assert.Equal(t, Unregistered, registration.Desired(), "Expected unregistering to be requested regardless")
+
+	err = registration.Unregister(func() error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, Unregistered, registration.State())
+}
+
+func TestRestoreAfterUnregister(t *testing.T) {
+	var registration Registration
+	require.NoError(t, registration.Register(func() error { return nil }))
+	require.NoError(t, registration.Unregister(func() error { return nil }))
+
+	restored, err := registration.Restore(func() error {
+		assert.Fail(t, "Expected registration not to be restored once unregistered")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.False(t, restored)
+	assert.Equal(t, Unregistered, registration.State())
+}
+
+func TestRestore(t *testing.T) {
+	var registration Registration
+	require.NoError(t, registration.Register(func() error { return nil }))
+
+	restored, err := registration.Restore(func() error { return nil })
+	require.NoError(t, err)
+	assert.True(t, restored)
+	assert.Equal(t, Registered, registration.State())
+}
+
+func TestUnregisterRacingRestore(t *testing.T) {
+	var registration Registration
+	require.NoError(t, registration.Register(func() error { return nil }))
+
+	var operations []string
+	var lock sync.Mutex
+	record := func(operation string) {
+		lock.Lock()
+		defer lock.Unlock()
+		operations = append(operations, operation)
+	}
+
+	restoreStarted := make(chan struct{})
+	releaseRestore := make(chan struct{})
+	restoreDone := make(chan struct{})
+	go func() {
+		defer close(restoreDone)
+		_, _ = registration.Restore(func() error {
+			close(restoreStarted)
+			<-releaseRestore
+			record("restore")
+			return nil
+		})
+	}()
+
+	// Unregister while the restore is in flight
+	<-restoreStarted
+	unregisterDone := make(chan struct{})
+	go func() {
+		defer close(unregisterDone)
+		_ = registration.Unregister(func() error {
+			record("unregister")
+			return nil
+		})
+	}()
+
+	select {
+	case <-unregisterDone:
+		require.Fail(t, "Expected unregister to wait for the restore in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(releaseRestore)
+	<-restoreDone
+	<-unregisterDone
+
+	assert.Equal(t, []string{"restore", "unregister"}, operations)
+	assert.Equal(t, Unregistered, registration.State())
+	assert.Equal(t, Unregistered, registration.Desired())
+
+	// A restore queued up behind the unregister is dropped
+	restored, err := registration.Restore(func() error { return nil })
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
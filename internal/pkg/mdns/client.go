@@ -0,0 +1,427 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/health"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/lifecycle"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/watch"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	defaultGroupHost   = "224.0.0.251"
+	defaultServiceType = "_edgex._tcp"
+)
+
+// mdnsClient implements a registry-less registry for single subnet deployments with multicast DNS Service Discovery.
+// Registering advertises the current service on the multicast group and answers the queries of its peers, which
+// discover it as long as it is running. Host and Port are the multicast group, the standard mDNS group by default.
+// Service keys must not contain dots, which would split the instance name.
+type mdnsClient struct {
+	config        *types.Config
+	scheduler     *watch.Scheduler
+	group         *net.UDPAddr
+	serviceType   string
+	browseTimeout time.Duration
+	serviceKey    string
+	serviceHost   string
+	servicePort   int
+
+	responderLock sync.Mutex
+	responder     *responder
+	registration  lifecycle.Registration
+}
+
+// NewMDNSClient creates new mDNS Client. Service details are optional, not needed just for discovery, but required if
+// advertising the current service
+func NewMDNSClient(registryConfig types.Config) (*mdnsClient, error) {
+	host := registryConfig.Host
+	if host == "" {
+		host = defaultGroupHost
+	}
+	port := registryConfig.Port
+	if port == 0 {
+		port = mdnsPort
+	}
+	group, err := groupAddress(host, port)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new mDNS Client: %w", err)
+	}
+	if !group.IP.IsMulticast() {
+		return nil, fmt.Errorf("unable to create new mDNS Client: %s isn't a multicast address", host)
+	}
+
+	browseTimeout, err := registryConfig.GetMDNSBrowseTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new mDNS Client: %w", err)
+	}
+	if browseTimeout == 0 {
+		return nil, fmt.Errorf("unable to create new mDNS Client: mDNS browse timeout must be greater than zero")
+	}
+
+	client := mdnsClient{
+		config:        &registryConfig,
+		scheduler:     watch.NewScheduler(registryConfig.WatchConcurrency),
+		group:         group,
+		serviceType:   registryConfig.MDNSServiceType,
+		browseTimeout: browseTimeout,
+		serviceKey:    registryConfig.ServiceKey,
+	}
+	if client.serviceType == "" {
+		client.serviceType = defaultServiceType
+	}
+
+	// ServiceHost will be empty when client isn't advertising the service
+	if registryConfig.ServiceHost != "" {
+		client.servicePort = registryConfig.ServicePort
+		client.serviceHost = registryConfig.ServiceHost
+	}
+
+	return &client, nil
+}
+
+// IsAlive checks if queries can be sent to the multicast group, there being no Registry to check
+func (c *mdnsClient) IsAlive() bool {
+	return c.IsAliveWithContext(context.Background())
+}
+
+// IsAliveWithContext checks if queries can be sent to the multicast group
+func (c *mdnsClient) IsAliveWithContext(_ context.Context) bool {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	query, err := newQuery(serviceName(c.serviceType), dnsmessage.TypePTR)
+	if err != nil {
+		return false
+	}
+	_, err = conn.WriteToUDP(query, c.group)
+	return err == nil
+}
+
+// Register advertises the current service on the multicast group until unregistered
+func (c *mdnsClient) Register() error {
+	return c.RegisterWithContext(context.Background())
+}
+
+// RegisterWithContext advertises the current service on the multicast group until unregistered
+func (c *mdnsClient) RegisterWithContext(ctx context.Context) error {
+	return c.registration.Register(func() error {
+		return c.register(ctx)
+	})
+}
+
+func (c *mdnsClient) register(ctx context.Context) error {
+	if c.serviceKey == "" || c.serviceHost == "" || c.servicePort == 0 {
+		return fmt.Errorf("unable to register service with mDNS: Service information not set")
+	}
+
+	i := instance{serviceKey: c.serviceKey, host: c.serviceHost, port: c.servicePort}
+	if c.config.GetCheckType() == types.CheckTypeHTTP {
+		i.checkUrl = c.config.GetHealthCheckUrl()
+		if c.config.GetProbeBeforeRegister() {
+			if err := health.Probe(ctx, i.checkUrl, c.config.GetCheckOptions(c.config.ServiceKey)); err != nil {
+				return fmt.Errorf("unable to register service with mDNS: %w", err)
+			}
+		}
+	}
+
+	c.responderLock.Lock()
+	defer c.responderLock.Unlock()
+
+	// Registering again advertises the latest service information
+	if c.responder != nil {
+		_ = c.responder.stop()
+		c.responder = nil
+	}
+
+	r, err := startResponder(c.group, c.serviceType, i)
+	if err != nil {
+		return fmt.Errorf("failed to advertise the %s service: %w", c.serviceKey, err)
+	}
+	c.responder = r
+
+	return nil
+}
+
+// RegisterCheck registers a health check with mDNS
+func (c *mdnsClient) RegisterCheck(id string, name string, notes string, url string, interval string) error {
+	return c.RegisterCheckWithContext(context.Background(), id, name, notes, url, interval)
+}
+
+// RegisterCheckWithContext registers a health check with mDNS
+func (c *mdnsClient) RegisterCheckWithContext(_ context.Context, id string, name string, notes string, url string, interval string) error {
+	// the health check URL is advertised along with the service, there being no Registry to run it
+	return nil
+}
+
+// Unregister stops advertising the current service, announcing it is going away
+func (c *mdnsClient) Unregister() error {
+	return c.UnregisterWithContext(context.Background())
+}
+
+// UnregisterWithContext stops advertising the current service, announcing it is going away
+func (c *mdnsClient) UnregisterWithContext(_ context.Context) error {
+	return c.registration.Unregister(c.unregister)
+}
+
+func (c *mdnsClient) unregister() error {
+	c.responderLock.Lock()
+	defer c.responderLock.Unlock()
+
+	if c.responder == nil {
+		return nil
+	}
+
+	err := c.responder.stop()
+	c.responder = nil
+	if err != nil {
+		return fmt.Errorf("failed to de-register %s: %w", c.serviceKey, err)
+	}
+
+	return nil
+}
+
+// Decommission stops advertising the current service. Other services can only stop advertising themselves.
+func (c *mdnsClient) Decommission(_ context.Context, serviceKey string) error {
+	if serviceKey != c.serviceKey {
+		return fmt.Errorf("unable to decommission %s: services are only advertised by themselves with mDNS", serviceKey)
+	}
+
+	return c.registration.Unregister(c.unregister)
+}
+
+// WatchSelf queries the multicast group for the current service and notifies the caller each time the advertised
+// endpoint has changed or disappeared, i.e. because another instance advertises itself under the same key. Watching
+// stops and the returned channel is closed once ctx is cancelled.
+func (c *mdnsClient) WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error) {
+	if c.serviceKey == "" || c.serviceHost == "" || c.servicePort == 0 {
+		return nil, fmt.Errorf("unable to watch service registration with mDNS: Service information not set")
+	}
+
+	interval, err := c.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	expected := types.ServiceEndpoint{ServiceId: c.serviceKey, Host: c.serviceHost, Port: c.servicePort}
+	return watch.Self(ctx, c.scheduler, interval, expected, func() (types.ServiceEndpoint, error) {
+		return c.registeredEndpoint(ctx, c.serviceKey)
+	}), nil
+}
+
+// WatchService queries the multicast group for the endpoint of the target service at every watch interval and sends
+// it each time it changes, starting with the current one. An empty endpoint is sent when the service isn't advertised
+// (anymore). Watching stops and the returned channel is closed once ctx is cancelled.
+func (c *mdnsClient) WatchService(ctx context.Context, serviceKey string) (<-chan types.ServiceEndpoint, error) {
+	interval, err := c.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Endpoint(ctx, c.scheduler, interval, func() (types.ServiceEndpoint, error) {
+		return c.registeredEndpoint(ctx, serviceKey)
+	}), nil
+}
+
+// registeredEndpoint resolves the endpoint of the target service, which is empty when it isn't advertised
+func (c *mdnsClient) registeredEndpoint(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	i, found, err := c.resolve(ctx, serviceKey)
+	if err != nil || !found {
+		return types.ServiceEndpoint{}, err
+	}
+
+	return endpoint(i), nil
+}
+
+// TriggerHealthCheck calls the health check route advertised by the target service, there being no Registry to run it
+func (c *mdnsClient) TriggerHealthCheck(ctx context.Context, serviceKey string) (types.HealthCheckResult, error) {
+	i, found, err := c.resolve(ctx, serviceKey)
+	if err != nil {
+		return types.HealthCheckResult{}, err
+	}
+	if !found {
+		return types.HealthCheckResult{}, types.Errorf(types.ErrNotRegistered, "unable to health check %s: service is not registered", serviceKey)
+	}
+
+	if i.checkUrl == "" {
+		return types.HealthCheckResult{Healthy: true, Output: "service registered without health check"}, nil
+	}
+
+	return health.Check(ctx, c.config.GetCheckOptions(serviceKey), i.checkUrl), nil
+}
+
+// GetServiceEndpoint queries the multicast group for the port, service ID and host of the target service.
+// If this operation is successful and a known endpoint is found, it is returned. Otherwise, an error is returned.
+func (c *mdnsClient) GetServiceEndpoint(serviceKey string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointWithContext queries the multicast group for the endpoint of the target service, aborting once ctx
+// is done
+func (c *mdnsClient) GetServiceEndpointWithContext(ctx context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	i, found, err := c.resolve(ctx, serviceKey)
+	if err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
+	}
+	if !found {
+		return types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+
+	endpoint := endpoint(i)
+	if err := endpoint.Validate(); err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
+	}
+	return endpoint, nil
+}
+
+// GetServiceEndpoints queries the multicast group for the endpoint of the target service, whose instance name is the
+// service key so a single instance is advertised per service key
+func (c *mdnsClient) GetServiceEndpoints(serviceKey string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointsWithContext queries the multicast group for the endpoint of the target service, aborting once
+// ctx is done
+func (c *mdnsClient) GetServiceEndpointsWithContext(ctx context.Context, serviceKey string) ([]types.ServiceEndpoint, error) {
+	endpoint, err := c.GetServiceEndpointWithContext(ctx, serviceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.ServiceEndpoint{endpoint}, nil
+}
+
+// GetAllServiceEndpoints browses the multicast group for the endpoints of all the services answering within the
+// browse timeout.
+func (c *mdnsClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+// GetAllServiceEndpointsWithContext browses the multicast group for the endpoints of all the services, aborting once
+// ctx is done
+func (c *mdnsClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	instances, err := c.query(ctx, serviceName(c.serviceType), dnsmessage.TypePTR, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all service endpoints: %w", err)
+	}
+
+	endpoints := make([]types.ServiceEndpoint, 0, len(instances))
+	for _, i := range instances {
+		endpoint := endpoint(i)
+		if err := endpoint.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to get all service endpoints: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	types.SortServiceEndpoints(endpoints, c.config.GetEndpointOrder())
+
+	return endpoints, nil
+}
+
+// IsServiceAvailable checks if the target service answers mDNS queries, which it does as long as it is running
+func (c *mdnsClient) IsServiceAvailable(serviceKey string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceKey)
+}
+
+// IsServiceAvailableWithContext checks if the target service answers mDNS queries, aborting once ctx is done
+func (c *mdnsClient) IsServiceAvailableWithContext(ctx context.Context, serviceKey string) (bool, error) {
+	_, found, err := c.resolve(ctx, serviceKey)
+	if err != nil {
+		return false, fmt.Errorf("unable to check if service %s is available: %w", serviceKey, err)
+	}
+	if !found {
+		return false, types.Errorf(types.ErrNotRegistered, "%s service is not registered. Might not have started... ", serviceKey)
+	}
+
+	return true, nil
+}
+
+// resolve queries the multicast group for the instance of the target service, reporting whether it answered
+func (c *mdnsClient) resolve(ctx context.Context, serviceKey string) (instance, bool, error) {
+	instances, err := c.query(ctx, instanceName(serviceKey, c.serviceType), dnsmessage.TypeSRV, serviceKey)
+	if err != nil {
+		return instance{}, false, err
+	}
+
+	i, found := instances[serviceKey]
+	return i, found, nil
+}
+
+// query sends a one-shot query to the multicast group and collects the advertised instances answering it until the
+// browse timeout, or until the instance of the awaited service key answered if set
+func (c *mdnsClient) query(ctx context.Context, name string, queryType dnsmessage.Type, awaited string) (map[string]instance, error) {
+	query, err := newQuery(name, queryType)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.browseTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	// Unblock the read once ctx is cancelled
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	if _, err := conn.WriteToUDP(query, c.group); err != nil {
+		return nil, err
+	}
+
+	instances := make(map[string]instance)
+	buffer := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFromUDP(buffer)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return instances, ctx.Err()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var response dnsmessage.Message
+		if err := response.Unpack(buffer[:n]); err != nil || !response.Response {
+			continue
+		}
+		for serviceKey, i := range parseInstances(response, c.serviceType) {
+			instances[serviceKey] = i
+		}
+		if _, ok := instances[awaited]; ok && awaited != "" {
+			return instances, nil
+		}
+	}
+}
+
+func endpoint(i instance) types.ServiceEndpoint {
+	return types.ServiceEndpoint{
+		ServiceId: i.serviceKey,
+		Host:      i.host,
+		Port:      i.port,
+	}
+}
@@ -0,0 +1,243 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mdns
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/hostile"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	testServiceName    = "mdnsunittest"
+	defaultServiceHost = "10.0.0.7"
+	defaultServicePort = 59880
+	testBrowseTimeout  = "200ms"
+)
+
+func TestRegisterAndDiscover(t *testing.T) {
+	port := getGroupPort(t)
+	first := makeMDNSClient(t, port, getUniqueServiceName(), defaultServiceHost)
+	second := makeMDNSClient(t, port, getUniqueServiceName(), "edgex-core-data")
+
+	require.NoError(t, first.Register())
+	defer first.Unregister()
+	require.NoError(t, second.Register())
+	defer second.Unregister()
+
+	endpoint, err := second.GetServiceEndpoint(first.serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, types.ServiceEndpoint{ServiceId: first.serviceKey, Host: defaultServiceHost, Port: defaultServicePort}, endpoint)
+
+	endpoint, err = first.GetServiceEndpoint(second.serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, "edgex-core-data", endpoint.Host)
+
+	available, err := first.IsServiceAvailable(second.serviceKey)
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	endpoints, err := first.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	assert.ElementsMatch(t, []string{first.serviceKey, second.serviceKey}, []string{endpoints[0].ServiceId, endpoints[1].ServiceId})
+}
+
+func TestGetServiceEndpointHostileAdvertisements(t *testing.T) {
+	for _, registration := range hostile.Registrations() {
+		t.Run(registration.Name, func(t *testing.T) {
+			host, port, ok := registration.Address()
+			if !ok || port < 0 || port > math.MaxUint16 || len(host) > types.MaxHostLength {
+				t.Skip("not representable as mDNS records")
+			}
+			groupPort := getGroupPort(t)
+			group, err := groupAddress(defaultGroupHost, groupPort)
+			require.NoError(t, err)
+			advertiser, err := startResponder(group, defaultServiceType, instance{serviceKey: getUniqueServiceName(), host: host, port: port})
+			require.NoError(t, err)
+			defer advertiser.stop()
+			client := makeMDNSClient(t, groupPort, getUniqueServiceName(), defaultServiceHost)
+
+			endpoint, err := client.GetServiceEndpoint(advertiser.instance.serviceKey)
+			endpoints, allErr := client.GetAllServiceEndpoints()
+			if !registration.Valid {
+				require.ErrorIs(t, err, types.ErrMalformedResponse)
+				require.ErrorIs(t, allErr, types.ErrMalformedResponse)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, hostile.ValidHost, endpoint.Host)
+			assert.Equal(t, hostile.ValidPort, endpoint.Port)
+			require.NoError(t, allErr)
+			assert.Equal(t, []types.ServiceEndpoint{endpoint}, endpoints)
+		})
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	port := getGroupPort(t)
+	client := makeMDNSClient(t, port, getUniqueServiceName(), defaultServiceHost)
+	require.NoError(t, client.Register())
+	require.NoError(t, client.Register(), "Expected registering again to advertise the service again")
+
+	_, err := client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Unregister())
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.EqualError(t, err, "no matching service endpoint found")
+
+	_, err = client.IsServiceAvailable(client.serviceKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service is not registered")
+
+	require.NoError(t, client.Register())
+	require.NoError(t, client.Decommission(context.Background(), client.serviceKey))
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.Error(t, err, "Expected decommissioned service not to be advertised")
+
+	require.Error(t, client.Decommission(context.Background(), getUniqueServiceName()),
+		"Expected error decommissioning another service")
+}
+
+func TestRegisterNoServiceInfoError(t *testing.T) {
+	client, err := NewMDNSClient(types.Config{Host: defaultGroupHost, Port: getGroupPort(t)})
+	require.NoError(t, err)
+
+	err = client.Register()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Service information not set")
+}
+
+func TestNewMDNSClientErrors(t *testing.T) {
+	_, err := NewMDNSClient(types.Config{Host: "127.0.0.1", Port: mdnsPort})
+	require.Error(t, err, "Expected error with unicast group address")
+
+	_, err = NewMDNSClient(types.Config{MDNSBrowseTimeout: "0s"})
+	require.Error(t, err, "Expected error with zero browse timeout")
+
+	client, err := NewMDNSClient(types.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, "224.0.0.251:5353", client.group.String())
+	assert.Equal(t, "_edgex._tcp", client.serviceType)
+	assert.True(t, client.IsAlive())
+}
+
+func TestTriggerHealthCheck(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+	serverUrl, _ := url.Parse(testServer.URL)
+	servicePort, _ := strconv.Atoi(serverUrl.Port())
+
+	client, err := NewMDNSClient(types.Config{
+		Host:              defaultGroupHost,
+		Port:              getGroupPort(t),
+		ServiceKey:        getUniqueServiceName(),
+		ServiceHost:       serverUrl.Hostname(),
+		ServicePort:       servicePort,
+		CheckRoute:        "/api/v3/ping",
+		MDNSBrowseTimeout: testBrowseTimeout,
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Register())
+	defer client.Unregister()
+
+	result, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, result.Output)
+
+	_, err = client.TriggerHealthCheck(context.Background(), getUniqueServiceName())
+	require.Error(t, err)
+}
+
+func TestWatchService(t *testing.T) {
+	port := getGroupPort(t)
+	client := makeMDNSClient(t, port, getUniqueServiceName(), defaultServiceHost)
+	client.config.WatchInterval = "10ms"
+	client.browseTimeout = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	endpoints, err := client.WatchService(ctx, client.serviceKey)
+	require.NoError(t, err)
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint before registering")
+
+	require.NoError(t, client.Register())
+	require.Equal(t, client.serviceKey, receiveEndpoint(t, endpoints).ServiceId)
+
+	require.NoError(t, client.Unregister())
+	require.Equal(t, types.ServiceEndpoint{}, receiveEndpoint(t, endpoints), "Expected empty endpoint once unregistered")
+}
+
+func TestParseInstances(t *testing.T) {
+	i := instance{serviceKey: "core-data", host: "10.0.0.1", port: 59880, checkUrl: "http://10.0.0.1:59880/api/v3/ping"}
+	resources, err := i.resources(defaultServiceType, recordTTL)
+	require.NoError(t, err)
+	require.Len(t, resources, 4, "Expected PTR, SRV, TXT and A records")
+
+	instances := parseInstances(dnsmessage.Message{Answers: resources[:1], Additionals: resources[1:]}, defaultServiceType)
+	assert.Equal(t, map[string]instance{"core-data": i}, instances)
+
+	goodbye, err := i.resources(defaultServiceType, 0)
+	require.NoError(t, err)
+	assert.Empty(t, parseInstances(dnsmessage.Message{Answers: goodbye}, defaultServiceType),
+		"Expected instances going away to be ignored")
+
+	assert.Empty(t, parseInstances(dnsmessage.Message{Answers: resources}, "_other._tcp"),
+		"Expected instances of other service types to be ignored")
+}
+
+func receiveEndpoint(t *testing.T, endpoints <-chan types.ServiceEndpoint) types.ServiceEndpoint {
+	select {
+	case endpoint := <-endpoints:
+		return endpoint
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "Timed out waiting for service endpoint")
+		return types.ServiceEndpoint{}
+	}
+}
+
+// getGroupPort returns a free port, so each test uses its own multicast group rather than the real mDNS one
+func getGroupPort(t *testing.T) int {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	require.NoError(t, err)
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	require.NoError(t, conn.Close())
+	return port
+}
+
+func makeMDNSClient(t *testing.T, port int, serviceName string, serviceHost string) *mdnsClient {
+	client, err := NewMDNSClient(types.Config{
+		Host:              defaultGroupHost,
+		Port:              port,
+		ServiceKey:        serviceName,
+		ServiceHost:       serviceHost,
+		ServicePort:       defaultServicePort,
+		MDNSBrowseTimeout: testBrowseTimeout,
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+func getUniqueServiceName() string {
+	return testServiceName + strconv.Itoa(time.Now().Nanosecond())
+}
@@ -0,0 +1,167 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mdns
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	localDomain = "local."
+	recordTTL   = 120
+
+	txtHost  = "host="
+	txtCheck = "check="
+)
+
+// instance is a service advertised with DNS Service Discovery, i.e. core-data._edgex._tcp.local. The TXT record carries
+// the host exactly as registered, since SRV targets must be host names, and the health check URL if any.
+type instance struct {
+	serviceKey string
+	host       string
+	port       int
+	checkUrl   string
+}
+
+// serviceName returns the name of the DNS-SD service type in the local domain, i.e. _edgex._tcp.local.
+func serviceName(serviceType string) string {
+	return strings.TrimSuffix(serviceType, ".") + "." + localDomain
+}
+
+// instanceName returns the name of the service instance of the target service, i.e. core-data._edgex._tcp.local.
+func instanceName(serviceKey string, serviceType string) string {
+	return serviceKey + "." + serviceName(serviceType)
+}
+
+// hostName returns the name the SRV record of the instance targets, i.e. core-data.local.
+func (i instance) hostName() string {
+	if net.ParseIP(i.host) == nil {
+		return strings.TrimSuffix(i.host, ".") + "."
+	}
+	return i.serviceKey + "." + localDomain
+}
+
+// resources returns the PTR, SRV and TXT records advertising the instance, followed by the A or AAAA record of its
+// host name when registered with an IP address. A TTL of 0 announces the instance is going away.
+func (i instance) resources(serviceType string, ttl uint32) ([]dnsmessage.Resource, error) {
+	service, err := dnsmessage.NewName(serviceName(serviceType))
+	if err != nil {
+		return nil, err
+	}
+	name, err := dnsmessage.NewName(instanceName(i.serviceKey, serviceType))
+	if err != nil {
+		return nil, err
+	}
+	host, err := dnsmessage.NewName(i.hostName())
+	if err != nil {
+		return nil, err
+	}
+
+	txt := []string{txtHost + i.host}
+	if i.checkUrl != "" {
+		txt = append(txt, txtCheck+i.checkUrl)
+	}
+
+	resources := []dnsmessage.Resource{
+		{
+			Header: dnsmessage.ResourceHeader{Name: service, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET, TTL: ttl},
+			Body:   &dnsmessage.PTRResource{PTR: name},
+		},
+		{
+			Header: dnsmessage.ResourceHeader{Name: name, Type: dnsmessage.TypeSRV, Class: dnsmessage.ClassINET, TTL: ttl},
+			Body:   &dnsmessage.SRVResource{Port: uint16(i.port), Target: host},
+		},
+		{
+			Header: dnsmessage.ResourceHeader{Name: name, Type: dnsmessage.TypeTXT, Class: dnsmessage.ClassINET, TTL: ttl},
+			Body:   &dnsmessage.TXTResource{TXT: txt},
+		},
+	}
+
+	ip := net.ParseIP(i.host)
+	switch {
+	case ip == nil:
+	case ip.To4() != nil:
+		resources = append(resources, dnsmessage.Resource{
+			Header: dnsmessage.ResourceHeader{Name: host, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: ttl},
+			Body:   &dnsmessage.AResource{A: [4]byte(ip.To4())},
+		})
+	default:
+		resources = append(resources, dnsmessage.Resource{
+			Header: dnsmessage.ResourceHeader{Name: host, Type: dnsmessage.TypeAAAA, Class: dnsmessage.ClassINET, TTL: ttl},
+			Body:   &dnsmessage.AAAAResource{AAAA: [16]byte(ip.To16())},
+		})
+	}
+
+	return resources, nil
+}
+
+// newQuery packs a query for the records of the given name
+func newQuery(name string, queryType dnsmessage.Type) ([]byte, error) {
+	queryName, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mDNS name %s: %v", name, err)
+	}
+
+	query := dnsmessage.Message{
+		Questions: []dnsmessage.Question{{Name: queryName, Type: queryType, Class: dnsmessage.ClassINET}},
+	}
+	return query.Pack()
+}
+
+// parseInstances returns the instances of the service type whose SRV records are in the response, keyed by service key
+func parseInstances(response dnsmessage.Message, serviceType string) map[string]instance {
+	suffix := "." + strings.ToLower(serviceName(serviceType))
+	instances := make(map[string]instance)
+
+	resources := append(append([]dnsmessage.Resource(nil), response.Answers...), response.Additionals...)
+	for _, resource := range resources {
+		name := resource.Header.Name.String()
+		if !strings.HasSuffix(strings.ToLower(name), suffix) || resource.Header.TTL == 0 {
+			continue
+		}
+		serviceKey := name[:len(name)-len(suffix)]
+
+		switch body := resource.Body.(type) {
+		case *dnsmessage.SRVResource:
+			i := instances[serviceKey]
+			i.serviceKey = serviceKey
+			i.port = int(body.Port)
+			if i.host == "" {
+				i.host = strings.TrimSuffix(body.Target.String(), ".")
+			}
+			instances[serviceKey] = i
+		case *dnsmessage.TXTResource:
+			i := instances[serviceKey]
+			for _, txt := range body.TXT {
+				switch {
+				case strings.HasPrefix(txt, txtHost):
+					i.host = strings.TrimPrefix(txt, txtHost)
+				case strings.HasPrefix(txt, txtCheck):
+					i.checkUrl = strings.TrimPrefix(txt, txtCheck)
+				}
+			}
+			instances[serviceKey] = i
+		}
+	}
+
+	// TXT records without SRV record don't advertise an endpoint
+	for serviceKey, i := range instances {
+		if i.serviceKey == "" {
+			delete(instances, serviceKey)
+		}
+	}
+
+	return instances
+}
+
+func groupAddress(host string, port int) (*net.UDPAddr, error) {
+	return net.ResolveUDPAddr("udp4", net.JoinHostPort(host, strconv.Itoa(port)))
+}
@@ -0,0 +1,131 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mdns
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// mdnsPort is the port of the queries expecting multicast responses, any other port being a one-shot querier
+// expecting unicast responses
+const mdnsPort = 5353
+
+// responder advertises an instance on the multicast group, answering the queries for its service type or instance
+// name. Queriers not sending from the mDNS port, like the registry client itself, get unicast responses.
+type responder struct {
+	conn        *net.UDPConn
+	group       *net.UDPAddr
+	serviceType string
+	instance    instance
+	stopped     sync.WaitGroup
+}
+
+// startResponder joins the multicast group, announces the instance and answers queries until stopped
+func startResponder(group *net.UDPAddr, serviceType string, i instance) (*responder, error) {
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &responder{conn: conn, group: group, serviceType: serviceType, instance: i}
+	if err := r.announce(recordTTL); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	r.stopped.Add(1)
+	go r.serve()
+
+	return r, nil
+}
+
+// stop announces the instance is going away, so peers drop it from their caches, then leaves the multicast group
+func (r *responder) stop() error {
+	err := r.announce(0)
+	_ = r.conn.Close()
+	r.stopped.Wait()
+	return err
+}
+
+func (r *responder) announce(ttl uint32) error {
+	response, err := r.response(dnsmessage.Header{}, nil, ttl)
+	if err != nil {
+		return err
+	}
+	_, err = r.conn.WriteToUDP(response, r.group)
+	return err
+}
+
+func (r *responder) serve() {
+	defer r.stopped.Done()
+
+	buffer := make([]byte, 9000)
+	for {
+		n, addr, err := r.conn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+
+		var query dnsmessage.Message
+		if err := query.Unpack(buffer[:n]); err != nil || query.Response || !r.matches(query.Questions) {
+			continue
+		}
+
+		unicast := addr.Port != mdnsPort
+		header := dnsmessage.Header{}
+		questions := []dnsmessage.Question(nil)
+		if unicast {
+			// One-shot queriers match the response to their query, as with unicast DNS
+			header.ID = query.ID
+			questions = query.Questions
+		}
+		response, err := r.response(header, questions, recordTTL)
+		if err != nil {
+			continue
+		}
+
+		target := r.group
+		if unicast {
+			target = addr
+		}
+		_, _ = r.conn.WriteToUDP(response, target)
+	}
+}
+
+// matches tells whether any of the questions is about the service type or the instance
+func (r *responder) matches(questions []dnsmessage.Question) bool {
+	service := strings.ToLower(serviceName(r.serviceType))
+	name := strings.ToLower(instanceName(r.instance.serviceKey, r.serviceType))
+
+	for _, question := range questions {
+		questionName := strings.ToLower(question.Name.String())
+		if questionName == service || questionName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *responder) response(header dnsmessage.Header, questions []dnsmessage.Question, ttl uint32) ([]byte, error) {
+	resources, err := r.instance.resources(r.serviceType, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	header.Response = true
+	header.Authoritative = true
+	response := dnsmessage.Message{
+		Header:      header,
+		Questions:   questions,
+		Answers:     resources[:1],
+		Additionals: resources[1:],
+	}
+	return response.Pack()
+}
@@ -0,0 +1,304 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/health"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/lifecycle"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/watch"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// registration is a service registered in memory, along with the health check URL it was registered with, if any
+type registration struct {
+	endpoint types.ServiceEndpoint
+	checkUrl string
+}
+
+// memoryClient keeps the registrations in-process, for running services without Registry in development mode and for
+// unit testing discovery logic. Each client has its own registrations, pre-seeded with the configured endpoints. The
+// services aren't health checked, so registered services are available.
+type memoryClient struct {
+	config      *types.Config
+	scheduler   *watch.Scheduler
+	serviceKey  string
+	serviceHost string
+	servicePort int
+
+	lock         sync.RWMutex
+	services     map[string]registration
+	registration lifecycle.Registration
+}
+
+// NewMemoryClient creates new in-memory Client pre-seeded with the configured endpoints. Service details are optional,
+// not needed just for discovery, but required if registering
+func NewMemoryClient(registryConfig types.Config) (*memoryClient, error) {
+	client := memoryClient{
+		config:     &registryConfig,
+		scheduler:  watch.NewScheduler(registryConfig.WatchConcurrency),
+		serviceKey: registryConfig.ServiceKey,
+		services:   make(map[string]registration, len(registryConfig.MemoryEndpoints)),
+	}
+
+	// ServiceHost will be empty when client isn't registering the service
+	if registryConfig.ServiceHost != "" {
+		client.servicePort = registryConfig.ServicePort
+		client.serviceHost = registryConfig.ServiceHost
+	}
+
+	for _, endpoint := range registryConfig.MemoryEndpoints {
+		if endpoint.ServiceId == "" {
+			return nil, fmt.Errorf("unable to create new memory Client: seeded endpoint %s:%d has no service ID", endpoint.Host, endpoint.Port)
+		}
+		client.services[endpoint.ServiceId] = registration{endpoint: endpoint}
+	}
+
+	return &client, nil
+}
+
+// IsAlive always returns true, the registrations being in-process
+func (c *memoryClient) IsAlive() bool {
+	return c.IsAliveWithContext(context.Background())
+}
+
+// IsAliveWithContext always returns true, the registrations being in-process
+func (c *memoryClient) IsAliveWithContext(_ context.Context) bool {
+	return true
+}
+
+// Register registers the current service in memory
+func (c *memoryClient) Register() error {
+	return c.RegisterWithContext(context.Background())
+}
+
+// RegisterWithContext registers the current service in memory
+func (c *memoryClient) RegisterWithContext(ctx context.Context) error {
+	return c.registration.Register(func() error {
+		return c.register(ctx)
+	})
+}
+
+// endpoint returns the endpoint of the current service, with copies of its metadata and tags so the registration isn't
+// changed along with the configuration
+func (c *memoryClient) endpoint() types.ServiceEndpoint {
+	return types.ServiceEndpoint{
+		ServiceId: c.serviceKey,
+		Host:      c.serviceHost,
+		Port:      c.servicePort,
+		Scheme:    c.config.GetServiceMetadata()[types.SchemeMetadataKey],
+		Metadata:  maps.Clone(c.config.GetServiceMetadata()),
+		Tags:      slices.Clone(c.config.ServiceTags),
+	}
+}
+
+func (c *memoryClient) register(ctx context.Context) error {
+	if c.serviceKey == "" || c.serviceHost == "" || c.servicePort == 0 {
+		return fmt.Errorf("unable to register service in memory: Service information not set")
+	}
+
+	r := registration{endpoint: c.endpoint()}
+	if c.config.GetCheckType() == types.CheckTypeHTTP && c.config.CheckRoute != "" {
+		r.checkUrl = c.config.GetHealthCheckUrl()
+		if c.config.GetProbeBeforeRegister() {
+			if err := health.Probe(ctx, r.checkUrl, c.config.GetCheckOptions(c.config.ServiceKey)); err != nil {
+				return fmt.Errorf("unable to register service in memory: %w", err)
+			}
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.services[c.serviceKey] = r
+	return nil
+}
+
+// RegisterCheck registers a health check in memory
+func (c *memoryClient) RegisterCheck(id string, name string, notes string, url string, interval string) error {
+	return c.RegisterCheckWithContext(context.Background(), id, name, notes, url, interval)
+}
+
+// RegisterCheckWithContext registers a health check in memory
+func (c *memoryClient) RegisterCheckWithContext(_ context.Context, id string, name string, notes string, url string, interval string) error {
+	// the health check URL is kept along with the registration, services aren't health checked in the background
+	return nil
+}
+
+// Unregister removes the current service from memory
+func (c *memoryClient) Unregister() error {
+	return c.UnregisterWithContext(context.Background())
+}
+
+// UnregisterWithContext removes the current service from memory
+func (c *memoryClient) UnregisterWithContext(_ context.Context) error {
+	return c.registration.Unregister(func() error {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		delete(c.services, c.serviceKey)
+		return nil
+	})
+}
+
+// Decommission permanently removes the target service from memory
+func (c *memoryClient) Decommission(_ context.Context, serviceKey string) error {
+	decommission := func() error {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if _, found := c.services[serviceKey]; !found {
+			return types.Errorf(types.ErrNotRegistered, "unable to decommission %s: service is not registered", serviceKey)
+		}
+		delete(c.services, serviceKey)
+		return nil
+	}
+
+	// Decommissioning the current service must not be undone by restoring its registration
+	if serviceKey == c.serviceKey {
+		return c.registration.Unregister(decommission)
+	}
+
+	return decommission()
+}
+
+// WatchSelf checks the registration of the current service at every watch interval and notifies the caller each time
+// it has been modified or removed by someone else, i.e. by decommissioning it. Watching stops and the returned channel
+// is closed once ctx is cancelled.
+func (c *memoryClient) WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error) {
+	if c.serviceKey == "" || c.serviceHost == "" || c.servicePort == 0 {
+		return nil, fmt.Errorf("unable to watch service registration in memory: Service information not set")
+	}
+
+	interval, err := c.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Self(ctx, c.scheduler, interval, c.endpoint(), func() (types.ServiceEndpoint, error) {
+		return c.registeredEndpoint(c.serviceKey), nil
+	}), nil
+}
+
+// WatchService checks the endpoint of the target service at every watch interval and sends it each time it changes,
+// starting with the current one. An empty endpoint is sent when the service isn't registered (anymore). Watching stops
+// and the returned channel is closed once ctx is cancelled.
+func (c *memoryClient) WatchService(ctx context.Context, serviceKey string) (<-chan types.ServiceEndpoint, error) {
+	interval, err := c.config.GetWatchInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Endpoint(ctx, c.scheduler, interval, func() (types.ServiceEndpoint, error) {
+		return c.registeredEndpoint(serviceKey), nil
+	}), nil
+}
+
+// registeredEndpoint returns the endpoint of the target service, which is empty when the service isn't registered
+func (c *memoryClient) registeredEndpoint(serviceKey string) types.ServiceEndpoint {
+	r, _ := c.get(serviceKey)
+	return r.endpoint
+}
+
+// TriggerHealthCheck calls the health check route the target service was registered with, if any
+func (c *memoryClient) TriggerHealthCheck(ctx context.Context, serviceKey string) (types.HealthCheckResult, error) {
+	r, found := c.get(serviceKey)
+	if !found {
+		return types.HealthCheckResult{}, types.Errorf(types.ErrNotRegistered, "unable to health check %s: service is not registered", serviceKey)
+	}
+
+	if r.checkUrl == "" {
+		return types.HealthCheckResult{Healthy: true, Output: "service registered without health check"}, nil
+	}
+
+	return health.Check(ctx, c.config.GetCheckOptions(serviceKey), r.checkUrl), nil
+}
+
+// GetServiceEndpoint retrieves the port, service ID and host of a known endpoint from memory.
+// If this operation is successful and a known endpoint is found, it is returned. Otherwise, an error is returned.
+func (c *memoryClient) GetServiceEndpoint(serviceKey string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointWithContext retrieves the port, service ID and host of a known endpoint from memory
+func (c *memoryClient) GetServiceEndpointWithContext(_ context.Context, serviceKey string) (types.ServiceEndpoint, error) {
+	r, found := c.get(serviceKey)
+	if !found {
+		return types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found")
+	}
+
+	if err := r.endpoint.Validate(); err != nil {
+		return types.ServiceEndpoint{}, fmt.Errorf("failed to get service %s endpoint: %w", serviceKey, err)
+	}
+	return r.endpoint, nil
+}
+
+// GetServiceEndpoints retrieves the endpoint of the target service from memory, which registers a single instance per
+// service key
+func (c *memoryClient) GetServiceEndpoints(serviceKey string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceKey)
+}
+
+// GetServiceEndpointsWithContext retrieves the endpoint of the target service from memory, aborting once ctx is done
+func (c *memoryClient) GetServiceEndpointsWithContext(ctx context.Context, serviceKey string) ([]types.ServiceEndpoint, error) {
+	endpoint, err := c.GetServiceEndpointWithContext(ctx, serviceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.ServiceEndpoint{endpoint}, nil
+}
+
+// GetAllServiceEndpoints retrieves all registered endpoints from memory.
+func (c *memoryClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+// GetAllServiceEndpointsWithContext retrieves all registered endpoints from memory
+func (c *memoryClient) GetAllServiceEndpointsWithContext(_ context.Context) ([]types.ServiceEndpoint, error) {
+	c.lock.RLock()
+	endpoints := make([]types.ServiceEndpoint, 0, len(c.services))
+	for _, r := range c.services {
+		endpoints = append(endpoints, r.endpoint)
+	}
+	c.lock.RUnlock()
+
+	for _, endpoint := range endpoints {
+		if err := endpoint.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to get all service endpoints: %w", err)
+		}
+	}
+
+	types.SortServiceEndpoints(endpoints, c.config.GetEndpointOrder())
+	return endpoints, nil
+}
+
+// IsServiceAvailable checks if the target service is registered in memory
+func (c *memoryClient) IsServiceAvailable(serviceKey string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceKey)
+}
+
+// IsServiceAvailableWithContext checks if the target service is registered in memory
+func (c *memoryClient) IsServiceAvailableWithContext(_ context.Context, serviceKey string) (bool, error) {
+	if _, found := c.get(serviceKey); !found {
+		return false, types.Errorf(types.ErrNotRegistered, "%s service is not registered. Might not have started... ", serviceKey)
+	}
+
+	return true, nil
+}
+
+func (c *memoryClient) get(serviceKey string) (registration, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	r, found := c.services[serviceKey]
+	return r, found
+}
@@ -0,0 +1,187 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/hostile"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	serviceName        = "memoryunittest"
+	defaultServiceHost = "localhost"
+	defaultServicePort = 59880
+)
+
+var seededEndpoints = []types.ServiceEndpoint{
+	{ServiceId: "core-metadata", Host: "edgex-core-metadata", Port: 59881},
+	{ServiceId: "core-command", Host: "edgex-core-command", Port: 59882},
+}
+
+func TestSeededEndpoints(t *testing.T) {
+	client := makeMemoryClient(t)
+
+	endpoint, err := client.GetServiceEndpoint("core-metadata")
+	require.NoError(t, err)
+	assert.Equal(t, seededEndpoints[0], endpoint)
+
+	available, err := client.IsServiceAvailable("core-command")
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	endpoints, err := client.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{seededEndpoints[1], seededEndpoints[0]}, endpoints)
+
+	_, err = client.GetServiceEndpoint("core-data")
+	require.EqualError(t, err, "no matching service endpoint found")
+	_, err = client.IsServiceAvailable("core-data")
+	require.Error(t, err)
+
+	_, err = NewMemoryClient(types.Config{MemoryEndpoints: []types.ServiceEndpoint{{Host: "localhost", Port: 59880}}})
+	require.Error(t, err, "Expected error seeding endpoint without service ID")
+}
+
+func TestGetServiceEndpointHostileRegistrations(t *testing.T) {
+	for _, registration := range hostile.Registrations() {
+		t.Run(registration.Name, func(t *testing.T) {
+			host, port, ok := registration.Address()
+			if !ok {
+				t.Skip("not representable as endpoint")
+			}
+			client, err := NewMemoryClient(types.Config{
+				MemoryEndpoints: []types.ServiceEndpoint{{ServiceId: serviceName, Host: host, Port: port}},
+			})
+			require.NoError(t, err)
+
+			endpoint, err := client.GetServiceEndpoint(serviceName)
+			endpoints, allErr := client.GetAllServiceEndpoints()
+			if !registration.Valid {
+				require.ErrorIs(t, err, types.ErrMalformedResponse)
+				require.ErrorIs(t, allErr, types.ErrMalformedResponse)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, hostile.ValidHost, endpoint.Host)
+			assert.Equal(t, hostile.ValidPort, endpoint.Port)
+			require.NoError(t, allErr)
+			assert.Equal(t, []types.ServiceEndpoint{endpoint}, endpoints)
+		})
+	}
+}
+
+func TestRegisterAndUnregister(t *testing.T) {
+	client := makeMemoryClient(t)
+	require.True(t, client.IsAlive())
+	require.NoError(t, client.Register())
+
+	endpoint, err := client.GetServiceEndpoint(client.serviceKey)
+	require.NoError(t, err)
+	assert.Equal(t, types.ServiceEndpoint{ServiceId: client.serviceKey, Host: defaultServiceHost, Port: defaultServicePort}, endpoint)
+
+	require.NoError(t, client.Unregister())
+	_, err = client.GetServiceEndpoint(client.serviceKey)
+	require.Error(t, err)
+
+	client.serviceHost = ""
+	require.Error(t, client.Register(), "Expected error registering without service information")
+}
+
+func TestDecommission(t *testing.T) {
+	client := makeMemoryClient(t)
+	require.NoError(t, client.Decommission(context.Background(), "core-metadata"))
+	_, err := client.GetServiceEndpoint("core-metadata")
+	require.Error(t, err)
+
+	require.Error(t, client.Decommission(context.Background(), "core-metadata"), "Expected error decommissioning unknown service")
+}
+
+func TestTriggerHealthCheck(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer testServer.Close()
+	serverUrl, _ := url.Parse(testServer.URL)
+	servicePort, _ := strconv.Atoi(serverUrl.Port())
+
+	client, err := NewMemoryClient(types.Config{
+		ServiceKey:      getUniqueServiceName(),
+		ServiceHost:     serverUrl.Hostname(),
+		ServicePort:     servicePort,
+		CheckRoute:      "/api/v3/ping",
+		MemoryEndpoints: seededEndpoints,
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Register())
+
+	result, err := client.TriggerHealthCheck(context.Background(), client.serviceKey)
+	require.NoError(t, err)
+	assert.False(t, result.Healthy)
+
+	result, err = client.TriggerHealthCheck(context.Background(), "core-metadata")
+	require.NoError(t, err)
+	assert.True(t, result.Healthy, "Expected seeded service without health check to be healthy")
+}
+
+func TestWatchSelf(t *testing.T) {
+	client := makeMemoryClient(t)
+	client.config.WatchInterval = "10ms"
+	require.NoError(t, client.Register())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.WatchSelf(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Decommission(context.Background(), client.serviceKey))
+	select {
+	case event := <-events:
+		assert.Equal(t, types.RegistrationDeleted, event.Type)
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for registration event")
+	}
+}
+
+func makeMemoryClient(t *testing.T) *memoryClient {
+	client, err := NewMemoryClient(types.Config{
+		Type:            "memory",
+		ServiceKey:      getUniqueServiceName(),
+		ServiceHost:     defaultServiceHost,
+		ServicePort:     defaultServicePort,
+		CheckType:       types.CheckTypeNone,
+		MemoryEndpoints: seededEndpoints,
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+func getUniqueServiceName() string {
+	return serviceName + strconv.Itoa(time.Now().Nanosecond())
+}
+
+func TestNotRegisteredErrors(t *testing.T) {
+	client := makeMemoryClient(t)
+
+	_, err := client.GetServiceEndpoint("core-data")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+	_, err = client.IsServiceAvailable("core-data")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+	_, err = client.TriggerHealthCheck(context.Background(), "core-data")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+	require.ErrorIs(t, client.Decommission(context.Background(), "core-data"), types.ErrNotRegistered)
+}
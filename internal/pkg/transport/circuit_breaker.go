@@ -0,0 +1,84 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// circuitBreaker fails the requests fast once the Registry failed threshold consecutive ones, rather than having each
+// of them wait for it to time out. Once the cooldown elapsed, a single probe request is sent, half-open, the others
+// still failing fast: the circuit closes if the probe succeeds and opens again for another cooldown otherwise.
+type circuitBreaker struct {
+	next      http.RoundTripper
+	threshold int
+	cooldown  time.Duration
+	lock      sync.Mutex
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+// WithCircuitBreaker wraps the RoundTripper to fail the requests with types.ErrCircuitOpen for the cooldown once
+// threshold consecutive requests failed to reach the Registry or were answered with a 502, 503 or 504 status code
+func WithCircuitBreaker(next http.RoundTripper, threshold int, cooldown time.Duration) http.RoundTripper {
+	return &circuitBreaker{next: next, threshold: threshold, cooldown: cooldown}
+}
+
+func (b *circuitBreaker) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	response, err := b.next.RoundTrip(request)
+	b.record(response, err)
+	return response, err
+}
+
+// allow fails if the circuit is open, other than for the probe once the cooldown elapsed
+func (b *circuitBreaker) allow() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return types.Errorf(types.ErrRegistryUnavailable, "%w after %d consecutive failures", types.ErrCircuitOpen, b.failures)
+	}
+	b.probing = true
+	return nil
+}
+
+// record counts the consecutive failures, opening the circuit once they reach the threshold. Cancelled requests tell
+// nothing about the Registry, so they are neither failures nor successes.
+func (b *circuitBreaker) record(response *http.Response, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.probing = false
+	switch {
+	case errors.Is(err, context.Canceled):
+	case err != nil || isUnavailableStatus(response.StatusCode):
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openedAt = time.Now()
+		}
+	default:
+		b.failures = 0
+	}
+}
+
+// isUnavailableStatus tells whether the status code is the Registry, or the proxy in front of it, being unavailable
+func isUnavailableStatus(statusCode int) bool {
+	return statusCode == http.StatusBadGateway || statusCode == http.StatusServiceUnavailable || statusCode == http.StatusGatewayTimeout
+}
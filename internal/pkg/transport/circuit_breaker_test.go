@@ -0,0 +1,103 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const testCooldown = 50 * time.Millisecond
+
+func TestCircuitBreaker(t *testing.T) {
+	var down atomic.Bool
+	var sent atomic.Int32
+	down.Store(true)
+	client := &http.Client{Transport: WithCircuitBreaker(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		sent.Add(1)
+		if down.Load() {
+			return nil, syscall.ECONNREFUSED
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}), 3, testCooldown)}
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Get("http://localhost:59890/api/v3/ping")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, types.ErrCircuitOpen)
+	}
+
+	// Open: the requests fail fast without being sent
+	_, err := client.Get("http://localhost:59890/api/v3/ping")
+	assert.ErrorIs(t, err, types.ErrCircuitOpen)
+	assert.ErrorIs(t, err, types.ErrRegistryUnavailable)
+	assert.Equal(t, types.TransportFailureCircuitOpen, types.ClassifyTransportFailure(err))
+	assert.Equal(t, int32(3), sent.Load())
+
+	// Half-open: the failed probe opens the circuit for another cooldown
+	time.Sleep(testCooldown)
+	_, err = client.Get("http://localhost:59890/api/v3/ping")
+	assert.NotErrorIs(t, err, types.ErrCircuitOpen)
+	_, err = client.Get("http://localhost:59890/api/v3/ping")
+	assert.ErrorIs(t, err, types.ErrCircuitOpen)
+	assert.Equal(t, int32(4), sent.Load())
+
+	// The successful probe closes the circuit
+	down.Store(false)
+	time.Sleep(testCooldown)
+	for i := 0; i < 3; i++ {
+		response, err := client.Get("http://localhost:59890/api/v3/ping")
+		require.NoError(t, err)
+		_ = response.Body.Close()
+	}
+	assert.Equal(t, int32(7), sent.Load())
+}
+
+func TestCircuitBreakerUnavailableStatus(t *testing.T) {
+	client := &http.Client{Transport: WithCircuitBreaker(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+	}), 2, time.Minute)}
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Get("http://localhost:59890/api/v3/ping")
+		require.NoError(t, err)
+		_ = response.Body.Close()
+	}
+	_, err := client.Get("http://localhost:59890/api/v3/ping")
+	assert.ErrorIs(t, err, types.ErrCircuitOpen)
+}
+
+func TestCircuitBreakerIgnoresCancelledRequests(t *testing.T) {
+	client := &http.Client{Transport: WithCircuitBreaker(roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return nil, request.Context().Err()
+	}), 1, time.Minute)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:59890/api/v3/ping", nil)
+	for i := 0; i < 2; i++ {
+		_, err := client.Do(request)
+		assert.NotErrorIs(t, err, types.ErrCircuitOpen, "Expected cancelled requests not to open the circuit")
+	}
+}
+
+func TestNewClientCircuitBreaker(t *testing.T) {
+	client, err := NewClient(types.Config{CircuitBreakerThreshold: 3})
+	require.NoError(t, err)
+	assert.IsType(t, &circuitBreaker{}, client.Transport)
+
+	_, err = NewClient(types.Config{CircuitBreakerThreshold: 3, CircuitBreakerCooldown: "10"})
+	assert.Error(t, err)
+}
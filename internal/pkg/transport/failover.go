@@ -0,0 +1,104 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+)
+
+// failoverTransport sends the requests to the replicas of the Registry in turn, moving on to the next one when one
+// can't be reached or responds with a 502, 503 or 504 status code, and sticking to the last one which responded. Only
+// the requests sent to the first replica, the configured Host and Port, fail over, so the requests to other hosts go
+// through as is.
+type failoverTransport struct {
+	next             http.RoundTripper
+	endpoints        []string
+	failbackInterval time.Duration
+	lc               logger.LoggingClient
+
+	lock    sync.Mutex
+	current int
+	// failedOverAt is when the first replica was last given up on or retried
+	failedOverAt time.Time
+}
+
+// WithFailover wraps the RoundTripper to fail the requests sent to the first of the given host:port endpoints over to
+// the others. Once failed over, the first endpoint is tried again once per failback interval if set, so the requests
+// go back to it once it recovers, otherwise they keep being sent to the one which last responded.
+func WithFailover(next http.RoundTripper, endpoints []string, failbackInterval time.Duration, lc logger.LoggingClient) http.RoundTripper {
+	return &failoverTransport{next: next, endpoints: endpoints, failbackInterval: failbackInterval, lc: lc}
+}
+
+func (f *failoverTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.URL.Host != f.endpoints[0] {
+		return f.next.RoundTrip(request)
+	}
+
+	start := f.start()
+	for attempt := 0; ; attempt++ {
+		index := (start + attempt) % len(f.endpoints)
+		endpointRequest := request.Clone(request.Context())
+		endpointRequest.URL.Host = f.endpoints[index]
+		endpointRequest.Host = ""
+		if attempt > 0 && request.Body != nil && request.Body != http.NoBody {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			endpointRequest.Body = body
+		}
+
+		response, err := f.next.RoundTrip(endpointRequest)
+		failed := err != nil || isUnavailableStatus(response.StatusCode)
+		if !failed {
+			f.use(index)
+			return response, nil
+		}
+		// The requests whose body can't be sent again, and the cancelled ones, aren't failed over
+		lastAttempt := attempt == len(f.endpoints)-1 || request.Context().Err() != nil ||
+			(request.Body != nil && request.Body != http.NoBody && request.GetBody == nil)
+		if lastAttempt {
+			return response, err
+		}
+
+		f.lc.Warnf("Registry at %s failed, failing over to %s", f.endpoints[index], f.endpoints[(index+1)%len(f.endpoints)])
+		if response != nil {
+			_, _ = io.Copy(io.Discard, response.Body)
+			_ = response.Body.Close()
+		}
+	}
+}
+
+// start returns the index of the endpoint to send the request to first, the first one when it is due to be retried
+func (f *failoverTransport) start() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.current != 0 && f.failbackInterval > 0 && time.Since(f.failedOverAt) >= f.failbackInterval {
+		f.failedOverAt = time.Now()
+		return 0
+	}
+	return f.current
+}
+
+// use sticks to the endpoint which responded
+func (f *failoverTransport) use(index int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if index == f.current {
+		return
+	}
+	if f.current == 0 {
+		f.failedOverAt = time.Now()
+	}
+	f.current = index
+}
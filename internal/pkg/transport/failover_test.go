@@ -0,0 +1,123 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+)
+
+// replica starts a replica of the Registry echoing the body of the requests, returning its host:port
+func replica(t *testing.T, name string, served *atomic.Int32) string {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		served.Add(1)
+		body, _ := io.ReadAll(request.Body)
+		_, _ = io.WriteString(writer, name+":"+string(body))
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+// downReplica returns the host:port of a replica of the Registry refusing connections
+func downReplica() string {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestFailover(t *testing.T) {
+	var served atomic.Int32
+	primary := downReplica()
+	secondary := replica(t, "secondary", &served)
+	client := &http.Client{Transport: WithFailover(http.DefaultTransport, []string{primary, secondary}, 0, logger.NewMockClient())}
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Post("http://"+primary+"/api/v3/registry", "application/json", strings.NewReader("core-data"))
+		require.NoError(t, err)
+		body, err := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "secondary:core-data", string(body), "Expected the request and its body to fail over")
+	}
+	assert.Equal(t, int32(2), served.Load())
+}
+
+func TestFailoverUnavailableStatus(t *testing.T) {
+	unavailable := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	var served atomic.Int32
+	primary := strings.TrimPrefix(unavailable.URL, "http://")
+	client := &http.Client{Transport: WithFailover(http.DefaultTransport, []string{primary, replica(t, "secondary", &served)}, 0, logger.NewMockClient())}
+
+	response, err := client.Get(unavailable.URL + "/api/v3/ping")
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestFailoverAllDown(t *testing.T) {
+	primary := downReplica()
+	client := &http.Client{Transport: WithFailover(http.DefaultTransport, []string{primary, downReplica()}, 0, logger.NewMockClient())}
+
+	_, err := client.Get("http://" + primary + "/api/v3/ping")
+	assert.Error(t, err)
+}
+
+func TestFailoverOtherHosts(t *testing.T) {
+	var primaryServed, otherServed atomic.Int32
+	primary := replica(t, "primary", &primaryServed)
+	other := replica(t, "other", &otherServed)
+	client := &http.Client{Transport: WithFailover(http.DefaultTransport, []string{primary, downReplica()}, 0, logger.NewMockClient())}
+
+	response, err := client.Get("http://" + other + "/api/v3/ping")
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, int32(1), otherServed.Load(), "Expected the requests to other hosts to go through as is")
+	assert.Zero(t, primaryServed.Load())
+}
+
+func TestFailback(t *testing.T) {
+	var primaryServed, secondaryServed atomic.Int32
+	var primaryDown atomic.Bool
+	primaryDown.Store(true)
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		if primaryDown.Load() {
+			writer.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		primaryServed.Add(1)
+	}))
+	defer primaryServer.Close()
+	primary := strings.TrimPrefix(primaryServer.URL, "http://")
+	client := &http.Client{Transport: WithFailover(http.DefaultTransport, []string{primary, replica(t, "secondary", &secondaryServed)}, 50*time.Millisecond, logger.NewMockClient())}
+
+	get := func() {
+		response, err := client.Get(primaryServer.URL + "/api/v3/ping")
+		require.NoError(t, err)
+		_ = response.Body.Close()
+	}
+	get()
+	primaryDown.Store(false)
+	get()
+	assert.Equal(t, int32(2), secondaryServed.Load(), "Expected the requests to stick to the secondary until the failback interval elapsed")
+
+	time.Sleep(60 * time.Millisecond)
+	get()
+	get()
+	assert.Equal(t, int32(2), primaryServed.Load(), "Expected the requests to go back to the primary once it recovered")
+}
@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ServerClock estimates the time of the Registry from the Date header of its responses, advancing it with the
+// monotonic clock in between, so it's unaffected by the local real-time clock being unset or jumping. It tells the
+// local time until the Registry answered with a Date.
+type ServerClock struct {
+	lock       sync.Mutex
+	date       time.Time
+	receivedAt time.Time
+}
+
+// NewServerClock creates a ServerClock, which tracks the Registry once it wraps the transport of its requests
+func NewServerClock() *ServerClock {
+	return &ServerClock{}
+}
+
+// Now returns the estimated time of the Registry
+func (c *ServerClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.date.IsZero() {
+		return time.Now()
+	}
+	return c.date.Add(time.Since(c.receivedAt))
+}
+
+// Wrap returns the RoundTripper tracking the Date of the responses sent through next
+func (c *ServerClock) Wrap(next http.RoundTripper) http.RoundTripper {
+	return &serverClockTransport{next: next, clock: c}
+}
+
+type serverClockTransport struct {
+	next  http.RoundTripper
+	clock *ServerClock
+}
+
+func (t *serverClockTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	if err == nil {
+		if date, err := http.ParseTime(response.Header.Get("Date")); err == nil {
+			t.clock.observe(date)
+		}
+	}
+	return response, err
+}
+
+// observe corrects the estimate with the Date of a response, unless it's within the one second resolution of the
+// header, so the estimate doesn't jitter from one response to the next
+func (c *ServerClock) observe(date time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	if !c.date.IsZero() {
+		if drift := date.Sub(c.date.Add(now.Sub(c.receivedAt))); drift > -time.Second && drift < time.Second {
+			return
+		}
+	}
+	c.date = date
+	c.receivedAt = now
+}
@@ -0,0 +1,47 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerClock(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Date", date.Format(http.TimeFormat))
+	}))
+	defer server.Close()
+
+	clock := NewServerClock()
+	assert.WithinDuration(t, time.Now(), clock.Now(), time.Second, "Expected the local time until the server answered")
+
+	client := &http.Client{Transport: clock.Wrap(http.DefaultTransport)}
+	response, err := client.Get(server.URL)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.WithinDuration(t, date, clock.Now(), time.Second)
+
+	// The estimate isn't corrected within the resolution of the header
+	estimate := clock.Now()
+	date = date.Add(500 * time.Millisecond)
+	response, err = client.Get(server.URL)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.WithinDuration(t, estimate, clock.Now(), 100*time.Millisecond)
+
+	date = date.Add(time.Hour)
+	response, err = client.Get(server.URL)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.WithinDuration(t, date, clock.Now(), time.Second)
+}
@@ -0,0 +1,151 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	certFile    string
+	keyFile     string
+}
+
+// newTestCertificate creates a certificate signed by parent, or a self-signed CA certificate when parent is nil, and
+// writes it along with its key as PEM files to dir
+func newTestCertificate(t *testing.T, dir string, name string, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.certificate, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	result := &testCertificate{
+		certificate: certificate,
+		key:         key,
+		certFile:    filepath.Join(dir, name+".crt"),
+		keyFile:     filepath.Join(dir, name+".key"),
+	}
+	require.NoError(t, os.WriteFile(result.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(result.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	return result
+}
+
+func TestNewClientMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCertificate(t, dir, "ca", nil)
+	serverCert := newTestCertificate(t, dir, "server", ca)
+	clientCert := newTestCertificate(t, dir, "client", ca)
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca.certificate)
+	serverKeyPair, err := tls.LoadX509KeyPair(serverCert.certFile, serverCert.keyFile)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		_, _ = writer.Write([]byte("pong"))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverKeyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    caPool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		config      types.Config
+		expectError bool
+	}{
+		{"CA and client certificate", types.Config{TLSCAFile: ca.certFile, TLSCertFile: clientCert.certFile, TLSKeyFile: clientCert.keyFile}, false},
+		{"insecure with client certificate", types.Config{TLSInsecureSkipVerify: true, TLSCertFile: clientCert.certFile, TLSKeyFile: clientCert.keyFile}, false},
+		{"missing client certificate", types.Config{TLSCAFile: ca.certFile}, true},
+		{"unknown CA", types.Config{TLSCertFile: clientCert.certFile, TLSKeyFile: clientCert.keyFile}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := NewClient(test.config)
+			require.NoError(t, err)
+
+			resp, err := client.Get(server.URL)
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPem := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(notPem, []byte("not a certificate"), 0600))
+
+	_, err := New(types.Config{TLSCAFile: filepath.Join(dir, "missing.crt")})
+	require.Error(t, err)
+
+	_, err = New(types.Config{TLSCAFile: notPem})
+	require.Error(t, err)
+
+	_, err = New(types.Config{TLSCertFile: filepath.Join(dir, "client.crt")})
+	require.Error(t, err, "Expected error loading client certificate without key")
+}
+
+func TestNewWithoutTLSSettings(t *testing.T) {
+	roundTripper, err := New(types.Config{})
+	require.NoError(t, err)
+	assert.Nil(t, roundTripper.(*http.Transport).TLSClientConfig)
+}
@@ -0,0 +1,239 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const keepAlive = 30 * time.Second
+
+// New creates the transport shared by all the requests a registry client sends. This is the RoundTripper from the
+// registry configuration when set, otherwise the secure transport of the AuthInjector when it provides one, otherwise a
+// pooled http.Transport starting from the settings of http.DefaultTransport. The connection settings from the registry
+// configuration are applied on top of a clone, leaving a transport supplied by the caller untouched, failing when the
+// supplied transport isn't an http.Transport as they couldn't be honoured.
+func New(config types.Config) (http.RoundTripper, error) {
+	roundTripper := config.RoundTripper
+	if roundTripper == nil && config.AuthInjector != nil {
+		roundTripper = config.AuthInjector.RoundTripper()
+	}
+	supplied := roundTripper != nil
+	if !supplied {
+		roundTripper = http.DefaultTransport
+	}
+
+	base, ok := roundTripper.(*http.Transport)
+	if !ok {
+		if hasConnectionSettings(config) {
+			return nil, fmt.Errorf("unable to apply the TLS, connection pool and dial settings to a %T transport, "+
+				"only to an http.Transport", roundTripper)
+		}
+		return roundTripper, nil
+	}
+
+	idleConnTimeout, err := config.GetIdleConnTimeout()
+	if err != nil {
+		return nil, err
+	}
+	dialTimeout, err := config.GetDialTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := base.Clone()
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.MaxIdleConns
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+	if idleConnTimeout > 0 {
+		transport.IdleConnTimeout = idleConnTimeout
+	}
+	if dialTimeout > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: dialTimeout, KeepAlive: keepAlive}).DialContext
+	}
+
+	if !supplied || hasTLSSettings(config) {
+		// The TLS configuration http.DefaultTransport picked up from being used isn't carried over
+		var baseTLSConfig *tls.Config
+		if supplied {
+			baseTLSConfig = base.TLSClientConfig
+		}
+		transport.TLSClientConfig, err = newTLSConfig(config, baseTLSConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return transport, nil
+}
+
+// hasConnectionSettings checks if any of the TLS, connection pool or dial settings is set in the registry configuration
+func hasConnectionSettings(config types.Config) bool {
+	return hasTLSSettings(config) || config.MaxIdleConns > 0 || config.MaxIdleConnsPerHost > 0 ||
+		config.IdleConnTimeout != "" || config.DialTimeout != ""
+}
+
+func hasTLSSettings(config types.Config) bool {
+	return config.TLSCAFile != "" || config.TLSCertFile != "" || config.TLSKeyFile != "" || config.TLSInsecureSkipVerify
+}
+
+// newTLSConfig creates the TLS configuration for connecting to the Registry over HTTPS, starting from a clone of base
+// when the transport already has one. This is nil when the registry configuration doesn't have any TLS settings so the
+// transport keeps its own, or the Go defaults apply.
+func newTLSConfig(config types.Config, base *tls.Config) (*tls.Config, error) {
+	if !hasTLSSettings(config) {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if base != nil {
+		tlsConfig = base.Clone()
+		if tlsConfig.MinVersion < tls.VersionTLS12 {
+			tlsConfig.MinVersion = tls.VersionTLS12
+		}
+	}
+	if config.TLSInsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true // nolint: gosec
+	}
+
+	if config.TLSCAFile != "" {
+		caCerts, err := os.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read TLS CA file %s: %w", config.TLSCAFile, err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCerts) {
+			return nil, fmt.Errorf("no PEM encoded certificates found in TLS CA file %s", config.TLSCAFile)
+		}
+	}
+
+	if config.TLSCertFile != "" || config.TLSKeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load TLS client certificate %s and key %s: %w", config.TLSCertFile, config.TLSKeyFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// NewClient creates the http.Client sending requests through the transport from New, failing over to the
+// FailoverEndpoints if set, behind a circuit breaker if CircuitBreakerThreshold is set, and bounding each of them by
+// the request timeout from the registry configuration
+func NewClient(config types.Config) (*http.Client, error) {
+	transport, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientWithTransport(config, transport)
+}
+
+// NewClientWithTransport is NewClient sending the requests through the given transport rather than the one from New,
+// i.e. once the registry type adjusted the latter to its own connection settings
+func NewClientWithTransport(config types.Config, transport http.RoundTripper) (*http.Client, error) {
+	requestTimeout, err := config.GetRequestTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(config.FailoverEndpoints) > 0 {
+		endpoints, err := config.GetRegistryEndpoints()
+		if err != nil {
+			return nil, err
+		}
+		failbackInterval, err := config.GetFailbackInterval()
+		if err != nil {
+			return nil, err
+		}
+		transport = WithFailover(transport, endpoints, failbackInterval, config.GetLoggingClient())
+	}
+	if config.CircuitBreakerThreshold > 0 {
+		cooldown, err := config.GetCircuitBreakerCooldown()
+		if err != nil {
+			return nil, err
+		}
+		transport = WithCircuitBreaker(transport, config.CircuitBreakerThreshold, cooldown)
+	}
+
+	return &http.Client{Transport: transport, Timeout: requestTimeout}, nil
+}
+
+// Unavailable returns err as types.ErrRegistryUnavailable when the request failed without reaching the Registry, i.e.
+// connection refused or timed out, otherwise err as is. Cancelled requests aren't considered as failures to reach it.
+func Unavailable(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, types.ErrRegistryUnavailable) {
+		return err
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) {
+		return types.Errorf(types.ErrRegistryUnavailable, "%w", err)
+	}
+	return err
+}
+
+// Malformed returns err as types.ErrMalformedResponse when it is a failure to decode the JSON response of the
+// Registry, i.e. invalid or truncated JSON or fields of the wrong type, otherwise err as is
+func Malformed(err error) error {
+	if err == nil || errors.Is(err, types.ErrMalformedResponse) {
+		return err
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return types.Errorf(types.ErrMalformedResponse, "%w", err)
+	}
+	return err
+}
+
+// loggingTransport logs the requests sent through the wrapped RoundTripper and their responses
+type loggingTransport struct {
+	next     http.RoundTripper
+	lc       logger.LoggingClient
+	registry string
+}
+
+// WithLogging wraps the RoundTripper to log the requests sent to the given Registry, i.e. Consul, at trace level and
+// their responses at debug level
+func WithLogging(next http.RoundTripper, lc logger.LoggingClient, registry string) http.RoundTripper {
+	return &loggingTransport{next: next, lc: lc, registry: registry}
+}
+
+func (t *loggingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// The URL is logged without its query, which may carry an ACL token
+	t.lc.Tracef("Sending %s request %s %s", t.registry, request.Method, request.URL.Path)
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		t.lc.Debugf("%s request %s %s failed after %s: %v", t.registry, request.Method, request.URL.Path, time.Since(start), err)
+		return nil, err
+	}
+
+	t.lc.Debugf("%s responded to %s %s with status code %d in %s", t.registry, request.Method, request.URL.Path, response.StatusCode, time.Since(start))
+	return response, nil
+}
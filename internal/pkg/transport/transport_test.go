@@ -0,0 +1,223 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	loggerMocks "github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger/mocks"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestNew(t *testing.T) {
+	defaults := http.DefaultTransport.(*http.Transport)
+
+	roundTripper, err := New(types.Config{})
+	require.NoError(t, err)
+	transport := roundTripper.(*http.Transport)
+	assert.Equal(t, defaults.MaxIdleConns, transport.MaxIdleConns)
+	assert.Equal(t, defaults.MaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, defaults.IdleConnTimeout, transport.IdleConnTimeout)
+
+	roundTripper, err = New(types.Config{MaxIdleConns: 10, MaxIdleConnsPerHost: 5, IdleConnTimeout: "30s", DialTimeout: "2s"})
+	require.NoError(t, err)
+	transport = roundTripper.(*http.Transport)
+	assert.Equal(t, 10, transport.MaxIdleConns)
+	assert.Equal(t, 5, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout)
+	assert.NotNil(t, transport.DialContext)
+	assert.NotSame(t, defaults, transport)
+
+	_, err = New(types.Config{IdleConnTimeout: "bogus"})
+	require.Error(t, err)
+
+	_, err = New(types.Config{DialTimeout: "-1s"})
+	require.Error(t, err)
+}
+
+func TestNewCustomRoundTripper(t *testing.T) {
+	custom := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusTeapot, Body: http.NoBody}, nil
+	})
+
+	_, err := NewClient(types.Config{RoundTripper: custom, IdleConnTimeout: "30s"})
+	require.Error(t, err, "Expected pool settings not to be silently dropped with a custom RoundTripper")
+	_, err = NewClient(types.Config{RoundTripper: custom, TLSInsecureSkipVerify: true})
+	require.Error(t, err, "Expected TLS settings not to be silently dropped with a custom RoundTripper")
+
+	client, err := NewClient(types.Config{RoundTripper: custom})
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://registry.invalid")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+}
+
+// secureTransportInjector provides a secure transport, like the AuthInjector of the zero trust deployments
+type secureTransportInjector struct {
+	roundTripper http.RoundTripper
+}
+
+func (i secureTransportInjector) AddAuthenticationData(*http.Request) error {
+	return nil
+}
+
+func (i secureTransportInjector) RoundTripper() http.RoundTripper {
+	return i.roundTripper
+}
+
+func TestNewCustomTransport(t *testing.T) {
+	custom := &http.Transport{
+		MaxIdleConns:    3,
+		TLSClientConfig: &tls.Config{ServerName: "registry.local", MinVersion: tls.VersionTLS13},
+	}
+
+	roundTripper, err := New(types.Config{RoundTripper: custom, MaxIdleConnsPerHost: 2, DialTimeout: "2s", TLSInsecureSkipVerify: true})
+	require.NoError(t, err)
+	transport := roundTripper.(*http.Transport)
+	assert.NotSame(t, custom, transport, "Expected the settings to be applied to a clone")
+	assert.Equal(t, 3, transport.MaxIdleConns, "Expected the transport's own settings to be kept")
+	assert.Equal(t, 2, transport.MaxIdleConnsPerHost)
+	assert.NotNil(t, transport.DialContext)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.Equal(t, "registry.local", transport.TLSClientConfig.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS13), transport.TLSClientConfig.MinVersion)
+
+	assert.Zero(t, custom.MaxIdleConnsPerHost, "Expected the supplied transport to be left untouched")
+	assert.Nil(t, custom.DialContext)
+	assert.False(t, custom.TLSClientConfig.InsecureSkipVerify)
+
+	roundTripper, err = New(types.Config{AuthInjector: secureTransportInjector{custom}, IdleConnTimeout: "30s"})
+	require.NoError(t, err)
+	transport = roundTripper.(*http.Transport)
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout, "Expected the settings to apply to the secure transport")
+	assert.Zero(t, custom.IdleConnTimeout)
+
+	roundTripper, err = New(types.Config{RoundTripper: custom})
+	require.NoError(t, err)
+	assert.Equal(t, "registry.local", roundTripper.(*http.Transport).TLSClientConfig.ServerName,
+		"Expected the TLS configuration of the transport to be kept without TLS settings")
+}
+
+func TestNewAuthInjectorRoundTripper(t *testing.T) {
+	teapot := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusTeapot, Body: http.NoBody}, nil
+	})
+	custom := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusAccepted, Body: http.NoBody}, nil
+	})
+
+	client, err := NewClient(types.Config{AuthInjector: secureTransportInjector{teapot}, CircuitBreakerThreshold: 3})
+	require.NoError(t, err)
+	assert.IsType(t, &circuitBreaker{}, client.Transport, "Expected the secure transport to be wrapped")
+	resp, err := client.Get("http://registry.invalid")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+
+	client, err = NewClient(types.Config{AuthInjector: secureTransportInjector{teapot}, RoundTripper: custom})
+	require.NoError(t, err)
+	resp, err = client.Get("http://registry.invalid")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode, "Expected the configured RoundTripper to take precedence")
+
+	roundTripper, err := New(types.Config{AuthInjector: secureTransportInjector{}})
+	require.NoError(t, err)
+	assert.IsType(t, &http.Transport{}, roundTripper, "Expected the pooled transport without secure transport")
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient(types.Config{RequestTimeout: "3s"})
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, client.Timeout)
+
+	client, err = NewClient(types.Config{})
+	require.NoError(t, err)
+	assert.Zero(t, client.Timeout)
+
+	_, err = NewClient(types.Config{RequestTimeout: "bogus"})
+	require.Error(t, err)
+}
+
+func TestNewClientFailover(t *testing.T) {
+	client, err := NewClient(types.Config{Host: "keeper-1", Port: 59890, FailoverEndpoints: []string{"keeper-2:59890"}, FailbackInterval: "1m"})
+	require.NoError(t, err)
+	assert.IsType(t, &failoverTransport{}, client.Transport)
+
+	_, err = NewClient(types.Config{Host: "keeper-1", Port: 59890, FailoverEndpoints: []string{"keeper-2"}})
+	require.Error(t, err, "Expected invalid failover endpoint error")
+	_, err = NewClient(types.Config{Host: "keeper-1", Port: 59890, FailoverEndpoints: []string{"keeper-2:59890"}, FailbackInterval: "soon"})
+	require.Error(t, err, "Expected invalid failback interval error")
+}
+
+func TestUnavailable(t *testing.T) {
+	client := &http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, syscall.ECONNREFUSED
+	})}
+	_, err := client.Get("http://localhost:59890/api/v3/ping")
+	assert.ErrorIs(t, Unavailable(err), types.ErrRegistryUnavailable)
+	assert.ErrorIs(t, Unavailable(err), syscall.ECONNREFUSED)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:59890/api/v3/ping", nil)
+	_, err = http.DefaultClient.Do(request)
+	assert.NotErrorIs(t, Unavailable(err), types.ErrRegistryUnavailable, "Expected cancelled request not to be unavailable")
+
+	assert.NotErrorIs(t, Unavailable(errors.New("failed to parse the response body")), types.ErrRegistryUnavailable)
+	assert.Nil(t, Unavailable(nil))
+}
+
+func TestMalformed(t *testing.T) {
+	var port struct {
+		Port int `json:"port"`
+	}
+	err := json.Unmarshal([]byte(`{"port":NaN}`), &port)
+	assert.ErrorIs(t, Malformed(err), types.ErrMalformedResponse)
+	err = json.Unmarshal([]byte(`{"port":"59880"}`), &port)
+	assert.ErrorIs(t, Malformed(err), types.ErrMalformedResponse)
+	err = json.NewDecoder(strings.NewReader(`{"port":`)).Decode(&port)
+	assert.ErrorIs(t, Malformed(err), types.ErrMalformedResponse)
+
+	assert.NotErrorIs(t, Malformed(syscall.ECONNREFUSED), types.ErrMalformedResponse)
+	assert.Nil(t, Malformed(nil))
+}
+
+func TestWithLogging(t *testing.T) {
+	lc := &loggerMocks.LoggingClient{}
+	lc.On("Tracef", "Sending %s request %s %s", "Consul", http.MethodGet, "/v1/status/leader").Once()
+	lc.On("Debugf", "%s responded to %s %s with status code %d in %s", "Consul", http.MethodGet, "/v1/status/leader", http.StatusOK, mock.Anything).Once()
+
+	client := &http.Client{Transport: WithLogging(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}), lc, "Consul")}
+	response, err := client.Get("http://localhost:8500/v1/status/leader?token=secret")
+	require.NoError(t, err)
+	_ = response.Body.Close()
+
+	lc.AssertExpectations(t)
+}
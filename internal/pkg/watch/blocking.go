@@ -0,0 +1,88 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// Blocking watches with the blocking queries of the Registry, i.e. Consul's: fetch is invoked with the index of the
+// last result, 0 for the first one, and returns once the result changed past it or the wait time of the query elapsed,
+// along with the index of the result. Each result which differs from the previously published one is published. A
+// failed fetch is retried after retryInterval with the index reset, so the watch resubscribes from the current result,
+// and is skipped like by Poll. The blocking queries wait on the Registry, so they don't take turns with the polls of
+// the Scheduler. The returned channel is closed once the context is cancelled.
+func Blocking[T any](ctx context.Context, retryInterval time.Duration, fetch func(index uint64) (T, uint64, error), equal func(a T, b T) bool) <-chan T {
+	results := make(chan T)
+
+	go func() {
+		defer close(results)
+
+		var last T
+		published := false
+		var index uint64
+		for {
+			current, currentIndex, err := fetch(index)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				index = 0
+				if !sleep(ctx, retryInterval) {
+					return
+				}
+				continue
+			}
+
+			if !published || !equal(current, last) {
+				select {
+				case results <- current:
+					last = current
+					published = true
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			switch {
+			case currentIndex == 0:
+				// The Registry doesn't block on this query, so it is polled instead of queried right away again
+				index = 0
+				if !sleep(ctx, retryInterval) {
+					return
+				}
+			case currentIndex < index:
+				// The index went backwards, i.e. the Registry was restored from a snapshot, so the watch starts over
+				index = 0
+			default:
+				index = currentIndex
+			}
+		}
+	}()
+
+	return results
+}
+
+// BlockingEndpoint is Blocking for the endpoint of a service, which isn't comparable as it carries its metadata and tags
+func BlockingEndpoint(ctx context.Context, retryInterval time.Duration, fetch func(index uint64) (types.ServiceEndpoint, uint64, error)) <-chan types.ServiceEndpoint {
+	return Blocking(ctx, retryInterval, fetch, types.ServiceEndpoint.Equal)
+}
+
+// sleep waits for the duration, returning false if ctx got cancelled first
+func sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
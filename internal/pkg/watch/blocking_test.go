@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingResult is the result of a blocking query along with its index
+type blockingResult struct {
+	value int
+	index uint64
+	err   error
+}
+
+// scriptedFetch returns the results in turn, the last one once they are all returned, recording the indexes queried
+func scriptedFetch(results []blockingResult, indexes chan<- uint64) func(index uint64) (int, uint64, error) {
+	calls := 0
+	return func(index uint64) (int, uint64, error) {
+		select {
+		case indexes <- index:
+		default:
+		}
+		result := results[min(calls, len(results)-1)]
+		calls++
+		return result.value, result.index, result.err
+	}
+}
+
+func TestBlockingPublishesOnlyChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	indexes := make(chan uint64, 100)
+	results := Blocking(ctx, time.Hour, scriptedFetch([]blockingResult{{1, 5, nil}, {1, 5, nil}, {2, 7, nil}, {3, 9, nil}}, indexes), func(a int, b int) bool { return a == b })
+
+	assert.Equal(t, 1, receive(t, results))
+	assert.Equal(t, 2, receive(t, results))
+	assert.Equal(t, 3, receive(t, results))
+	assert.Equal(t, []uint64{0, 5, 5, 7}, receiveIndexes(indexes, 4), "Expected each query to wait past the index of the last result")
+}
+
+func TestBlockingResubscribesAfterErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	indexes := make(chan uint64, 100)
+	results := Blocking(ctx, testInterval, scriptedFetch([]blockingResult{{1, 5, nil}, {0, 0, errors.New("registry unreachable")}, {2, 8, nil}}, indexes), func(a int, b int) bool { return a == b })
+
+	assert.Equal(t, 1, receive(t, results))
+	assert.Equal(t, 2, receive(t, results), "Expected the failed query to be skipped")
+	assert.Equal(t, []uint64{0, 5, 0}, receiveIndexes(indexes, 3), "Expected the index to be reset once the query failed")
+}
+
+func TestBlockingIndexGoingBackwards(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	indexes := make(chan uint64, 100)
+	results := Blocking(ctx, time.Hour, scriptedFetch([]blockingResult{{1, 9, nil}, {2, 3, nil}, {3, 4, nil}}, indexes), func(a int, b int) bool { return a == b })
+
+	assert.Equal(t, 1, receive(t, results))
+	assert.Equal(t, 2, receive(t, results))
+	assert.Equal(t, 3, receive(t, results))
+	assert.Equal(t, []uint64{0, 9, 0}, receiveIndexes(indexes, 3), "Expected the index to be reset once it went backwards")
+}
+
+func TestBlockingClosedOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := Blocking(ctx, testInterval, func(index uint64) (int, uint64, error) {
+		if index == 0 {
+			return 1, 1, nil
+		}
+		<-ctx.Done()
+		return 0, 0, ctx.Err()
+	}, func(a int, b int) bool { return a == b })
+	receive(t, results)
+	cancel()
+
+	select {
+	case _, ok := <-results:
+		assert.False(t, ok, "Expected channel to be closed")
+	case <-time.After(time.Second):
+		t.Fatal("Channel not closed after cancel")
+	}
+}
+
+// receiveIndexes returns the first count indexes queried
+func receiveIndexes(indexes <-chan uint64, count int) []uint64 {
+	received := make([]uint64, 0, count)
+	for len(received) < count {
+		select {
+		case index := <-indexes:
+			received = append(received, index)
+		case <-time.After(time.Second):
+			return received
+		}
+	}
+	return received
+}
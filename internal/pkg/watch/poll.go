@@ -0,0 +1,97 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// Poll invokes fetch every interval until the context is cancelled and publishes each result that differs from the
+// previously published one. Failed fetches are skipped so a Registry which is temporarily unreachable doesn't show up
+// as a change. fetch is invoked once the scheduler lets the poll run, the shared Scheduler if nil. The returned channel
+// is closed once the context is cancelled.
+func Poll[T comparable](ctx context.Context, scheduler *Scheduler, interval time.Duration, fetch func() (T, error)) <-chan T {
+	return PollFunc(ctx, scheduler, interval, fetch, func(a T, b T) bool { return a == b })
+}
+
+// Endpoint is Poll for the endpoint of a service, which isn't comparable as it carries its metadata and tags
+func Endpoint(ctx context.Context, scheduler *Scheduler, interval time.Duration, fetch func() (types.ServiceEndpoint, error)) <-chan types.ServiceEndpoint {
+	return PollFunc(ctx, scheduler, interval, fetch, types.ServiceEndpoint.Equal)
+}
+
+// PollFunc is Poll with the results compared by equal
+func PollFunc[T any](ctx context.Context, scheduler *Scheduler, interval time.Duration, fetch func() (T, error), equal func(a T, b T) bool) <-chan T {
+	results := make(chan T)
+
+	go func() {
+		defer close(results)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last T
+		published := false
+		for {
+			if current, err := run(ctx, scheduler, fetch); err == nil && (!published || !equal(current, last)) {
+				select {
+				case results <- current:
+					last = current
+					published = true
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// Self polls a service's own registration and publishes an event each time it is found to deviate from the expected
+// endpoint, i.e. it was modified or deleted by someone else. fetch must return an empty endpoint when the registration
+// no longer exists.
+func Self(ctx context.Context, scheduler *Scheduler, interval time.Duration, expected types.ServiceEndpoint, fetch func() (types.ServiceEndpoint, error)) <-chan types.RegistrationEvent {
+	return SelfEvents(ctx, Endpoint(ctx, scheduler, interval, fetch), expected)
+}
+
+// SelfEvents publishes an event each time the endpoints of a service's own registration, however they are watched,
+// deviate from the expected endpoint, an empty endpoint meaning the registration no longer exists. The returned
+// channel is closed once the endpoints channel is, or the context is cancelled.
+func SelfEvents(ctx context.Context, endpoints <-chan types.ServiceEndpoint, expected types.ServiceEndpoint) <-chan types.RegistrationEvent {
+	events := make(chan types.RegistrationEvent)
+
+	go func() {
+		defer close(events)
+
+		for current := range endpoints {
+			if current.Equal(expected) {
+				continue
+			}
+
+			event := types.RegistrationEvent{Type: types.RegistrationModified, Endpoint: current}
+			if current.IsZero() {
+				event.Type = types.RegistrationDeleted
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
@@ -0,0 +1,113 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const testInterval = 10 * time.Millisecond
+
+func TestPollPublishesOnlyChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	values := []int{1, 1, 2, 2, 2, 3}
+	calls := 0
+	results := Poll(ctx, nil, testInterval, func() (int, error) {
+		value := values[len(values)-1]
+		if calls < len(values) {
+			value = values[calls]
+		}
+		calls++
+		return value, nil
+	})
+
+	assert.Equal(t, 1, receive(t, results))
+	assert.Equal(t, 2, receive(t, results))
+	assert.Equal(t, 3, receive(t, results))
+}
+
+func TestPollSkipsFailedFetches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	results := Poll(ctx, nil, testInterval, func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, errors.New("registry unreachable")
+		}
+		return calls, nil
+	})
+
+	assert.Equal(t, 3, receive(t, results))
+}
+
+func TestPollClosedOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := Poll(ctx, nil, testInterval, func() (int, error) {
+		return 1, nil
+	})
+	receive(t, results)
+	cancel()
+
+	select {
+	case _, ok := <-results:
+		assert.False(t, ok, "Expected channel to be closed")
+	case <-time.After(time.Second):
+		t.Fatal("Channel not closed after cancel")
+	}
+}
+
+func TestSelf(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expected := types.ServiceEndpoint{ServiceId: "my-service", Host: "localhost", Port: 8000}
+	modified := types.ServiceEndpoint{ServiceId: "my-service", Host: "localhost", Port: 9000}
+	states := []types.ServiceEndpoint{expected, expected, modified, {}}
+	calls := 0
+	events := Self(ctx, nil, testInterval, expected, func() (types.ServiceEndpoint, error) {
+		state := states[len(states)-1]
+		if calls < len(states) {
+			state = states[calls]
+		}
+		calls++
+		return state, nil
+	})
+
+	event := receive(t, events)
+	require.Equal(t, types.RegistrationModified, event.Type)
+	assert.Equal(t, modified, event.Endpoint)
+
+	event = receive(t, events)
+	require.Equal(t, types.RegistrationDeleted, event.Type)
+	assert.Empty(t, event.Endpoint)
+}
+
+// receive returns the next value published, failing the test if none is within a second
+func receive[T any](t *testing.T, values <-chan T) T {
+	t.Helper()
+
+	select {
+	case value := <-values:
+		return value
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for value")
+		var zero T
+		return zero
+	}
+}
@@ -0,0 +1,81 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// defaultScheduler is shared by the clients whose WatchConcurrency isn't set
+var defaultScheduler = &Scheduler{slots: make(chan struct{}, runtime.NumCPU())}
+
+// Scheduler limits the number of polls running at once among the watches sharing it, so watching many services keeps
+// the CPU usage of the gateway predictable. The polls of the other watches wait for a running one to complete.
+type Scheduler struct {
+	slots chan struct{}
+}
+
+// NewScheduler creates a Scheduler running up to concurrency polls at once, or returns the Scheduler shared by the
+// whole process, sized by the number of CPUs, if concurrency isn't positive
+func NewScheduler(concurrency int) *Scheduler {
+	if concurrency <= 0 {
+		return defaultScheduler
+	}
+	return &Scheduler{slots: make(chan struct{}, concurrency)}
+}
+
+// run invokes fetch once a poll can run, failing with the error of ctx if it is done first. A nil Scheduler is the
+// shared one.
+func run[T any](ctx context.Context, scheduler *Scheduler, fetch func() (T, error)) (T, error) {
+	if scheduler == nil {
+		scheduler = defaultScheduler
+	}
+
+	select {
+	case scheduler.slots <- struct{}{}:
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+	defer func() { <-scheduler.slots }()
+
+	return fetch()
+}
+
+// Shared shares the result of a fetch between the watches polling within a given age of it, so the watches of many
+// services are served by a single request listing all of them, where the backend allows it
+type Shared[T any] struct {
+	fetch     func(ctx context.Context) (T, error)
+	lock      sync.Mutex
+	result    T
+	fetchedAt time.Time
+}
+
+// NewShared creates a Shared fetching its result with fetch
+func NewShared[T any](fetch func(ctx context.Context) (T, error)) *Shared[T] {
+	return &Shared[T]{fetch: fetch}
+}
+
+// Get returns the result of the last fetch if it is more recent than maxAge, i.e. half the watch interval, or else
+// fetches it with ctx. The calls made while fetching wait for the result, which is only shared if the fetch succeeded.
+func (s *Shared[T]) Get(ctx context.Context, maxAge time.Duration) (T, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.fetchedAt.IsZero() && time.Since(s.fetchedAt) < maxAge {
+		return s.result, nil
+	}
+
+	result, err := s.fetch(ctx)
+	if err != nil {
+		return result, err
+	}
+	s.result, s.fetchedAt = result, time.Now()
+	return result, nil
+}
@@ -0,0 +1,110 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerLimitsConcurrentPolls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scheduler := NewScheduler(2)
+	var running, maxRunning atomic.Int32
+	fetch := func() (int, error) {
+		n := running.Add(1)
+		for {
+			current := maxRunning.Load()
+			if n <= current || maxRunning.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(testInterval)
+		running.Add(-1)
+		return 1, nil
+	}
+
+	var results []<-chan int
+	for i := 0; i < 6; i++ {
+		results = append(results, Poll(ctx, scheduler, testInterval, fetch))
+	}
+	for _, result := range results {
+		assert.Equal(t, 1, receive(t, result))
+	}
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2), "Expected at most 2 polls running at once")
+}
+
+func TestNewSchedulerDefault(t *testing.T) {
+	assert.Same(t, defaultScheduler, NewScheduler(0))
+	assert.Same(t, defaultScheduler, NewScheduler(-1))
+	assert.NotSame(t, defaultScheduler, NewScheduler(1))
+}
+
+func TestRunCancelledWhileWaiting(t *testing.T) {
+	scheduler := NewScheduler(1)
+	scheduler.slots <- struct{}{}
+	defer func() { <-scheduler.slots }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := run(ctx, scheduler, func() (int, error) {
+		t.Fatal("Expected no fetch once the context is cancelled")
+		return 0, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSharedFetchesOncePerMaxAge(t *testing.T) {
+	calls := 0
+	shared := NewShared(func(ctx context.Context) (int, error) {
+		calls++
+		return calls, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := shared.Get(context.Background(), time.Minute)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, result)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, calls, "Expected the watches to share a single fetch")
+
+	result, err := shared.Get(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result, "Expected a new fetch once the result is older than maxAge")
+}
+
+func TestSharedDoesNotShareFailures(t *testing.T) {
+	calls := 0
+	shared := NewShared(func(ctx context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errors.New("registry unreachable")
+		}
+		return calls, nil
+	})
+
+	_, err := shared.Get(context.Background(), time.Minute)
+	require.Error(t, err)
+
+	result, err := shared.Get(context.Background(), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result)
+}
@@ -0,0 +1,440 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package keepertest provides an in-process fake Core Keeper implementing the ping and registry routes, so services
+// using the registry client with Keeper can be tested without running Keeper:
+//
+//	keeper := keepertest.NewServer()
+//	server := keeper.Start()
+//	defer server.Close()
+//
+// The fake health checks services once when they register, with a plain GET of their check route, and its
+// responses can be scripted with canned responses, latency, failures and health status overrides.
+package keepertest
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	dtoCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/requests"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/health"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+var (
+	apiBase                       = "/api/" + common.ApiVersion
+	pingRoute                     = apiBase + "/ping"
+	registryRoute                 = apiBase + "/registry"
+	allRegistrationsRoute         = registryRoute + "/" + common.All
+	registrationByServiceIdPrefix = registryRoute + "/" + common.ServiceId + "/"
+)
+
+// cannedResponse is a response scripted with SetResponse
+type cannedResponse struct {
+	statusCode int
+	body       []byte
+}
+
+// Server is a fake Core Keeper keeping the registrations in memory
+type Server struct {
+	serviceStore          map[string]types.KeeperRegistration
+	healthOverrides       map[string]func() string
+	responses             map[string]cannedResponse
+	delay                 time.Duration
+	failures              int
+	expectedAuthorization string
+	serviceLock           sync.Mutex
+}
+
+// NewServer creates a fake Core Keeper without any registration, to be started with Start
+func NewServer() *Server {
+	mock := Server{
+		serviceStore:    make(map[string]types.KeeperRegistration),
+		healthOverrides: make(map[string]func() string),
+		responses:       make(map[string]cannedResponse),
+	}
+
+	return &mock
+}
+
+// AddRegistration stores the given registration as is, as if the service had registered itself and been health
+// checked, i.e. the status is taken from the registration
+func (mock *Server) AddRegistration(registration dtos.Registration) {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	mock.serviceStore[registration.ServiceId] = types.KeeperRegistration{Registration: registration}
+}
+
+// SetResponse has every request with the given method and path answered with the given status code and body, encoded
+// as JSON unless nil, instead of being handled, until ClearResponses is called. The path is unescaped, e.g.
+// /api/v3/registry/serviceId/core-data.
+func (mock *Server) SetResponse(method string, path string, statusCode int, body any) error {
+	response := cannedResponse{statusCode: statusCode}
+	if body != nil {
+		var err error
+		if response.body, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	mock.responses[method+" "+path] = response
+	return nil
+}
+
+// ClearResponses removes all the responses scripted with SetResponse
+func (mock *Server) ClearResponses() {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	mock.responses = make(map[string]cannedResponse)
+}
+
+// validateRegistration validates the registration request as Keeper does, against the AddRegistrationRequest of
+// core-contracts
+func validateRegistration(body []byte) error {
+	var req requests.AddRegistrationRequest
+	return json.Unmarshal(body, &req)
+}
+
+// respondCanned writes the response scripted with SetResponse for the request, if any, and tells whether it did
+func (mock *Server) respondCanned(writer http.ResponseWriter, request *http.Request) bool {
+	mock.serviceLock.Lock()
+	response, ok := mock.responses[request.Method+" "+request.URL.Path]
+	mock.serviceLock.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	if response.body != nil {
+		writer.Header().Set(common.ContentType, common.ContentTypeJSON)
+	}
+	writer.WriteHeader(response.statusCode)
+	_, _ = writer.Write(response.body)
+	return true
+}
+
+// MarkDown reports the target service as DOWN regardless of its health check results, until MarkUp or Flap is called
+func (mock *Server) MarkDown(serviceKey string) {
+	mock.setHealthOverride(serviceKey, func() string { return string(types.StatusDown) })
+}
+
+// MarkUp reports the target service as UP regardless of its health check results, until MarkDown or Flap is called
+func (mock *Server) MarkUp(serviceKey string) {
+	mock.setHealthOverride(serviceKey, func() string { return string(types.StatusUp) })
+}
+
+// Flap reports the target service as alternating between DOWN and UP every period, starting with DOWN, until MarkDown
+// or MarkUp is called
+func (mock *Server) Flap(serviceKey string, period time.Duration) {
+	start := time.Now()
+	mock.setHealthOverride(serviceKey, func() string {
+		if period <= 0 || (time.Since(start)/period)%2 == 0 {
+			return string(types.StatusDown)
+		}
+		return string(types.StatusUp)
+	})
+}
+
+// Delay holds every response for the given duration, or until the request is cancelled. Zero disables the delay.
+func (mock *Server) Delay(duration time.Duration) {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	mock.delay = duration
+}
+
+// Forget drops the registration of the target service, as Keeper does when restarting without persistent storage
+func (mock *Server) Forget(serviceKey string) {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	delete(mock.serviceStore, serviceKey)
+}
+
+// Fail rejects the next count requests with 503 Service Unavailable, as Keeper does while restarting
+func (mock *Server) Fail(count int) {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	mock.failures = count
+}
+
+// failing tells whether the current request is to be rejected as scripted by Fail
+func (mock *Server) failing() bool {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	if mock.failures <= 0 {
+		return false
+	}
+	mock.failures--
+	return true
+}
+
+func (mock *Server) setHealthOverride(serviceKey string, status func() string) {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	mock.healthOverrides[serviceKey] = status
+}
+
+// registration returns the stored registration of the target service with its scripted health status applied.
+// Callers must hold serviceLock.
+func (mock *Server) registration(serviceKey string) (types.KeeperRegistration, bool) {
+	r, ok := mock.serviceStore[serviceKey]
+	if !ok {
+		return r, false
+	}
+	if status, ok := mock.healthOverrides[serviceKey]; ok && !types.ParseStatus(r.Status).IsHalted() {
+		r.Status = status()
+	}
+	return r, true
+}
+
+func (mock *Server) wait(request *http.Request) {
+	mock.serviceLock.Lock()
+	delay := mock.delay
+	mock.serviceLock.Unlock()
+
+	if delay <= 0 {
+		return
+	}
+
+	select {
+	case <-time.After(delay):
+	case <-request.Context().Done():
+	}
+}
+
+// SetExpectedAuthorization has every request without the given Authorization header rejected with 401 Unauthorized
+func (mock *Server) SetExpectedAuthorization(authorization string) {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	mock.expectedAuthorization = authorization
+}
+
+// ClearExpectedAuthorization accepts requests regardless of their Authorization header again
+func (mock *Server) ClearExpectedAuthorization() {
+	mock.SetExpectedAuthorization("")
+}
+
+func (mock *Server) authorized(request *http.Request) bool {
+	mock.serviceLock.Lock()
+	defer mock.serviceLock.Unlock()
+
+	return mock.expectedAuthorization == "" || request.Header.Get("Authorization") == mock.expectedAuthorization
+}
+
+// Start starts serving the Keeper API on a local port. The caller closes the returned server once done.
+func (mock *Server) Start() *httptest.Server {
+	testMockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mock.wait(request)
+
+		if mock.failing() {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if !mock.authorized(request) {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if mock.respondCanned(writer, request) {
+			return
+		}
+
+		if request.URL.Path == registryRoute {
+			switch request.Method {
+			case http.MethodPost:
+				mock.serviceLock.Lock()
+				defer mock.serviceLock.Unlock()
+
+				bodyBytes, err := io.ReadAll(request.Body)
+				if err != nil {
+					log.Printf("error reading request body: %s", err.Error())
+				}
+
+				var req types.KeeperRegistrationRequest
+				err = json.Unmarshal(bodyBytes, &req)
+				if err != nil {
+					log.Printf("error decoding request body: %s", err.Error())
+				}
+				// Like Keeper, registrations missing required fields, i.e. the path of the health check, are rejected
+				if err := validateRegistration(bodyBytes); err != nil {
+					writer.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				// Like Keeper, only the http checks are run, the status of the other types is reported by the client
+				registration := req.Registration
+				if registration.HealthCheck.Type == types.CheckTypeHTTP {
+					checkUrl := registration.HealthCheck.Type + "://" + registration.Host + ":" + strconv.Itoa(registration.Port) + registration.HealthCheck.Path
+					if err := health.Probe(request.Context(), checkUrl, types.HealthCheckOptions{}); err != nil {
+						log.Printf("error health checking: %s", err.Error())
+						registration.Status = string(types.StatusDown)
+					} else {
+						registration.Status = string(types.StatusUp)
+					}
+				}
+				mock.serviceStore[registration.ServiceId] = registration
+
+				writer.Header().Set(common.ContentTypeJSON, common.ContentTypeJSON)
+				writer.WriteHeader(http.StatusCreated)
+			case http.MethodPut:
+				mock.serviceLock.Lock()
+				defer mock.serviceLock.Unlock()
+
+				bodyBytes, err := io.ReadAll(request.Body)
+				if err != nil {
+					log.Printf("error reading request body: %s", err.Error())
+				}
+
+				var req types.KeeperRegistrationRequest
+				err = json.Unmarshal(bodyBytes, &req)
+				if err != nil {
+					log.Printf("error decoding request body: %s", err.Error())
+				}
+				// Like Keeper, registrations missing required fields, i.e. the path of the health check, are rejected
+				if err := validateRegistration(bodyBytes); err != nil {
+					writer.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				mock.serviceStore[req.Registration.ServiceId] = req.Registration
+
+				writer.WriteHeader(http.StatusNoContent)
+			case http.MethodPatch:
+				mock.serviceLock.Lock()
+				defer mock.serviceLock.Unlock()
+
+				bodyBytes, err := io.ReadAll(request.Body)
+				if err != nil {
+					log.Printf("error reading request body: %s", err.Error())
+				}
+
+				var req types.KeeperRegistrationPatchRequest
+				err = json.Unmarshal(bodyBytes, &req)
+				if err != nil {
+					log.Printf("error decoding request body: %s", err.Error())
+				}
+				registration, found := mock.serviceStore[req.Registration.ServiceId]
+				if !found {
+					writer.WriteHeader(http.StatusNotFound)
+					return
+				}
+				mock.serviceStore[req.Registration.ServiceId] = req.Registration.Apply(registration)
+
+				writer.WriteHeader(http.StatusNoContent)
+			}
+		} else if request.URL.Path == allRegistrationsRoute {
+			switch request.Method {
+			case http.MethodGet:
+				mock.serviceLock.Lock()
+				defer mock.serviceLock.Unlock()
+
+				var registrations []types.KeeperRegistration
+				for key := range mock.serviceStore {
+					r, _ := mock.registration(key)
+					registrations = append(registrations, r)
+				}
+				resp := types.KeeperMultiRegistrationsResponse{
+					BaseWithTotalCountResponse: dtoCommon.BaseWithTotalCountResponse{
+						BaseResponse: dtoCommon.BaseResponse{
+							Versionable: dtoCommon.Versionable{ApiVersion: common.ApiVersion},
+							RequestId:   "",
+							Message:     "",
+							StatusCode:  200,
+						},
+						TotalCount: uint32(len(mock.serviceStore)),
+					},
+					Registrations: registrations,
+				}
+				jsonData, _ := json.Marshal(resp)
+				writer.Header().Set(common.ContentType, common.ContentTypeJSON)
+				writer.WriteHeader(http.StatusOK)
+				_, err := writer.Write(jsonData)
+				if err != nil {
+					log.Printf("error writing data response: %s", err.Error())
+				}
+			}
+		} else if strings.HasPrefix(request.URL.Path, registrationByServiceIdPrefix) {
+			key := strings.TrimPrefix(request.URL.Path, registrationByServiceIdPrefix)
+			switch request.Method {
+			case http.MethodGet:
+				mock.serviceLock.Lock()
+				defer mock.serviceLock.Unlock()
+
+				var resp interface{}
+				r, ok := mock.registration(key)
+				if !ok {
+					resp = dtoCommon.BaseResponse{
+						Versionable: dtoCommon.Versionable{ApiVersion: common.ApiVersion},
+						RequestId:   "",
+						Message:     "not found",
+						StatusCode:  http.StatusNotFound,
+					}
+				} else {
+					resp = types.KeeperRegistrationResponse{
+						BaseResponse: dtoCommon.BaseResponse{
+							Versionable: dtoCommon.Versionable{ApiVersion: common.ApiVersion},
+							RequestId:   "",
+							Message:     "",
+							StatusCode:  http.StatusOK,
+						},
+						Registration: r,
+					}
+				}
+
+				jsonData, _ := json.Marshal(resp)
+				writer.Header().Set(common.ContentType, common.ContentTypeJSON)
+				_, err := writer.Write(jsonData)
+				if err != nil {
+					log.Printf("error writing data response: %s", err.Error())
+				}
+			case http.MethodDelete:
+				mock.serviceLock.Lock()
+				defer mock.serviceLock.Unlock()
+
+				_, ok := mock.serviceStore[key]
+				if ok {
+					delete(mock.serviceStore, key)
+				}
+
+				writer.WriteHeader(http.StatusNoContent)
+			}
+		} else if request.URL.Path == pingRoute {
+			switch request.Method {
+			case http.MethodGet:
+				resp := dtoCommon.PingResponse{
+					Versionable: dtoCommon.Versionable{ApiVersion: common.ApiVersion},
+					Timestamp:   "",
+					ServiceName: "",
+				}
+				jsonData, _ := json.Marshal(resp)
+				writer.Header().Set(common.ContentType, common.ContentTypeJSON)
+				_, _ = writer.Write(jsonData)
+			}
+		}
+	}))
+
+	return testMockServer
+}
@@ -0,0 +1,62 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keepertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	dtoCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/responses"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+func TestAddRegistration(t *testing.T) {
+	keeper := NewServer()
+	server := keeper.Start()
+	defer server.Close()
+
+	keeper.AddRegistration(dtos.Registration{ServiceId: "core-data", Host: "edgex-core-data", Port: 59880, Status: string(types.StatusUp)})
+
+	response, err := http.Get(server.URL + registrationByServiceIdPrefix + "core-data")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	var registration responses.RegistrationResponse
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&registration))
+	assert.Equal(t, "edgex-core-data", registration.Registration.Host)
+	assert.Equal(t, string(types.StatusUp), registration.Registration.Status)
+}
+
+func TestSetResponse(t *testing.T) {
+	keeper := NewServer()
+	server := keeper.Start()
+	defer server.Close()
+
+	canned := dtoCommon.BaseResponse{Message: "keeper is shutting down", StatusCode: http.StatusServiceUnavailable}
+	require.NoError(t, keeper.SetResponse(http.MethodGet, pingRoute, http.StatusServiceUnavailable, canned))
+
+	response, err := http.Get(server.URL + pingRoute)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+
+	var body dtoCommon.BaseResponse
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, canned.Message, body.Message)
+
+	keeper.ClearResponses()
+	response, err = http.Get(server.URL + pingRoute)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
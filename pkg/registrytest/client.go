@@ -0,0 +1,182 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package registrytest provides an in-process fake registry Client whose health can be scripted, so services can
+// test how they react to their dependencies going down, flapping or answering slowly without running a registry:
+//
+//	fake, err := registrytest.NewClient(types.Config{ServiceKey: "core-command", ServiceHost: "localhost", ServicePort: 59882})
+//	fake.MarkDown("core-data")
+//	fake.Delay(100 * time.Millisecond)
+//
+// The registrations are kept in memory, like with the memory registry type, the services registered being available
+// unless scripted otherwise. pkg/keepertest provides the same controls for services talking to Keeper over HTTP.
+package registrytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/memory"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry"
+)
+
+// Client is a fake registry Client keeping the registrations in memory, whose reported health and latency can be
+// scripted with MarkDown, MarkUp, Flap and Delay
+type Client struct {
+	registry.Client
+	lock            sync.Mutex
+	healthOverrides map[string]func() bool
+	delay           time.Duration
+}
+
+// NewClient creates a fake registry Client pre-seeded with the MemoryEndpoints of the configuration, its Type being
+// ignored. Service details are optional, not needed just for discovery, but required if registering.
+func NewClient(config types.Config) (*Client, error) {
+	client, err := memory.NewMemoryClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new fake registry Client: %w", err)
+	}
+
+	return &Client{Client: client, healthOverrides: make(map[string]func() bool)}, nil
+}
+
+// MarkDown reports the target service as unavailable, until MarkUp or Flap is called
+func (c *Client) MarkDown(serviceKey string) {
+	c.setHealthOverride(serviceKey, func() bool { return false })
+}
+
+// MarkUp reports the target service as available, until MarkDown or Flap is called
+func (c *Client) MarkUp(serviceKey string) {
+	c.setHealthOverride(serviceKey, func() bool { return true })
+}
+
+// Flap reports the target service as alternating between unavailable and available every period, starting with
+// unavailable, until MarkDown or MarkUp is called
+func (c *Client) Flap(serviceKey string, period time.Duration) {
+	start := time.Now()
+	c.setHealthOverride(serviceKey, func() bool {
+		return period > 0 && (time.Since(start)/period)%2 == 1
+	})
+}
+
+// Delay holds every registration, lookup and availability check for the given duration, or until its context is
+// done. Zero disables the delay.
+func (c *Client) Delay(duration time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.delay = duration
+}
+
+func (c *Client) setHealthOverride(serviceKey string, available func() bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.healthOverrides[serviceKey] = available
+}
+
+// wait holds the call for the scripted delay, returning the error of ctx if it's done first
+func (c *Client) wait(ctx context.Context) error {
+	c.lock.Lock()
+	delay := c.delay
+	c.lock.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) Register() error {
+	return c.RegisterWithContext(context.Background())
+}
+
+func (c *Client) RegisterWithContext(ctx context.Context) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.RegisterWithContext(ctx)
+}
+
+func (c *Client) Unregister() error {
+	return c.UnregisterWithContext(context.Background())
+}
+
+func (c *Client) UnregisterWithContext(ctx context.Context) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.UnregisterWithContext(ctx)
+}
+
+func (c *Client) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *Client) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	if err := c.wait(ctx); err != nil {
+		return types.ServiceEndpoint{}, err
+	}
+	return c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+}
+
+func (c *Client) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceId)
+}
+
+func (c *Client) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) ([]types.ServiceEndpoint, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+}
+
+func (c *Client) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+func (c *Client) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.Client.GetAllServiceEndpointsWithContext(ctx)
+}
+
+func (c *Client) IsServiceAvailable(serviceId string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceId)
+}
+
+// IsServiceAvailableWithContext checks if the target service is registered, reporting it unhealthy when scripted
+// unavailable. Scripted health never makes a service which isn't registered available.
+func (c *Client) IsServiceAvailableWithContext(ctx context.Context, serviceId string) (bool, error) {
+	if err := c.wait(ctx); err != nil {
+		return false, err
+	}
+	available, err := c.Client.IsServiceAvailableWithContext(ctx, serviceId)
+	if err != nil {
+		return available, err
+	}
+
+	c.lock.Lock()
+	override, found := c.healthOverrides[serviceId]
+	c.lock.Unlock()
+
+	if found && !override() {
+		return false, types.Errorf(types.ErrUnhealthy, "%s service not healthy...", serviceId)
+	}
+	return true, nil
+}
@@ -0,0 +1,80 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registrytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const testServiceKey = "core-data"
+
+func makeClient(t *testing.T) *Client {
+	client, err := NewClient(types.Config{
+		MemoryEndpoints: []types.ServiceEndpoint{{ServiceId: testServiceKey, Host: "edgex-core-data", Port: 59880}},
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestMarkDown(t *testing.T) {
+	client := makeClient(t)
+
+	client.MarkDown(testServiceKey)
+	available, err := client.IsServiceAvailable(testServiceKey)
+	require.ErrorIs(t, err, types.ErrUnhealthy)
+	assert.False(t, available)
+
+	_, err = client.GetServiceEndpoint(testServiceKey)
+	require.NoError(t, err, "Expected a service marked down to stay registered")
+
+	client.MarkUp(testServiceKey)
+	available, err = client.IsServiceAvailable(testServiceKey)
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	client.MarkUp("core-command")
+	_, err = client.IsServiceAvailable("core-command")
+	require.ErrorIs(t, err, types.ErrNotRegistered, "Expected scripted health not to register the service")
+}
+
+func TestFlap(t *testing.T) {
+	client := makeClient(t)
+
+	period := 50 * time.Millisecond
+	client.Flap(testServiceKey, period)
+
+	available, _ := client.IsServiceAvailable(testServiceKey)
+	require.False(t, available, "Expected service to start flapping unavailable")
+
+	isAvailable := func() bool {
+		available, _ := client.IsServiceAvailable(testServiceKey)
+		return available
+	}
+	require.Eventually(t, isAvailable, 10*period, period/10, "Expected service to have flapped available")
+	require.Eventually(t, func() bool { return !isAvailable() }, 10*period, period/10, "Expected service to have flapped unavailable again")
+}
+
+func TestDelay(t *testing.T) {
+	client := makeClient(t)
+
+	client.Delay(time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.GetServiceEndpointWithContext(ctx, testServiceKey)
+	require.ErrorIs(t, err, context.Canceled, "Expected the delayed lookup to give up once ctx is done")
+
+	client.Delay(0)
+	endpoint, err := client.GetServiceEndpoint(testServiceKey)
+	require.NoError(t, err)
+	assert.Equal(t, 59880, endpoint.Port)
+}
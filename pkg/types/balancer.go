@@ -0,0 +1,156 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// WeightMetadataKey is the metadata key of the weight of a service instance for the Weighted Balancer, i.e. 3 for an
+// instance to get three times the lookups of an instance registered without weight
+const WeightMetadataKey = "weight"
+
+// RoleMetadataKey is the metadata key of the role of a service instance, RoleStandby for a standby instance which the
+// BalancingClient doesn't select until it is promoted with registry.PromoteStandby
+const RoleMetadataKey = "role"
+
+// The roles of a service instance, registered in its RoleMetadataKey metadata. The instances registered without role
+// are active.
+const (
+	RoleActive  = "active"
+	RoleStandby = "standby"
+)
+
+// IsStandby tells whether the service instance registered as a standby instance
+func (e ServiceEndpoint) IsStandby() bool {
+	return e.Metadata[RoleMetadataKey] == RoleStandby
+}
+
+// Weight returns the weight the service registered with for the Weighted Balancer, 1 if it registered without a
+// valid weight
+func (e ServiceEndpoint) Weight() int {
+	if weight, err := strconv.Atoi(e.Metadata[WeightMetadataKey]); err == nil && weight >= 0 {
+		return weight
+	}
+	return 1
+}
+
+// Balancer selects the endpoint GetServiceEndpoint returns out of the instances of a service, i.e. the replicas of a
+// horizontally scaled service. Implementations must be safe for concurrent use, and may keep state per service.
+type Balancer interface {
+	// Select returns the endpoint to use out of the endpoints of the instances of the target service, at least two
+	Select(serviceKey string, endpoints []ServiceEndpoint) ServiceEndpoint
+}
+
+// BalancerFunc is a stateless Balancer implemented by a function
+type BalancerFunc func(serviceKey string, endpoints []ServiceEndpoint) ServiceEndpoint
+
+// Select calls f
+func (f BalancerFunc) Select(serviceKey string, endpoints []ServiceEndpoint) ServiceEndpoint {
+	return f(serviceKey, endpoints)
+}
+
+// instanceKey identifies the instance of an endpoint, by its host and port for the registry types without instance IDs
+func instanceKey(endpoint ServiceEndpoint) string {
+	if endpoint.InstanceId != "" {
+		return endpoint.InstanceId
+	}
+	return net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))
+}
+
+type roundRobinBalancer struct {
+	lock sync.Mutex
+	next map[string]int
+}
+
+// RoundRobin returns a Balancer selecting the instances of each service in turn, in the order they are discovered in
+func RoundRobin() Balancer {
+	return &roundRobinBalancer{next: make(map[string]int)}
+}
+
+func (b *roundRobinBalancer) Select(serviceKey string, endpoints []ServiceEndpoint) ServiceEndpoint {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	next := b.next[serviceKey] % len(endpoints)
+	b.next[serviceKey] = next + 1
+	return endpoints[next]
+}
+
+// Random returns a Balancer selecting an instance at random
+func Random() Balancer {
+	return BalancerFunc(func(_ string, endpoints []ServiceEndpoint) ServiceEndpoint {
+		return endpoints[rand.Intn(len(endpoints))]
+	})
+}
+
+type leastRecentlyUsedBalancer struct {
+	lock sync.Mutex
+	// uses counts the selections, lastUsed being the count at the last selection of each instance, by service
+	uses     uint64
+	lastUsed map[string]map[string]uint64
+}
+
+// LeastRecentlyUsed returns a Balancer selecting the instance selected the longest time ago, the ones never selected
+// first. Unlike RoundRobin, the instances joining the service are selected right away.
+func LeastRecentlyUsed() Balancer {
+	return &leastRecentlyUsedBalancer{lastUsed: make(map[string]map[string]uint64)}
+}
+
+func (b *leastRecentlyUsedBalancer) Select(serviceKey string, endpoints []ServiceEndpoint) ServiceEndpoint {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	lastUsed := b.lastUsed[serviceKey]
+	selected := 0
+	for i := range endpoints {
+		if lastUsed[instanceKey(endpoints[i])] < lastUsed[instanceKey(endpoints[selected])] {
+			selected = i
+		}
+	}
+
+	// Forget the instances gone, so the map doesn't keep growing as the instances of the service are replaced
+	used := make(map[string]uint64, len(endpoints))
+	for _, endpoint := range endpoints {
+		if use, found := lastUsed[instanceKey(endpoint)]; found {
+			used[instanceKey(endpoint)] = use
+		}
+	}
+	b.uses++
+	used[instanceKey(endpoints[selected])] = b.uses
+	b.lastUsed[serviceKey] = used
+
+	return endpoints[selected]
+}
+
+// Weighted returns a Balancer selecting an instance at random in proportion of its Weight, the ServiceWeight it
+// registered with, so the share of the lookups of a canary instance is set through the registry alone. The instances
+// registered without a valid weight weigh 1, and those weighing 0 are only selected if all the instances do.
+func Weighted() Balancer {
+	return BalancerFunc(func(serviceKey string, endpoints []ServiceEndpoint) ServiceEndpoint {
+		total := 0
+		weights := make([]int, len(endpoints))
+		for i, endpoint := range endpoints {
+			weights[i] = endpoint.Weight()
+			total += weights[i]
+		}
+		if total == 0 {
+			return Random().Select(serviceKey, endpoints)
+		}
+
+		n := rand.Intn(total)
+		for i, weight := range weights {
+			if n < weight {
+				return endpoints[i]
+			}
+			n -= weight
+		}
+		return endpoints[len(endpoints)-1]
+	})
+}
@@ -0,0 +1,94 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	replica0 = ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-0", Host: "10.0.0.1", Port: 59880}
+	replica1 = ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-1", Host: "10.0.0.2", Port: 59880}
+	replica2 = ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-2", Host: "10.0.0.3", Port: 59880}
+)
+
+func TestRoundRobin(t *testing.T) {
+	balancer := RoundRobin()
+	replicas := []ServiceEndpoint{replica0, replica1, replica2}
+
+	var selected []ServiceEndpoint
+	for i := 0; i < 4; i++ {
+		selected = append(selected, balancer.Select("core-data", replicas))
+	}
+	assert.Equal(t, []ServiceEndpoint{replica0, replica1, replica2, replica0}, selected)
+
+	assert.Equal(t, replica1, balancer.Select("core-data", replicas[:2]), "Expected the turn to be kept as an instance leaves")
+}
+
+func TestRandom(t *testing.T) {
+	balancer := Random()
+	replicas := []ServiceEndpoint{replica0, replica1}
+
+	for i := 0; i < 10; i++ {
+		assert.Contains(t, replicas, balancer.Select("core-data", replicas))
+	}
+}
+
+func TestLeastRecentlyUsed(t *testing.T) {
+	balancer := LeastRecentlyUsed()
+
+	assert.Equal(t, replica0, balancer.Select("core-data", []ServiceEndpoint{replica0, replica1}))
+	assert.Equal(t, replica1, balancer.Select("core-data", []ServiceEndpoint{replica0, replica1}))
+	assert.Equal(t, replica2, balancer.Select("core-data", []ServiceEndpoint{replica0, replica1, replica2}),
+		"Expected the instance joining to be selected first")
+	assert.Equal(t, replica0, balancer.Select("core-data", []ServiceEndpoint{replica0, replica1, replica2}))
+
+	withoutIds := []ServiceEndpoint{{ServiceId: "core-command", Host: "10.0.0.4", Port: 59882}, {ServiceId: "core-command", Host: "10.0.0.5", Port: 59882}}
+	assert.Equal(t, withoutIds[0], balancer.Select("core-command", withoutIds))
+	assert.Equal(t, withoutIds[1], balancer.Select("core-command", withoutIds), "Expected the instances to be told apart by address")
+}
+
+func TestWeighted(t *testing.T) {
+	balancer := Weighted()
+	heavy := replica0
+	heavy.Metadata = map[string]string{WeightMetadataKey: "3"}
+	drained := replica1
+	drained.Metadata = map[string]string{WeightMetadataKey: "0"}
+
+	counts := make(map[string]int)
+	for i := 0; i < 400; i++ {
+		counts[balancer.Select("core-data", []ServiceEndpoint{heavy, drained, replica2}).InstanceId]++
+	}
+	assert.Zero(t, counts[drained.InstanceId], "Expected the instance weighing 0 not to be selected")
+	assert.Greater(t, counts[heavy.InstanceId], counts[replica2.InstanceId])
+
+	undrained := balancer.Select("core-data", []ServiceEndpoint{drained, drained})
+	assert.Equal(t, drained, undrained, "Expected an instance to be selected when all weigh 0")
+}
+
+func TestWeightedCanary(t *testing.T) {
+	stable := Config{ServiceKey: "core-data", ServiceWeight: 9, ServiceMetadata: map[string]string{"version": "3.1"}}
+	canary := Config{ServiceKey: "core-data", ServiceWeight: 1, ServiceMetadata: map[string]string{"version": "3.2"}}
+	assert.Equal(t, map[string]string{"version": "3.1", WeightMetadataKey: "9"}, stable.GetServiceMetadata())
+
+	endpoints := []ServiceEndpoint{replica0, replica1}
+	endpoints[0].Metadata = stable.GetServiceMetadata()
+	endpoints[1].Metadata = canary.GetServiceMetadata()
+	assert.Equal(t, 9, endpoints[0].Weight())
+	assert.Equal(t, 1, endpoints[1].Weight())
+	assert.Equal(t, 1, replica2.Weight(), "Expected instance registered without weight to weigh 1")
+
+	balancer := Weighted()
+	canaryLookups := 0
+	for i := 0; i < 10000; i++ {
+		if balancer.Select("core-data", endpoints).Equal(endpoints[1]) {
+			canaryLookups++
+		}
+	}
+	assert.InDelta(t, 1000, canaryLookups, 200, "Expected the canary to get about 10% of the lookups")
+}
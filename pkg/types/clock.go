@@ -0,0 +1,22 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "time"
+
+// Clock tells the time the cached service endpoints are aged by, i.e. the time of the Registry estimated from its
+// responses rather than the local real-time clock, which may be unreliable on devices without a battery-backed one
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is a Clock implemented by a function, i.e. ClockFunc(time.Now) for the local clock
+type ClockFunc func() time.Time
+
+// Now calls f
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
@@ -18,12 +18,52 @@ package types
 
 import (
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type GetAccessTokenCallback func() (string, error)
 
+// GetEncryptionKeyCallback is a callback function that retrieves an encryption key, i.e. from the SecretProvider
+type GetEncryptionKeyCallback func() ([]byte, error)
+
+const (
+	defaultWatchInterval                   = 10 * time.Second
+	defaultWatchWaitTime                   = 5 * time.Minute
+	defaultRetryBaseDelay                  = 500 * time.Millisecond
+	defaultRetryConnectionRefusedBaseDelay = 50 * time.Millisecond
+	defaultMDNSBrowseTimeout               = time.Second
+	defaultCircuitBreakerCooldown          = 10 * time.Second
+	defaultEndpointSnapshotInterval        = 30 * time.Second
+)
+
+const (
+	// CheckTypeHTTP has the Registry health check the service by calling its CheckRoute over HTTP
+	CheckTypeHTTP = "http"
+	// CheckTypeNone registers the service for discovery only, for services which don't serve HTTP
+	CheckTypeNone = "none"
+	// CheckTypeTCP health checks the service by opening a TCP connection to its ServicePort. Only supported by the
+	// keeper and etcd registry types
+	CheckTypeTCP = "tcp"
+	// CheckTypeGRPC health checks the service by calling the grpc.health.v1 Check method of the plaintext gRPC server
+	// listening on its ServicePort, for the gRPC service named by CheckRoute or the overall server health if empty. Only
+	// supported by the keeper and etcd registry types
+	CheckTypeGRPC = "grpc"
+	// CheckTypeTTL has the service push its health instead of being checked: it is healthy as long as it called
+	// PassTTL within the last CheckInterval, i.e. with a registry.Heartbeater. Only supported by the keeper and consul
+	// registry types
+	CheckTypeTTL = "ttl"
+)
+
 // Config defines the information need to connect to the registry service and optionally register the service
 // for discovery and health checks
 type Config struct {
@@ -33,28 +73,311 @@ type Config struct {
 	Host string
 	// Port is the HTTP port of the registry service
 	Port int
-	// Type is the implementation type of the registry service, i.e. consul
+	// FailoverEndpoints are the host:port endpoints of the other replicas of the registry service, i.e. for keeper or
+	// Consul deployments without load balancer in front of them. The requests sent to Host and Port fail over to them
+	// in turn when it can't be reached or responds with a 502, 503 or 504 status code, within the same RequestTimeout,
+	// so DialTimeout is to be set for a replica down not to use it up. Only used by the keeper, consul and etcd
+	// registry types. May be left empty
+	FailoverEndpoints []string
+	// FailbackInterval is how often the requests are sent to Host and Port again once failed over, so they go back to it
+	// once it recovers, i.e. 1m. The requests keep being sent to the replica which last responded if left empty
+	FailbackInterval string
+	// Type is the implementation type of the registry service, i.e. consul, keeper, etcd, kubernetes, dns, mdns or memory
 	Type string
 	// ServiceKey is the key identifying the service for Registration and building the services base configuration path.
 	ServiceKey string
+	// ServiceInstanceId is the ID of the current instance among the replicas registering with the same ServiceKey, i.e.
+	// core-data-1, the ServiceKey being used if neither set nor generated. Only used by the consul, etcd and kubernetes
+	// registry types, etcd storing each instance under the key of the service suffixed with /<instance id>. The keeper
+	// type refuses to register with it, or with GenerateInstanceId, as Keeper keys the registrations by service key
+	// only, so a single instance per service key can be registered with it. May be left empty
+	ServiceInstanceId string
+	// GenerateInstanceId generates the ServiceInstanceId when not set with the InstanceIdGenerator, so each replica
+	// registers its own instance
+	GenerateInstanceId bool
+	// InstanceIdGenerator generates the ServiceInstanceId with GenerateInstanceId, i.e. UUIDv7InstanceIds,
+	// MACInstanceIds or FileInstanceIds for IDs tied to the hardware. Defaults to HostPortInstanceIds, suffixing the
+	// ServiceKey with the ServiceHost and ServicePort, i.e. core-data-10.0.0.7:59880, if not set
+	InstanceIdGenerator InstanceIdGenerator
 	// ServiceHost is the hostname or IP address of the current running service using this module. May be left empty if not using registration
 	ServiceHost string
+	// DetectServiceHost has the ServiceHost detected when left empty or set to localhost or a loopback address the
+	// other containers can't reach: the address of the ServiceInterface if set, otherwise of the interface routing to
+	// the registry Host, otherwise of the first interface up. Creating the client fails when no address other than
+	// loopback is found. A loopback ServiceHost is kept without DetectServiceHost, a warning being logged
+	DetectServiceHost bool
+	// ServiceInterface is the network interface whose address is registered as ServiceHost with DetectServiceHost, i.e.
+	// eth1 on gateways with a plant network interface. May be left empty
+	ServiceInterface string
 	// ServicePort is the HTTP port of the current running service using this module. May be left unset if not using registration
 	ServicePort int
-	// The ServiceProtocol that should be used to call the current running service using this module. May be left empty if not using registration
+	// The ServiceProtocol that should be used to call the current running service using this module, i.e. https.
+	// Registered as its SchemeMetadataKey metadata when set, returned in the Scheme of its ServiceEndpoint by the
+	// registry types storing the ServiceMetadata. HTTP is used if not set. May be left empty if not using registration
 	ServiceProtocol string
+	// ServiceMetadata are the key/value pairs registered along with the current service, i.e. its version, region or
+	// device profile, returned in the Metadata of its ServiceEndpoint so consumers can choose between services. Stored
+	// by the consul, etcd and memory registry types, which Consul limits to 64 pairs, while the keeper type refuses to
+	// register the current service with it, as Keeper has nowhere to store it. May be left empty
+	ServiceMetadata map[string]string
+	// ServiceTags are the tags registered along with the current service, i.e. its capabilities, returned in the Tags
+	// of its ServiceEndpoint. Stored by the same registry types as ServiceMetadata. May be left empty
+	ServiceTags []string
+	// ServiceNamedEndpoints are the endpoints the current service registers by name next to ServiceHost and
+	// ServicePort, i.e. opcua with the 4840 port and opc.tcp scheme for a device service also serving OPC UA, resolved
+	// with registry.GetServiceEndpointByName. Each is registered as metadata keyed by its name prefixed with
+	// EndpointMetadataKeyPrefix, so Consul only accepts names made of letters, digits, - and _. Stored by the same
+	// registry types as ServiceMetadata. May be left empty
+	ServiceNamedEndpoints map[string]NamedEndpoint
+	// ServiceZone is the zone of the current service, i.e. the redundant plant network it is reached on, registered as
+	// its ZoneMetadataKey metadata. Stored by the same registry types as ServiceMetadata. May be left empty
+	ServiceZone string
+	// ServiceWeight is the weight of the current service among the instances of its service key for the Weighted
+	// Balancer, registered as its WeightMetadataKey metadata, i.e. 1 for a canary instance next to one weighing 9 for
+	// the canary to get 10% of the lookups. Stored by the same registry types as ServiceMetadata. May be left unset, the
+	// service then weighing 1
+	ServiceWeight int
+	// ZoneFailoverOrder is the order in which the zones are preferred when discovering redundant services, i.e. zone-a,
+	// zone-b then AnyZone, used by registry.GetServiceEndpointByZone and to order the endpoints returned by
+	// GetAllServiceEndpoints with ByZone unless EndpointOrder is set. May be left empty
+	ZoneFailoverOrder []string
+	// CheckType is the type of health check performed on the current running service, i.e. http, tcp, grpc, ttl or none.
+	// HTTP is used if not set. CheckRoute is only required for HTTP health checks and CheckInterval for all but none.
+	// May be left empty if not using registration
+	CheckType string
+	// UncheckedServicesAvailable indicates whether IsServiceAvailable reports the services registered without health
+	// check, i.e. with the none check type, available as long as they are registered, as registering them so is the
+	// choice of their own side. Consul and Keeper report them unhealthy when set to false, as nothing tells they are up.
+	// The other registry types have no health status to report them by, so always report them available. Defaults to
+	// true if not set
+	UncheckedServicesAvailable *bool
 	// Health check callback route for the current running service using this module. May be left empty if not using registration
 	CheckRoute string
 	// Health check callback interval. May be left empty if not using registration
 	CheckInterval string
+	// CheckExpectation is the content the response of the HTTP health check of the current service must have on top of
+	// a healthy status. The etcd registry type verifies it on every health check, as its client runs them to keep the
+	// lease of the registration alive. The keeper type runs the HTTP health checks with other options than the default
+	// ones itself and reports the status to Keeper, which only calls the check route with a plain GET. The other types
+	// verify it with ProbeBeforeRegister and TriggerHealthCheck of the current service only, as Consul only supports
+	// status code checks. No content is expected if not set
+	CheckExpectation HealthCheckExpectation
+	// CheckMethod is the HTTP method of the health check of the current service, GET or HEAD. Unlike the other HTTP
+	// health check options, it is also passed to Consul. GET is used if not set
+	CheckMethod string
+	// CheckHeaders are added to the HTTP health check requests of the current service, i.e. an Authorization header
+	// for check routes requiring a bearer token. Never registered, as the registrations are readable by every service:
+	// the keeper and consul types run the checks with headers themselves and report the status, which ConsulCatalog
+	// doesn't support. May be left empty
+	CheckHeaders map[string]string
+	// CheckStatusCodes are the status codes of the healthy responses to the HTTP health check of the current service.
+	// Consul ignores them, reporting any 2xx status healthy. Only 200 OK if not set
+	CheckStatusCodes []int
+	// ProbeBeforeRegister indicates whether the health check route of the current running service is called once before
+	// registering, refusing to register unless its response is healthy. The registration template applies if not set,
+	// otherwise the service isn't probed. May be left unset if not using registration
+	ProbeBeforeRegister *bool
+	// DeregisterCriticalAfter is how long the current service may fail its health check before the Registry removes
+	// its registration, so a crashed service doesn't linger in the discovery results, i.e. 30m. Passed to Consul as the
+	// DeregisterCriticalServiceAfter of the health check, which Consul doesn't honor below a minute, and to Keeper in
+	// the registration. The etcd registrations expire with their lease regardless. See registry.StalePruner for the
+	// registries not purging registrations themselves. Never removed if not set
+	DeregisterCriticalAfter string
+	// Template is the name of the entry of RegistrationTemplates whose settings apply to the check settings left empty.
+	// May be left empty if not using registration templates
+	Template string
+	// RegistrationTemplates are the registration templates services can reference by name
+	RegistrationTemplates map[string]RegistrationTemplate
+	// RegistrationVerifyInterval is the interval at which Keeper is checked for the registration of the current running
+	// service once registered, which is restored if Keeper lost it, i.e. after restarting without persistent storage.
+	// The registration isn't verified if left empty. May be left empty if not using registration
+	RegistrationVerifyInterval string
+	// EndpointOrder is the order of the service endpoints returned by GetAllServiceEndpoints. Defaults to ByZone with
+	// the ZoneFailoverOrder if set, or else ByServiceId if not set
+	EndpointOrder EndpointOrder
+	// EndpointPolicy optionally verifies every discovered service endpoint, rejecting the ones it returns an error for,
+	// i.e. AllowCIDRs or DenyPublicIPs. Rejected endpoints are treated as not found. Endpoints aren't verified if not set
+	EndpointPolicy EndpointPolicy
+	// Balancer optionally selects the endpoint GetServiceEndpoint returns out of the instances of the target service
+	// returned by GetServiceEndpoints, i.e. RoundRobin, Random, LeastRecentlyUsed or Weighted, leaving out the standby
+	// instances registered with the RoleStandby role in their ServiceMetadata. The endpoint of the service as
+	// registered is returned if not set
+	Balancer Balancer
+	// EndpointCacheTTL is how long the endpoints looked up with GetServiceEndpoint and GetServiceEndpoints are served
+	// from memory before the Registry is asked again, i.e. 5s. The endpoints aren't cached if left empty
+	EndpointCacheTTL string
+	// EndpointCacheMaxStale is how long the cached endpoints keep being served once expired while they are refreshed in
+	// the background, including while the Registry is unreachable, i.e. 30s. The expired endpoints are looked up again
+	// before being returned if left empty
+	EndpointCacheMaxStale string
+	// EndpointCacheWatch has the cached endpoints of a service forgotten as soon as the Registry reports a change of its
+	// registration rather than once expired, each cached service being watched for the lifetime of the client
+	EndpointCacheWatch bool
+	// WatchInterval is the interval at which the Registry is polled when watching registrations. Defaults to 10s if left empty
+	WatchInterval string
+	// WatchWaitTime is the longest a blocking query of a watch waits for a change, on the Registries watched with them
+	// rather than polled, i.e. consul, before it is sent again. Defaults to 5m if left empty, Consul capping it at 10m,
+	// and is shortened to half the RequestTimeout if longer, so the queries don't time out
+	WatchWaitTime string
+	// WatchConcurrency is the maximum number of watches of the client polling the Registry at once, the others waiting
+	// for their turn. Defaults to a pool sized by the number of CPUs shared by all the clients if left unset. It doesn't
+	// apply to the consul watches, which wait on blocking queries rather than polling
+	WatchConcurrency int
+	// RequestTimeout is the time limit for each request sent to the Registry, i.e. 10s. Requests have no time limit other
+	// than the one of their context if left empty
+	RequestTimeout string
+	// DialTimeout is the time limit for establishing a connection to the Registry, i.e. 30s. The Go default is used if left empty
+	DialTimeout string
+	// RoundTripper optionally replaces the pooled transport used for sending requests to the Registry, i.e. to go through
+	// a corporate proxy, taking precedence over the secure transport of the AuthInjector. The TLS, MaxIdleConns,
+	// MaxIdleConnsPerHost, IdleConnTimeout and DialTimeout settings are applied to a clone of it when it is an
+	// http.Transport, creating the client failing when they are set with any other RoundTripper
+	RoundTripper http.RoundTripper
+	// TLSCAFile is the optional PEM encoded CA bundle used to verify the certificate of the Registry served over HTTPS.
+	// The system CAs are used if left empty
+	TLSCAFile string
+	// TLSCertFile and TLSKeyFile are the optional PEM encoded client certificate and key presented to the Registry for mutual TLS
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSInsecureSkipVerify disables the verification of the certificate of the Registry. Only intended for testing
+	TLSInsecureSkipVerify bool
+	// MaxIdleConns is the maximum number of idle (keep-alive) connections kept open to the Registry. The Go default is used if not set
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle (keep-alive) connections kept open per host. The Go default is used if not set
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle (keep-alive) connection is kept open before closing itself, i.e. 90s. The Go default is used if left empty
+	IdleConnTimeout string
+	// RetryMaxAttempts is the maximum number of attempts of a Keeper request failing with a transient error, i.e. connection
+	// refused or a 5xx status, so short Registry restarts are ridden out. Requests failing because the Registry host name
+	// doesn't exist or the TLS handshake failed aren't retried, as retrying won't help. The POST requests registering
+	// services aren't idempotent, so they are only retried when they failed to connect to the Registry, i.e. connection
+	// refused. Requests aren't retried if not set
+	RetryMaxAttempts int
+	// RetryBaseDelay is the delay before the first retry of a request, doubled for each subsequent retry. Defaults to 500ms if left empty
+	RetryBaseDelay string
+	// RetryConnectionRefusedBaseDelay replaces RetryBaseDelay for the requests whose connection was refused, which often
+	// succeed within milliseconds once the restarting Registry listens again. Defaults to 50ms if left empty
+	RetryConnectionRefusedBaseDelay string
+	// RetryJitter is the fraction of each retry delay, between 0 and 1, which is randomized to spread the retries of
+	// clients failing at the same time. Retry delays aren't randomized if not set
+	RetryJitter float64
+	// CircuitBreakerThreshold is the number of consecutive requests failing to reach the Registry, or answered with a
+	// 502, 503 or 504 status code, after which the requests fail fast with ErrCircuitOpen rather than each waiting for
+	// the Registry to time out. Along with EndpointCacheMaxStale, the cached endpoints keep being served meanwhile.
+	// Requests are always sent if not set
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is how long the requests fail fast once the circuit breaker opened, before a single probe
+	// request is sent to the Registry, closing the circuit breaker if it succeeds. Defaults to 10s if left empty
+	CircuitBreakerCooldown string
+	// EndpointSnapshotFile is the file the endpoints last looked up with GetServiceEndpoint and GetServiceEndpoints
+	// are saved to, loaded when the client is created and returned by the lookups when the Registry is unavailable, so
+	// services restarting during a Registry outage still resolve their dependencies. The file is written readable by
+	// its owner only. No snapshot is kept if left empty
+	EndpointSnapshotFile string
+	// EndpointSnapshotInterval is the interval at which the endpoints looked up since are saved to the
+	// EndpointSnapshotFile. Defaults to 30s if left empty
+	EndpointSnapshotInterval string
+	// GetEndpointSnapshotKey retrieves the AES key, of 16, 24 or 32 bytes, the EndpointSnapshotFile is encrypted with,
+	// i.e. from the SecretProvider of the service, so the endpoints saved on stolen gateway storage don't reveal the
+	// internal topology. The snapshot is saved unencrypted if not set. Creating the client fails if the
+	// EndpointSnapshotFile was saved unencrypted, unless EndpointSnapshotMigrate is set
+	GetEndpointSnapshotKey GetEncryptionKeyCallback
+	// EndpointSnapshotMigrate encrypts the EndpointSnapshotFile with the key of GetEndpointSnapshotKey when the client
+	// is created, if it was saved unencrypted before the key was set. It is a one-shot migration, to be unset once the
+	// snapshot was encrypted so an unencrypted snapshot planted afterwards isn't trusted
+	EndpointSnapshotMigrate bool
+	// FallbackEndpoints are the host:port endpoints of the services, by service key, returned by the lookups when the
+	// Registry is unavailable, i.e. core-data = 10.1.2.3:59880, so edge gateways keep reaching the services they depend
+	// on during Registry outages. The lookups of the other services keep failing. May be left empty
+	FallbackEndpoints map[string]string
+	// ConsulDatacenter is the datacenter the services are discovered in with the consul registry type, i.e. dc2, their
+	// instances being looked up in its catalog rather than among the services registered with the agent. The current
+	// service still registers with the agent, in its datacenter. The services of the datacenter of the agent are
+	// discovered if left empty
+	ConsulDatacenter string
+	// ConsulNamespace is the Consul Enterprise namespace the current service registers in and the services are
+	// discovered in with the consul registry type. The namespace of the ACL token, otherwise default, is used if left empty
+	ConsulNamespace string
+	// ConsulPartition is the Consul Enterprise admin partition the current service registers in and the services are
+	// discovered in with the consul registry type. The partition of the agent is used if left empty
+	ConsulPartition string
+	// ConsulCatalog has the consul registry type register and discover the services directly in the catalog of the
+	// Consul server at Host and Port, for containers without local Consul agent. The current service registers on its
+	// own node, external to Consul, whose HTTP health checks are run by consul-esm if deployed, as Consul servers don't
+	// run health checks themselves. The ttl check type, and the http checks with CheckHeaders the client runs itself
+	// with a TTL check, aren't supported, as nothing would expire them. The local agent is used if not set
+	ConsulCatalog bool
+	// ConsulNode is the node of the catalog the current service registers on with ConsulCatalog, i.e. the hostname to
+	// share it with the other services of the host. Defaults to a node of its own, named after the ServiceInstanceId
+	// and deregistered along with the service, if left empty
+	ConsulNode string
+	// KubeconfigFile is the kubeconfig file providing the API server and credentials of the current context for the
+	// kubernetes registry type. Host and Port, then the in-cluster service account, are used if left empty
+	KubeconfigFile string
+	// KubernetesNamespace is the namespace the services are discovered and registered in with the kubernetes registry
+	// type. Defaults to the namespace of the kubeconfig context or of the service account, otherwise default
+	KubernetesNamespace string
+	// KubernetesRegisterEndpoints indicates whether Register publishes an EndpointSlice for the current running service
+	// with the kubernetes registry type, for services not selected by a Kubernetes Service. Kubernetes registers the
+	// pods selected by Services itself, so registering does nothing if not set
+	KubernetesRegisterEndpoints bool
+	// DNSDomain is the domain of the SRV records services are discovered from with the dns registry type, i.e.
+	// cluster.local. The search domains of the resolver apply if left empty
+	DNSDomain string
+	// DNSServicePrefix is prepended to the service key in the SRV record names with the dns registry type, i.e. edgex-
+	// to discover core-data from _edgex-core-data._tcp.cluster.local. May be left empty
+	DNSServicePrefix string
+	// DNSProtocol is the protocol of the SRV record names with the dns registry type, i.e. tcp or udp. Defaults to tcp if left empty
+	DNSProtocol string
+	// MDNSServiceType is the DNS-SD service type the services are advertised and browsed as with the mdns registry type.
+	// Defaults to _edgex._tcp if left empty
+	MDNSServiceType string
+	// MDNSBrowseTimeout is how long the answers to mDNS queries are collected with the mdns registry type, i.e. 500ms.
+	// Defaults to 1s if left empty
+	MDNSBrowseTimeout string
+	// MemoryEndpoints are the endpoints the services are pre-seeded with with the memory registry type, i.e. the services
+	// the current one depends on when running without Registry. Seeded services are healthy. May be left empty
+	MemoryEndpoints []ServiceEndpoint
 	// AccessToken is the optional ACL token for accessing the Registry. This token is only needed when the Registry has
 	// been secured with a ACL
 	AccessToken string
+	// AccessTokenFile is the optional path of the file holding the ACL token of Consul, i.e. rendered by the Vault agent,
+	// used in place of AccessToken and read again each time it changes, so the rotated tokens are used without
+	// restarting. Defaults to the CONSUL_HTTP_TOKEN_FILE environment variable when AccessToken is also left empty, the
+	// CONSUL_HTTP_TOKEN environment variable being used otherwise. GetAccessToken isn't called when set
+	AccessTokenFile string
 	// GetAccessToken is a callback function that retrieves a new Access Token.
-	// This callback is used when a '403 Forbidden' status is received from any call to the configuration provider service.
+	// This callback is used when a '403 Forbidden' status is received from any call to the configuration provider service,
+	// or a '401 Unauthorized' or '403 Forbidden' status from any call to Keeper, whose renewed token is sent as bearer token.
 	GetAccessToken GetAccessTokenCallback
-	// AuthInjector is an interface to obtain a JWT and secure transport for remote service calls
+	// AuthInjector is an interface to obtain a JWT and secure transport for remote service calls. The secure transport,
+	// if any, replaces the pooled transport unless RoundTripper is set, the failover, circuit breaker and connection
+	// settings still applying like for RoundTripper
 	AuthInjector interfaces.AuthenticationInjector
+	// LoggingClient optionally logs the requests sent to the Registry, their responses and retries at debug and trace
+	// level, and the registrations restored or updated, the latter at info level with the fields which changed. Only
+	// used by the keeper and consul registry types. Nothing is logged if not set
+	LoggingClient logger.LoggingClient
+	// Logger is the standard library logger used instead, for programs not using the EdgeX LoggingClient. Its records
+	// are tagged with the RegistryTypeLogKey and ServiceKeyLogKey attributes. Ignored if LoggingClient is set
+	Logger *slog.Logger
+	// OnRegistrationUpdate is optionally called each time Register replaces an existing registration of the current
+	// service, with the fields which changed, which are also logged at info level. Only called by the keeper registry
+	// type, as the other types don't tell whether the service was already registered
+	OnRegistrationUpdate func(update RegistrationUpdate)
+	// RegistrationMutator is optionally called with the registration of the current service right before it is sent to
+	// Keeper, each time it is, to add organization-specific Extensions or adjust its fields without forking Register.
+	// The registration isn't sent if it returns an error, which is returned instead. Only called by the keeper registry
+	// type, as the only one taking a registration payload
+	RegistrationMutator func(registration *KeeperRegistration) error
+	// TracerProvider optionally creates an OpenTelemetry span for each registry operation, tagged with the service key,
+	// registry type and status, and for each notification sent by the watches, from the change being detected until
+	// the subscriber receives it. Operations aren't traced if not set
+	TracerProvider trace.TracerProvider
+	// TextMapPropagator is the OpenTelemetry propagator injecting the trace context of the requests sent to Keeper into
+	// their headers, so Keeper requests join the distributed trace of the registry operation. Defaults to the global
+	// propagator, set with otel.SetTextMapPropagator, if not set
+	TextMapPropagator propagation.TextMapPropagator
 	// EnableNameFieldEscape indicates whether enables NameFieldEscape in this service
 	// The name field escape could allow the system to use special or Chinese characters in the different name fields, including device, profile, and so on.  If the EnableNameFieldEscape is false, some special characters might cause system error.
 	// TODO: remove in EdgeX 4.0
@@ -77,6 +400,259 @@ func (config Config) GetExpandedRoute(route string) string {
 	return fmt.Sprintf("%s://%s:%v%s", config.GetServiceProtocol(), config.ServiceHost, config.ServicePort, route)
 }
 
+func (config Config) GetCheckType() string {
+	if config.CheckType == "" {
+		return CheckTypeHTTP
+	}
+
+	return config.CheckType
+}
+
+// GetProbeBeforeRegister tells whether the current running service is probed before registering, false if not set
+func (config Config) GetProbeBeforeRegister() bool {
+	return config.ProbeBeforeRegister != nil && *config.ProbeBeforeRegister
+}
+
+// GetUncheckedServicesAvailable tells whether the services registered without health check are reported available as
+// long as they are registered, true if not set
+func (config Config) GetUncheckedServicesAvailable() bool {
+	return config.UncheckedServicesAvailable == nil || *config.UncheckedServicesAvailable
+}
+
+func (config Config) GetWatchInterval() (time.Duration, error) {
+	if config.WatchInterval == "" {
+		return defaultWatchInterval, nil
+	}
+
+	interval, err := time.ParseDuration(config.WatchInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid watch interval '%s': %v", config.WatchInterval, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("invalid watch interval '%s': must be greater than zero", config.WatchInterval)
+	}
+
+	return interval, nil
+}
+
+func (config Config) GetWatchWaitTime() (time.Duration, error) {
+	waitTime := defaultWatchWaitTime
+	if config.WatchWaitTime != "" {
+		var err error
+		waitTime, err = time.ParseDuration(config.WatchWaitTime)
+		if err != nil {
+			return 0, fmt.Errorf("invalid watch wait time '%s': %v", config.WatchWaitTime, err)
+		}
+		if waitTime <= 0 {
+			return 0, fmt.Errorf("invalid watch wait time '%s': must be greater than zero", config.WatchWaitTime)
+		}
+	}
+
+	requestTimeout, err := config.GetRequestTimeout()
+	if err != nil {
+		return 0, err
+	}
+	if requestTimeout > 0 {
+		waitTime = min(waitTime, requestTimeout/2)
+	}
+	return waitTime, nil
+}
+
+func (config Config) GetRegistrationVerifyInterval() (time.Duration, error) {
+	return parseOptionalDuration("registration verify interval", config.RegistrationVerifyInterval)
+}
+
+func (config Config) GetDeregisterCriticalAfter() (time.Duration, error) {
+	return parseOptionalDuration("deregister critical after", config.DeregisterCriticalAfter)
+}
+
+func (config Config) GetEndpointCacheTTL() (time.Duration, error) {
+	return parseOptionalDuration("endpoint cache TTL", config.EndpointCacheTTL)
+}
+
+func (config Config) GetEndpointCacheMaxStale() (time.Duration, error) {
+	return parseOptionalDuration("endpoint cache max stale", config.EndpointCacheMaxStale)
+}
+
+func (config Config) GetFailbackInterval() (time.Duration, error) {
+	return parseOptionalDuration("failback interval", config.FailbackInterval)
+}
+
+// GetRegistryEndpoints returns the host:port endpoints of the replicas of the registry service, Host and Port then the
+// FailoverEndpoints
+func (config Config) GetRegistryEndpoints() ([]string, error) {
+	endpoints := []string{net.JoinHostPort(config.Host, strconv.Itoa(config.Port))}
+	for _, endpoint := range config.FailoverEndpoints {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil {
+			return nil, fmt.Errorf("invalid failover endpoint '%s': %v", endpoint, err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+func (config Config) GetRequestTimeout() (time.Duration, error) {
+	return parseOptionalDuration("request timeout", config.RequestTimeout)
+}
+
+func (config Config) GetDialTimeout() (time.Duration, error) {
+	return parseOptionalDuration("dial timeout", config.DialTimeout)
+}
+
+func (config Config) GetIdleConnTimeout() (time.Duration, error) {
+	return parseOptionalDuration("idle connection timeout", config.IdleConnTimeout)
+}
+
+func (config Config) GetRetryBaseDelay() (time.Duration, error) {
+	if config.RetryBaseDelay == "" {
+		return defaultRetryBaseDelay, nil
+	}
+
+	return parseOptionalDuration("retry base delay", config.RetryBaseDelay)
+}
+
+func (config Config) GetRetryConnectionRefusedBaseDelay() (time.Duration, error) {
+	if config.RetryConnectionRefusedBaseDelay == "" {
+		return defaultRetryConnectionRefusedBaseDelay, nil
+	}
+
+	return parseOptionalDuration("retry connection refused base delay", config.RetryConnectionRefusedBaseDelay)
+}
+
+func (config Config) GetCircuitBreakerCooldown() (time.Duration, error) {
+	if config.CircuitBreakerCooldown == "" {
+		return defaultCircuitBreakerCooldown, nil
+	}
+
+	return parseOptionalDuration("circuit breaker cooldown", config.CircuitBreakerCooldown)
+}
+
+func (config Config) GetEndpointSnapshotInterval() (time.Duration, error) {
+	if config.EndpointSnapshotInterval == "" {
+		return defaultEndpointSnapshotInterval, nil
+	}
+
+	interval, err := parseOptionalDuration("endpoint snapshot interval", config.EndpointSnapshotInterval)
+	if err == nil && interval == 0 {
+		return 0, fmt.Errorf("invalid endpoint snapshot interval '%s': must be greater than zero", config.EndpointSnapshotInterval)
+	}
+
+	return interval, err
+}
+
+func (config Config) GetMDNSBrowseTimeout() (time.Duration, error) {
+	if config.MDNSBrowseTimeout == "" {
+		return defaultMDNSBrowseTimeout, nil
+	}
+
+	return parseOptionalDuration("mDNS browse timeout", config.MDNSBrowseTimeout)
+}
+
+// GetFallbackEndpoints returns the FallbackEndpoints parsed as service endpoints, by service key
+func (config Config) GetFallbackEndpoints() (map[string]ServiceEndpoint, error) {
+	endpoints := make(map[string]ServiceEndpoint, len(config.FallbackEndpoints))
+	for serviceKey, address := range config.FallbackEndpoints {
+		host, portValue, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fallback endpoint '%s' of %s: %v", address, serviceKey, err)
+		}
+		port, err := strconv.Atoi(portValue)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid fallback endpoint '%s' of %s: invalid port", address, serviceKey)
+		}
+		endpoints[serviceKey] = ServiceEndpoint{ServiceId: serviceKey, Host: host, Port: port}
+	}
+
+	return endpoints, nil
+}
+
+// GetCheckOptions returns the HTTP health check options of the current service when the given service is the current
+// one, otherwise the default options as the ones of the other services aren't known
+func (config Config) GetCheckOptions(serviceKey string) HealthCheckOptions {
+	if serviceKey != config.ServiceKey {
+		return HealthCheckOptions{}
+	}
+
+	return HealthCheckOptions{
+		Method:                 config.CheckMethod,
+		Headers:                config.CheckHeaders,
+		StatusCodes:            config.CheckStatusCodes,
+		HealthCheckExpectation: config.CheckExpectation,
+	}
+}
+
+// GetServiceInstanceId returns the ID the current instance registers with, the ServiceInstanceId, the one generated
+// from the ServiceKey, ServiceHost and ServicePort with GenerateInstanceId unless generated by the InstanceIdGenerator
+// with WithInstanceId, or else the ServiceKey
+func (config Config) GetServiceInstanceId() string {
+	switch {
+	case config.ServiceInstanceId != "":
+		return config.ServiceInstanceId
+	case config.GenerateInstanceId && config.ServiceHost != "":
+		return hostPortInstanceId(config)
+	default:
+		return config.ServiceKey
+	}
+}
+
+// GetServiceMetadata returns the metadata the current service registers with, the ServiceMetadata along with the
+// ServiceZone, ServiceWeight, ServiceProtocol and ServiceNamedEndpoints if set
+func (config Config) GetServiceMetadata() map[string]string {
+	if config.ServiceZone == "" && config.ServiceWeight == 0 && config.ServiceProtocol == "" && len(config.ServiceNamedEndpoints) == 0 {
+		return config.ServiceMetadata
+	}
+
+	metadata := make(map[string]string, len(config.ServiceMetadata)+len(config.ServiceNamedEndpoints)+3)
+	for key, value := range config.ServiceMetadata {
+		metadata[key] = value
+	}
+	if config.ServiceZone != "" {
+		metadata[ZoneMetadataKey] = config.ServiceZone
+	}
+	if config.ServiceWeight != 0 {
+		metadata[WeightMetadataKey] = strconv.Itoa(config.ServiceWeight)
+	}
+	if config.ServiceProtocol != "" {
+		metadata[SchemeMetadataKey] = config.ServiceProtocol
+	}
+	for name, endpoint := range config.ServiceNamedEndpoints {
+		metadata[EndpointMetadataKeyPrefix+name] = endpoint.String()
+	}
+	return metadata
+}
+
+// GetEndpointOrder returns the EndpointOrder, or ByZone with the ZoneFailoverOrder if set, or else nil for the default
+// order
+func (config Config) GetEndpointOrder() EndpointOrder {
+	if config.EndpointOrder == nil && len(config.ZoneFailoverOrder) > 0 {
+		return ByZone(config.ZoneFailoverOrder...)
+	}
+
+	return config.EndpointOrder
+}
+
+// GetLoggingClient returns the LoggingClient, one logging to the Logger if not set, or else one logging nothing
+func (config Config) GetLoggingClient() logger.LoggingClient {
+	if config.LoggingClient != nil {
+		return config.LoggingClient
+	}
+	if config.Logger != nil {
+		return NewSlogLoggingClient(config.Logger.With(RegistryTypeLogKey, config.Type, ServiceKeyLogKey, config.ServiceKey))
+	}
+
+	return logger.NewMockClient()
+}
+
+// GetTextMapPropagator returns the TextMapPropagator, or the global one if not set
+func (config Config) GetTextMapPropagator() propagation.TextMapPropagator {
+	if config.TextMapPropagator == nil {
+		return otel.GetTextMapPropagator()
+	}
+
+	return config.TextMapPropagator
+}
+
 func (config Config) GetRegistryProtocol() string {
 	if config.Protocol == "" {
 		return "http"
@@ -92,3 +668,20 @@ func (config Config) GetServiceProtocol() string {
 
 	return config.ServiceProtocol
 }
+
+// parseOptionalDuration parses a duration setting, returning zero when it is left empty
+func parseOptionalDuration(name string, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s '%s': %v", name, value, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("invalid %s '%s': must not be negative", name, value)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,40 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"errors"
+
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v3/errors"
+)
+
+// EdgeXKind returns the EdgeX error kind of the failure mode of err, so the EdgeX service layers can handle registry
+// failures like any other: KindEntityDoesNotExist for ErrNotRegistered, KindServiceUnavailable for ErrUnhealthy and
+// ErrRegistryUnavailable, and KindNotAllowed for ErrUnauthorized as EdgeX has no unauthorized kind. The kind of the
+// EdgeX errors err wraps, i.e. the Keeper ones, is returned otherwise, or KindUnknown.
+func EdgeXKind(err error) edgexErrors.ErrKind {
+	switch {
+	case errors.Is(err, ErrNotRegistered):
+		return edgexErrors.KindEntityDoesNotExist
+	case errors.Is(err, ErrUnauthorized):
+		return edgexErrors.KindNotAllowed
+	case errors.Is(err, ErrUnhealthy), errors.Is(err, ErrRegistryUnavailable):
+		return edgexErrors.KindServiceUnavailable
+	default:
+		return edgexErrors.Kind(err)
+	}
+}
+
+// ToEdgeX wraps err, returned by a registry Client, into an errors.EdgeX of its EdgeXKind, keeping its message and
+// failure mode for errors.Is. The HTTP status code of the EdgeX error is the one of its kind, i.e. 404 for
+// ErrNotRegistered, so it can be returned by the service APIs as is. It returns nil if err is nil.
+func ToEdgeX(err error) edgexErrors.EdgeX {
+	if err == nil {
+		return nil
+	}
+
+	return edgexErrors.NewCommonEdgeX(EdgeXKind(err), "", err)
+}
@@ -0,0 +1,46 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v3/errors"
+)
+
+func TestToEdgeX(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedKind edgexErrors.ErrKind
+		expectedCode int
+	}{
+		{"not registered", Errorf(ErrNotRegistered, "no matching service endpoint found"), edgexErrors.KindEntityDoesNotExist, http.StatusNotFound},
+		{"unhealthy", Errorf(ErrUnhealthy, "core-data service not healthy..."), edgexErrors.KindServiceUnavailable, http.StatusServiceUnavailable},
+		{"registry unavailable", fmt.Errorf("failed to get service core-data endpoint: %w", Errorf(ErrRegistryUnavailable, "connection refused")), edgexErrors.KindServiceUnavailable, http.StatusServiceUnavailable},
+		{"unauthorized", &KeeperError{StatusCode: http.StatusForbidden}, edgexErrors.KindNotAllowed, http.StatusMethodNotAllowed},
+		{"edgex", edgexErrors.NewCommonEdgeX(edgexErrors.KindStatusConflict, "conflict", nil), edgexErrors.KindStatusConflict, http.StatusConflict},
+		{"other", errors.New("failed"), edgexErrors.KindUnknown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edgexErr := ToEdgeX(tt.err)
+			require.NotNil(t, edgexErr)
+			assert.Equal(t, tt.expectedKind, edgexErrors.Kind(edgexErr))
+			assert.Equal(t, tt.expectedCode, edgexErr.Code())
+			assert.Equal(t, tt.err.Error(), edgexErr.Error())
+			assert.ErrorIs(t, edgexErr, tt.err, "Expected the failure mode to be kept")
+		})
+	}
+
+	assert.Nil(t, ToEdgeX(nil))
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// EndpointPolicy verifies a discovered service endpoint before it is handed to the caller, returning an error to reject
+// it, i.e. to protect against a poisoned Registry redirecting traffic off the plant network
+type EndpointPolicy func(ctx context.Context, endpoint ServiceEndpoint) error
+
+// AllowCIDRs returns an EndpointPolicy rejecting the endpoints whose host isn't within any of the given CIDRs, i.e.
+// 10.0.0.0/8. Hostnames are resolved, and rejected unless all their addresses are allowed.
+func AllowCIDRs(cidrs ...string) (EndpointPolicy, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR '%s': %v", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return func(ctx context.Context, endpoint ServiceEndpoint) error {
+		return verifyAddresses(ctx, endpoint, func(addr netip.Addr) bool {
+			for _, prefix := range prefixes {
+				if prefix.Contains(addr) {
+					return true
+				}
+			}
+			return false
+		})
+	}, nil
+}
+
+// DenyPublicIPs returns an EndpointPolicy rejecting the endpoints whose host is a public IP address, i.e. neither
+// private, loopback nor link-local. Hostnames are resolved, and rejected if any of their addresses is public.
+func DenyPublicIPs() EndpointPolicy {
+	return func(ctx context.Context, endpoint ServiceEndpoint) error {
+		return verifyAddresses(ctx, endpoint, func(addr netip.Addr) bool {
+			return addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast()
+		})
+	}
+}
+
+// AllOf returns an EndpointPolicy rejecting the endpoints rejected by any of the given policies
+func AllOf(policies ...EndpointPolicy) EndpointPolicy {
+	return func(ctx context.Context, endpoint ServiceEndpoint) error {
+		for _, policy := range policies {
+			if err := policy(ctx, endpoint); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// verifyAddresses rejects the endpoint unless all the addresses of its host are allowed
+func verifyAddresses(ctx context.Context, endpoint ServiceEndpoint, allowed func(addr netip.Addr) bool) error {
+	addrs, err := resolveHost(ctx, endpoint.Host)
+	if err != nil {
+		return fmt.Errorf("unable to verify host %s: %v", endpoint.Host, err)
+	}
+
+	for _, addr := range addrs {
+		if !allowed(addr) {
+			return fmt.Errorf("address %s of host %s is not allowed", addr, endpoint.Host)
+		}
+	}
+	return nil
+}
+
+func resolveHost(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr.Unmap()}, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	for i := range addrs {
+		addrs[i] = addrs[i].Unmap()
+	}
+	return addrs, nil
+}
@@ -0,0 +1,72 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowCIDRs(t *testing.T) {
+	policy, err := AllowCIDRs("10.0.0.0/8", "fd00::/8")
+	require.NoError(t, err)
+
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{"10.1.2.3", true},
+		{"::ffff:10.1.2.3", true},
+		{"fd12::1", true},
+		{"192.168.1.10", false},
+		{"8.8.8.8", false},
+		{"localhost", false},
+	}
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			err := policy(context.Background(), ServiceEndpoint{ServiceId: "core-data", Host: test.host, Port: 59880})
+			assert.Equal(t, test.allowed, err == nil, "unexpected result: %v", err)
+		})
+	}
+
+	_, err = AllowCIDRs("10.0.0.0/33")
+	require.Error(t, err)
+}
+
+func TestDenyPublicIPs(t *testing.T) {
+	policy := DenyPublicIPs()
+
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{"10.1.2.3", true},
+		{"192.168.1.10", true},
+		{"127.0.0.1", true},
+		{"169.254.1.1", true},
+		{"fe80::1", true},
+		{"8.8.8.8", false},
+		{"2001:4860:4860::8888", false},
+	}
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			err := policy(context.Background(), ServiceEndpoint{ServiceId: "core-data", Host: test.host, Port: 59880})
+			assert.Equal(t, test.allowed, err == nil, "unexpected result: %v", err)
+		})
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	allowCIDRs, err := AllowCIDRs("0.0.0.0/0")
+	require.NoError(t, err)
+	policy := AllOf(allowCIDRs, DenyPublicIPs())
+
+	require.NoError(t, policy(context.Background(), ServiceEndpoint{Host: "10.1.2.3"}))
+	require.Error(t, policy(context.Background(), ServiceEndpoint{Host: "8.8.8.8"}))
+}
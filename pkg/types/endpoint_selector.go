@@ -0,0 +1,78 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EndpointSelector selects the service endpoints with all the given tags and metadata, i.e. the device services
+// tagged modbus in the eu region. The zero EndpointSelector selects all the services.
+type EndpointSelector struct {
+	// Tags are the tags the services must all have been registered with
+	Tags []string
+	// Metadata are the key/value pairs the services must all have been registered with
+	Metadata map[string]string
+	// AvailableOnly only selects the services which are available, i.e. healthy
+	AvailableOnly bool
+}
+
+// ParseEndpointSelector parses a label selector made of comma separated terms, each term being either a key=value pair
+// the metadata must have or a tag, i.e. "protocol=modbus,gpu" for the services with the modbus protocol metadata and
+// the gpu tag. Spaces around the terms are ignored.
+func ParseEndpointSelector(selector string) (EndpointSelector, error) {
+	var parsed EndpointSelector
+	if strings.TrimSpace(selector) == "" {
+		return parsed, nil
+	}
+
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		key, value, isPair := strings.Cut(term, "=")
+		key = strings.TrimSpace(key)
+		switch {
+		case key == "":
+			return EndpointSelector{}, fmt.Errorf("invalid endpoint selector '%s': empty term", selector)
+		case !isPair:
+			parsed.Tags = append(parsed.Tags, key)
+		default:
+			if parsed.Metadata == nil {
+				parsed.Metadata = make(map[string]string)
+			}
+			parsed.Metadata[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return parsed, nil
+}
+
+// Matches tells whether the endpoint has all the tags and metadata of the selector. The availability of the service
+// isn't known from its endpoint, so it isn't checked.
+func (s EndpointSelector) Matches(endpoint ServiceEndpoint) bool {
+	for _, tag := range s.Tags {
+		if !endpoint.HasTag(tag) {
+			return false
+		}
+	}
+	for key, value := range s.Metadata {
+		if actual, found := endpoint.Metadata[key]; !found || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the endpoints matched by the selector, in the same order
+func (s EndpointSelector) Filter(endpoints []ServiceEndpoint) []ServiceEndpoint {
+	var matched []ServiceEndpoint
+	for _, endpoint := range endpoints {
+		if s.Matches(endpoint) {
+			matched = append(matched, endpoint)
+		}
+	}
+	return matched
+}
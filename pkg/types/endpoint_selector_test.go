@@ -0,0 +1,44 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEndpointSelector(t *testing.T) {
+	selector, err := ParseEndpointSelector(" protocol=modbus, gpu ,region = eu")
+	require.NoError(t, err)
+	assert.Equal(t, EndpointSelector{
+		Tags:     []string{"gpu"},
+		Metadata: map[string]string{"protocol": "modbus", "region": "eu"},
+	}, selector)
+
+	selector, err = ParseEndpointSelector("")
+	require.NoError(t, err)
+	assert.Equal(t, EndpointSelector{}, selector)
+
+	_, err = ParseEndpointSelector("gpu,,region=eu")
+	require.Error(t, err)
+	_, err = ParseEndpointSelector("=modbus")
+	require.Error(t, err)
+}
+
+func TestEndpointSelectorMatches(t *testing.T) {
+	modbus := ServiceEndpoint{ServiceId: "device-modbus", Metadata: map[string]string{"protocol": "modbus", "region": "eu"}, Tags: []string{"gpu"}}
+	onvif := ServiceEndpoint{ServiceId: "device-onvif", Metadata: map[string]string{"protocol": "onvif", "region": "eu"}}
+	endpoints := []ServiceEndpoint{modbus, onvif}
+
+	assert.Equal(t, endpoints, EndpointSelector{}.Filter(endpoints))
+	assert.Equal(t, endpoints, EndpointSelector{Metadata: map[string]string{"region": "eu"}}.Filter(endpoints))
+	assert.Equal(t, []ServiceEndpoint{modbus}, EndpointSelector{Metadata: map[string]string{"protocol": "modbus"}}.Filter(endpoints))
+	assert.Equal(t, []ServiceEndpoint{modbus}, EndpointSelector{Tags: []string{"gpu"}}.Filter(endpoints))
+	assert.Empty(t, EndpointSelector{Tags: []string{"gpu"}, Metadata: map[string]string{"protocol": "onvif"}}.Filter(endpoints))
+	assert.False(t, EndpointSelector{Metadata: map[string]string{"version": ""}}.Matches(modbus), "Expected missing metadata not to match an empty value")
+}
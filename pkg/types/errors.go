@@ -0,0 +1,53 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The failure modes of the registry clients, which callers can branch on with errors.Is
+var (
+	// ErrNotRegistered is returned when the target service isn't registered, or has been unregistered
+	ErrNotRegistered = errors.New("service is not registered")
+	// ErrEndpointNotRegistered is returned when the target service is registered, but registered no named endpoint
+	// with the requested name
+	ErrEndpointNotRegistered = errors.New("named endpoint is not registered")
+	// ErrUnhealthy is returned when the target service is registered but fails its health check
+	ErrUnhealthy = errors.New("service is not healthy")
+	// ErrRegistryUnavailable is returned when the Registry can't be reached, i.e. connection refused or timed out
+	ErrRegistryUnavailable = errors.New("registry is unavailable")
+	// ErrUnauthorized is returned when the Registry rejects the request for its access token, i.e. expired or lacking
+	// the ACL permissions, once renewing the token didn't help
+	ErrUnauthorized = errors.New("registry request is unauthorized")
+	// ErrMalformedResponse is returned when the Registry responds with data the client can't use, i.e. invalid JSON,
+	// fields of the wrong type or a registration with an out of range port
+	ErrMalformedResponse = errors.New("registry response is malformed")
+	// ErrNotSupported is returned when the registry type doesn't support what is requested, i.e. registering service
+	// metadata with Keeper, which has nowhere to store it
+	ErrNotSupported = errors.New("not supported by the registry type")
+)
+
+// kindError is an error of one of the failure modes above, keeping its own message
+type kindError struct {
+	kind error
+	err  error
+}
+
+// Errorf formats an error like fmt.Errorf, which is also of the given failure mode for errors.Is, i.e.
+// ErrNotRegistered. The message is the formatted one, without the message of the failure mode.
+func Errorf(kind error, format string, args ...any) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
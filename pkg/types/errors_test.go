@@ -0,0 +1,27 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorf(t *testing.T) {
+	err := Errorf(ErrNotRegistered, "%s service is not registered. Might not have started... ", "core-data")
+	assert.EqualError(t, err, "core-data service is not registered. Might not have started... ")
+	assert.ErrorIs(t, err, ErrNotRegistered)
+	assert.NotErrorIs(t, err, ErrUnhealthy)
+
+	wrapped := fmt.Errorf("failed to get service core-data endpoint: %w", Errorf(ErrRegistryUnavailable, "%w", context.DeadlineExceeded))
+	assert.ErrorIs(t, wrapped, ErrRegistryUnavailable)
+	assert.ErrorIs(t, wrapped, context.DeadlineExceeded, "Expected the cause to be kept")
+	assert.True(t, errors.Is(wrapped, ErrRegistryUnavailable))
+}
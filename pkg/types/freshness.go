@@ -0,0 +1,21 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "time"
+
+// Freshness hints how long the endpoints a lookup returned can be relied on, so the callers can schedule their own
+// refreshes or decide when to re-resolve long-lived connections. The zero Freshness is the one of endpoints looked up
+// in the Registry right away, which are to be looked up again for each use.
+type Freshness struct {
+	// Age is how long ago the endpoints were looked up in the Registry
+	Age time.Duration
+	// TTL is how long the endpoints are still served before being refreshed, zero once expired
+	TTL time.Duration
+	// Stale tells whether the endpoints are served past their TTL while being refreshed, i.e. while the Registry is
+	// unreachable
+	Stale bool
+}
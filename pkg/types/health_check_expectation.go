@@ -0,0 +1,121 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// HealthCheckExpectation is the content the response of an HTTP health check must have, on top of a healthy status,
+// to catch services responding while internally broken. The zero value expects no particular content.
+type HealthCheckExpectation struct {
+	// BodyContains is a substring the response body must contain, i.e. "UP". Not verified if left empty
+	BodyContains string `json:"bodyContains,omitempty"`
+	// JSONPath is the path of a field of the JSON response body, with the names of the nested fields and the indexes
+	// of the array elements separated by dots, i.e. status or checks.0.status. Not verified if left empty
+	JSONPath string `json:"jsonPath,omitempty"`
+	// JSONValue is the value the field at JSONPath must have. Strings are compared without their quotes and other
+	// values by their JSON encoding, i.e. true or 3. Only a field at JSONPath is required if left empty
+	JSONValue string `json:"jsonValue,omitempty"`
+	// JSONFragment is a JSON document the JSON response body must match: objects match when they have all the fields
+	// of the fragment with matching values, other values when they are equal, i.e. {"status":"UP"} matches the bodies
+	// of healthy services whatever their other fields. Not verified if left empty
+	JSONFragment string `json:"jsonFragment,omitempty"`
+}
+
+// IsZero tells whether no particular content is expected
+func (e HealthCheckExpectation) IsZero() bool {
+	return e == HealthCheckExpectation{}
+}
+
+// Verify returns an error unless the response body has the expected content
+func (e HealthCheckExpectation) Verify(body []byte) error {
+	if e.BodyContains != "" && !strings.Contains(string(body), e.BodyContains) {
+		return fmt.Errorf("response body doesn't contain '%s'", e.BodyContains)
+	}
+
+	if e.JSONPath == "" && e.JSONFragment == "" {
+		return nil
+	}
+
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return fmt.Errorf("response body isn't JSON: %v", err)
+	}
+
+	if e.JSONFragment != "" {
+		var fragment any
+		if err := json.Unmarshal([]byte(e.JSONFragment), &fragment); err != nil {
+			return fmt.Errorf("invalid expected JSON fragment: %v", err)
+		}
+		if !matchesFragment(document, fragment) {
+			return fmt.Errorf("response body doesn't match '%s'", e.JSONFragment)
+		}
+	}
+
+	if e.JSONPath == "" {
+		return nil
+	}
+
+	value := document
+	for _, name := range strings.Split(e.JSONPath, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			field, found := node[name]
+			if !found {
+				return fmt.Errorf("response body has no field at '%s'", e.JSONPath)
+			}
+			value = field
+		case []any:
+			index, err := strconv.Atoi(name)
+			if err != nil || index < 0 || index >= len(node) {
+				return fmt.Errorf("response body has no field at '%s'", e.JSONPath)
+			}
+			value = node[index]
+		default:
+			return fmt.Errorf("response body has no field at '%s'", e.JSONPath)
+		}
+	}
+
+	if e.JSONValue == "" {
+		return nil
+	}
+	actual, ok := value.(string)
+	if !ok {
+		encoded, _ := json.Marshal(value)
+		actual = string(encoded)
+	}
+	if actual != e.JSONValue {
+		return fmt.Errorf("response body field at '%s' is '%s' instead of '%s'", e.JSONPath, actual, e.JSONValue)
+	}
+
+	return nil
+}
+
+// matchesFragment tells whether the decoded JSON value matches the decoded fragment, i.e. has all of its fields when
+// both are objects
+func matchesFragment(value any, fragment any) bool {
+	fragmentObject, ok := fragment.(map[string]any)
+	if !ok {
+		return reflect.DeepEqual(value, fragment)
+	}
+
+	object, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+	for name, fragmentField := range fragmentObject {
+		field, found := object[name]
+		if !found || !matchesFragment(field, fragmentField) {
+			return false
+		}
+	}
+	return true
+}
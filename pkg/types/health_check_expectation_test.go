@@ -0,0 +1,52 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheckExpectationVerify(t *testing.T) {
+	body := []byte(`{"status":"UP","checks":[{"name":"database","status":"DOWN"}],"connected":true,"workers":3}`)
+
+	tests := []struct {
+		name        string
+		expectation HealthCheckExpectation
+		expectError bool
+	}{
+		{"no expectation", HealthCheckExpectation{}, false},
+		{"body contains", HealthCheckExpectation{BodyContains: `"status":"UP"`}, false},
+		{"body doesn't contain", HealthCheckExpectation{BodyContains: "pong"}, true},
+		{"string field", HealthCheckExpectation{JSONPath: "status", JSONValue: "UP"}, false},
+		{"array element field", HealthCheckExpectation{JSONPath: "checks.0.status", JSONValue: "UP"}, true},
+		{"boolean field", HealthCheckExpectation{JSONPath: "connected", JSONValue: "true"}, false},
+		{"number field", HealthCheckExpectation{JSONPath: "workers", JSONValue: "3"}, false},
+		{"field present", HealthCheckExpectation{JSONPath: "checks.0.name"}, false},
+		{"field missing", HealthCheckExpectation{JSONPath: "checks.1.name"}, true},
+		{"path through value", HealthCheckExpectation{JSONPath: "status.code"}, true},
+		{"fragment", HealthCheckExpectation{JSONFragment: `{"status":"UP","connected":true}`}, false},
+		{"nested fragment", HealthCheckExpectation{JSONFragment: `{"checks":[{"name":"database","status":"DOWN"}]}`}, false},
+		{"fragment mismatch", HealthCheckExpectation{JSONFragment: `{"status":"DOWN"}`}, true},
+		{"fragment missing field", HealthCheckExpectation{JSONFragment: `{"version":"3.1"}`}, true},
+		{"partial array fragment", HealthCheckExpectation{JSONFragment: `{"checks":[{"name":"database"}]}`}, true},
+		{"invalid fragment", HealthCheckExpectation{JSONFragment: `{"status"`}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.expectation.Verify(body)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Error(t, HealthCheckExpectation{JSONPath: "status"}.Verify([]byte("pong")), "Expected body which isn't JSON to fail")
+}
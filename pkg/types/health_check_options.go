@@ -0,0 +1,71 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// HealthCheckOptions are the options of an HTTP health check, for check routes which require authentication or don't
+// respond with 200 OK when healthy. The zero value calls the check route with GET and expects 200 OK.
+type HealthCheckOptions struct {
+	// Method is the HTTP method of the health check requests, GET or HEAD. GET is used if left empty
+	Method string `json:"method,omitempty"`
+	// Headers are added to the health check requests, i.e. an Authorization header with a bearer token
+	Headers map[string]string `json:"headers,omitempty"`
+	// StatusCodes are the status codes of the healthy responses. Only 200 OK if left empty
+	StatusCodes []int `json:"statusCodes,omitempty"`
+	// HealthCheckExpectation is the content the healthy responses must have. Not verified for HEAD responses, which
+	// have no content
+	HealthCheckExpectation
+}
+
+// IsZero tells whether the default options apply
+func (o HealthCheckOptions) IsZero() bool {
+	return o.Method == "" && len(o.Headers) == 0 && len(o.StatusCodes) == 0 && o.HealthCheckExpectation.IsZero()
+}
+
+// GetMethod returns the Method, or GET if not set
+func (o HealthCheckOptions) GetMethod() string {
+	if o.Method == "" {
+		return http.MethodGet
+	}
+
+	return o.Method
+}
+
+// Validate returns an error if the method isn't GET or HEAD, a status code is invalid, or content is expected from
+// HEAD responses
+func (o HealthCheckOptions) Validate() error {
+	switch o.GetMethod() {
+	case http.MethodGet:
+	case http.MethodHead:
+		if !o.HealthCheckExpectation.IsZero() {
+			return fmt.Errorf("invalid health check options: HEAD responses have no content to verify")
+		}
+	default:
+		return fmt.Errorf("invalid health check options: unsupported method '%s', expected GET or HEAD", o.Method)
+	}
+
+	for _, statusCode := range o.StatusCodes {
+		if statusCode < 100 || statusCode > 599 {
+			return fmt.Errorf("invalid health check options: invalid status code %d", statusCode)
+		}
+	}
+
+	return nil
+}
+
+// IsHealthyStatusCode tells whether a response with the given status code is healthy
+func (o HealthCheckOptions) IsHealthyStatusCode(statusCode int) bool {
+	if len(o.StatusCodes) == 0 {
+		return statusCode == http.StatusOK
+	}
+
+	return slices.Contains(o.StatusCodes, statusCode)
+}
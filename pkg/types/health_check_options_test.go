@@ -0,0 +1,48 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheckOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     HealthCheckOptions
+		expectError bool
+	}{
+		{"default", HealthCheckOptions{}, false},
+		{"get with expectation", HealthCheckOptions{Method: http.MethodGet, HealthCheckExpectation: HealthCheckExpectation{BodyContains: "UP"}}, false},
+		{"head", HealthCheckOptions{Method: http.MethodHead, StatusCodes: []int{204}}, false},
+		{"head with expectation", HealthCheckOptions{Method: http.MethodHead, HealthCheckExpectation: HealthCheckExpectation{BodyContains: "UP"}}, true},
+		{"unsupported method", HealthCheckOptions{Method: http.MethodPost}, true},
+		{"invalid status code", HealthCheckOptions{StatusCodes: []int{200, 42}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.options.Validate()
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHealthCheckOptionsIsHealthyStatusCode(t *testing.T) {
+	assert.True(t, HealthCheckOptions{}.IsHealthyStatusCode(http.StatusOK))
+	assert.False(t, HealthCheckOptions{}.IsHealthyStatusCode(http.StatusNoContent))
+
+	options := HealthCheckOptions{StatusCodes: []int{http.StatusNoContent, http.StatusTooManyRequests}}
+	assert.True(t, options.IsHealthyStatusCode(http.StatusTooManyRequests))
+	assert.False(t, options.IsHealthyStatusCode(http.StatusOK))
+}
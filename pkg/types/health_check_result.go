@@ -0,0 +1,14 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// HealthCheckResult is the outcome of an on-demand health check of a service
+type HealthCheckResult struct {
+	// Healthy indicates whether all the health checks of the service passed
+	Healthy bool
+	// Output describes the outcome of the health checks, i.e. why they failed
+	Output string
+}
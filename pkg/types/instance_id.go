@@ -0,0 +1,129 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// InstanceIdGenerator generates the ServiceInstanceId of the current service with GenerateInstanceId, once per
+// registry client. The generated ID must be unique among all the services registered, not only the instances of the
+// same service key, as Consul requires it, which the generators below ensure by prefixing it with the ServiceKey.
+type InstanceIdGenerator interface {
+	GenerateInstanceId(config Config) (string, error)
+}
+
+// InstanceIdGeneratorFunc is an InstanceIdGenerator implemented by a function
+type InstanceIdGeneratorFunc func(config Config) (string, error)
+
+// GenerateInstanceId calls f
+func (f InstanceIdGeneratorFunc) GenerateInstanceId(config Config) (string, error) {
+	return f(config)
+}
+
+// HostPortInstanceIds returns an InstanceIdGenerator suffixing the ServiceKey with the ServiceHost and ServicePort,
+// i.e. core-data-10.0.0.7:59880. This is the default generator, the ID being the same across restarts.
+func HostPortInstanceIds() InstanceIdGenerator {
+	return InstanceIdGeneratorFunc(func(config Config) (string, error) {
+		return hostPortInstanceId(config), nil
+	})
+}
+
+// UUIDv7InstanceIds returns an InstanceIdGenerator suffixing the ServiceKey with a new time-ordered UUID, i.e.
+// core-data-01920f6e-8d5c-7b3a-9f1e-2c4d5e6f7a8b. The ID changes on every restart, so the registrations of the
+// previous runs of the service are to be removed by the Registry, i.e. with DeregisterCriticalAfter.
+func UUIDv7InstanceIds() InstanceIdGenerator {
+	return InstanceIdGeneratorFunc(func(config Config) (string, error) {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return "", fmt.Errorf("unable to generate instance ID: %v", err)
+		}
+		return config.ServiceKey + "-" + id.String(), nil
+	})
+}
+
+// MACInstanceIds returns an InstanceIdGenerator suffixing the ServiceKey with the MAC address of the given network
+// interface, i.e. core-data-00163e5a1b2c for eth0, tying the ID to the hardware the service runs on. The first
+// interface up with a MAC address, other than loopback, is used if the interface name is empty.
+func MACInstanceIds(interfaceName string) InstanceIdGenerator {
+	return InstanceIdGeneratorFunc(func(config Config) (string, error) {
+		address, err := hardwareAddress(interfaceName)
+		if err != nil {
+			return "", fmt.Errorf("unable to generate instance ID: %v", err)
+		}
+		return config.ServiceKey + "-" + hex.EncodeToString(address), nil
+	})
+}
+
+// FileInstanceIds returns an InstanceIdGenerator suffixing the ServiceKey with the ID provisioned by the operator in
+// the given file, i.e. the asset tag of the gateway, leading and trailing white space trimmed
+func FileInstanceIds(path string) InstanceIdGenerator {
+	return InstanceIdGeneratorFunc(func(config Config) (string, error) {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to generate instance ID: %v", err)
+		}
+		id := string(bytes.TrimSpace(contents))
+		if id == "" {
+			return "", fmt.Errorf("unable to generate instance ID: %s is empty", path)
+		}
+		return config.ServiceKey + "-" + id, nil
+	})
+}
+
+// WithInstanceId returns a copy of the config with the ServiceInstanceId generated by the InstanceIdGenerator with
+// GenerateInstanceId, unless set or not registering, so the ID is generated once
+func (config Config) WithInstanceId() (Config, error) {
+	if !config.GenerateInstanceId || config.ServiceInstanceId != "" || config.ServiceHost == "" || config.InstanceIdGenerator == nil {
+		return config, nil
+	}
+
+	id, err := config.InstanceIdGenerator.GenerateInstanceId(config)
+	if err != nil {
+		return config, err
+	}
+	config.ServiceInstanceId = id
+	return config, nil
+}
+
+func hostPortInstanceId(config Config) string {
+	return config.ServiceKey + "-" + net.JoinHostPort(config.ServiceHost, strconv.Itoa(config.ServicePort))
+}
+
+// hardwareAddress returns the MAC address of the named network interface, or of the first one up other than loopback
+func hardwareAddress(interfaceName string) (net.HardwareAddr, error) {
+	if interfaceName != "" {
+		networkInterface, err := net.InterfaceByName(interfaceName)
+		if err != nil {
+			return nil, err
+		}
+		if len(networkInterface.HardwareAddr) == 0 {
+			return nil, fmt.Errorf("network interface %s has no MAC address", interfaceName)
+		}
+		return networkInterface.HardwareAddr, nil
+	}
+
+	networkInterfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, networkInterface := range networkInterfaces {
+		if networkInterface.Flags&net.FlagUp != 0 && networkInterface.Flags&net.FlagLoopback == 0 && len(networkInterface.HardwareAddr) > 0 {
+			return networkInterface.HardwareAddr, nil
+		}
+		names = append(names, networkInterface.Name)
+	}
+	return nil, fmt.Errorf("none of the network interfaces %s has a MAC address", strings.Join(names, ", "))
+}
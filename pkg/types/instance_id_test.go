@@ -0,0 +1,77 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithInstanceId(t *testing.T) {
+	config := Config{ServiceKey: "core-data", ServiceHost: "10.0.0.7", ServicePort: 59880, GenerateInstanceId: true}
+	generatorCalls := 0
+	config.InstanceIdGenerator = InstanceIdGeneratorFunc(func(config Config) (string, error) {
+		generatorCalls++
+		return config.ServiceKey + "-gateway-7", nil
+	})
+
+	generated, err := config.WithInstanceId()
+	require.NoError(t, err)
+	assert.Equal(t, "core-data-gateway-7", generated.GetServiceInstanceId())
+
+	// The ID is generated once, then set
+	_, err = generated.WithInstanceId()
+	require.NoError(t, err)
+	assert.Equal(t, 1, generatorCalls)
+
+	config.InstanceIdGenerator = nil
+	generated, err = config.WithInstanceId()
+	require.NoError(t, err)
+	assert.Equal(t, "core-data-10.0.0.7:59880", generated.GetServiceInstanceId(), "Expected the host and port to be used by default")
+}
+
+func TestHostPortInstanceIds(t *testing.T) {
+	config := Config{ServiceKey: "core-data", ServiceHost: "10.0.0.7", ServicePort: 59880}
+	id, err := HostPortInstanceIds().GenerateInstanceId(config)
+	require.NoError(t, err)
+	assert.Equal(t, "core-data-10.0.0.7:59880", id)
+}
+
+func TestUUIDv7InstanceIds(t *testing.T) {
+	id, err := UUIDv7InstanceIds().GenerateInstanceId(Config{ServiceKey: "core-data"})
+	require.NoError(t, err)
+
+	generated, err := uuid.Parse(strings.TrimPrefix(id, "core-data-"))
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), generated.Version())
+}
+
+func TestMACInstanceIds(t *testing.T) {
+	_, err := MACInstanceIds("no-such-interface").GenerateInstanceId(Config{ServiceKey: "core-data"})
+	assert.Error(t, err)
+}
+
+func TestFileInstanceIds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asset-tag")
+	require.NoError(t, os.WriteFile(path, []byte("GW-0042\n"), 0600))
+
+	id, err := FileInstanceIds(path).GenerateInstanceId(Config{ServiceKey: "core-data"})
+	require.NoError(t, err)
+	assert.Equal(t, "core-data-GW-0042", id)
+
+	require.NoError(t, os.WriteFile(path, []byte("\n"), 0600))
+	_, err = FileInstanceIds(path).GenerateInstanceId(Config{ServiceKey: "core-data"})
+	assert.Error(t, err, "Expected empty instance ID file error")
+
+	_, err = FileInstanceIds(filepath.Join(t.TempDir(), "missing")).GenerateInstanceId(Config{ServiceKey: "core-data"})
+	assert.Error(t, err)
+}
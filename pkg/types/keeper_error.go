@@ -0,0 +1,47 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// KeeperError is the error returned when Core Keeper responds to a request with a non 2xx status code, which callers
+// can retrieve with errors.As to handle i.e. 404, 409 and 503 differently, and to correlate the failure with the
+// Keeper logs. It is also ErrNotRegistered for 404, ErrUnauthorized for 401 and 403 and ErrRegistryUnavailable for
+// 502, 503 and 504 for errors.Is.
+type KeeperError struct {
+	// StatusCode is the status code of the response
+	StatusCode int
+	// ErrorCode is the EdgeX error kind of the status code, i.e. NotFound or StatusConflict, as Keeper doesn't send
+	// error codes of its own
+	ErrorCode string
+	// RequestId is the request ID reported in the response body, if any
+	RequestId string
+	// CorrelationId is the X-Correlation-ID the request was sent with, which Keeper logs along with the failure
+	CorrelationId string
+	// Message is the error message of the response body, or the raw body if it isn't a Keeper response
+	Message string
+}
+
+func (e *KeeperError) Error() string {
+	return fmt.Sprintf("request failed, status code: %d, error code: %s, correlation ID: %s, err: %s", e.StatusCode, e.ErrorCode, e.CorrelationId, e.Message)
+}
+
+// Is reports whether the error is of the failure mode target, i.e. ErrNotRegistered
+func (e *KeeperError) Is(target error) bool {
+	switch target {
+	case ErrNotRegistered:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRegistryUnavailable:
+		return e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusServiceUnavailable || e.StatusCode == http.StatusGatewayTimeout
+	default:
+		return false
+	}
+}
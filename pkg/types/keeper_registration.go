@@ -0,0 +1,89 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	dtoCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
+)
+
+// KeeperRegistrationRequest is the request registering a service with Keeper, the AddRegistrationRequest of Keeper
+// extended with the deregistration delay of the service. Keeper versions not knowing of it ignore it.
+type KeeperRegistrationRequest struct {
+	dtoCommon.BaseRequest `json:",inline"`
+	Registration          KeeperRegistration `json:"registration"`
+}
+
+// KeeperRegistration is the Registration of Keeper extended with the deregistration delay of the service. Keeper has no
+// field for the metadata, tags and HTTP health check options of the services, which aren't registered with it.
+type KeeperRegistration struct {
+	dtos.Registration
+	// DeregisterCriticalAfter is how long the service may be DOWN before Keeper removes its registration, if set
+	DeregisterCriticalAfter string `json:"deregisterCriticalAfter,omitempty"`
+	// Extensions are additional fields sent along with the registration fields, i.e. organization-specific ones set by
+	// the RegistrationMutator. They must not collide with the fields of the registration. Not decoded from responses
+	Extensions map[string]any `json:"-"`
+}
+
+// MarshalJSON encodes the registration with its Extensions as additional fields
+func (r KeeperRegistration) MarshalJSON() ([]byte, error) {
+	// registration has the fields of KeeperRegistration but not its methods, so it is encoded as usual
+	type registration KeeperRegistration
+	encoded, err := json.Marshal(registration(r))
+	if err != nil || len(r.Extensions) == 0 {
+		return encoded, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range r.Extensions {
+		if _, found := fields[name]; found {
+			return nil, fmt.Errorf("extension field %s collides with the registration field", name)
+		}
+		if fields[name], err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("unable to encode extension field %s: %w", name, err)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// CreatedAt returns when Keeper first stored the registration, zero if it didn't report it
+func (r KeeperRegistration) CreatedAt() time.Time {
+	return millisecondsTime(r.Created)
+}
+
+// ModifiedAt returns when Keeper last updated the registration, i.e. its status following a health check, zero if it
+// didn't report it
+func (r KeeperRegistration) ModifiedAt() time.Time {
+	return millisecondsTime(r.Modified)
+}
+
+// millisecondsTime converts the milliseconds since the epoch of the Keeper timestamps, zero being left unset
+func millisecondsTime(milliseconds int64) time.Time {
+	if milliseconds == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(milliseconds)
+}
+
+// KeeperRegistrationResponse is the RegistrationResponse of Keeper with the extended registration of the service
+type KeeperRegistrationResponse struct {
+	dtoCommon.BaseResponse `json:",inline"`
+	Registration           KeeperRegistration `json:"registration"`
+}
+
+// KeeperMultiRegistrationsResponse is the MultiRegistrationsResponse of Keeper with the extended registrations of the
+// services
+type KeeperMultiRegistrationsResponse struct {
+	dtoCommon.BaseWithTotalCountResponse `json:",inline"`
+	Registrations                        []KeeperRegistration `json:"registrations"`
+}
@@ -0,0 +1,53 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+)
+
+func TestKeeperRegistrationExtensions(t *testing.T) {
+	registration := KeeperRegistration{Registration: dtos.Registration{ServiceId: "core-data", Host: "edgex-core-data", Port: 59880}}
+	plain, err := json.Marshal(registration)
+	require.NoError(t, err)
+
+	registration.Extensions = map[string]any{"site": "plant-7", "owner": map[string]string{"team": "ot"}}
+	encoded, err := json.Marshal(registration)
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	assert.Equal(t, "core-data", fields["serviceId"])
+	assert.Equal(t, "plant-7", fields["site"])
+	assert.Equal(t, map[string]any{"team": "ot"}, fields["owner"])
+
+	var decoded KeeperRegistration
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, "edgex-core-data", decoded.Host)
+	assert.Nil(t, decoded.Extensions)
+
+	registration.Extensions = map[string]any{"host": "elsewhere"}
+	_, err = json.Marshal(registration)
+	require.Error(t, err, "Expected extension colliding with a registration field to fail")
+
+	registration.Extensions = nil
+	unchanged, err := json.Marshal(registration)
+	require.NoError(t, err)
+	assert.Equal(t, plain, unchanged)
+}
+
+func TestKeeperRegistrationTimestamps(t *testing.T) {
+	registration := KeeperRegistration{Registration: dtos.Registration{DBTimestamp: dtos.DBTimestamp{Created: 1712000000000}}}
+	assert.Equal(t, time.UnixMilli(1712000000000), registration.CreatedAt())
+	assert.True(t, registration.ModifiedAt().IsZero(), "Expected unreported timestamps to be zero")
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// EndpointMetadataKeyPrefix prefixes the name of each named endpoint of a service in the metadata key it is registered
+// as, i.e. endpoint-opcua
+const EndpointMetadataKeyPrefix = "endpoint-"
+
+// NamedEndpoint is an endpoint a service registers by name next to its main one, i.e. the OPC UA port of a device
+// service next to its REST port
+type NamedEndpoint struct {
+	// Host is the hostname or IP address of the endpoint, the host of the service being used if left empty
+	Host string
+	// Port is the port of the endpoint
+	Port int
+	// Scheme is the scheme the endpoint is called with, i.e. opc.tcp. May be left empty
+	Scheme string
+}
+
+// String returns the endpoint as registered in the metadata of the service, i.e. opc.tcp://:4840
+func (e NamedEndpoint) String() string {
+	address := net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+	if e.Scheme == "" {
+		return address
+	}
+	return e.Scheme + "://" + address
+}
+
+// parseNamedEndpoint parses the endpoint as registered in the metadata of a service, found false if malformed
+func parseNamedEndpoint(value string) (NamedEndpoint, bool) {
+	var endpoint NamedEndpoint
+	if scheme, address, found := strings.Cut(value, "://"); found {
+		endpoint.Scheme = scheme
+		value = address
+	}
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return NamedEndpoint{}, false
+	}
+	endpoint.Host = host
+	if endpoint.Port, err = strconv.Atoi(port); err != nil {
+		return NamedEndpoint{}, false
+	}
+	return endpoint, true
+}
+
+// NamedEndpoint returns the endpoint the service registered with the given name, i.e. opcua, as a copy of its main
+// endpoint with the host, port and scheme of the named one. Found is false when the service registered none with that
+// name, or a malformed one.
+func (e ServiceEndpoint) NamedEndpoint(name string) (ServiceEndpoint, bool) {
+	value, ok := e.Metadata[EndpointMetadataKeyPrefix+name]
+	if !ok {
+		return ServiceEndpoint{}, false
+	}
+	named, ok := parseNamedEndpoint(value)
+	if !ok {
+		return ServiceEndpoint{}, false
+	}
+
+	endpoint := e
+	if named.Host != "" {
+		endpoint.Host = named.Host
+	}
+	endpoint.Port = named.Port
+	endpoint.Scheme = named.Scheme
+	return endpoint, true
+}
@@ -0,0 +1,49 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamedEndpoint(t *testing.T) {
+	config := Config{
+		ServiceKey:  "device-opcua",
+		ServiceHost: "10.0.0.3",
+		ServicePort: 59997,
+		ServiceNamedEndpoints: map[string]NamedEndpoint{
+			"opcua":   {Port: 4840, Scheme: "opc.tcp"},
+			"metrics": {Host: "fe80::1", Port: 9100},
+		},
+	}
+	metadata := config.GetServiceMetadata()
+	assert.Equal(t, map[string]string{
+		EndpointMetadataKeyPrefix + "opcua":   "opc.tcp://:4840",
+		EndpointMetadataKeyPrefix + "metrics": "[fe80::1]:9100",
+	}, metadata)
+
+	endpoint := ServiceEndpoint{ServiceId: config.ServiceKey, Host: config.ServiceHost, Port: config.ServicePort, Metadata: metadata}
+	opcua, found := endpoint.NamedEndpoint("opcua")
+	assert.True(t, found)
+	assert.Equal(t, "10.0.0.3", opcua.Host, "Expected the host of the service to be used")
+	assert.Equal(t, 4840, opcua.Port)
+	assert.Equal(t, "opc.tcp", opcua.Scheme)
+	assert.Equal(t, config.ServiceKey, opcua.ServiceId)
+
+	metrics, found := endpoint.NamedEndpoint("metrics")
+	assert.True(t, found)
+	assert.Equal(t, "fe80::1", metrics.Host)
+	assert.Equal(t, 9100, metrics.Port)
+	assert.Empty(t, metrics.Scheme)
+
+	_, found = endpoint.NamedEndpoint("modbus")
+	assert.False(t, found)
+	endpoint.Metadata = map[string]string{EndpointMetadataKeyPrefix + "opcua": "opc.tcp://4840"}
+	_, found = endpoint.NamedEndpoint("opcua")
+	assert.False(t, found, "Expected malformed endpoints not to be found")
+}
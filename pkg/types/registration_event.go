@@ -0,0 +1,23 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// RegistrationEventType identifies the kind of change observed on a service registration
+type RegistrationEventType string
+
+const (
+	// RegistrationModified indicates the registration now differs from what the service registered
+	RegistrationModified RegistrationEventType = "Modified"
+	// RegistrationDeleted indicates the registration has been removed or de-registered
+	RegistrationDeleted RegistrationEventType = "Deleted"
+)
+
+// RegistrationEvent describes a change to a service registration detected by watching the Registry
+type RegistrationEvent struct {
+	Type RegistrationEventType
+	// Endpoint is the registration as currently found in the Registry. It is empty when the registration was deleted.
+	Endpoint ServiceEndpoint
+}
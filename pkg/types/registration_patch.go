@@ -0,0 +1,112 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	dtoCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/common"
+)
+
+// RegistrationPatch are the fields of an existing registration to update, the others being left as registered. The
+// nil fields are left unchanged.
+type RegistrationPatch struct {
+	Host          *string
+	Port          *int
+	CheckInterval *string
+	CheckRoute    *string
+}
+
+// IsZero tells whether the patch doesn't update any field
+func (p RegistrationPatch) IsZero() bool {
+	return p.Host == nil && p.Port == nil && p.CheckInterval == nil && p.CheckRoute == nil
+}
+
+// Validate checks that the patch updates at least one field and that the fields updated are valid, i.e. a positive
+// check interval
+func (p RegistrationPatch) Validate() error {
+	if p.IsZero() {
+		return fmt.Errorf("the registration patch doesn't update any field")
+	}
+	if p.Host != nil && *p.Host == "" {
+		return fmt.Errorf("invalid registration patch: empty host")
+	}
+	if p.Port != nil && (*p.Port <= 0 || *p.Port > 65535) {
+		return fmt.Errorf("invalid registration patch: port %d out of range", *p.Port)
+	}
+	if p.CheckInterval != nil {
+		if interval, err := time.ParseDuration(*p.CheckInterval); err != nil || interval <= 0 {
+			return fmt.Errorf("invalid registration patch: invalid check interval '%s'", *p.CheckInterval)
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the registration with the fields of the patch updated
+func (p RegistrationPatch) Apply(registration KeeperRegistration) KeeperRegistration {
+	if p.Host != nil {
+		registration.Host = *p.Host
+	}
+	if p.Port != nil {
+		registration.Port = *p.Port
+	}
+	if p.CheckInterval != nil {
+		registration.HealthCheck.Interval = *p.CheckInterval
+	}
+	if p.CheckRoute != nil {
+		registration.HealthCheck.Path = *p.CheckRoute
+	}
+	return registration
+}
+
+// KeeperRegistrationPatchRequest is the request updating only some fields of the registration of a service with
+// Keeper, the fields left out being left as registered
+type KeeperRegistrationPatchRequest struct {
+	dtoCommon.BaseRequest `json:",inline"`
+	Registration          KeeperRegistrationPatch `json:"registration"`
+}
+
+// KeeperRegistrationPatch are the fields of the registration of the service to update, the nil ones being left out
+type KeeperRegistrationPatch struct {
+	ServiceId   string                  `json:"serviceId"`
+	Host        *string                 `json:"host,omitempty"`
+	Port        *int                    `json:"port,omitempty"`
+	HealthCheck *KeeperHealthCheckPatch `json:"healthCheck,omitempty"`
+}
+
+// KeeperHealthCheckPatch are the fields of the health check of the registration to update
+type KeeperHealthCheckPatch struct {
+	Interval *string `json:"interval,omitempty"`
+	Path     *string `json:"path,omitempty"`
+}
+
+// NewKeeperRegistrationPatchRequest builds the request updating the fields of the patch in the registration of the
+// given service
+func NewKeeperRegistrationPatchRequest(serviceKey string, patch RegistrationPatch) KeeperRegistrationPatchRequest {
+	registration := KeeperRegistrationPatch{ServiceId: serviceKey, Host: patch.Host, Port: patch.Port}
+	if patch.CheckInterval != nil || patch.CheckRoute != nil {
+		registration.HealthCheck = &KeeperHealthCheckPatch{Interval: patch.CheckInterval, Path: patch.CheckRoute}
+	}
+
+	return KeeperRegistrationPatchRequest{
+		BaseRequest: dtoCommon.BaseRequest{
+			Versionable: dtoCommon.Versionable{ApiVersion: common.ApiVersion},
+		},
+		Registration: registration,
+	}
+}
+
+// Apply returns a copy of the registration with the fields of the patch updated, as Keeper does
+func (p KeeperRegistrationPatch) Apply(registration KeeperRegistration) KeeperRegistration {
+	patch := RegistrationPatch{Host: p.Host, Port: p.Port}
+	if p.HealthCheck != nil {
+		patch.CheckInterval = p.HealthCheck.Interval
+		patch.CheckRoute = p.HealthCheck.Path
+	}
+	return patch.Apply(registration)
+}
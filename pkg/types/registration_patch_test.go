@@ -0,0 +1,81 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+)
+
+func TestRegistrationPatchValidate(t *testing.T) {
+	host := "10.0.0.7"
+	emptyHost := ""
+	port := 59880
+	invalidPort := 0
+	interval := "15s"
+	invalidInterval := "soon"
+	route := "/api/v3/health"
+
+	tests := []struct {
+		name        string
+		patch       RegistrationPatch
+		expectedErr bool
+	}{
+		{"valid", RegistrationPatch{Host: &host, Port: &port, CheckInterval: &interval}, false},
+		{"route only", RegistrationPatch{CheckRoute: &route}, false},
+		{"no field", RegistrationPatch{}, true},
+		{"empty host", RegistrationPatch{Host: &emptyHost}, true},
+		{"invalid port", RegistrationPatch{Port: &invalidPort}, true},
+		{"invalid interval", RegistrationPatch{CheckInterval: &invalidInterval}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.patch.Validate()
+			assert.Equal(t, test.expectedErr, err != nil, "Unexpected validation error: %v", err)
+		})
+	}
+}
+
+func TestRegistrationPatchApply(t *testing.T) {
+	registration := KeeperRegistration{
+		Registration: dtos.Registration{
+			ServiceId:   "core-data",
+			Host:        "10.0.0.7",
+			Port:        59880,
+			HealthCheck: dtos.HealthCheck{Interval: "10s", Path: "/api/v3/ping", Type: CheckTypeHTTP},
+		},
+	}
+	interval := "30s"
+
+	patched := RegistrationPatch{CheckInterval: &interval}.Apply(registration)
+	assert.Equal(t, "30s", patched.HealthCheck.Interval)
+	assert.Equal(t, "/api/v3/ping", patched.HealthCheck.Path)
+	assert.Equal(t, 59880, patched.Port)
+	assert.Equal(t, "10s", registration.HealthCheck.Interval, "Expected the registration not to be modified")
+}
+
+func TestKeeperRegistrationPatchRequest(t *testing.T) {
+	interval := "30s"
+	request := NewKeeperRegistrationPatchRequest("core-data", RegistrationPatch{CheckInterval: &interval})
+
+	encoded, err := json.Marshal(request.Registration)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"serviceId":"core-data","healthCheck":{"interval":"30s"}}`, string(encoded))
+
+	var decoded KeeperRegistrationPatchRequest
+	require.NoError(t, json.Unmarshal(encoded, &decoded.Registration))
+	patched := decoded.Registration.Apply(KeeperRegistration{
+		Registration: dtos.Registration{Host: "10.0.0.7", HealthCheck: dtos.HealthCheck{Interval: "10s", Path: "/api/v3/ping"}},
+	})
+	assert.Equal(t, "30s", patched.HealthCheck.Interval)
+	assert.Equal(t, "/api/v3/ping", patched.HealthCheck.Path)
+	assert.Equal(t, "10.0.0.7", patched.Host)
+}
@@ -0,0 +1,50 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "fmt"
+
+// RegistrationTemplate defines registration settings shared by a fleet of services, which each service references by
+// name from its Config instead of duplicating them
+type RegistrationTemplate struct {
+	// CheckType is the type of health check performed on the services, i.e. http or none
+	CheckType string
+	// CheckRoute is the health check callback route of the services
+	CheckRoute string
+	// CheckInterval is the health check callback interval of the services
+	CheckInterval string
+	// ProbeBeforeRegister indicates whether services are probed once before registering
+	ProbeBeforeRegister bool
+}
+
+// WithTemplate returns a copy of the config with the settings of the referenced registration template applied to the
+// check settings left empty or unset, so service specific settings take precedence over the template
+func (config Config) WithTemplate() (Config, error) {
+	if config.Template == "" {
+		return config, nil
+	}
+
+	template, ok := config.RegistrationTemplates[config.Template]
+	if !ok {
+		return config, fmt.Errorf("unknown registration template '%s'", config.Template)
+	}
+
+	if config.CheckType == "" {
+		config.CheckType = template.CheckType
+	}
+	if config.CheckRoute == "" {
+		config.CheckRoute = template.CheckRoute
+	}
+	if config.CheckInterval == "" {
+		config.CheckInterval = template.CheckInterval
+	}
+	if config.ProbeBeforeRegister == nil {
+		probeBeforeRegister := template.ProbeBeforeRegister
+		config.ProbeBeforeRegister = &probeBeforeRegister
+	}
+
+	return config, nil
+}
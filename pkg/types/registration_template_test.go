@@ -0,0 +1,43 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTemplate(t *testing.T) {
+	templates := map[string]RegistrationTemplate{
+		"device-service": {
+			CheckType:           CheckTypeHTTP,
+			CheckRoute:          "/api/v3/ping",
+			CheckInterval:       "10s",
+			ProbeBeforeRegister: true,
+		},
+	}
+
+	config, err := Config{RegistrationTemplates: templates}.WithTemplate()
+	require.NoError(t, err)
+	assert.Empty(t, config.CheckRoute, "Expected no template to apply when none is referenced")
+
+	config, err = Config{Template: "device-service", RegistrationTemplates: templates, CheckInterval: "30s"}.WithTemplate()
+	require.NoError(t, err)
+	assert.Equal(t, CheckTypeHTTP, config.CheckType)
+	assert.Equal(t, "/api/v3/ping", config.CheckRoute)
+	assert.Equal(t, "30s", config.CheckInterval, "Expected service setting to take precedence over the template")
+	assert.True(t, config.GetProbeBeforeRegister())
+
+	probeBeforeRegister := false
+	config, err = Config{Template: "device-service", RegistrationTemplates: templates, ProbeBeforeRegister: &probeBeforeRegister}.WithTemplate()
+	require.NoError(t, err)
+	assert.False(t, config.GetProbeBeforeRegister(), "Expected service setting to take precedence over the template")
+
+	_, err = Config{Template: "bogus", RegistrationTemplates: templates}.WithTemplate()
+	require.Error(t, err)
+}
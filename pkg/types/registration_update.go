@@ -0,0 +1,70 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RegistrationFieldChange is a field of a registration which changed when it was replaced
+type RegistrationFieldChange struct {
+	// Field is the name of the field, i.e. host, port, checkType, checkRoute or checkInterval
+	Field string
+	// Old is the value replaced
+	Old string
+	// New is the value registered instead
+	New string
+}
+
+// RegistrationUpdate describes a registration replaced by Register over an existing one, to tell why registrations
+// churn
+type RegistrationUpdate struct {
+	// ServiceKey is the key of the service whose registration was replaced
+	ServiceKey string
+	// Changes are the fields which changed, none if the registration was replaced as is
+	Changes []RegistrationFieldChange
+}
+
+// String describes the changes, i.e. "host changed from 10.0.0.1 to 10.0.0.2"
+func (u RegistrationUpdate) String() string {
+	if len(u.Changes) == 0 {
+		return "no change"
+	}
+
+	descriptions := make([]string, 0, len(u.Changes))
+	for _, change := range u.Changes {
+		descriptions = append(descriptions, fmt.Sprintf("%s changed from '%s' to '%s'", change.Field, change.Old, change.New))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// DiffRegistrations returns the update from the old to the new registration of the given service, with the fields of
+// the endpoint and of the health check which changed. The status isn't compared, as it is left to the Registry.
+func DiffRegistrations(serviceKey string, old RegistrationFields, new RegistrationFields) RegistrationUpdate {
+	update := RegistrationUpdate{ServiceKey: serviceKey}
+	compare := func(field string, oldValue string, newValue string) {
+		if oldValue != newValue {
+			update.Changes = append(update.Changes, RegistrationFieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	compare("host", old.Host, new.Host)
+	compare("port", fmt.Sprint(old.Port), fmt.Sprint(new.Port))
+	compare("checkType", old.CheckType, new.CheckType)
+	compare("checkRoute", old.CheckRoute, new.CheckRoute)
+	compare("checkInterval", old.CheckInterval, new.CheckInterval)
+	return update
+}
+
+// RegistrationFields are the fields of a registration compared by DiffRegistrations
+type RegistrationFields struct {
+	Host          string
+	Port          int
+	CheckType     string
+	CheckRoute    string
+	CheckInterval string
+}
@@ -0,0 +1,31 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRegistrations(t *testing.T) {
+	old := RegistrationFields{Host: "10.0.0.1", Port: 59880, CheckType: CheckTypeHTTP, CheckRoute: "/api/v3/ping", CheckInterval: "10s"}
+
+	update := DiffRegistrations("core-data", old, old)
+	assert.Equal(t, "core-data", update.ServiceKey)
+	assert.Empty(t, update.Changes)
+	assert.Equal(t, "no change", update.String())
+
+	updated := old
+	updated.Host = "10.0.0.2"
+	updated.CheckInterval = "5s"
+	update = DiffRegistrations("core-data", old, updated)
+	assert.Equal(t, []RegistrationFieldChange{
+		{Field: "host", Old: "10.0.0.1", New: "10.0.0.2"},
+		{Field: "checkInterval", Old: "10s", New: "5s"},
+	}, update.Changes)
+	assert.Equal(t, "host changed from '10.0.0.1' to '10.0.0.2', checkInterval changed from '10s' to '5s'", update.String())
+}
@@ -0,0 +1,56 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"context"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/interfaces"
+)
+
+type requestAuthKey struct{}
+
+// requestAuth is the authentication data overriding the client-wide one for the requests made with a context
+type requestAuth struct {
+	accessToken  string
+	authInjector interfaces.AuthenticationInjector
+}
+
+// WithAccessToken returns a copy of ctx carrying the access token to authenticate the registry requests made with it,
+// overriding the authentication data configured for the client. Consul sends it as the ACL token of the request,
+// Keeper as a bearer token. An empty access token keeps the authentication data of ctx, or of the client, with all the
+// registry types, so callers may pass on the token of the request they serve whether it has one or not.
+func WithAccessToken(ctx context.Context, accessToken string) context.Context {
+	if accessToken == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestAuthKey{}, requestAuth{accessToken: accessToken})
+}
+
+// WithAuthInjector returns a copy of ctx carrying the AuthenticationInjector to authenticate the registry requests made
+// with it, overriding the one configured for the client. Only supported by Keeper, which ignores the RoundTripper of
+// the injector since the connection to the registry is shared by all requests.
+func WithAuthInjector(ctx context.Context, authInjector interfaces.AuthenticationInjector) context.Context {
+	return context.WithValue(ctx, requestAuthKey{}, requestAuth{authInjector: authInjector})
+}
+
+// AccessTokenFromContext returns the access token set on ctx by WithAccessToken, if any
+func AccessTokenFromContext(ctx context.Context) (string, bool) {
+	auth, ok := ctx.Value(requestAuthKey{}).(requestAuth)
+	if !ok || auth.authInjector != nil {
+		return "", false
+	}
+	return auth.accessToken, true
+}
+
+// AuthInjectorFromContext returns the AuthenticationInjector set on ctx by WithAuthInjector, if any
+func AuthInjectorFromContext(ctx context.Context) (interfaces.AuthenticationInjector, bool) {
+	auth, ok := ctx.Value(requestAuthKey{}).(requestAuth)
+	if !ok || auth.authInjector == nil {
+		return nil, false
+	}
+	return auth.authInjector, true
+}
@@ -0,0 +1,27 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAccessToken(t *testing.T) {
+	ctx := WithAccessToken(context.Background(), "token")
+	accessToken, ok := AccessTokenFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "token", accessToken)
+
+	accessToken, ok = AccessTokenFromContext(WithAccessToken(ctx, ""))
+	assert.True(t, ok, "Expected an empty access token to keep the one of the context")
+	assert.Equal(t, "token", accessToken)
+
+	_, ok = AccessTokenFromContext(WithAccessToken(context.Background(), ""))
+	assert.False(t, ok, "Expected an empty access token to keep the authentication of the client")
+}
@@ -0,0 +1,43 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"errors"
+	"time"
+)
+
+// ServiceAvailability is the outcome of the availability check of a service, so callers can tell whether to retry
+// without parsing error messages
+type ServiceAvailability struct {
+	// Available indicates whether the service is registered and healthy
+	Available bool
+	// Status is the health status of the service: StatusUp when available, StatusDown when unhealthy, StatusHalt when
+	// de-registered, or else StatusUnknown, i.e. when it isn't registered
+	Status Status
+	// Reason is why the service isn't available, as reported by the Registry. Empty when it is available
+	Reason string
+	// LastChecked is when the availability of the service was checked
+	LastChecked time.Time
+	// Err is why the service isn't available, i.e. ErrNotRegistered or ErrUnhealthy. Nil when it is available
+	Err error
+}
+
+// NewServiceAvailability returns the availability of a service checked right now with IsServiceAvailable, given its
+// results, the Status being the one the error stands for
+func NewServiceAvailability(available bool, err error) ServiceAvailability {
+	availability := ServiceAvailability{Available: available && err == nil, Status: StatusUnknown, LastChecked: time.Now(), Err: err}
+	switch {
+	case availability.Available:
+		availability.Status = StatusUp
+	case errors.Is(err, ErrUnhealthy):
+		availability.Status = StatusDown
+	}
+	if err != nil {
+		availability.Reason = err.Error()
+	}
+	return availability
+}
@@ -0,0 +1,29 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServiceAvailability(t *testing.T) {
+	available := NewServiceAvailability(true, nil)
+	assert.True(t, available.Available)
+	assert.Equal(t, StatusUp, available.Status)
+	assert.Empty(t, available.Reason)
+	assert.False(t, available.LastChecked.IsZero())
+
+	unhealthy := NewServiceAvailability(false, Errorf(ErrUnhealthy, "core-data service not healthy"))
+	assert.False(t, unhealthy.Available)
+	assert.Equal(t, StatusDown, unhealthy.Status)
+	assert.Contains(t, unhealthy.Reason, "core-data service not healthy")
+
+	notRegistered := NewServiceAvailability(false, Errorf(ErrNotRegistered, "core-data service is not registered"))
+	assert.Equal(t, StatusUnknown, notRegistered.Status)
+	assert.ErrorIs(t, notRegistered.Err, ErrNotRegistered)
+}
@@ -16,9 +16,104 @@
 
 package types
 
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// SchemeMetadataKey is the metadata key of the scheme the service is called with, i.e. https, registered from the
+// ServiceProtocol
+const SchemeMetadataKey = "scheme"
+
+// MaxHostLength is the length of the longest host a service endpoint may have, the maximum length of a DNS name
+const MaxHostLength = 253
+
 // ServiceEndpoint defines the service information returned by GetServiceEndpoint() need to connect to the target service
 type ServiceEndpoint struct {
 	ServiceId string
-	Host      string
-	Port      int
+	// InstanceId identifies the instance of the service among those registered with the same ServiceId, as returned by
+	// GetServiceEndpoints. Empty for the registry types registering a single instance per service key
+	InstanceId string
+	Host       string
+	Port       int
+	// Scheme is the scheme the service is called with, i.e. http or https, as registered in its SchemeMetadataKey
+	// metadata. Empty if the service didn't declare it or the registry type doesn't store metadata, http being assumed
+	Scheme string
+	// Metadata are the key/value pairs the service registered with, i.e. its version or region, to choose between
+	// services. Empty if the service registered none or the registry type doesn't store them
+	Metadata map[string]string
+	// Tags are the tags the service registered with, i.e. its capabilities. Empty if the service registered none or
+	// the registry type doesn't store them
+	Tags []string
+}
+
+// IsZero tells whether the endpoint is empty, as sent by WatchService when the service isn't registered
+func (e ServiceEndpoint) IsZero() bool {
+	return e.Equal(ServiceEndpoint{})
+}
+
+// Equal tells whether both endpoints have the same address, metadata and tags, the tags being in the same order
+func (e ServiceEndpoint) Equal(other ServiceEndpoint) bool {
+	return e.ServiceId == other.ServiceId && e.InstanceId == other.InstanceId && e.Host == other.Host && e.Port == other.Port &&
+		e.Scheme == other.Scheme && maps.Equal(e.Metadata, other.Metadata) && slices.Equal(e.Tags, other.Tags)
+}
+
+// Validate checks the endpoint can be connected to, failing with ErrMalformedResponse when the Registry returned a port
+// out of range or a host longer than MaxHostLength, i.e. a registration left corrupted by a misbehaving client
+func (e ServiceEndpoint) Validate() error {
+	if e.Port <= 0 || e.Port > 65535 {
+		return Errorf(ErrMalformedResponse, "invalid port %d for service %s", e.Port, e.ServiceId)
+	}
+	if len(e.Host) > MaxHostLength {
+		return Errorf(ErrMalformedResponse, "host of service %s longer than %d characters", e.ServiceId, MaxHostLength)
+	}
+	return nil
+}
+
+// HasTag tells whether the service registered with the given tag
+func (e ServiceEndpoint) HasTag(tag string) bool {
+	return slices.Contains(e.Tags, tag)
+}
+
+// EndpointOrder compares two service endpoints, returning a negative number when a sorts before b, a positive number
+// when a sorts after b and zero when they are equal
+type EndpointOrder func(a ServiceEndpoint, b ServiceEndpoint) int
+
+// ByServiceId orders service endpoints by service id, then by instance id, then by host and port. This is the default
+// order of the discovered service endpoints.
+func ByServiceId(a ServiceEndpoint, b ServiceEndpoint) int {
+	if c := cmp.Compare(a.ServiceId, b.ServiceId); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.InstanceId, b.InstanceId); c != 0 {
+		return c
+	}
+	return compareAddress(a, b)
+}
+
+// ByAddress orders service endpoints by host and port, then by service id and instance id
+func ByAddress(a ServiceEndpoint, b ServiceEndpoint) int {
+	if c := compareAddress(a, b); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.ServiceId, b.ServiceId); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.InstanceId, b.InstanceId)
+}
+
+// SortServiceEndpoints sorts the service endpoints in the given order, or ByServiceId if nil
+func SortServiceEndpoints(endpoints []ServiceEndpoint, order EndpointOrder) {
+	if order == nil {
+		order = ByServiceId
+	}
+	slices.SortStableFunc(endpoints, order)
+}
+
+func compareAddress(a ServiceEndpoint, b ServiceEndpoint) int {
+	if c := cmp.Compare(a.Host, b.Host); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Port, b.Port)
 }
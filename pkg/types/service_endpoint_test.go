@@ -0,0 +1,113 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortServiceEndpoints(t *testing.T) {
+	a := ServiceEndpoint{ServiceId: "core-data", Host: "10.0.0.2", Port: 59880}
+	b := ServiceEndpoint{ServiceId: "core-data", Host: "10.0.0.1", Port: 59880}
+	c := ServiceEndpoint{ServiceId: "core-command", Host: "10.0.0.3", Port: 59882}
+
+	endpoints := []ServiceEndpoint{a, b, c}
+	SortServiceEndpoints(endpoints, nil)
+	assert.Equal(t, []ServiceEndpoint{c, b, a}, endpoints)
+
+	SortServiceEndpoints(endpoints, ByAddress)
+	assert.Equal(t, []ServiceEndpoint{b, a, c}, endpoints)
+
+	// The instances of a service are ordered by instance id rather than by address
+	first := ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-1", Host: "10.0.0.9", Port: 59880}
+	second := ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-2", Host: "10.0.0.1", Port: 59880}
+	endpoints = []ServiceEndpoint{second, first}
+	SortServiceEndpoints(endpoints, nil)
+	assert.Equal(t, []ServiceEndpoint{first, second}, endpoints)
+}
+
+func TestServiceEndpointEqual(t *testing.T) {
+	endpoint := ServiceEndpoint{
+		ServiceId: "device-modbus",
+		Host:      "10.0.0.1",
+		Port:      59901,
+		Metadata:  map[string]string{"version": "3.1", "region": "eu"},
+		Tags:      []string{"modbus-tcp"},
+	}
+
+	assert.True(t, endpoint.Equal(endpoint))
+	assert.True(t, endpoint.HasTag("modbus-tcp"))
+	assert.False(t, endpoint.HasTag("modbus-rtu"))
+	assert.False(t, endpoint.IsZero())
+	assert.True(t, ServiceEndpoint{Metadata: map[string]string{}, Tags: []string{}}.IsZero(), "Expected empty metadata and tags to be ignored")
+
+	upgraded := endpoint
+	upgraded.Metadata = map[string]string{"version": "3.2", "region": "eu"}
+	assert.False(t, endpoint.Equal(upgraded))
+
+	retagged := endpoint
+	retagged.Tags = append([]string{"modbus-rtu"}, endpoint.Tags...)
+	assert.False(t, endpoint.Equal(retagged))
+
+	secured := endpoint
+	secured.Scheme = "https"
+	assert.False(t, endpoint.Equal(secured))
+}
+
+func TestServiceProtocolMetadata(t *testing.T) {
+	config := Config{ServiceMetadata: map[string]string{"version": "3.1"}}
+	assert.Equal(t, config.ServiceMetadata, config.GetServiceMetadata(), "Expected no scheme registered unless declared")
+
+	config.ServiceProtocol = "https"
+	assert.Equal(t, map[string]string{"version": "3.1", SchemeMetadataKey: "https"}, config.GetServiceMetadata())
+	assert.Equal(t, map[string]string{"version": "3.1"}, config.ServiceMetadata, "Expected the ServiceMetadata to be left as is")
+}
+
+func TestServiceEndpointValidate(t *testing.T) {
+	endpoint := ServiceEndpoint{ServiceId: "core-data", Host: "10.0.0.1", Port: 59880}
+	assert.NoError(t, endpoint.Validate())
+
+	for _, port := range []int{0, -1, 65536} {
+		invalid := endpoint
+		invalid.Port = port
+		assert.ErrorIs(t, invalid.Validate(), ErrMalformedResponse, "Expected port %d to be invalid", port)
+	}
+
+	invalid := endpoint
+	invalid.Host = strings.Repeat("a", MaxHostLength+1)
+	assert.ErrorIs(t, invalid.Validate(), ErrMalformedResponse)
+}
+
+func TestGetServiceInstanceId(t *testing.T) {
+	config := Config{ServiceKey: "core-data", ServiceHost: "10.0.0.7", ServicePort: 59880}
+	assert.Equal(t, "core-data", config.GetServiceInstanceId())
+
+	config.GenerateInstanceId = true
+	assert.Equal(t, "core-data-10.0.0.7:59880", config.GetServiceInstanceId())
+
+	config.ServiceInstanceId = "core-data-1"
+	assert.Equal(t, "core-data-1", config.GetServiceInstanceId())
+}
+
+func TestGetFallbackEndpoints(t *testing.T) {
+	config := Config{FallbackEndpoints: map[string]string{"core-data": "10.1.2.3:59880", "core-command": "[fd00::7]:59882"}}
+	endpoints, err := config.GetFallbackEndpoints()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ServiceEndpoint{
+		"core-data":    {ServiceId: "core-data", Host: "10.1.2.3", Port: 59880},
+		"core-command": {ServiceId: "core-command", Host: "fd00::7", Port: 59882},
+	}, endpoints)
+
+	for _, address := range []string{"10.1.2.3", "10.1.2.3:data", "10.1.2.3:0", "10.1.2.3:65536"} {
+		config.FallbackEndpoints = map[string]string{"core-data": address}
+		_, err = config.GetFallbackEndpoints()
+		assert.Error(t, err, address)
+	}
+}
@@ -0,0 +1,114 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// WithServiceHost returns a copy of the config with the ServiceHost detected with DetectServiceHost, when left empty or
+// set to localhost or a loopback address, so the services in other containers can reach the current one. Without
+// DetectServiceHost, a loopback ServiceHost is kept as is, a warning being logged as only the services on the same
+// host can reach it.
+func (config Config) WithServiceHost() (Config, error) {
+	loopback := isLoopbackHost(config.ServiceHost)
+	if !config.DetectServiceHost {
+		if loopback {
+			config.GetLoggingClient().Warnf("Registering %s with the loopback service host %s, which the services on "+
+				"other hosts or containers can't reach: set DetectServiceHost to register a reachable address",
+				config.ServiceKey, config.ServiceHost)
+		}
+		return config, nil
+	}
+	if config.ServiceHost != "" && !loopback {
+		return config, nil
+	}
+
+	address, err := serviceAddress(config)
+	if err != nil {
+		return config, fmt.Errorf("unable to detect service host: %w", err)
+	}
+	config.ServiceHost = address.String()
+	return config, nil
+}
+
+// isLoopbackHost tells whether the host is localhost, whatever its case, or a loopback address
+func isLoopbackHost(host string) bool {
+	return strings.EqualFold(host, "localhost") || net.ParseIP(host).IsLoopback()
+}
+
+// serviceAddress returns the address of the ServiceInterface if set, otherwise of the interface routing to the
+// registry Host, otherwise of the first interface up, other than loopback
+func serviceAddress(config Config) (net.IP, error) {
+	if config.ServiceInterface != "" {
+		networkInterface, err := net.InterfaceByName(config.ServiceInterface)
+		if err != nil {
+			return nil, err
+		}
+		address, found := interfaceAddress(*networkInterface)
+		if !found {
+			return nil, fmt.Errorf("network interface %s has no address other than loopback", config.ServiceInterface)
+		}
+		return address, nil
+	}
+
+	if config.Host != "" {
+		if address, err := outboundAddress(net.JoinHostPort(config.Host, strconv.Itoa(config.Port))); err == nil && !address.IsLoopback() {
+			return address, nil
+		}
+	}
+
+	networkInterfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, networkInterface := range networkInterfaces {
+		if networkInterface.Flags&net.FlagUp == 0 || networkInterface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if address, found := interfaceAddress(networkInterface); found {
+			return address, nil
+		}
+	}
+	return nil, errors.New("no network interface up has an address other than loopback")
+}
+
+// outboundAddress returns the local address of the route to the target, without sending any packet
+func outboundAddress(target string) (net.IP, error) {
+	conn, err := net.Dial("udp", target)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// interfaceAddress returns the first IPv4 address of the network interface, otherwise its first global IPv6 address,
+// found false if it has neither
+func interfaceAddress(networkInterface net.Interface) (net.IP, bool) {
+	addresses, err := networkInterface.Addrs()
+	if err != nil {
+		return nil, false
+	}
+	var global net.IP
+	for _, address := range addresses {
+		network, ok := address.(*net.IPNet)
+		if !ok || network.IP.IsLoopback() {
+			continue
+		}
+		if network.IP.To4() != nil {
+			return network.IP, true
+		}
+		if global == nil && network.IP.IsGlobalUnicast() {
+			global = network.IP
+		}
+	}
+	return global, global != nil
+}
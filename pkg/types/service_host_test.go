@@ -0,0 +1,77 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	loggerMocks "github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger/mocks"
+)
+
+func TestWithServiceHost(t *testing.T) {
+	config := Config{Host: "127.0.0.1", Port: 8500, ServiceKey: "core-data"}
+	detected, err := config.WithServiceHost()
+	require.NoError(t, err)
+	assert.Empty(t, detected.ServiceHost, "Expected an empty host to be detected only with DetectServiceHost")
+
+	for _, host := range []string{"LocalHost", "127.0.0.2", "::1"} {
+		lc := &loggerMocks.LoggingClient{}
+		lc.On("Warnf", mock.Anything, "core-data", host).Once()
+		config.ServiceHost = host
+		config.LoggingClient = lc
+		detected, err = config.WithServiceHost()
+		require.NoError(t, err)
+		assert.Equal(t, host, detected.ServiceHost, "Expected a loopback host to be kept without DetectServiceHost")
+		lc.AssertExpectations(t)
+	}
+	config.LoggingClient = nil
+
+	config.DetectServiceHost = true
+	config.ServiceHost = "10.0.0.7"
+	detected, err = config.WithServiceHost()
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.7", detected.ServiceHost, "Expected the host set to be kept")
+
+	config.ServiceHost = "localhost"
+	detected, err = config.WithServiceHost()
+	if err != nil {
+		t.Skipf("No network interface other than loopback to detect the host from: %v", err)
+	}
+	ip := net.ParseIP(detected.ServiceHost)
+	require.NotNil(t, ip)
+	assert.False(t, ip.IsLoopback(), "Expected the loopback address of the registry not to be registered")
+}
+
+func TestWithServiceHostInterface(t *testing.T) {
+	config := Config{ServiceKey: "core-data", DetectServiceHost: true, ServiceInterface: "lo"}
+	_, err := config.WithServiceHost()
+	require.Error(t, err, "Expected the loopback interface not to be registered")
+
+	config.ServiceInterface = "no-such-interface"
+	_, err = config.WithServiceHost()
+	require.Error(t, err)
+}
+
+func TestIsLoopbackHost(t *testing.T) {
+	for host, expected := range map[string]bool{
+		"localhost":       true,
+		"LOCALHOST":       true,
+		"127.0.0.1":       true,
+		"127.1.2.3":       true,
+		"::1":             true,
+		"":                false,
+		"10.0.0.7":        false,
+		"edgex-core-data": false,
+		"localhost.lan":   false,
+	} {
+		assert.Equal(t, expected, isLoopbackHost(host), host)
+	}
+}
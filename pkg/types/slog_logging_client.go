@@ -0,0 +1,122 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+)
+
+// The attribute keys of the records logged to the Logger, matching the attributes of the spans of the TracingClient
+const (
+	// RegistryTypeLogKey is the registry type, i.e. keeper
+	RegistryTypeLogKey = "registry.type"
+	// ServiceKeyLogKey is the key of the current service
+	ServiceKeyLogKey = "registry.service_key"
+)
+
+// LevelTrace is the slog level the TRACE messages are logged at, below slog.LevelDebug as slog has no trace level
+const LevelTrace = slog.LevelDebug - 4
+
+var slogLevels = map[string]slog.Level{
+	models.TraceLog: LevelTrace,
+	models.DebugLog: slog.LevelDebug,
+	models.InfoLog:  slog.LevelInfo,
+	models.WarnLog:  slog.LevelWarn,
+	models.ErrorLog: slog.LevelError,
+}
+
+// slogLoggingClient is the LoggingClient logging to a slog.Logger. The key-value pairs of the messages logged with
+// the non formatting methods are passed as slog attributes.
+type slogLoggingClient struct {
+	logger   *slog.Logger
+	logLevel *atomic.Value
+}
+
+// NewSlogLoggingClient returns a LoggingClient logging to the given slog.Logger, for programs using the standard
+// library logger rather than the EdgeX one. All levels are passed to the Logger, whose handler filters them, until
+// SetLogLevel is called.
+func NewSlogLoggingClient(l *slog.Logger) logger.LoggingClient {
+	logLevel := &atomic.Value{}
+	logLevel.Store(models.TraceLog)
+	return slogLoggingClient{logger: l, logLevel: logLevel}
+}
+
+func (lc slogLoggingClient) SetLogLevel(logLevel string) errors.EdgeX {
+	if _, ok := slogLevels[logLevel]; !ok {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("invalid log level `%s`", logLevel), nil)
+	}
+
+	lc.logLevel.Store(logLevel)
+	return nil
+}
+
+func (lc slogLoggingClient) LogLevel() string {
+	return lc.logLevel.Load().(string)
+}
+
+func (lc slogLoggingClient) Debug(msg string, args ...interface{}) {
+	lc.log(slog.LevelDebug, msg, args...)
+}
+
+func (lc slogLoggingClient) Error(msg string, args ...interface{}) {
+	lc.log(slog.LevelError, msg, args...)
+}
+
+func (lc slogLoggingClient) Info(msg string, args ...interface{}) {
+	lc.log(slog.LevelInfo, msg, args...)
+}
+
+func (lc slogLoggingClient) Trace(msg string, args ...interface{}) {
+	lc.log(LevelTrace, msg, args...)
+}
+
+func (lc slogLoggingClient) Warn(msg string, args ...interface{}) {
+	lc.log(slog.LevelWarn, msg, args...)
+}
+
+func (lc slogLoggingClient) Debugf(msg string, args ...interface{}) {
+	lc.logf(slog.LevelDebug, msg, args...)
+}
+
+func (lc slogLoggingClient) Errorf(msg string, args ...interface{}) {
+	lc.logf(slog.LevelError, msg, args...)
+}
+
+func (lc slogLoggingClient) Infof(msg string, args ...interface{}) {
+	lc.logf(slog.LevelInfo, msg, args...)
+}
+
+func (lc slogLoggingClient) Tracef(msg string, args ...interface{}) {
+	lc.logf(LevelTrace, msg, args...)
+}
+
+func (lc slogLoggingClient) Warnf(msg string, args ...interface{}) {
+	lc.logf(slog.LevelWarn, msg, args...)
+}
+
+func (lc slogLoggingClient) log(level slog.Level, msg string, args ...interface{}) {
+	if lc.enabled(level) {
+		lc.logger.Log(context.Background(), level, msg, args...)
+	}
+}
+
+func (lc slogLoggingClient) logf(level slog.Level, msg string, args ...interface{}) {
+	// Formatting is skipped for the levels not logged
+	if lc.enabled(level) {
+		lc.logger.Log(context.Background(), level, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (lc slogLoggingClient) enabled(level slog.Level) bool {
+	return level >= slogLevels[lc.LogLevel()] && lc.logger.Enabled(context.Background(), level)
+}
@@ -0,0 +1,72 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
+)
+
+func TestSlogLoggingClient(t *testing.T) {
+	var output bytes.Buffer
+	handler := slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: LevelTrace})
+	config := Config{Type: "keeper", ServiceKey: "core-data", Logger: slog.New(handler)}
+
+	lc := config.GetLoggingClient()
+	lc.Tracef("Sending %s request %s", "Keeper", "/api/v3/ping")
+	lc.Info("registered", "host", "localhost", "port", 59880)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var trace map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &trace))
+	assert.Equal(t, "DEBUG-4", trace["level"])
+	assert.Equal(t, "Sending Keeper request /api/v3/ping", trace["msg"])
+	assert.Equal(t, "keeper", trace[RegistryTypeLogKey])
+	assert.Equal(t, "core-data", trace[ServiceKeyLogKey])
+
+	var info map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &info))
+	assert.Equal(t, "INFO", info["level"])
+	assert.Equal(t, "localhost", info["host"])
+	assert.Equal(t, float64(59880), info["port"])
+}
+
+func TestSlogLoggingClientLogLevel(t *testing.T) {
+	var output bytes.Buffer
+	lc := NewSlogLoggingClient(slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: LevelTrace})))
+	assert.Equal(t, models.TraceLog, lc.LogLevel())
+
+	require.NoError(t, lc.SetLogLevel(models.WarnLog))
+	assert.Equal(t, models.WarnLog, lc.LogLevel())
+	lc.Debugf("dropped")
+	lc.Infof("dropped")
+	lc.Warnf("kept")
+	lc.Error("kept")
+	assert.Equal(t, 2, strings.Count(output.String(), "kept"))
+	assert.NotContains(t, output.String(), "dropped")
+
+	assert.Error(t, lc.SetLogLevel("VERBOSE"))
+	assert.Equal(t, models.WarnLog, lc.LogLevel())
+}
+
+func TestGetLoggingClientPrecedence(t *testing.T) {
+	var output bytes.Buffer
+	edgexLogger := NewSlogLoggingClient(slog.New(slog.NewTextHandler(&output, nil)))
+	config := Config{LoggingClient: edgexLogger, Logger: slog.New(slog.NewTextHandler(&output, nil))}
+	assert.Equal(t, edgexLogger, config.GetLoggingClient(), "Expected LoggingClient to take precedence over Logger")
+
+	assert.NotNil(t, Config{}.GetLoggingClient(), "Expected a LoggingClient logging nothing without logger")
+}
@@ -0,0 +1,52 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "strings"
+
+// Status is the health status of a registered service, with the same meaning whichever registry backend reports it
+type Status string
+
+const (
+	// StatusUnknown is the status of a service whose health hasn't been checked yet or isn't recognized
+	StatusUnknown Status = "UNKNOWN"
+	// StatusUp is the status of a service passing its health check
+	StatusUp Status = "UP"
+	// StatusDown is the status of a service failing its health check
+	StatusDown Status = "DOWN"
+	// StatusHalt is the status of a service which has been de-registered but whose registration hasn't been removed
+	StatusHalt Status = "HALT"
+	// StatusMaintenance is the status of a service deliberately taken out of service, i.e. while being decommissioned
+	StatusMaintenance Status = "MAINTENANCE"
+)
+
+// ParseStatus parses the status reported by a registry backend, regardless of case. Besides the Status values, the
+// Consul health check statuses are recognized: passing is StatusUp, while warning and critical are StatusDown.
+// Unrecognized statuses are StatusUnknown.
+func ParseStatus(status string) Status {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case string(StatusUp), "PASSING":
+		return StatusUp
+	case string(StatusDown), "WARNING", "CRITICAL":
+		return StatusDown
+	case string(StatusHalt):
+		return StatusHalt
+	case string(StatusMaintenance):
+		return StatusMaintenance
+	default:
+		return StatusUnknown
+	}
+}
+
+// IsUp tells whether the service is registered and healthy
+func (status Status) IsUp() bool {
+	return status == StatusUp
+}
+
+// IsHalted tells whether the service has been de-registered
+func (status Status) IsHalted() bool {
+	return status == StatusHalt
+}
@@ -0,0 +1,42 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected Status
+	}{
+		{"UP", StatusUp},
+		{"up", StatusUp},
+		{"passing", StatusUp},
+		{"DOWN", StatusDown},
+		{"warning", StatusDown},
+		{"critical", StatusDown},
+		{"Halt", StatusHalt},
+		{"maintenance", StatusMaintenance},
+		{"UNKNOWN", StatusUnknown},
+		{"", StatusUnknown},
+		{"bogus", StatusUnknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.status, func(t *testing.T) {
+			assert.Equal(t, test.expected, ParseStatus(test.status))
+		})
+	}
+
+	assert.True(t, StatusUp.IsUp())
+	assert.False(t, StatusMaintenance.IsUp())
+	assert.True(t, StatusHalt.IsHalted())
+	assert.False(t, StatusDown.IsHalted())
+}
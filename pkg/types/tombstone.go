@@ -0,0 +1,15 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "time"
+
+// Tombstone marks a service as permanently retired in the Registry, so it's treated as not registered by the
+// registry.DecommissionClient of every service, even when a retired instance still running registers itself again
+type Tombstone struct {
+	ServiceKey     string    `json:"serviceKey"`
+	Decommissioned time.Time `json:"decommissioned"`
+}
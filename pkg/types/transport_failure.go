@@ -0,0 +1,87 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"syscall"
+)
+
+// TransportFailure is the kind of failure of a request which didn't reach the Registry, as the kinds differ in how
+// likely a retry is to succeed: a name which doesn't resolve won't resolve any better a few milliseconds later, while a
+// refused connection often succeeds once the restarting Registry listens again
+type TransportFailure string
+
+const (
+	// TransportFailureDNSNotFound is the Registry host name not resolving, i.e. NXDOMAIN
+	TransportFailureDNSNotFound TransportFailure = "dns_not_found"
+	// TransportFailureDNS is the resolution of the Registry host name failing otherwise, i.e. no DNS server answered
+	TransportFailureDNS TransportFailure = "dns"
+	// TransportFailureConnectionRefused is the Registry host refusing the connection, i.e. while the Registry restarts
+	TransportFailureConnectionRefused TransportFailure = "connection_refused"
+	// TransportFailureTLS is the TLS handshake failing, i.e. a certificate not verified
+	TransportFailureTLS TransportFailure = "tls"
+	// TransportFailureTimeout is the connection or request timing out
+	TransportFailureTimeout TransportFailure = "timeout"
+	// TransportFailureCircuitOpen is the request not sent as the circuit breaker of the client is open
+	TransportFailureCircuitOpen TransportFailure = "circuit_open"
+	// TransportFailureOther is any other failure to reach the Registry, i.e. the connection being reset
+	TransportFailureOther TransportFailure = "other"
+)
+
+// ErrCircuitOpen is returned, as ErrRegistryUnavailable, for the requests failed fast without being sent while the
+// circuit breaker of the client is open, the Registry having failed CircuitBreakerThreshold consecutive requests
+var ErrCircuitOpen = errors.New("registry circuit breaker is open")
+
+// ClassifyTransportFailure returns the kind of transport failure err is, or an empty TransportFailure if err isn't a
+// failure to reach the Registry, i.e. a 5xx response or a cancelled request
+func ClassifyTransportFailure(err error) TransportFailure {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return ""
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return TransportFailureCircuitOpen
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return TransportFailureDNSNotFound
+		}
+		return TransportFailureDNS
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return TransportFailureConnectionRefused
+	}
+
+	var recordHeaderErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verificationErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var certificateInvalidErr x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &recordHeaderErr) || errors.As(err, &alertErr) || errors.As(err, &verificationErr) ||
+		errors.As(err, &unknownAuthorityErr) || errors.As(err, &certificateInvalidErr) || errors.As(err, &hostnameErr) {
+		return TransportFailureTLS
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return TransportFailureTimeout
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) {
+		return TransportFailureOther
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyTransportFailure(t *testing.T) {
+	requestErr := func(err error) error {
+		return Errorf(ErrRegistryUnavailable, "%w", &url.Error{Op: "Get", URL: "http://edgex-core-keeper:59890/api/v3/ping", Err: err})
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected TransportFailure
+	}{
+		{"nil", nil, ""},
+		{"not a transport failure", errors.New("request failed, status code: 503"), ""},
+		{"cancelled", requestErr(context.Canceled), ""},
+		{"DNS not found", requestErr(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}), TransportFailureDNSNotFound},
+		{"DNS timeout", requestErr(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}), TransportFailureDNS},
+		{"connection refused", requestErr(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), TransportFailureConnectionRefused},
+		{"TLS unknown authority", requestErr(&tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}), TransportFailureTLS},
+		{"TLS record header", requestErr(tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}), TransportFailureTLS},
+		{"timeout", requestErr(fmt.Errorf("net/http: request canceled: %w", context.DeadlineExceeded)), TransportFailureTimeout},
+		{"connection reset", requestErr(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), TransportFailureOther},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ClassifyTransportFailure(test.err))
+		})
+	}
+}
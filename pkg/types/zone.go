@@ -0,0 +1,69 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"cmp"
+	"slices"
+)
+
+const (
+	// ZoneMetadataKey is the metadata key of the zone of a service, i.e. the redundant plant network it is reached on
+	ZoneMetadataKey = "zone"
+	// AnyZone in a failover order stands for all the zones not listed, including services without zone
+	AnyZone = "*"
+)
+
+// Zone returns the zone the service registered in, empty if none
+func (e ServiceEndpoint) Zone() string {
+	return e.Metadata[ZoneMetadataKey]
+}
+
+// zoneRank returns the position of the zone of the endpoint in the failover order, the zones not listed being ranked
+// as AnyZone, and found false if AnyZone isn't listed either
+func zoneRank(endpoint ServiceEndpoint, failoverOrder []string) (rank int, found bool) {
+	if zone := endpoint.Zone(); zone != "" {
+		if rank = slices.Index(failoverOrder, zone); rank >= 0 {
+			return rank, true
+		}
+	}
+	if rank = slices.Index(failoverOrder, AnyZone); rank >= 0 {
+		return rank, true
+	}
+	return len(failoverOrder), false
+}
+
+// ByZone orders service endpoints by the position of their zone in the failover order, i.e. zone-a, zone-b then
+// AnyZone, then by service id, host and port. The endpoints in zones not listed come at the position of AnyZone, or
+// last if it isn't listed.
+func ByZone(failoverOrder ...string) EndpointOrder {
+	return func(a ServiceEndpoint, b ServiceEndpoint) int {
+		rankA, _ := zoneRank(a, failoverOrder)
+		rankB, _ := zoneRank(b, failoverOrder)
+		if c := cmp.Compare(rankA, rankB); c != 0 {
+			return c
+		}
+		return ByServiceId(a, b)
+	}
+}
+
+// SelectByZone returns the endpoint to use out of the redundant endpoints given, the first one in the first zone of
+// the failover order having any, in ByServiceId order within the zone. The endpoints in zones not listed are only
+// selected if AnyZone is listed. It returns false if none can be selected.
+func SelectByZone(endpoints []ServiceEndpoint, failoverOrder []string) (ServiceEndpoint, bool) {
+	var selected ServiceEndpoint
+	selectedRank := -1
+	for _, endpoint := range endpoints {
+		rank, found := zoneRank(endpoint, failoverOrder)
+		if !found {
+			continue
+		}
+		if selectedRank < 0 || rank < selectedRank || (rank == selectedRank && ByServiceId(endpoint, selected) < 0) {
+			selected, selectedRank = endpoint, rank
+		}
+	}
+	return selected, selectedRank >= 0
+}
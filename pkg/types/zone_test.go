@@ -0,0 +1,49 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZoneFailover(t *testing.T) {
+	zoneA := ServiceEndpoint{ServiceId: "core-data-a", Metadata: map[string]string{ZoneMetadataKey: "zone-a"}}
+	zoneB := ServiceEndpoint{ServiceId: "core-data-b", Metadata: map[string]string{ZoneMetadataKey: "zone-b"}}
+	zoneC := ServiceEndpoint{ServiceId: "core-data-c", Metadata: map[string]string{ZoneMetadataKey: "zone-c"}}
+	noZone := ServiceEndpoint{ServiceId: "core-data"}
+
+	endpoints := []ServiceEndpoint{noZone, zoneC, zoneB, zoneA}
+	SortServiceEndpoints(endpoints, ByZone("zone-b", "zone-a"))
+	assert.Equal(t, []ServiceEndpoint{zoneB, zoneA, noZone, zoneC}, endpoints)
+	SortServiceEndpoints(endpoints, ByZone("zone-a", AnyZone, "zone-b"))
+	assert.Equal(t, []ServiceEndpoint{zoneA, noZone, zoneC, zoneB}, endpoints)
+
+	failoverOrder := []string{"zone-a", "zone-b", AnyZone}
+	selected, found := SelectByZone([]ServiceEndpoint{zoneC, zoneB, zoneA}, failoverOrder)
+	assert.True(t, found)
+	assert.Equal(t, zoneA, selected)
+	selected, _ = SelectByZone([]ServiceEndpoint{zoneC, zoneB}, failoverOrder)
+	assert.Equal(t, zoneB, selected, "Expected failover to zone-b")
+	selected, _ = SelectByZone([]ServiceEndpoint{zoneC, noZone}, failoverOrder)
+	assert.Equal(t, noZone, selected, "Expected failover to any zone, in service id order")
+
+	_, found = SelectByZone([]ServiceEndpoint{zoneC, noZone}, []string{"zone-a", "zone-b"})
+	assert.False(t, found, "Expected no failover to zones not listed")
+}
+
+func TestConfigZone(t *testing.T) {
+	config := Config{ServiceMetadata: map[string]string{"version": "3.1"}}
+	assert.Equal(t, config.ServiceMetadata, config.GetServiceMetadata())
+	assert.Nil(t, config.GetEndpointOrder())
+
+	config.ServiceZone = "zone-a"
+	config.ZoneFailoverOrder = []string{"zone-b"}
+	assert.Equal(t, map[string]string{"version": "3.1", ZoneMetadataKey: "zone-a"}, config.GetServiceMetadata())
+	assert.Equal(t, map[string]string{"version": "3.1"}, config.ServiceMetadata, "Expected the ServiceMetadata to be left as is")
+	assert.NotNil(t, config.GetEndpointOrder())
+}
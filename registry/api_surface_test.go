@@ -0,0 +1,156 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateAPI = flag.Bool("update", false, "record the current exported API surface in testdata/api_surface.txt")
+
+const apiFile = "testdata/api_surface.txt"
+
+// deprecatedMarker is appended to the API lines of the methods and fields documented as deprecated, so deprecating
+// them is recorded before they can be removed
+const deprecatedMarker = " // Deprecated"
+
+// TestAPISurface fails whenever the Client interface, or the fields and methods of Config and ServiceEndpoint, change
+// from the snapshot of their declarations recorded in testdata/api_surface.txt, as the downstream services break on
+// any of them changing. It only compares the text of the declarations: every difference is reported, compatible or
+// not, and the types the declarations refer to aren't compared. The only deprecation it knows of is the "Deprecated:"
+// paragraph of the doc comment, recorded in the snapshot, so that removing a method or field is reported differently
+// once it was documented as deprecated in a previous snapshot.
+func TestAPISurface(t *testing.T) {
+	current := exportedAPI(t)
+
+	if *updateAPI {
+		require.NoError(t, os.WriteFile(apiFile, []byte(strings.Join(current, "\n")+"\n"), 0644))
+		return
+	}
+
+	contents, err := os.ReadFile(apiFile)
+	require.NoError(t, err, "Run go test ./registry -run TestAPISurface -update to record the API surface")
+	recorded := strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+
+	for _, line := range recorded {
+		if slices.Contains(current, line) {
+			continue
+		}
+		if strings.HasSuffix(line, deprecatedMarker) {
+			assert.Fail(t, "Deprecated API removed, record the removal with -update once it is deliberate", line)
+		} else {
+			assert.Fail(t, "API removed or changed without being deprecated first, keep it marked Deprecated: instead", line)
+		}
+	}
+	for _, line := range current {
+		if !slices.Contains(recorded, line) {
+			assert.Fail(t, "API added, record it with -update once it is deliberate", line)
+		}
+	}
+}
+
+// exportedAPI renders the exported API protected by TestAPISurface as sorted lines
+func exportedAPI(t *testing.T) []string {
+	files := token.NewFileSet()
+	var lines []string
+
+	registryFile, err := parser.ParseFile(files, "interface.go", nil, parser.ParseComments)
+	require.NoError(t, err)
+	client := findType(registryFile, "Client")
+	require.NotNil(t, client)
+	for _, method := range client.Type.(*ast.InterfaceType).Methods.List {
+		for _, name := range method.Names {
+			lines = append(lines, apiLine(files, "Client."+name.Name, method.Type, method.Doc))
+		}
+	}
+
+	typesFiles, err := filepath.Glob("../pkg/types/*.go")
+	require.NoError(t, err)
+	for _, path := range typesFiles {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(files, path, nil, parser.ParseComments)
+		require.NoError(t, err)
+
+		for _, typeName := range []string{"Config", "ServiceEndpoint"} {
+			if spec := findType(file, typeName); spec != nil {
+				for _, field := range spec.Type.(*ast.StructType).Fields.List {
+					for _, name := range field.Names {
+						if name.IsExported() {
+							lines = append(lines, apiLine(files, typeName+"."+name.Name, fieldType(field), field.Doc))
+						}
+					}
+				}
+			}
+		}
+
+		for _, decl := range file.Decls {
+			function, isFunction := decl.(*ast.FuncDecl)
+			if !isFunction || function.Recv == nil || !function.Name.IsExported() {
+				continue
+			}
+			receiver := render(files, function.Recv.List[0].Type)
+			if receiver == "Config" || receiver == "*Config" || receiver == "ServiceEndpoint" || receiver == "*ServiceEndpoint" {
+				lines = append(lines, apiLine(files, "("+receiver+")."+function.Name.Name, function.Type, function.Doc))
+			}
+		}
+	}
+
+	slices.Sort(lines)
+	return lines
+}
+
+func findType(file *ast.File, name string) *ast.TypeSpec {
+	for _, decl := range file.Decls {
+		if declaration, isGen := decl.(*ast.GenDecl); isGen && declaration.Tok == token.TYPE {
+			for _, spec := range declaration.Specs {
+				if typeSpec := spec.(*ast.TypeSpec); typeSpec.Name.Name == name {
+					return typeSpec
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fieldType renders the tag along with the type of the field, as the tags are part of the encoded Config
+func fieldType(field *ast.Field) ast.Node {
+	if field.Tag == nil {
+		return field.Type
+	}
+	return &ast.Field{Type: field.Type, Tag: field.Tag}
+}
+
+func apiLine(files *token.FileSet, name string, node ast.Node, doc *ast.CommentGroup) string {
+	line := name + " " + render(files, node)
+	if _, isFunction := node.(*ast.FuncType); isFunction {
+		line = name + strings.TrimPrefix(render(files, node), "func")
+	}
+	if doc != nil && (strings.HasPrefix(doc.Text(), "Deprecated:") || strings.Contains(doc.Text(), "\nDeprecated:")) {
+		line += deprecatedMarker
+	}
+	return line
+}
+
+func render(files *token.FileSet, node ast.Node) string {
+	var buffer bytes.Buffer
+	_ = printer.Fprint(&buffer, files, node)
+	return strings.Join(strings.Fields(buffer.String()), " ")
+}
@@ -0,0 +1,64 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/consul"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/dnssrv"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/etcd"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/keeper"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/kubernetes"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/mdns"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/memory"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// The Client decorators implement the whole Client interface, so changing it fails the build until they are updated
+var (
+	_ Client = (*BalancingClient)(nil)
+	_ Client = (*CachingClient)(nil)
+	_ Client = (*EndpointPolicyClient)(nil)
+	_ Client = (*FallbackClient)(nil)
+	_ Client = (*LatencyBudgetClient)(nil)
+	_ Client = (*MetricsClient)(nil)
+	_ Client = (*NotificationWatchClient)(nil)
+	_ Client = (*ShadowClient)(nil)
+	_ Client = (*SLOClient)(nil)
+	_ Client = (*SnapshotClient)(nil)
+	_ Client = (*TracingClient)(nil)
+)
+
+// The Clients of the registry types are unexported, so they are asserted through their constructors instead
+func _() {
+	implementsClient(consul.NewConsulClient)
+	implementsClient(dnssrv.NewDNSClient)
+	implementsClient(etcd.NewEtcdClient)
+	implementsClient(keeper.NewKeeperClient)
+	implementsClient(kubernetes.NewKubernetesClient)
+	implementsClient(mdns.NewMDNSClient)
+	implementsClient(memory.NewMemoryClient)
+
+	// The optional capabilities of the registry types documented as supporting them
+	implementsTTLReporter(consul.NewConsulClient)
+	implementsTTLReporter(keeper.NewKeeperClient)
+	implementsEndpointFilter(consul.NewConsulClient)
+	implementsAvailabilityChecker(keeper.NewKeeperClient)
+	implementsRegistrationReader(keeper.NewKeeperClient)
+	implementsRegistrationUpdater(keeper.NewKeeperClient)
+}
+
+// implementsClient only compiles if the Client created by the constructor implements the Client interface
+func implementsClient[C Client](func(types.Config) (C, error)) {}
+
+func implementsTTLReporter[C TTLReporter](func(types.Config) (C, error)) {}
+
+func implementsEndpointFilter[C EndpointFilter](func(types.Config) (C, error)) {}
+
+func implementsAvailabilityChecker[C AvailabilityChecker](func(types.Config) (C, error)) {}
+
+func implementsRegistrationReader[C RegistrationReader](func(types.Config) (C, error)) {}
+
+func implementsRegistrationUpdater[C RegistrationUpdater](func(types.Config) (C, error)) {}
@@ -0,0 +1,68 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// AvailabilityChecker is implemented by the Clients of the registry types able to check the availability of many
+// services with a single request, i.e. keeper, so IsServicesAvailable doesn't check each of them in turn
+type AvailabilityChecker interface {
+	// Checks the availability of all the target services at once, i.e. the dependencies of the current service
+	IsServicesAvailableWithContext(ctx context.Context, serviceKeys []string) (map[string]types.ServiceAvailability, error)
+}
+
+// IsServicesAvailable checks the availability of all the target services, i.e. the dependencies of the current service
+// on startup, returning it by service key. The services unregistered or unhealthy are reported unavailable with the
+// reason, while failing to check any service, i.e. the Registry being unavailable, fails the whole check. The services
+// are checked with a single round trip when the client, or any Client it wraps, is an AvailabilityChecker, and in turn
+// otherwise.
+func IsServicesAvailable(ctx context.Context, client Client, serviceKeys []string) (map[string]types.ServiceAvailability, error) {
+	if checker, ok := As[AvailabilityChecker](client); ok {
+		availabilities, err := checker.IsServicesAvailableWithContext(ctx, serviceKeys)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check the availability of the services: %w", err)
+		}
+		return availabilities, nil
+	}
+
+	availabilities := make(map[string]types.ServiceAvailability, len(serviceKeys))
+	for _, serviceKey := range serviceKeys {
+		available, err := client.IsServiceAvailableWithContext(ctx, serviceKey)
+		if err != nil && !errors.Is(err, types.ErrUnhealthy) && !errors.Is(err, types.ErrNotRegistered) {
+			return nil, fmt.Errorf("unable to check the availability of %s: %w", serviceKey, err)
+		}
+		availabilities[serviceKey] = types.NewServiceAvailability(available, err)
+	}
+	return availabilities, nil
+}
+
+// GetServiceAvailability checks the availability of the target service like IsServiceAvailable, returning it as a
+// ServiceAvailability. Like IsServicesAvailable, failing to check it, i.e. the Registry being unavailable, is returned
+// as an error rather than as the service being unavailable.
+func GetServiceAvailability(ctx context.Context, client Client, serviceKey string) (types.ServiceAvailability, error) {
+	available, err := client.IsServiceAvailableWithContext(ctx, serviceKey)
+	if err != nil && !errors.Is(err, types.ErrUnhealthy) && !errors.Is(err, types.ErrNotRegistered) {
+		return types.ServiceAvailability{}, fmt.Errorf("unable to check the availability of %s: %w", serviceKey, err)
+	}
+	return types.NewServiceAvailability(available, err), nil
+}
+
+// serviceAvailability interprets the results of IsServiceAvailable for the watchers of the availability of services.
+// IsServiceAvailable reports unhealthy and not registered services as errors, which all mean unavailable, except for
+// ErrRegistryUnavailable: the Registry couldn't be asked, so the availability is unknown and the error is returned for
+// the watcher to keep the last known one.
+func serviceAvailability(available bool, err error) (bool, error) {
+	if errors.Is(err, types.ErrRegistryUnavailable) {
+		return false, err
+	}
+	return available && err == nil, nil
+}
@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"time"
+)
+
+// AvailabilityPoll is the result of a poll of an AvailabilityPoller
+type AvailabilityPoll struct {
+	// RegistryUp indicates whether the Registry was reachable
+	RegistryUp bool
+	// Available indicates by service key whether the services are registered and healthy. The services whose
+	// availability couldn't be checked, i.e. while the Registry is unavailable, are left out as it is unknown.
+	Available map[string]bool
+}
+
+// AvailabilityPoller polls the Registry for the availability of the given services, for the components exposing it
+// elsewhere, i.e. DependencyGauges, to keep what they last exposed for the services whose availability is unknown
+type AvailabilityPoller struct {
+	client       Client
+	services     []string
+	pollInterval time.Duration
+}
+
+// NewAvailabilityPoller creates an AvailabilityPoller which polls the availability of the given services every
+// pollInterval once Run is called
+func NewAvailabilityPoller(client Client, services []string, pollInterval time.Duration) *AvailabilityPoller {
+	return &AvailabilityPoller{
+		client:       client,
+		services:     append([]string(nil), services...),
+		pollInterval: pollInterval,
+	}
+}
+
+// Run polls right away, then every pollInterval until ctx is cancelled, calling update with the result of each poll
+func (p *AvailabilityPoller) Run(ctx context.Context, update func(poll AvailabilityPoll)) {
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		update(p.Poll(ctx))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks whether the Registry is reachable and, if so, the availability of the services
+func (p *AvailabilityPoller) Poll(ctx context.Context) AvailabilityPoll {
+	poll := AvailabilityPoll{
+		RegistryUp: p.client.IsAliveWithContext(ctx),
+		Available:  make(map[string]bool, len(p.services)),
+	}
+	if !poll.RegistryUp {
+		// The availability of the services can't be checked while the Registry isn't reachable
+		return poll
+	}
+
+	for _, serviceKey := range p.services {
+		if available, err := serviceAvailability(p.client.IsServiceAvailableWithContext(ctx, serviceKey)); err == nil {
+			poll.Available[serviceKey] = available
+		}
+	}
+	return poll
+}
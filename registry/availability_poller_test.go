@@ -0,0 +1,65 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestAvailabilityPollerPoll(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(true)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(true, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-command").Return(false, errors.New("service not healthy"))
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-metadata").Return(false, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+
+	poll := NewAvailabilityPoller(client, []string{"core-data", "core-command", "core-metadata"}, testPollInterval).Poll(context.Background())
+	assert.True(t, poll.RegistryUp)
+	assert.Equal(t, map[string]bool{"core-data": true, "core-command": false}, poll.Available)
+}
+
+func TestAvailabilityPollerRegistryDown(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(false)
+
+	poll := NewAvailabilityPoller(client, []string{"core-data"}, testPollInterval).Poll(context.Background())
+	assert.False(t, poll.RegistryUp)
+	assert.Empty(t, poll.Available, "Expected the availability to be unknown while the Registry is down")
+	client.AssertNotCalled(t, "IsServiceAvailableWithContext", mock.Anything, mock.Anything)
+}
+
+func TestAvailabilityPollerRun(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(true)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(true, nil)
+
+	polls := make(chan AvailabilityPoll, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewAvailabilityPoller(client, []string{"core-data"}, testPollInterval).Run(ctx, func(poll AvailabilityPoll) {
+		select {
+		case polls <- poll:
+		default:
+		}
+	})
+
+	select {
+	case poll := <-polls:
+		assert.True(t, poll.Available["core-data"])
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for the first poll")
+	}
+}
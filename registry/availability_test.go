@@ -0,0 +1,83 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+// availabilityCheckingClient is a Client checking the availability of many services at once
+type availabilityCheckingClient struct {
+	*mocks.Client
+	availabilities map[string]types.ServiceAvailability
+}
+
+func (c *availabilityCheckingClient) IsServicesAvailableWithContext(context.Context, []string) (map[string]types.ServiceAvailability, error) {
+	return c.availabilities, nil
+}
+
+func TestIsServicesAvailable(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(true, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-command").Return(false, types.Errorf(types.ErrUnhealthy, "core-command"))
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-metadata").Return(false, types.Errorf(types.ErrNotRegistered, "core-metadata"))
+
+	availabilities, err := IsServicesAvailable(context.Background(), client, []string{"core-data", "core-command", "core-metadata"})
+	require.NoError(t, err)
+	assert.True(t, availabilities["core-data"].Available)
+	assert.Equal(t, types.StatusUp, availabilities["core-data"].Status)
+	assert.False(t, availabilities["core-command"].Available)
+	assert.Equal(t, types.StatusDown, availabilities["core-command"].Status)
+	assert.ErrorIs(t, availabilities["core-command"].Err, types.ErrUnhealthy)
+	assert.False(t, availabilities["core-metadata"].Available)
+	assert.Equal(t, types.StatusUnknown, availabilities["core-metadata"].Status)
+	assert.ErrorIs(t, availabilities["core-metadata"].Err, types.ErrNotRegistered)
+}
+
+func TestGetServiceAvailability(t *testing.T) {
+	unhealthy := types.Errorf(types.ErrUnhealthy, "core-command service not healthy")
+	client := &mocks.Client{}
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-command").Return(false, unhealthy)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(false, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+
+	start := time.Now()
+	availability, err := GetServiceAvailability(context.Background(), client, "core-command")
+	require.NoError(t, err)
+	assert.False(t, availability.Available)
+	assert.Equal(t, types.StatusDown, availability.Status)
+	assert.Equal(t, unhealthy.Error(), availability.Reason)
+	assert.False(t, availability.LastChecked.Before(start))
+
+	_, err = GetServiceAvailability(context.Background(), client, "core-data")
+	assert.ErrorIs(t, err, types.ErrRegistryUnavailable, "Expected failing to check the service to be an error")
+}
+
+func TestIsServicesAvailableRegistryUnavailable(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsServiceAvailableWithContext", mock.Anything, mock.Anything).Return(false, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+
+	_, err := IsServicesAvailable(context.Background(), client, []string{"core-data"})
+	assert.ErrorIs(t, err, types.ErrRegistryUnavailable)
+}
+
+func TestIsServicesAvailableChecker(t *testing.T) {
+	// The services are checked at once rather than in turn with IsServiceAvailable, which isn't mocked
+	expected := map[string]types.ServiceAvailability{"core-data": types.NewServiceAvailability(true, nil)}
+	client := &availabilityCheckingClient{Client: &mocks.Client{}, availabilities: expected}
+
+	availabilities, err := IsServicesAvailable(context.Background(), client, []string{"core-data"})
+	require.NoError(t, err)
+	assert.Equal(t, expected, availabilities)
+}
@@ -0,0 +1,85 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// BalancingClient is a Client spreading the lookups of GetServiceEndpoint over the instances of the target service,
+// i.e. the replicas of a horizontally scaled service, selecting the endpoint of one of them with a Balancer. The
+// endpoint of the service is returned as is when it has a single instance. The standby instances are left out, the
+// lookups failing with types.ErrUnhealthy when the service only has standby instances, until one is promoted with
+// PromoteStandby. The instances whose circuit is open in SharedInstanceHealth are left out too, unless all the active
+// instances of the service are, so every component of the process avoids the instances one of them found failing.
+type BalancingClient struct {
+	Client
+	balancer types.Balancer
+	health   *InstanceHealth
+}
+
+// NewBalancingClient wraps the given Client to select the endpoints it returns with the given Balancer, i.e.
+// types.RoundRobin()
+func NewBalancingClient(client Client, balancer types.Balancer) *BalancingClient {
+	return &BalancingClient{
+		Client:   client,
+		balancer: balancer,
+		health:   SharedInstanceHealth,
+	}
+}
+
+// Unwrap returns the Client wrapped by the BalancingClient
+func (c *BalancingClient) Unwrap() Client {
+	return c.Client
+}
+
+func (c *BalancingClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *BalancingClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+	if err != nil {
+		return types.ServiceEndpoint{}, err
+	}
+
+	if len(endpoints) == 0 {
+		// The service may still be reachable without any instance discovered, i.e. a Kubernetes Service without ready pods
+		return c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+	}
+
+	active := make([]types.ServiceEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !endpoint.IsStandby() {
+			active = append(active, endpoint)
+		}
+	}
+	// Try the failing instances anyway rather than failing the lookup, as they may have recovered
+	if closed := c.closedInstances(active); len(closed) > 0 {
+		active = closed
+	}
+	switch len(active) {
+	case 0:
+		return types.ServiceEndpoint{}, types.Errorf(types.ErrUnhealthy, "only standby instances of %s are registered, waiting for one to be promoted", serviceId)
+	case 1:
+		return active[0], nil
+	default:
+		return c.balancer.Select(serviceId, active), nil
+	}
+}
+
+// closedInstances returns the endpoints of the instances whose circuit isn't open
+func (c *BalancingClient) closedInstances(endpoints []types.ServiceEndpoint) []types.ServiceEndpoint {
+	closed := make([]types.ServiceEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !c.health.IsOpen(endpoint) {
+			closed = append(closed, endpoint)
+		}
+	}
+	return closed
+}
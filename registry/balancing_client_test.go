@@ -0,0 +1,68 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestBalancingClient(t *testing.T) {
+	replica0 := types.ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-0", Host: "10.0.0.1", Port: 59880}
+	replica1 := types.ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-1", Host: "10.0.0.2", Port: 59880}
+	single := types.ServiceEndpoint{ServiceId: "core-command", Host: "10.0.0.3", Port: 59882}
+	kubernetesService := types.ServiceEndpoint{ServiceId: "core-metadata", Host: "core-metadata.edgex.svc", Port: 59881}
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, "core-data").Return([]types.ServiceEndpoint{replica0, replica1}, nil)
+	client.On("GetServiceEndpointsWithContext", mock.Anything, "core-command").Return([]types.ServiceEndpoint{single}, nil)
+	client.On("GetServiceEndpointsWithContext", mock.Anything, "core-metadata").Return([]types.ServiceEndpoint{}, nil)
+	client.On("GetServiceEndpointsWithContext", mock.Anything, "support-cron").Return(nil, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found"))
+	client.On("GetServiceEndpointWithContext", mock.Anything, "core-metadata").Return(kubernetesService, nil)
+	balancingClient := NewBalancingClient(client, types.RoundRobin())
+
+	for _, expected := range []types.ServiceEndpoint{replica0, replica1, replica0} {
+		endpoint, err := balancingClient.GetServiceEndpoint("core-data")
+		require.NoError(t, err)
+		assert.Equal(t, expected, endpoint)
+	}
+
+	endpoint, err := balancingClient.GetServiceEndpoint("core-command")
+	require.NoError(t, err)
+	assert.Equal(t, single, endpoint)
+
+	endpoint, err = balancingClient.GetServiceEndpoint("core-metadata")
+	require.NoError(t, err)
+	assert.Equal(t, kubernetesService, endpoint, "Expected the service endpoint without any instance discovered")
+
+	_, err = balancingClient.GetServiceEndpoint("support-cron")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+}
+
+func TestBalancingClientStandby(t *testing.T) {
+	standbyMetadata := map[string]string{types.RoleMetadataKey: types.RoleStandby}
+	active := types.ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-0", Host: "10.0.0.1", Port: 59880}
+	standby := types.ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-1", Host: "10.0.0.2", Port: 59880, Metadata: standbyMetadata}
+	onlyStandby := types.ServiceEndpoint{ServiceId: "core-command", InstanceId: "core-command-0", Host: "10.0.0.3", Port: 59882, Metadata: standbyMetadata}
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, "core-data").Return([]types.ServiceEndpoint{standby, active}, nil)
+	client.On("GetServiceEndpointsWithContext", mock.Anything, "core-command").Return([]types.ServiceEndpoint{onlyStandby}, nil)
+	balancingClient := NewBalancingClient(client, types.RoundRobin())
+
+	for i := 0; i < 3; i++ {
+		endpoint, err := balancingClient.GetServiceEndpoint("core-data")
+		require.NoError(t, err)
+		assert.Equal(t, active, endpoint, "Expected the standby instance to be left out")
+	}
+
+	_, err := balancingClient.GetServiceEndpoint("core-command")
+	require.ErrorIs(t, err, types.ErrUnhealthy, "Expected the service with only standby instances not to be used")
+}
@@ -0,0 +1,248 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// CachingClient is a Client serving the service endpoints looked up on the hot path from memory, so most lookups
+// don't reach the Registry. The endpoints of a service are served for the TTL once looked up, then keep being served
+// for up to maxStale while they are refreshed in the background, which is how the lookups ride out the Registry being
+// briefly unreachable. Failed lookups aren't cached, and a service found unregistered by a refresh is forgotten. With
+// WatchChanges, the cached endpoints of a service are also forgotten as soon as the Registry reports it changed. The
+// endpoints are aged by the time of the Registry when the wrapped Client is a ServerClockProvider, so they expire as
+// expected on devices whose real-time clock is unset or jumps, and by the local clock otherwise.
+type CachingClient struct {
+	Client
+	ttl       time.Duration
+	maxStale  time.Duration
+	clock     types.Clock
+	endpoint  endpointCache[types.ServiceEndpoint]
+	endpoints endpointCache[[]types.ServiceEndpoint]
+
+	watchLock sync.Mutex
+	// watchCtx is the context of the watches of the cached services, nil unless WatchChanges has been called
+	watchCtx context.Context
+	watched  map[string]struct{}
+}
+
+// NewCachingClient wraps the given Client to cache the endpoints of GetServiceEndpoint and GetServiceEndpoints for
+// the given TTL, then serve them for up to maxStale while refreshing them
+func NewCachingClient(client Client, ttl time.Duration, maxStale time.Duration) *CachingClient {
+	var clock types.Clock = types.ClockFunc(time.Now)
+	if provider, ok := As[ServerClockProvider](client); ok {
+		clock = provider.ServerClock()
+	}
+
+	return &CachingClient{
+		Client:    client,
+		ttl:       ttl,
+		maxStale:  maxStale,
+		clock:     clock,
+		endpoint:  endpointCache[types.ServiceEndpoint]{entries: make(map[string]*cacheEntry[types.ServiceEndpoint])},
+		endpoints: endpointCache[[]types.ServiceEndpoint]{entries: make(map[string]*cacheEntry[[]types.ServiceEndpoint])},
+		watched:   make(map[string]struct{}),
+	}
+}
+
+// ServerClockProvider is implemented by the Clients of the registry types whose responses tell the time of the
+// Registry, i.e. keeper, consul and etcd with their Date header
+type ServerClockProvider interface {
+	// ServerClock returns the time of the Registry, estimated from its last responses
+	ServerClock() types.Clock
+}
+
+// Unwrap returns the Client wrapped by the CachingClient
+func (c *CachingClient) Unwrap() Client {
+	return c.Client
+}
+
+func (c *CachingClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *CachingClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	endpoint, err := c.endpoint.get(ctx, serviceId, c.clock, c.ttl, c.maxStale, func(ctx context.Context) (types.ServiceEndpoint, error) {
+		return c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+	})
+	if err == nil {
+		c.watch(serviceId)
+	}
+	return endpoint, err
+}
+
+func (c *CachingClient) GetServiceEndpoints(serviceKey string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceKey)
+}
+
+func (c *CachingClient) GetServiceEndpointsWithContext(ctx context.Context, serviceKey string) ([]types.ServiceEndpoint, error) {
+	endpoints, err := c.endpoints.get(ctx, serviceKey, c.clock, c.ttl, c.maxStale, func(ctx context.Context) ([]types.ServiceEndpoint, error) {
+		return c.Client.GetServiceEndpointsWithContext(ctx, serviceKey)
+	})
+	if err == nil {
+		c.watch(serviceKey)
+	}
+	// The callers may filter the endpoints in place
+	return slices.Clone(endpoints), err
+}
+
+func (c *CachingClient) Decommission(ctx context.Context, serviceKey string) error {
+	defer c.Invalidate(serviceKey)
+	return c.Client.Decommission(ctx, serviceKey)
+}
+
+// Freshness returns the freshness of the cached endpoints of the target service, the oldest of those of
+// GetServiceEndpoint and GetServiceEndpoints, false if none are cached
+func (c *CachingClient) Freshness(serviceKey string) (types.Freshness, bool) {
+	endpoint, endpointFound := c.endpoint.freshness(serviceKey, c.clock, c.ttl, c.maxStale)
+	endpoints, endpointsFound := c.endpoints.freshness(serviceKey, c.clock, c.ttl, c.maxStale)
+	if !endpointFound || (endpointsFound && endpoints.Age > endpoint.Age) {
+		return endpoints, endpointsFound
+	}
+	return endpoint, true
+}
+
+// Invalidate forgets the cached endpoints of the target service, which are looked up again on the next lookup, i.e.
+// once it is known to have moved
+func (c *CachingClient) Invalidate(serviceKey string) {
+	c.endpoint.invalidate(serviceKey)
+	c.endpoints.invalidate(serviceKey)
+}
+
+// WatchChanges watches each service once its endpoints are cached, forgetting them as soon as the Registry reports a
+// change of its registration rather than once expired, so the lookups don't return the endpoints of dead hosts in the
+// meantime. The services are watched with WatchService until ctx is done, the other cached endpoints then expiring as
+// usual. The registry types failing to watch a service fall back to expiring its endpoints.
+func (c *CachingClient) WatchChanges(ctx context.Context) {
+	c.watchLock.Lock()
+	defer c.watchLock.Unlock()
+
+	c.watchCtx = ctx
+}
+
+// watch starts watching the target service for changes, unless it is already watched or the changes aren't watched
+func (c *CachingClient) watch(serviceKey string) {
+	c.watchLock.Lock()
+	defer c.watchLock.Unlock()
+
+	if c.watchCtx == nil || c.watchCtx.Err() != nil {
+		return
+	}
+	if _, found := c.watched[serviceKey]; found {
+		return
+	}
+
+	changes, err := c.Client.WatchService(c.watchCtx, serviceKey)
+	if err != nil {
+		return
+	}
+	c.watched[serviceKey] = struct{}{}
+
+	go func() {
+		defer func() {
+			c.watchLock.Lock()
+			delete(c.watched, serviceKey)
+			c.watchLock.Unlock()
+		}()
+
+		// The first endpoint sent is the current one, which the cached endpoints were just looked up as
+		first := true
+		for range changes {
+			if !first {
+				c.Invalidate(serviceKey)
+			}
+			first = false
+		}
+	}()
+}
+
+type cacheEntry[T any] struct {
+	value     T
+	fetchedAt time.Time
+	// refreshing is set while the expired value is refreshed in the background, so only one refresh is in flight
+	refreshing bool
+}
+
+type endpointCache[T any] struct {
+	lock    sync.Mutex
+	entries map[string]*cacheEntry[T]
+}
+
+// get returns the cached value of the service unless older than ttl+maxStale by the clock, refreshing it in the
+// background once older than ttl, or else fetches it with ctx
+func (cache *endpointCache[T]) get(ctx context.Context, serviceKey string, clock types.Clock, ttl time.Duration, maxStale time.Duration, fetch func(ctx context.Context) (T, error)) (T, error) {
+	cache.lock.Lock()
+	if entry, found := cache.entries[serviceKey]; found {
+		age := clock.Now().Sub(entry.fetchedAt)
+		// A negative age means the clock went back, i.e. the time of the Registry was corrected, so the value is fetched again
+		if age >= 0 && age < ttl+maxStale {
+			if age >= ttl && !entry.refreshing {
+				entry.refreshing = true
+				// The refresh carries on once the lookup which triggered it returned
+				go func() {
+					value, err := fetch(context.WithoutCancel(ctx))
+					cache.store(serviceKey, value, clock.Now(), err)
+				}()
+			}
+			value := entry.value
+			cache.lock.Unlock()
+			return value, nil
+		}
+	}
+	cache.lock.Unlock()
+
+	value, err := fetch(ctx)
+	cache.store(serviceKey, value, clock.Now(), err)
+	return value, err
+}
+
+// store caches the value fetched for the service, or keeps the stale one if the fetch failed but for the service not
+// being registered anymore
+func (cache *endpointCache[T]) store(serviceKey string, value T, fetchedAt time.Time, err error) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	switch {
+	case err == nil:
+		cache.entries[serviceKey] = &cacheEntry[T]{value: value, fetchedAt: fetchedAt}
+	case errors.Is(err, types.ErrNotRegistered):
+		delete(cache.entries, serviceKey)
+	default:
+		if entry, found := cache.entries[serviceKey]; found {
+			entry.refreshing = false
+		}
+	}
+}
+
+// freshness returns the freshness of the cached value of the service, false unless it is still served
+func (cache *endpointCache[T]) freshness(serviceKey string, clock types.Clock, ttl time.Duration, maxStale time.Duration) (types.Freshness, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	entry, found := cache.entries[serviceKey]
+	if !found {
+		return types.Freshness{}, false
+	}
+	age := clock.Now().Sub(entry.fetchedAt)
+	if age < 0 || age >= ttl+maxStale {
+		return types.Freshness{}, false
+	}
+	return types.Freshness{Age: age, TTL: max(ttl-age, 0), Stale: age >= ttl}, true
+}
+
+func (cache *endpointCache[T]) invalidate(serviceKey string) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	delete(cache.entries, serviceKey)
+}
@@ -0,0 +1,195 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+const testCacheTTL = 50 * time.Millisecond
+
+func TestCachingClient(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+	cachingClient := NewCachingClient(client, testCacheTTL, 0)
+
+	for i := 0; i < 3; i++ {
+		endpoint, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+		require.NoError(t, err)
+		assert.Equal(t, testEndpoint, endpoint)
+	}
+	client.AssertNumberOfCalls(t, "GetServiceEndpointWithContext", 1)
+
+	// Without max stale, the expired endpoint is looked up again before being returned
+	moved := testEndpoint
+	moved.Host = "edgex-core-data-2"
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(moved, nil).Once()
+	time.Sleep(testCacheTTL)
+
+	endpoint, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, moved, endpoint)
+}
+
+func TestCachingClientStaleWhileRevalidate(t *testing.T) {
+	moved := testEndpoint
+	moved.Host = "edgex-core-data-2"
+
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(moved, nil).Once()
+	cachingClient := NewCachingClient(client, testCacheTTL, time.Minute)
+
+	_, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	time.Sleep(testCacheTTL)
+
+	endpoint, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpoint, endpoint, "Expected the stale endpoint while refreshing it")
+
+	assert.Eventually(t, func() bool {
+		endpoint, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+		return err == nil && endpoint.Equal(moved)
+	}, time.Second, 10*time.Millisecond, "Expected the refreshed endpoint")
+	client.AssertNumberOfCalls(t, "GetServiceEndpointWithContext", 2)
+}
+
+func TestCachingClientRegistryUnavailable(t *testing.T) {
+	unavailable := types.Errorf(types.ErrRegistryUnavailable, "connection refused")
+
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(types.ServiceEndpoint{}, unavailable)
+	cachingClient := NewCachingClient(client, testCacheTTL, 2*testCacheTTL)
+
+	_, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+
+	// The stale endpoint is served while the refreshes fail, until it is older than the TTL plus max stale
+	deadline := time.Now().Add(2 * testCacheTTL)
+	for time.Now().Before(deadline) {
+		endpoint, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+		require.NoError(t, err)
+		assert.Equal(t, testEndpoint, endpoint)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(testCacheTTL)
+	_, err = cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	assert.ErrorIs(t, err, types.ErrRegistryUnavailable)
+}
+
+func TestCachingClientNotRegistered(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, testEndpoint.ServiceId).Return([]types.ServiceEndpoint{testEndpoint}, nil).Once()
+	client.On("GetServiceEndpointsWithContext", mock.Anything, testEndpoint.ServiceId).Return(nil, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found"))
+	cachingClient := NewCachingClient(client, testCacheTTL, time.Minute)
+
+	endpoints, err := cachingClient.GetServiceEndpoints(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{testEndpoint}, endpoints)
+	time.Sleep(testCacheTTL)
+
+	// The refresh finds the service unregistered, which is then forgotten rather than served stale
+	_, err = cachingClient.GetServiceEndpoints(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, err := cachingClient.GetServiceEndpoints(testEndpoint.ServiceId)
+		return errors.Is(err, types.ErrNotRegistered)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCachingClientDecommission(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Twice()
+	client.On("Decommission", mock.Anything, testEndpoint.ServiceId).Return(nil).Once()
+	cachingClient := NewCachingClient(client, time.Minute, 0)
+
+	_, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	require.NoError(t, cachingClient.Decommission(context.Background(), testEndpoint.ServiceId))
+	_, err = cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+
+	client.AssertExpectations(t)
+}
+
+func TestCachingClientWatchChanges(t *testing.T) {
+	moved := testEndpoint
+	moved.Host = "edgex-core-data-2"
+	changes := make(chan types.ServiceEndpoint)
+
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(moved, nil).Once()
+	client.On("WatchService", mock.Anything, testEndpoint.ServiceId).Return((<-chan types.ServiceEndpoint)(changes), nil).Once()
+	cachingClient := NewCachingClient(client, time.Minute, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cachingClient.WatchChanges(ctx)
+
+	_, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	endpoint, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpoint, endpoint, "Expected the cached endpoint until a change is reported")
+
+	// The current endpoint sent first isn't a change
+	changes <- testEndpoint
+	changes <- moved
+	assert.Eventually(t, func() bool {
+		endpoint, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+		return err == nil && endpoint.Equal(moved)
+	}, time.Second, 10*time.Millisecond, "Expected the endpoint looked up again once changed, well before the TTL")
+
+	cancel()
+	close(changes)
+	client.AssertExpectations(t)
+}
+
+type serverClockClient struct {
+	*mocks.Client
+	now time.Time
+}
+
+func (c *serverClockClient) ServerClock() types.Clock {
+	return types.ClockFunc(func() time.Time { return c.now })
+}
+
+func TestCachingClientServerClock(t *testing.T) {
+	client := &serverClockClient{Client: &mocks.Client{}, now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+	cachingClient := NewCachingClient(client, time.Minute, 0)
+
+	_, err := cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	time.Sleep(testCacheTTL)
+	_, err = cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	client.AssertNumberOfCalls(t, "GetServiceEndpointWithContext", 1)
+
+	client.now = client.now.Add(time.Minute)
+	_, err = cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	client.AssertNumberOfCalls(t, "GetServiceEndpointWithContext", 2)
+
+	client.now = client.now.Add(-time.Hour)
+	_, err = cachingClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	client.AssertNumberOfCalls(t, "GetServiceEndpointWithContext", 3)
+}
@@ -0,0 +1,102 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/watch"
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// ReconnectReason identifies why a tracked connection needs to be re-established
+type ReconnectReason string
+
+const (
+	// EndpointUnhealthy indicates the service backing the connection is no longer available
+	EndpointUnhealthy ReconnectReason = "Unhealthy"
+	// EndpointReplaced indicates the service is now registered with a different host and/or port
+	EndpointReplaced ReconnectReason = "Replaced"
+)
+
+// ReconnectSignal is sent for a tracked connection once it should be re-established
+type ReconnectSignal struct {
+	Reason ReconnectReason
+	// Endpoint is the endpoint the service is currently registered with
+	Endpoint types.ServiceEndpoint
+}
+
+// ConnectionManager keeps long-lived connections (i.e. WebSocket or MQTT bridges) pinned to healthy instances by
+// watching the Registry for the endpoints they were established against
+type ConnectionManager struct {
+	client       Client
+	pollInterval time.Duration
+}
+
+type connectionState struct {
+	endpoint  types.ServiceEndpoint
+	available bool
+}
+
+func (s connectionState) equal(other connectionState) bool {
+	return s.endpoint.Equal(other.endpoint) && s.available == other.available
+}
+
+// NewConnectionManager creates a ConnectionManager which checks the Registry for changes every pollInterval
+func NewConnectionManager(client Client, pollInterval time.Duration) *ConnectionManager {
+	return &ConnectionManager{
+		client:       client,
+		pollInterval: pollInterval,
+	}
+}
+
+// Track starts tracking a connection established against the given endpoint. The returned channel receives a single
+// signal once the backing service becomes unhealthy or is replaced, after which the channel is closed and the caller
+// is expected to reconnect and track the new connection. Tracking stops when ctx is cancelled.
+func (m *ConnectionManager) Track(ctx context.Context, endpoint types.ServiceEndpoint) <-chan ReconnectSignal {
+	signals := make(chan ReconnectSignal, 1)
+
+	go func() {
+		defer close(signals)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// Tracked connections share the poll pool of the process with the watches of the clients left unset
+		for state := range watch.PollFunc(ctx, nil, m.pollInterval, func() (connectionState, error) {
+			return m.fetchState(endpoint.ServiceId)
+		}, connectionState.equal) {
+			if state.endpoint.Host != endpoint.Host || state.endpoint.Port != endpoint.Port {
+				signals <- ReconnectSignal{Reason: EndpointReplaced, Endpoint: state.endpoint}
+				return
+			}
+
+			if !state.available {
+				signals <- ReconnectSignal{Reason: EndpointUnhealthy, Endpoint: state.endpoint}
+				return
+			}
+		}
+	}()
+
+	return signals
+}
+
+func (m *ConnectionManager) fetchState(serviceKey string) (connectionState, error) {
+	endpoint, err := m.client.GetServiceEndpoint(serviceKey)
+	if err != nil {
+		// Unable to tell whether the endpoint changed, so try again on the next poll
+		return connectionState{}, err
+	}
+
+	available, err := serviceAvailability(m.client.IsServiceAvailable(serviceKey))
+	if err != nil {
+		// Unable to tell whether the service is still available, so keep the last known state
+		return connectionState{}, err
+	}
+
+	return connectionState{endpoint: endpoint, available: available}, nil
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+const testPollInterval = 10 * time.Millisecond
+
+var testEndpoint = types.ServiceEndpoint{ServiceId: "core-data", Host: "edgex-core-data", Port: 59880}
+
+func TestConnectionManagerReplaced(t *testing.T) {
+	replaced := testEndpoint
+	replaced.Port = 59881
+
+	client := &mocks.Client{}
+	client.On("GetServiceEndpoint", testEndpoint.ServiceId).Return(testEndpoint, nil).Twice()
+	client.On("GetServiceEndpoint", testEndpoint.ServiceId).Return(replaced, nil)
+	client.On("IsServiceAvailable", testEndpoint.ServiceId).Return(true, nil)
+
+	manager := NewConnectionManager(client, testPollInterval)
+	signal, ok := <-manager.Track(context.Background(), testEndpoint)
+	require.True(t, ok)
+	assert.Equal(t, EndpointReplaced, signal.Reason)
+	assert.Equal(t, replaced, signal.Endpoint)
+}
+
+func TestConnectionManagerUnhealthy(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpoint", testEndpoint.ServiceId).Return(testEndpoint, nil)
+	client.On("IsServiceAvailable", testEndpoint.ServiceId).Return(true, nil).Once()
+	client.On("IsServiceAvailable", testEndpoint.ServiceId).Return(false, errors.New("service not healthy"))
+
+	manager := NewConnectionManager(client, testPollInterval)
+	signal, ok := <-manager.Track(context.Background(), testEndpoint)
+	require.True(t, ok)
+	assert.Equal(t, EndpointUnhealthy, signal.Reason)
+}
+
+func TestConnectionManagerRegistryUnreachable(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpoint", testEndpoint.ServiceId).Return(types.ServiceEndpoint{}, errors.New("connection refused"))
+	client.On("IsServiceAvailable", mock.Anything).Return(false, errors.New("connection refused")).Maybe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*testPollInterval)
+	defer cancel()
+
+	manager := NewConnectionManager(client, testPollInterval)
+	_, ok := <-manager.Track(ctx, testEndpoint)
+	assert.False(t, ok, "Expected no reconnect signal while the Registry is unreachable")
+}
+
+func TestConnectionManagerAvailabilityUnknown(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpoint", testEndpoint.ServiceId).Return(testEndpoint, nil)
+	client.On("IsServiceAvailable", testEndpoint.ServiceId).Return(true, nil).Once()
+	client.On("IsServiceAvailable", testEndpoint.ServiceId).Return(false, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*testPollInterval)
+	defer cancel()
+
+	manager := NewConnectionManager(client, testPollInterval)
+	_, ok := <-manager.Track(ctx, testEndpoint)
+	assert.False(t, ok, "Expected no reconnect signal while the availability of the service is unknown")
+}
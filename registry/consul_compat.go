@@ -0,0 +1,356 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	consulCatalogServicesPath = "/v1/catalog/services"
+	consulHealthServicePath   = "/v1/health/service/"
+	// consulDefaultWait and consulMaxWait are the default and maximum wait of the blocking queries, as Consul's
+	consulDefaultWait = 5 * time.Minute
+	consulMaxWait     = 10 * time.Minute
+	// consulCatalogPollInterval is how often the services are listed again while a blocking query of the catalog waits,
+	// as there is no watch of all the services
+	consulCatalogPollInterval = time.Second
+	// consulMaxQueries is the maximum number of queries whose last response is kept, the least recently queried ones
+	// being dropped beyond, so clients varying the query parameters can't grow the responses without bound
+	consulMaxQueries = 1024
+)
+
+type consulCompatHandler struct {
+	client Client
+	mux    *http.ServeMux
+	// lock guards index and responses, the last response of each query along with its index
+	lock      sync.Mutex
+	index     uint64
+	responses map[string]indexedResponse
+}
+
+// indexedResponse is the last response to a query, along with the index it got when it last changed and when it was
+// last queried
+type indexedResponse struct {
+	body    []byte
+	index   uint64
+	queried time.Time
+}
+
+// NewConsulCompatHandler creates an http.Handler exposing a read-only subset of the Consul HTTP API, backed by the
+// given Client, so tooling written for Consul (i.e. Prometheus consul_sd_configs or Fabio) can discover services from
+// any registry. Only /v1/catalog/services and /v1/health/service/<name> (with the optional passing filter) are
+// supported. The responses carry an X-Consul-Index which changes with their content, and blocking queries with an
+// index wait for the content to change, for up to the wait parameter. The health of a service is watched with
+// WatchService while its query waits, while the catalog is listed again every second.
+func NewConsulCompatHandler(client Client) http.Handler {
+	handler := &consulCompatHandler{
+		client:    client,
+		mux:       http.NewServeMux(),
+		responses: make(map[string]indexedResponse),
+	}
+
+	handler.mux.HandleFunc(consulCatalogServicesPath, handler.catalogServices)
+	handler.mux.HandleFunc(consulHealthServicePath, handler.healthService)
+
+	return handler
+}
+
+func (h *consulCompatHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.mux.ServeHTTP(writer, request)
+}
+
+// catalogServices lists the registered services, which have no tags, as Consul's GET /v1/catalog/services
+func (h *consulCompatHandler) catalogServices(writer http.ResponseWriter, request *http.Request) {
+	h.serveBlocking(writer, request, h.listServices, func(ctx context.Context) (<-chan struct{}, error) {
+		return pollChanges(ctx, consulCatalogPollInterval), nil
+	})
+}
+
+func (h *consulCompatHandler) listServices(ctx context.Context) (any, error) {
+	endpoints, err := h.client.GetAllServiceEndpointsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[string][]string, len(endpoints))
+	for _, endpoint := range endpoints {
+		services[endpoint.ServiceId] = []string{}
+	}
+	return services, nil
+}
+
+// healthService lists the instances of a service along with their health as Consul's GET /v1/health/service/<name>
+func (h *consulCompatHandler) healthService(writer http.ResponseWriter, request *http.Request) {
+	serviceKey := strings.TrimPrefix(request.URL.Path, consulHealthServicePath)
+	if serviceKey == "" {
+		http.Error(writer, "missing service name", http.StatusBadRequest)
+		return
+	}
+	_, passingOnly := request.URL.Query()[consulapi.HealthPassing]
+
+	h.serveBlocking(writer, request, func(ctx context.Context) (any, error) {
+		return h.serviceEntries(ctx, serviceKey, passingOnly)
+	}, func(ctx context.Context) (<-chan struct{}, error) {
+		endpoints, err := h.client.WatchService(ctx, serviceKey)
+		if err != nil {
+			return nil, err
+		}
+		return notifyChanges(endpoints), nil
+	})
+}
+
+func (h *consulCompatHandler) serviceEntries(ctx context.Context, serviceKey string, passingOnly bool) (any, error) {
+	endpoints, err := h.client.GetAllServiceEndpointsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*consulapi.ServiceEntry, 0)
+	// The availability is reported per service key, so it is checked once for all the instances of the service
+	status := ""
+	for _, endpoint := range endpoints {
+		if endpoint.ServiceId != serviceKey {
+			continue
+		}
+
+		if status == "" {
+			status = consulapi.HealthPassing
+			if available, _ := h.client.IsServiceAvailableWithContext(ctx, serviceKey); !available {
+				status = consulapi.HealthCritical
+			}
+		}
+		if passingOnly && status != consulapi.HealthPassing {
+			continue
+		}
+
+		entries = append(entries, consulServiceEntry(endpoint, status))
+	}
+	return entries, nil
+}
+
+// serveBlocking writes the response of the query, once its index is past the index of the request, if any, waiting
+// for its content to change on each notification of changes until the wait of the request is over, as Consul's
+// blocking queries
+func (h *consulCompatHandler) serveBlocking(writer http.ResponseWriter, request *http.Request,
+	respond func(ctx context.Context) (any, error), changes func(ctx context.Context) (<-chan struct{}, error)) {
+	minIndex, wait, err := blockingQuery(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := queryKey(request)
+	body, index, err := h.query(request.Context(), key, respond)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if minIndex > 0 && index <= minIndex {
+		ctx, cancel := context.WithTimeout(request.Context(), wait)
+		defer cancel()
+
+		changed, err := changes(ctx)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	waiting:
+		for index <= minIndex {
+			select {
+			case <-ctx.Done():
+				break waiting
+			case _, ok := <-changed:
+				if !ok {
+					break waiting
+				}
+				changedBody, changedIndex, err := h.query(ctx, key, respond)
+				if err != nil {
+					if ctx.Err() == nil {
+						http.Error(writer, err.Error(), http.StatusInternalServerError)
+						return
+					}
+					// The last response is returned as is once the wait is over
+					break waiting
+				}
+				body, index = changedBody, changedIndex
+			}
+		}
+	}
+
+	writeConsulResponse(writer, body, index)
+}
+
+// query responds to the query and returns the response encoded, along with its index, which changes each time the
+// response changes
+func (h *consulCompatHandler) query(ctx context.Context, key string, respond func(ctx context.Context) (any, error)) ([]byte, uint64, error) {
+	data, err := respond(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	response, found := h.responses[key]
+	if !found || !bytes.Equal(response.body, body) {
+		if !found {
+			h.pruneResponses()
+		}
+		h.index++
+		response = indexedResponse{body: body, index: h.index}
+	}
+	response.queried = time.Now()
+	h.responses[key] = response
+	return response.body, response.index, nil
+}
+
+// pruneResponses drops the least recently queried responses until there is room for another one. A query dropped gets
+// a new index when queried again, so its blocking queries return right away once. Callers must hold lock.
+func (h *consulCompatHandler) pruneResponses() {
+	for len(h.responses) >= consulMaxQueries {
+		var oldest string
+		var oldestQueried time.Time
+		for key, response := range h.responses {
+			if oldestQueried.IsZero() || response.queried.Before(oldestQueried) {
+				oldest, oldestQueried = key, response.queried
+			}
+		}
+		delete(h.responses, oldest)
+	}
+}
+
+// blockingQuery parses the index and wait parameters of a blocking query, the index being zero for other queries
+func blockingQuery(request *http.Request) (uint64, time.Duration, error) {
+	query := request.URL.Query()
+	var index uint64
+	if value := query.Get("index"); value != "" {
+		var err error
+		if index, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid index '%s'", value)
+		}
+	}
+
+	wait := consulDefaultWait
+	if value := query.Get("wait"); value != "" {
+		var err error
+		if wait, err = time.ParseDuration(value); err != nil || wait < 0 {
+			return 0, 0, fmt.Errorf("invalid wait '%s'", value)
+		}
+	}
+	return index, min(wait, consulMaxWait), nil
+}
+
+// queryKey identifies the query of the request, by its path and parameters but the ones of the blocking queries
+func queryKey(request *http.Request) string {
+	query := request.URL.Query()
+	query.Del("index")
+	query.Del("wait")
+	return request.URL.Path + "?" + query.Encode()
+}
+
+// notifyChanges notifies of each value received until the given channel is closed, a notification not yet received
+// standing for those following it
+func notifyChanges[T any](values <-chan T) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		for range values {
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return changes
+}
+
+// pollChanges notifies of possible changes every interval until ctx is done
+func pollChanges(ctx context.Context, interval time.Duration) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return changes
+}
+
+// consulServiceEntry describes an instance of a service as Consul does, the ID of the service being the instance ID,
+// or the service key for the registry types registering a single instance per service key
+func consulServiceEntry(endpoint types.ServiceEndpoint, status string) *consulapi.ServiceEntry {
+	id := endpoint.InstanceId
+	if id == "" {
+		id = endpoint.ServiceId
+	}
+
+	return &consulapi.ServiceEntry{
+		Node: &consulapi.Node{
+			Node:    endpoint.Host,
+			Address: endpoint.Host,
+		},
+		Service: &consulapi.AgentService{
+			ID:      id,
+			Service: endpoint.ServiceId,
+			Tags:    []string{},
+			Address: endpoint.Host,
+			Port:    endpoint.Port,
+		},
+		Checks: consulapi.HealthChecks{
+			{
+				Node:        endpoint.Host,
+				CheckID:     "service:" + id,
+				Name:        "Service '" + endpoint.ServiceId + "' check",
+				Status:      status,
+				ServiceID:   id,
+				ServiceName: endpoint.ServiceId,
+			},
+		},
+	}
+}
+
+// writeConsulResponse writes the encoded response along with the headers of Consul's responses. There is no leader
+// here, so it is reported known and just contacted.
+func writeConsulResponse(writer http.ResponseWriter, body []byte, index uint64) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("X-Consul-Index", strconv.FormatUint(index, 10))
+	writer.Header().Set("X-Consul-KnownLeader", "true")
+	writer.Header().Set("X-Consul-LastContact", "0")
+	_, _ = writer.Write(body)
+}
@@ -0,0 +1,170 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func newConsulCompatTestClient(t *testing.T, client Client) *consulapi.Client {
+	server := httptest.NewServer(NewConsulCompatHandler(client))
+	t.Cleanup(server.Close)
+
+	config := consulapi.DefaultConfig()
+	config.Address = server.URL
+	consulClient, err := consulapi.NewClient(config)
+	require.NoError(t, err)
+
+	return consulClient
+}
+
+func TestConsulCompatCatalogServices(t *testing.T) {
+	other := types.ServiceEndpoint{ServiceId: "core-metadata", Host: "edgex-core-metadata", Port: 59881}
+
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{testEndpoint, other}, nil)
+
+	services, _, err := newConsulCompatTestClient(t, client).Catalog().Services(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{testEndpoint.ServiceId: {}, other.ServiceId: {}}, services)
+}
+
+func TestConsulCompatHealthService(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{testEndpoint}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(true, nil).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(false, errors.New("not healthy"))
+	consulClient := newConsulCompatTestClient(t, client)
+
+	entries, _, err := consulClient.Health().Service(testEndpoint.ServiceId, "", false, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, testEndpoint.ServiceId, entries[0].Service.ID)
+	assert.Equal(t, testEndpoint.Host, entries[0].Service.Address)
+	assert.Equal(t, testEndpoint.Port, entries[0].Service.Port)
+	assert.Equal(t, consulapi.HealthPassing, entries[0].Checks.AggregatedStatus())
+
+	entries, _, err = consulClient.Health().Service(testEndpoint.ServiceId, "", true, nil)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "Expected unhealthy service to be filtered out")
+
+	entries, _, err = consulClient.Health().Service("unknown", "", false, nil)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestConsulCompatHealthServiceInstances(t *testing.T) {
+	first := types.ServiceEndpoint{ServiceId: testEndpoint.ServiceId, InstanceId: "core-data-1", Host: "10.0.0.7", Port: 59880}
+	second := types.ServiceEndpoint{ServiceId: testEndpoint.ServiceId, InstanceId: "core-data-2", Host: "10.0.0.8", Port: 59880}
+
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{first, second}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(true, nil)
+
+	entries, _, err := newConsulCompatTestClient(t, client).Health().Service(testEndpoint.ServiceId, "", false, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, first.InstanceId, entries[0].Service.ID)
+	assert.Equal(t, second.InstanceId, entries[1].Service.ID)
+	assert.Equal(t, testEndpoint.ServiceId, entries[1].Service.Service)
+	client.AssertNumberOfCalls(t, "IsServiceAvailableWithContext", 1)
+}
+
+func TestConsulCompatResponsesBounded(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{testEndpoint}, nil)
+	handler := NewConsulCompatHandler(client).(*consulCompatHandler)
+
+	for i := 0; i < consulMaxQueries+10; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, consulCatalogServicesPath+"?filter="+strconv.Itoa(i), nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+	}
+	assert.Len(t, handler.responses, consulMaxQueries)
+}
+
+func TestConsulCompatErrors(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return(nil, errors.New("registry unreachable"))
+	consulClient := newConsulCompatTestClient(t, client)
+
+	_, _, err := consulClient.Catalog().Services(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "registry unreachable")
+
+	recorder := httptest.NewRecorder()
+	NewConsulCompatHandler(client).ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, consulCatalogServicesPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestConsulCompatBlockingQuery(t *testing.T) {
+	// Each watch gets its own channel, closed once its query is over as the watches of the clients are
+	watches := make(chan chan types.ServiceEndpoint, 2)
+	var available atomic.Bool
+	available.Store(true)
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{testEndpoint}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(
+		func(context.Context, string) bool { return available.Load() }, nil)
+	client.On("WatchService", mock.Anything, testEndpoint.ServiceId).Return(func(ctx context.Context, _ string) <-chan types.ServiceEndpoint {
+		endpoints := make(chan types.ServiceEndpoint, 1)
+		watches <- endpoints
+		go func() {
+			<-ctx.Done()
+			close(endpoints)
+		}()
+		return endpoints
+	}, nil)
+	consulClient := newConsulCompatTestClient(t, client)
+
+	entries, meta, err := consulClient.Health().Service(testEndpoint.ServiceId, "", true, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotZero(t, meta.LastIndex)
+	assert.True(t, meta.KnownLeader)
+
+	// Without change, the blocking query returns the same index once its wait is over
+	_, unchanged, err := consulClient.Health().Service(testEndpoint.ServiceId, "", true, &consulapi.QueryOptions{WaitIndex: meta.LastIndex, WaitTime: 50 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, meta.LastIndex, unchanged.LastIndex)
+	<-watches
+
+	done := make(chan *consulapi.QueryMeta, 1)
+	go func() {
+		_, changed, err := consulClient.Health().Service(testEndpoint.ServiceId, "", true, &consulapi.QueryOptions{WaitIndex: meta.LastIndex, WaitTime: time.Minute})
+		assert.NoError(t, err)
+		done <- changed
+	}()
+	select {
+	case <-done:
+		t.Fatal("Expected the blocking query to wait for a change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	available.Store(false)
+	(<-watches) <- testEndpoint
+	select {
+	case changed := <-done:
+		assert.Greater(t, changed.LastIndex, meta.LastIndex)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the blocking query to return once the health of the service changed")
+	}
+}
@@ -0,0 +1,309 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// Drainer is implemented by the Clients of the registry types able to take a service out of service while keeping it
+// registered, i.e. keeper halting it and consul putting its instances into maintenance mode
+type Drainer interface {
+	// Takes the target service out of service, so discovery stops treating it as available, keeping it registered.
+	// ErrNotSupported is returned when the service can't be drained, i.e. with ConsulCatalog.
+	Drain(ctx context.Context, serviceKey string) error
+}
+
+// Tombstoner is implemented by the Clients of the registry types able to keep the tombstones of the decommissioned
+// services in the Registry, i.e. consul in its KV store and etcd, so they are shared by the DecommissionClients of all
+// the services and outlive them
+type Tombstoner interface {
+	// Keeps the tombstone of the decommissioned service in the Registry
+	PutTombstone(ctx context.Context, tombstone types.Tombstone) error
+
+	// Gets the tombstones of all the decommissioned services from the Registry
+	GetTombstones(ctx context.Context) ([]types.Tombstone, error)
+}
+
+const (
+	// maxDecommissionRecords is the maximum number of audit records kept by a DecommissionClient, the oldest ones
+	// being dropped beyond
+	maxDecommissionRecords = 256
+	// maxTombstones is the maximum number of tombstones kept by a DecommissionClient, the ones of the services
+	// decommissioned first being dropped beyond
+	maxTombstones = 4096
+	// tombstonesRefreshInterval is how often a DecommissionClient reads the tombstones from the Registry again, for the
+	// services decommissioned by others
+	tombstonesRefreshInterval = 30 * time.Second
+)
+
+// DecommissionNotifier notifies that a service was decommissioned, i.e. by publishing a system event on the message
+// bus. It is implemented by wrapping the message bus client of choice, like Publisher.
+type DecommissionNotifier interface {
+	NotifyDecommissioned(ctx context.Context, record DecommissionRecord) error
+}
+
+// DecommissionRecord is the audit record of the decommissioning of a service
+type DecommissionRecord struct {
+	ServiceKey string
+	// Endpoints are the instances of the service registered when decommissioning started
+	Endpoints []types.ServiceEndpoint
+	// Drained indicates whether the service was taken out of service before its registration was removed
+	Drained bool
+	// DrainErr is why the service wasn't drained, i.e. ErrNotSupported when the registry type can't drain services
+	DrainErr error
+	// TombstoneErr is why the tombstone of the service wasn't kept in the Registry, in which case only the client
+	// knows of it, i.e. ErrNotSupported when the registry type can't keep tombstones
+	TombstoneErr error
+	Started      time.Time
+	Completed    time.Time
+	// Err is why decommissioning the service failed, nil when it succeeded
+	Err error
+}
+
+// DecommissionClient is a Client retiring services permanently as a single audited operation, in place of the ad-hoc
+// scripts used to retire device services: Decommission drains the service, leaves it drained for the drain period so
+// its consumers move away, removes its registration with the wrapped Client, tombstones it and notifies it. The
+// tombstoned services are treated as not registered by the lookups of the client, so a retired instance still running
+// and registering itself again isn't discovered. The tombstones are kept in the Registry when the wrapped Client is a
+// Tombstoner, and read from it every 30 seconds, so the services decommissioned by others are tombstoned too. With the
+// other registry types, i.e. keeper, the tombstones are only kept by the client. The last 256 audit records are kept.
+type DecommissionClient struct {
+	Client
+	drainPeriod time.Duration
+	notifier    DecommissionNotifier
+	lock        sync.RWMutex
+	tombstones  map[string]types.Tombstone
+	// tombstonesRead is when the tombstones were last read from the Registry
+	tombstonesRead time.Time
+	records        []DecommissionRecord
+}
+
+// NewDecommissionClient wraps the given Client to decommission the services as a single audited operation, leaving
+// them drained for the drain period, and notifying the notifier, if not nil, of each service decommissioned
+func NewDecommissionClient(client Client, drainPeriod time.Duration, notifier DecommissionNotifier) *DecommissionClient {
+	return &DecommissionClient{
+		Client:      client,
+		drainPeriod: drainPeriod,
+		notifier:    notifier,
+		tombstones:  make(map[string]types.Tombstone),
+	}
+}
+
+// Unwrap returns the Client wrapped by the DecommissionClient
+func (c *DecommissionClient) Unwrap() Client {
+	return c.Client
+}
+
+// Decommission drains the target service, waits for the drain period, removes its registration, then tombstones and
+// notifies it. The attempt is recorded whether it succeeds or not. The service is left drained when decommissioning
+// fails or ctx is cancelled after draining it. When the service can't be drained, it is decommissioned right away and
+// the record reports why in DrainErr, as it reports in TombstoneErr why its tombstone can't be kept in the Registry.
+// An error is returned when the tombstone can't be kept in the Registry although the registry type supports it.
+func (c *DecommissionClient) Decommission(ctx context.Context, serviceKey string) error {
+	record := DecommissionRecord{ServiceKey: serviceKey, Started: time.Now()}
+	// The instances are recorded for auditing only, decommissioning doesn't depend on them
+	record.Endpoints, _ = c.Client.GetServiceEndpointsWithContext(ctx, serviceKey)
+
+	record.Drained, record.DrainErr, record.Err = c.decommission(ctx, serviceKey)
+	record.Completed = time.Now()
+	if record.Err == nil {
+		record.TombstoneErr = c.tombstone(ctx, types.Tombstone{ServiceKey: serviceKey, Decommissioned: record.Completed})
+	}
+
+	c.lock.Lock()
+	c.records = append(c.records, record)
+	if len(c.records) > maxDecommissionRecords {
+		c.records = append([]DecommissionRecord(nil), c.records[len(c.records)-maxDecommissionRecords:]...)
+	}
+	c.lock.Unlock()
+
+	if record.Err != nil {
+		return record.Err
+	}
+
+	if c.notifier != nil {
+		if err := c.notifier.NotifyDecommissioned(ctx, record); err != nil {
+			return fmt.Errorf("service %s decommissioned, but unable to notify it: %w", serviceKey, err)
+		}
+	}
+	if record.TombstoneErr != nil && !errors.Is(record.TombstoneErr, types.ErrNotSupported) {
+		return fmt.Errorf("service %s decommissioned, but unable to keep its tombstone in the registry: %w", serviceKey, record.TombstoneErr)
+	}
+	return nil
+}
+
+// decommission drains the target service if possible, reporting why it wasn't, then removes its registration
+func (c *DecommissionClient) decommission(ctx context.Context, serviceKey string) (drained bool, drainErr error, err error) {
+	drainer, ok := As[Drainer](c.Client)
+	if !ok {
+		drainErr = types.Errorf(types.ErrNotSupported, "unable to drain %s: the registry type can't drain services", serviceKey)
+		return false, drainErr, c.Client.Decommission(ctx, serviceKey)
+	}
+
+	if drainErr = drainer.Drain(ctx, serviceKey); drainErr != nil {
+		if !errors.Is(drainErr, types.ErrNotSupported) {
+			return false, drainErr, fmt.Errorf("unable to drain %s before decommissioning it: %w", serviceKey, drainErr)
+		}
+		return false, drainErr, c.Client.Decommission(ctx, serviceKey)
+	}
+
+	if c.drainPeriod > 0 {
+		timer := time.NewTimer(c.drainPeriod)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return true, nil, fmt.Errorf("unable to decommission %s while draining it: %w", serviceKey, ctx.Err())
+		}
+	}
+
+	return true, nil, c.Client.Decommission(ctx, serviceKey)
+}
+
+// tombstone keeps the tombstone of the decommissioned service in the Registry if possible, and by the client anyway
+func (c *DecommissionClient) tombstone(ctx context.Context, tombstone types.Tombstone) error {
+	c.lock.Lock()
+	c.addTombstone(tombstone)
+	c.lock.Unlock()
+
+	tombstoner, ok := As[Tombstoner](c.Client)
+	if !ok {
+		return types.Errorf(types.ErrNotSupported, "unable to keep the tombstone of %s in the registry: the registry type can't keep tombstones", tombstone.ServiceKey)
+	}
+	return tombstoner.PutTombstone(ctx, tombstone)
+}
+
+// addTombstone keeps the tombstone, dropping the ones of the services decommissioned first when there are too many.
+// Callers must hold lock.
+func (c *DecommissionClient) addTombstone(tombstone types.Tombstone) {
+	c.tombstones[tombstone.ServiceKey] = tombstone
+	for len(c.tombstones) > maxTombstones {
+		var oldest types.Tombstone
+		for _, tombstone := range c.tombstones {
+			if oldest.ServiceKey == "" || tombstone.Decommissioned.Before(oldest.Decommissioned) {
+				oldest = tombstone
+			}
+		}
+		delete(c.tombstones, oldest.ServiceKey)
+	}
+}
+
+// readTombstones reads the tombstones from the Registry again once tombstonesRefreshInterval elapsed since they were
+// last read, keeping the ones already known when the Registry can't be read
+func (c *DecommissionClient) readTombstones(ctx context.Context) {
+	tombstoner, ok := As[Tombstoner](c.Client)
+	if !ok {
+		return
+	}
+
+	// The tombstones are read by a single lookup at a time, the others using the ones already known meanwhile
+	c.lock.Lock()
+	if time.Since(c.tombstonesRead) < tombstonesRefreshInterval {
+		c.lock.Unlock()
+		return
+	}
+	c.tombstonesRead = time.Now()
+	c.lock.Unlock()
+
+	tombstones, err := tombstoner.GetTombstones(ctx)
+	if err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, tombstone := range tombstones {
+		c.addTombstone(tombstone)
+	}
+}
+
+// Tombstone returns the tombstone of the target service, if it was decommissioned by the client or by others as last
+// read from the Registry
+func (c *DecommissionClient) Tombstone(ctx context.Context, serviceKey string) (types.Tombstone, bool) {
+	c.readTombstones(ctx)
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	tombstone, found := c.tombstones[serviceKey]
+	return tombstone, found
+}
+
+// Records returns the audit records of the last services the client decommissioned or failed to, in order
+func (c *DecommissionClient) Records() []DecommissionRecord {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return append([]DecommissionRecord(nil), c.records...)
+}
+
+func (c *DecommissionClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *DecommissionClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	if err := c.tombstoned(ctx, serviceId); err != nil {
+		return types.ServiceEndpoint{}, err
+	}
+	return c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+}
+
+func (c *DecommissionClient) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceId)
+}
+
+func (c *DecommissionClient) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) ([]types.ServiceEndpoint, error) {
+	if err := c.tombstoned(ctx, serviceId); err != nil {
+		return nil, err
+	}
+	return c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+}
+
+func (c *DecommissionClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+func (c *DecommissionClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.GetAllServiceEndpointsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make([]types.ServiceEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if c.tombstoned(ctx, endpoint.ServiceId) == nil {
+			registered = append(registered, endpoint)
+		}
+	}
+	return registered, nil
+}
+
+func (c *DecommissionClient) IsServiceAvailable(serviceId string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceId)
+}
+
+func (c *DecommissionClient) IsServiceAvailableWithContext(ctx context.Context, serviceId string) (bool, error) {
+	if err := c.tombstoned(ctx, serviceId); err != nil {
+		return false, err
+	}
+	return c.Client.IsServiceAvailableWithContext(ctx, serviceId)
+}
+
+// tombstoned returns ErrNotRegistered when the target service was decommissioned
+func (c *DecommissionClient) tombstoned(ctx context.Context, serviceKey string) error {
+	if tombstone, found := c.Tombstone(ctx, serviceKey); found {
+		return types.Errorf(types.ErrNotRegistered, "%s was decommissioned at %s", serviceKey, tombstone.Decommissioned.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+// drainingClient is a Client able to drain services
+type drainingClient struct {
+	*mocks.Client
+	drained []string
+	err     error
+}
+
+func (c *drainingClient) Drain(_ context.Context, serviceKey string) error {
+	c.drained = append(c.drained, serviceKey)
+	return c.err
+}
+
+// tombstoningClient is a Client able to keep tombstones, in the registry it stands for
+type tombstoningClient struct {
+	*mocks.Client
+	tombstones map[string]types.Tombstone
+}
+
+func (c *tombstoningClient) PutTombstone(_ context.Context, tombstone types.Tombstone) error {
+	c.tombstones[tombstone.ServiceKey] = tombstone
+	return nil
+}
+
+func (c *tombstoningClient) GetTombstones(context.Context) ([]types.Tombstone, error) {
+	tombstones := make([]types.Tombstone, 0, len(c.tombstones))
+	for _, tombstone := range c.tombstones {
+		tombstones = append(tombstones, tombstone)
+	}
+	return tombstones, nil
+}
+
+type fakeDecommissionNotifier struct {
+	records []DecommissionRecord
+}
+
+func (n *fakeDecommissionNotifier) NotifyDecommissioned(_ context.Context, record DecommissionRecord) error {
+	n.records = append(n.records, record)
+	return nil
+}
+
+func TestDecommissionClient(t *testing.T) {
+	drainPeriod := 20 * time.Millisecond
+	client := &drainingClient{Client: &mocks.Client{}}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, testEndpoint.ServiceId).Return([]types.ServiceEndpoint{testEndpoint}, nil)
+	client.On("Decommission", mock.Anything, testEndpoint.ServiceId).Return(nil)
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{testEndpoint, poisonedEndpoint}, nil)
+	notifier := &fakeDecommissionNotifier{}
+	decommissionClient := NewDecommissionClient(client, drainPeriod, notifier)
+
+	err := decommissionClient.Decommission(context.Background(), testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, []string{testEndpoint.ServiceId}, client.drained)
+
+	tombstone, found := decommissionClient.Tombstone(context.Background(), testEndpoint.ServiceId)
+	require.True(t, found)
+	records := decommissionClient.Records()
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, record.Completed, tombstone.Decommissioned)
+	assert.True(t, record.Drained)
+	assert.NoError(t, record.DrainErr)
+	assert.ErrorIs(t, record.TombstoneErr, types.ErrNotSupported, "Expected the tombstone only kept by the client to be reported")
+	assert.Equal(t, []types.ServiceEndpoint{testEndpoint}, record.Endpoints)
+	assert.GreaterOrEqual(t, record.Completed.Sub(record.Started), drainPeriod, "Expected the service to be left drained for the drain period")
+	assert.Equal(t, []DecommissionRecord{record}, notifier.records)
+
+	// The tombstoned service isn't discovered even if registered again
+	_, err = decommissionClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	assert.ErrorIs(t, err, types.ErrNotRegistered)
+	available, err := decommissionClient.IsServiceAvailable(testEndpoint.ServiceId)
+	assert.ErrorIs(t, err, types.ErrNotRegistered)
+	assert.False(t, available)
+	endpoints, err := decommissionClient.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{poisonedEndpoint}, endpoints)
+}
+
+func TestDecommissionClientFailure(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, testEndpoint.ServiceId).Return(nil, types.Errorf(types.ErrNotRegistered, "core-data"))
+	client.On("Decommission", mock.Anything, testEndpoint.ServiceId).Return(errors.New("service is not registered"))
+	notifier := &fakeDecommissionNotifier{}
+	decommissionClient := NewDecommissionClient(client, time.Minute, notifier)
+
+	err := decommissionClient.Decommission(context.Background(), testEndpoint.ServiceId)
+	require.Error(t, err)
+
+	_, found := decommissionClient.Tombstone(context.Background(), testEndpoint.ServiceId)
+	assert.False(t, found)
+	assert.Empty(t, notifier.records)
+	records := decommissionClient.Records()
+	require.Len(t, records, 1, "Expected the failed attempt to be recorded")
+	assert.False(t, records[0].Drained, "Expected the service not to be drained without Drainer")
+	assert.ErrorIs(t, records[0].DrainErr, types.ErrNotSupported, "Expected the service not being drained to be reported")
+	assert.Equal(t, err, records[0].Err)
+}
+
+func TestDecommissionClientPersistentTombstones(t *testing.T) {
+	client := &tombstoningClient{Client: &mocks.Client{}, tombstones: make(map[string]types.Tombstone)}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, testEndpoint.ServiceId).Return([]types.ServiceEndpoint{testEndpoint}, nil)
+	client.On("Decommission", mock.Anything, testEndpoint.ServiceId).Return(nil)
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+
+	decommissionClient := NewDecommissionClient(client, 0, nil)
+	require.NoError(t, decommissionClient.Decommission(context.Background(), testEndpoint.ServiceId))
+	assert.NoError(t, decommissionClient.Records()[0].TombstoneErr)
+	require.Contains(t, client.tombstones, testEndpoint.ServiceId, "Expected the tombstone to be kept in the registry")
+
+	// Another client, i.e. of another service or after restarting, reads the tombstone from the registry
+	_, err := NewDecommissionClient(client, 0, nil).GetServiceEndpoint(testEndpoint.ServiceId)
+	assert.ErrorIs(t, err, types.ErrNotRegistered)
+}
+
+func TestDecommissionClientBounded(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, mock.Anything).Return(nil, nil)
+	client.On("Decommission", mock.Anything, mock.Anything).Return(nil)
+	decommissionClient := NewDecommissionClient(client, 0, nil)
+
+	for i := 0; i < maxTombstones+10; i++ {
+		require.NoError(t, decommissionClient.Decommission(context.Background(), "device-"+strconv.Itoa(i)))
+	}
+	records := decommissionClient.Records()
+	assert.Len(t, records, maxDecommissionRecords)
+	assert.Equal(t, "device-"+strconv.Itoa(maxTombstones+9), records[len(records)-1].ServiceKey)
+	assert.Len(t, decommissionClient.tombstones, maxTombstones)
+	_, found := decommissionClient.Tombstone(context.Background(), "device-"+strconv.Itoa(maxTombstones+9))
+	assert.True(t, found, "Expected the tombstones of the services decommissioned last to be kept")
+}
+
+func TestDecommissionClientDrainNotSupported(t *testing.T) {
+	client := &drainingClient{Client: &mocks.Client{}, err: types.Errorf(types.ErrNotSupported, "catalog")}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, testEndpoint.ServiceId).Return([]types.ServiceEndpoint{testEndpoint}, nil)
+	client.On("Decommission", mock.Anything, testEndpoint.ServiceId).Return(nil)
+
+	// The drain period isn't waited for when the service can't be drained
+	decommissionClient := NewDecommissionClient(client, time.Minute, nil)
+	err := decommissionClient.Decommission(context.Background(), testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.ErrorIs(t, decommissionClient.Records()[0].DrainErr, types.ErrNotSupported)
+}
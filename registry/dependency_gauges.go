@@ -0,0 +1,105 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DependencyGauges tracks whether the Registry and the services a service depends on are available, and exposes them
+// in the Prometheus text format as the registry_up and dependency_up{service="<serviceKey>"} gauges, so alert rules
+// can detect broken inter-service wiring from the metrics endpoint of any service.
+type DependencyGauges struct {
+	poller       *AvailabilityPoller
+	dependencies []string
+	lock         sync.RWMutex
+	registryUp   bool
+	dependencyUp map[string]bool
+}
+
+// NewDependencyGauges creates the gauges of the given dependencies, which are updated every pollInterval once Run is
+// called. All gauges report 0 until the first update, and each dependency gauge keeps its value while the availability
+// of the dependency is unknown, i.e. the Registry being unavailable.
+func NewDependencyGauges(client Client, dependencies []string, pollInterval time.Duration) *DependencyGauges {
+	sorted := append([]string(nil), dependencies...)
+	sort.Strings(sorted)
+
+	return &DependencyGauges{
+		poller:       NewAvailabilityPoller(client, sorted, pollInterval),
+		dependencies: sorted,
+		dependencyUp: make(map[string]bool, len(sorted)),
+	}
+}
+
+// Run updates the gauges right away, then every pollInterval until ctx is cancelled
+func (g *DependencyGauges) Run(ctx context.Context) {
+	g.poller.Run(ctx, g.update)
+}
+
+// RegistryUp returns whether the Registry was reachable on the last update
+func (g *DependencyGauges) RegistryUp() bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	return g.registryUp
+}
+
+// DependencyUp returns whether the target dependency was registered and healthy when last known
+func (g *DependencyGauges) DependencyUp(serviceKey string) bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	return g.dependencyUp[serviceKey]
+}
+
+// ServeHTTP writes the gauges in the Prometheus text exposition format, to be served on /metrics or appended to the
+// output of an existing metrics endpoint
+func (g *DependencyGauges) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	var builder strings.Builder
+	builder.WriteString("# HELP registry_up Whether the Registry is reachable (1) or not (0).\n")
+	builder.WriteString("# TYPE registry_up gauge\n")
+	fmt.Fprintf(&builder, "registry_up %d\n", gaugeValue(g.registryUp))
+	builder.WriteString("# HELP dependency_up Whether the dependency is registered and healthy (1) or not (0).\n")
+	builder.WriteString("# TYPE dependency_up gauge\n")
+	for _, serviceKey := range g.dependencies {
+		fmt.Fprintf(&builder, "dependency_up{service=\"%s\"} %d\n", escapeLabelValue(serviceKey), gaugeValue(g.dependencyUp[serviceKey]))
+	}
+
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = writer.Write([]byte(builder.String()))
+}
+
+func (g *DependencyGauges) update(poll AvailabilityPoll) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.registryUp = poll.RegistryUp
+	for serviceKey, available := range poll.Available {
+		g.dependencyUp[serviceKey] = available
+	}
+}
+
+func gaugeValue(up bool) int {
+	if up {
+		return 1
+	}
+	return 0
+}
+
+// escapeLabelValue escapes the backslashes, double quotes and line feeds of a label value as the Prometheus text
+// format requires
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
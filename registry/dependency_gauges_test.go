@@ -0,0 +1,81 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestDependencyGauges(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(true)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-metadata").Return(true, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(false, errors.New("service not healthy"))
+
+	gauges := NewDependencyGauges(client, []string{"core-metadata", "core-data"}, testPollInterval)
+	assert.False(t, gauges.RegistryUp(), "Expected gauges to report down before the first update")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go gauges.Run(ctx)
+	require.Eventually(t, gauges.RegistryUp, time.Second, testPollInterval)
+	assert.True(t, gauges.DependencyUp("core-metadata"))
+	assert.False(t, gauges.DependencyUp("core-data"))
+
+	recorder := httptest.NewRecorder()
+	gauges.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, `# HELP registry_up Whether the Registry is reachable (1) or not (0).
+# TYPE registry_up gauge
+registry_up 1
+# HELP dependency_up Whether the dependency is registered and healthy (1) or not (0).
+# TYPE dependency_up gauge
+dependency_up{service="core-data"} 0
+dependency_up{service="core-metadata"} 1
+`, recorder.Body.String())
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+}
+
+func TestDependencyGaugesRegistryDown(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(false)
+
+	gauges := NewDependencyGauges(client, []string{"core-metadata"}, testPollInterval)
+	gauges.update(gauges.poller.Poll(context.Background()))
+
+	assert.False(t, gauges.RegistryUp())
+	assert.False(t, gauges.DependencyUp("core-metadata"))
+	client.AssertNotCalled(t, "IsServiceAvailableWithContext", mock.Anything, mock.Anything)
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	assert.Equal(t, `a\\b\"c\nd`, escapeLabelValue("a\\b\"c\nd"))
+}
+
+func TestDependencyGaugesAvailabilityUnknown(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(true)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-metadata").Return(true, nil).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-metadata").Return(false, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+
+	gauges := NewDependencyGauges(client, []string{"core-metadata"}, testPollInterval)
+	gauges.update(gauges.poller.Poll(context.Background()))
+	require.True(t, gauges.DependencyUp("core-metadata"))
+
+	gauges.update(gauges.poller.Poll(context.Background()))
+	assert.True(t, gauges.DependencyUp("core-metadata"), "Expected the gauge to keep its value while the availability is unknown")
+}
@@ -0,0 +1,43 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// defaultShutdownUnregisterTimeout is how long de-registering on shutdown may take by default
+const defaultShutdownUnregisterTimeout = 5 * time.Second
+
+// DeregisterOnShutdown de-registers the current service of the client once the process receives SIGINT or SIGTERM, or
+// ctx is done, i.e. cancelled by the shutdown of the bootstrap, so orderly shutdowns don't leave registrations of
+// stopped services behind. De-registering is bounded by timeout, 5s if not positive, so shutting down never blocks on
+// a dead Registry. The signals no longer terminate the process until then, so the caller is to exit once the returned
+// channel, receiving the result of de-registering, is closed. A second signal terminates the process right away.
+func DeregisterOnShutdown(ctx context.Context, client Client, timeout time.Duration) <-chan error {
+	if timeout <= 0 {
+		timeout = defaultShutdownUnregisterTimeout
+	}
+
+	signalled, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	result := make(chan error, 1)
+	go func() {
+		defer close(result)
+
+		<-signalled.Done()
+		stop()
+
+		// ctx is done by now, so only its values are kept
+		unregisterCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+		result <- client.UnregisterWithContext(unregisterCtx)
+	}()
+	return result
+}
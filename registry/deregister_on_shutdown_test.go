@@ -0,0 +1,87 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestDeregisterOnShutdownContextDone(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("UnregisterWithContext", mock.Anything).Return(func(ctx context.Context) error {
+		// The attempt isn't cancelled along with the shutdown context
+		return ctx.Err()
+	}).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := DeregisterOnShutdown(ctx, client, time.Second)
+	select {
+	case <-result:
+		require.Fail(t, "Expected the service not to be de-registered before shutting down")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, receiveResult(t, result))
+	client.AssertExpectations(t)
+}
+
+func TestDeregisterOnShutdownSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM can't be sent to the current process on Windows")
+	}
+
+	client := &mocks.Client{}
+	client.On("UnregisterWithContext", mock.Anything).Return(nil).Once()
+
+	result := DeregisterOnShutdown(context.Background(), client, time.Second)
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(syscall.SIGTERM))
+
+	require.NoError(t, receiveResult(t, result))
+	client.AssertExpectations(t)
+}
+
+func TestDeregisterOnShutdownDeadRegistry(t *testing.T) {
+	// The Registry doesn't respond before the attempt times out
+	client := &mocks.Client{}
+	client.On("UnregisterWithContext", mock.Anything).Return(func(ctx context.Context) error {
+		<-ctx.Done()
+		return types.Errorf(types.ErrRegistryUnavailable, "%w", ctx.Err())
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := receiveResult(t, DeregisterOnShutdown(ctx, client, 50*time.Millisecond))
+	assert.ErrorIs(t, err, types.ErrRegistryUnavailable)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// receiveResult returns the result of de-registering on shutdown, once the channel is closed
+func receiveResult(t *testing.T, result <-chan error) error {
+	select {
+	case err := <-result:
+		_, open := <-result
+		assert.False(t, open, "Expected the channel to be closed once the result is sent")
+		return err
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for the service to be de-registered")
+		return nil
+	}
+}
@@ -0,0 +1,154 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// EndpointPolicyClient is a Client verifying every discovered service endpoint against an EndpointPolicy. Rejected
+// endpoints are treated as not found: lookups fail, GetAllServiceEndpoints leaves them out, the services aren't
+// available and WatchService sends an empty endpoint instead. NewRegistryClient wraps the registry client with it when
+// Config.EndpointPolicy is set.
+type EndpointPolicyClient struct {
+	Client
+	policy types.EndpointPolicy
+}
+
+// NewEndpointPolicyClient wraps the given Client to verify the discovered service endpoints against the given policy
+func NewEndpointPolicyClient(client Client, policy types.EndpointPolicy) *EndpointPolicyClient {
+	return &EndpointPolicyClient{
+		Client: client,
+		policy: policy,
+	}
+}
+
+// Unwrap returns the Client wrapped by the EndpointPolicyClient
+func (c *EndpointPolicyClient) Unwrap() Client {
+	return c.Client
+}
+
+func (c *EndpointPolicyClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *EndpointPolicyClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	endpoint, err := c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+	if err != nil {
+		return types.ServiceEndpoint{}, err
+	}
+
+	if err := c.verify(ctx, endpoint); err != nil {
+		return types.ServiceEndpoint{}, err
+	}
+	return endpoint, nil
+}
+
+func (c *EndpointPolicyClient) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceId)
+}
+
+func (c *EndpointPolicyClient) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) ([]types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]types.ServiceEndpoint, 0, len(endpoints))
+	var rejected error
+	for _, endpoint := range endpoints {
+		if err := c.verify(ctx, endpoint); err != nil {
+			rejected = err
+			continue
+		}
+		allowed = append(allowed, endpoint)
+	}
+	// Like GetServiceEndpointWithContext, fail when the only instances registered are rejected
+	if len(allowed) == 0 && rejected != nil {
+		return nil, rejected
+	}
+	return allowed, nil
+}
+
+func (c *EndpointPolicyClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+func (c *EndpointPolicyClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.GetAllServiceEndpointsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]types.ServiceEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if c.verify(ctx, endpoint) == nil {
+			allowed = append(allowed, endpoint)
+		}
+	}
+	return allowed, nil
+}
+
+func (c *EndpointPolicyClient) IsServiceAvailable(serviceId string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceId)
+}
+
+// IsServiceAvailableWithContext checks the target service is available, then that its endpoint is allowed
+func (c *EndpointPolicyClient) IsServiceAvailableWithContext(ctx context.Context, serviceId string) (bool, error) {
+	available, err := c.Client.IsServiceAvailableWithContext(ctx, serviceId)
+	if err != nil || !available {
+		return available, err
+	}
+
+	if _, err := c.GetServiceEndpointWithContext(ctx, serviceId); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WatchService watches the endpoint of the target service, sending an empty endpoint in place of the rejected ones
+func (c *EndpointPolicyClient) WatchService(ctx context.Context, serviceId string) (<-chan types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.WatchService(ctx, serviceId)
+	if err != nil {
+		return nil, err
+	}
+
+	verified := make(chan types.ServiceEndpoint)
+	go func() {
+		defer close(verified)
+
+		sent := false
+		var last types.ServiceEndpoint
+		for endpoint := range endpoints {
+			if !endpoint.IsZero() && c.verify(ctx, endpoint) != nil {
+				endpoint = types.ServiceEndpoint{}
+			}
+			// Consecutive rejected endpoints all become empty, which has already been sent
+			if sent && endpoint.Equal(last) {
+				continue
+			}
+
+			select {
+			case verified <- endpoint:
+				sent, last = true, endpoint
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return verified, nil
+}
+
+func (c *EndpointPolicyClient) verify(ctx context.Context, endpoint types.ServiceEndpoint) error {
+	if err := c.policy(ctx, endpoint); err != nil {
+		return fmt.Errorf("endpoint %s:%d of %s rejected by endpoint policy: %v", endpoint.Host, endpoint.Port, endpoint.ServiceId, err)
+	}
+	return nil
+}
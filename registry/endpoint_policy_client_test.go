@@ -0,0 +1,97 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+var (
+	plantEndpoint    = types.ServiceEndpoint{ServiceId: "core-data", Host: "10.1.2.3", Port: 59880}
+	poisonedEndpoint = types.ServiceEndpoint{ServiceId: "core-command", Host: "203.0.113.7", Port: 59882}
+)
+
+func TestEndpointPolicyClient(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, plantEndpoint.ServiceId).Return(plantEndpoint, nil)
+	client.On("GetServiceEndpointWithContext", mock.Anything, poisonedEndpoint.ServiceId).Return(poisonedEndpoint, nil)
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{poisonedEndpoint, plantEndpoint}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, mock.Anything).Return(true, nil)
+	policyClient := NewEndpointPolicyClient(client, types.DenyPublicIPs())
+
+	endpoint, err := policyClient.GetServiceEndpoint(plantEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, plantEndpoint, endpoint)
+
+	_, err = policyClient.GetServiceEndpoint(poisonedEndpoint.ServiceId)
+	require.Error(t, err, "Expected public endpoint to be rejected")
+
+	endpoints, err := policyClient.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{plantEndpoint}, endpoints)
+
+	available, err := policyClient.IsServiceAvailable(plantEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	available, err = policyClient.IsServiceAvailable(poisonedEndpoint.ServiceId)
+	require.Error(t, err)
+	assert.False(t, available)
+}
+
+func TestEndpointPolicyClientGetServiceEndpoints(t *testing.T) {
+	poisoned := plantEndpoint
+	poisoned.InstanceId, poisoned.Host = "core-data-2", poisonedEndpoint.Host
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, plantEndpoint.ServiceId).Return([]types.ServiceEndpoint{plantEndpoint, poisoned}, nil)
+	client.On("GetServiceEndpointsWithContext", mock.Anything, poisonedEndpoint.ServiceId).Return([]types.ServiceEndpoint{poisonedEndpoint}, nil)
+	policyClient := NewEndpointPolicyClient(client, types.DenyPublicIPs())
+
+	endpoints, err := policyClient.GetServiceEndpoints(plantEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{plantEndpoint}, endpoints)
+
+	_, err = policyClient.GetServiceEndpoints(poisonedEndpoint.ServiceId)
+	require.Error(t, err, "Expected the only instance, public, to be rejected")
+}
+
+func TestEndpointPolicyClientWatchService(t *testing.T) {
+	poisoned := plantEndpoint
+	poisoned.Host = "203.0.113.7"
+	moved := poisoned
+	moved.Port = 59890
+
+	endpoints := make(chan types.ServiceEndpoint, 4)
+	endpoints <- plantEndpoint
+	endpoints <- poisoned
+	endpoints <- moved
+	endpoints <- plantEndpoint
+	close(endpoints)
+
+	client := &mocks.Client{}
+	client.On("WatchService", mock.Anything, plantEndpoint.ServiceId).Return((<-chan types.ServiceEndpoint)(endpoints), nil)
+	policyClient := NewEndpointPolicyClient(client, types.DenyPublicIPs())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	verified, err := policyClient.WatchService(ctx, plantEndpoint.ServiceId)
+	require.NoError(t, err)
+
+	var received []types.ServiceEndpoint
+	for endpoint := range verified {
+		received = append(received, endpoint)
+	}
+	assert.Equal(t, []types.ServiceEndpoint{plantEndpoint, {}, plantEndpoint}, received)
+}
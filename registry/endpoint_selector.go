@@ -0,0 +1,108 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// EndpointFilter is implemented by the Clients of the registry types able to select the service endpoints by tags and
+// metadata server-side, i.e. consul, so GetServiceEndpointsBySelector doesn't retrieve all the endpoints
+type EndpointFilter interface {
+	// Gets the endpoints of the services with the tags and metadata of the selector, regardless of their availability
+	GetServiceEndpointsMatchingWithContext(ctx context.Context, selector types.EndpointSelector) ([]types.ServiceEndpoint, error)
+}
+
+// GetServiceEndpointsBySelector returns the endpoints of the services registered with all the tags and metadata of the
+// selector, i.e. all the device services with the modbus protocol metadata, in the order of GetAllServiceEndpoints.
+// They are selected by the Registry when the client, or any Client it wraps, is an EndpointFilter, and out of all the
+// endpoints otherwise. With
+// AvailableOnly, the availability of each selected service is then checked, leaving out the unhealthy ones and those
+// unregistered in between.
+func GetServiceEndpointsBySelector(ctx context.Context, client Client, selector types.EndpointSelector) ([]types.ServiceEndpoint, error) {
+	var endpoints []types.ServiceEndpoint
+	var err error
+	if filter, ok := As[EndpointFilter](client); ok {
+		endpoints, err = filter.GetServiceEndpointsMatchingWithContext(ctx, selector)
+	} else {
+		endpoints, err = client.GetAllServiceEndpointsWithContext(ctx)
+		endpoints = selector.Filter(endpoints)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the selected service endpoints: %w", err)
+	}
+
+	if !selector.AvailableOnly {
+		return endpoints, nil
+	}
+
+	var available []types.ServiceEndpoint
+	for _, endpoint := range endpoints {
+		isAvailable, err := client.IsServiceAvailableWithContext(ctx, endpoint.ServiceId)
+		switch {
+		case err == nil:
+			if isAvailable {
+				available = append(available, endpoint)
+			}
+		case errors.Is(err, types.ErrUnhealthy) || errors.Is(err, types.ErrNotRegistered):
+		default:
+			return nil, fmt.Errorf("unable to check the availability of %s: %w", endpoint.ServiceId, err)
+		}
+	}
+	return available, nil
+}
+
+// GetServiceEndpointsByTag returns the endpoints of the services registered with the given tag, like
+// GetServiceEndpointsBySelector
+func GetServiceEndpointsByTag(ctx context.Context, client Client, tag string) ([]types.ServiceEndpoint, error) {
+	return GetServiceEndpointsBySelector(ctx, client, types.EndpointSelector{Tags: []string{tag}})
+}
+
+// GetServiceEndpointByZone returns the endpoint to use out of the redundant services with the tags and metadata of the
+// selector, i.e. the core-data instances of each plant network, following the zone failover order, i.e. zone-a, zone-b
+// then types.AnyZone: the first available service of the first zone having any is returned. Only the available
+// services are considered, so an unhealthy zone fails over to the next one. The types.ErrNotRegistered error is
+// returned when no service of the failover order is available.
+func GetServiceEndpointByZone(ctx context.Context, client Client, selector types.EndpointSelector, failoverOrder []string) (types.ServiceEndpoint, error) {
+	selector.AvailableOnly = true
+	endpoints, err := GetServiceEndpointsBySelector(ctx, client, selector)
+	if err != nil {
+		return types.ServiceEndpoint{}, err
+	}
+
+	endpoint, found := types.SelectByZone(endpoints, failoverOrder)
+	if !found {
+		return types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "no available service endpoint in zones %v", failoverOrder)
+	}
+	return endpoint, nil
+}
+
+// GetServiceEndpointByName returns the endpoint the target service registered with the given name, i.e. the opcua
+// endpoint of a device service next to its REST one. The types.ErrNotRegistered error is returned when the service
+// isn't registered, types.ErrEndpointNotRegistered when it registered no endpoint with that name, and
+// types.ErrMalformedResponse when the endpoint it registered with that name is malformed.
+func GetServiceEndpointByName(ctx context.Context, client Client, serviceKey string, endpointName string) (types.ServiceEndpoint, error) {
+	endpoint, err := client.GetServiceEndpointWithContext(ctx, serviceKey)
+	if err != nil {
+		return types.ServiceEndpoint{}, err
+	}
+
+	named, found := endpoint.NamedEndpoint(endpointName)
+	if !found {
+		if value, registered := endpoint.Metadata[types.EndpointMetadataKeyPrefix+endpointName]; registered {
+			return types.ServiceEndpoint{}, types.Errorf(types.ErrMalformedResponse, "malformed %s endpoint '%s' registered by service %s", endpointName, value, serviceKey)
+		}
+		return types.ServiceEndpoint{}, types.Errorf(types.ErrEndpointNotRegistered, "no %s endpoint registered by service %s", endpointName, serviceKey)
+	}
+	if err := named.Validate(); err != nil {
+		return types.ServiceEndpoint{}, err
+	}
+	return named, nil
+}
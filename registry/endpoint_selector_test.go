@@ -0,0 +1,134 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+var (
+	modbusEndpoint = types.ServiceEndpoint{ServiceId: "device-modbus", Host: "10.0.0.1", Port: 59901, Metadata: map[string]string{"protocol": "modbus"}}
+	onvifEndpoint  = types.ServiceEndpoint{ServiceId: "device-onvif", Host: "10.0.0.2", Port: 59984, Metadata: map[string]string{"protocol": "onvif"}}
+	modbusSelector = types.EndpointSelector{Metadata: map[string]string{"protocol": "modbus"}}
+)
+
+// filteringClient is a Client selecting the endpoints server-side
+type filteringClient struct {
+	*mocks.Client
+	selected []types.EndpointSelector
+}
+
+func (c *filteringClient) GetServiceEndpointsMatchingWithContext(_ context.Context, selector types.EndpointSelector) ([]types.ServiceEndpoint, error) {
+	c.selected = append(c.selected, selector)
+	return []types.ServiceEndpoint{modbusEndpoint}, nil
+}
+
+func TestGetServiceEndpointsBySelector(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{modbusEndpoint, onvifEndpoint}, nil)
+
+	endpoints, err := GetServiceEndpointsBySelector(context.Background(), client, modbusSelector)
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{modbusEndpoint}, endpoints)
+
+	endpoints, err = GetServiceEndpointsByTag(context.Background(), client, "gpu")
+	require.NoError(t, err)
+	assert.Empty(t, endpoints)
+}
+
+func TestGetServiceEndpointsBySelectorServerSide(t *testing.T) {
+	client := &filteringClient{Client: &mocks.Client{}}
+
+	endpoints, err := GetServiceEndpointsBySelector(context.Background(), client, modbusSelector)
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{modbusEndpoint}, endpoints)
+	assert.Equal(t, []types.EndpointSelector{modbusSelector}, client.selected)
+	client.AssertNotCalled(t, "GetAllServiceEndpointsWithContext", mock.Anything)
+}
+
+func TestGetServiceEndpointsBySelectorAvailableOnly(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{modbusEndpoint, onvifEndpoint}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, modbusEndpoint.ServiceId).Return(true, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, onvifEndpoint.ServiceId).Return(false, types.Errorf(types.ErrUnhealthy, "device-onvif service not healthy"))
+
+	endpoints, err := GetServiceEndpointsBySelector(context.Background(), client, types.EndpointSelector{AvailableOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{modbusEndpoint}, endpoints)
+
+	client = &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{modbusEndpoint}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, modbusEndpoint.ServiceId).Return(false, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+
+	_, err = GetServiceEndpointsBySelector(context.Background(), client, types.EndpointSelector{AvailableOnly: true})
+	require.ErrorIs(t, err, types.ErrRegistryUnavailable, "Expected unknown availability to fail the lookup")
+}
+
+func TestGetServiceEndpointsBySelectorError(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return(nil, errors.New("connection refused"))
+
+	_, err := GetServiceEndpointsBySelector(context.Background(), client, modbusSelector)
+	require.EqualError(t, err, "unable to get the selected service endpoints: connection refused")
+}
+
+func TestGetServiceEndpointByZone(t *testing.T) {
+	zoneA := types.ServiceEndpoint{ServiceId: "core-data-a", Metadata: map[string]string{"service": "core-data", types.ZoneMetadataKey: "zone-a"}}
+	zoneB := types.ServiceEndpoint{ServiceId: "core-data-b", Metadata: map[string]string{"service": "core-data", types.ZoneMetadataKey: "zone-b"}}
+	selector := types.EndpointSelector{Metadata: map[string]string{"service": "core-data"}}
+	failoverOrder := []string{"zone-a", "zone-b"}
+
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{zoneB, zoneA, modbusEndpoint}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, zoneA.ServiceId).Return(false, types.Errorf(types.ErrUnhealthy, "core-data-a service not healthy")).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, zoneA.ServiceId).Return(true, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, zoneB.ServiceId).Return(true, nil).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, zoneB.ServiceId).Return(false, types.Errorf(types.ErrUnhealthy, "core-data-b service not healthy"))
+
+	endpoint, err := GetServiceEndpointByZone(context.Background(), client, selector, failoverOrder)
+	require.NoError(t, err)
+	assert.Equal(t, zoneB, endpoint, "Expected failover to zone-b while zone-a is unhealthy")
+
+	endpoint, err = GetServiceEndpointByZone(context.Background(), client, selector, failoverOrder)
+	require.NoError(t, err)
+	assert.Equal(t, zoneA, endpoint)
+
+	_, err = GetServiceEndpointByZone(context.Background(), client, selector, []string{"zone-c"})
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+}
+
+func TestGetServiceEndpointByName(t *testing.T) {
+	opcuaEndpoint := types.ServiceEndpoint{ServiceId: "device-opcua", Host: "10.0.0.3", Port: 59997, Metadata: map[string]string{
+		types.EndpointMetadataKeyPrefix + "opcua":  "opc.tcp://:4840",
+		types.EndpointMetadataKeyPrefix + "broken": "opc.tcp://4840",
+	}}
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, opcuaEndpoint.ServiceId).Return(opcuaEndpoint, nil)
+	client.On("GetServiceEndpointWithContext", mock.Anything, "device-modbus").Return(types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found"))
+
+	endpoint, err := GetServiceEndpointByName(context.Background(), client, opcuaEndpoint.ServiceId, "opcua")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.3", endpoint.Host)
+	assert.Equal(t, 4840, endpoint.Port)
+	assert.Equal(t, "opc.tcp", endpoint.Scheme)
+
+	_, err = GetServiceEndpointByName(context.Background(), client, opcuaEndpoint.ServiceId, "metrics")
+	require.ErrorIs(t, err, types.ErrEndpointNotRegistered)
+	require.NotErrorIs(t, err, types.ErrNotRegistered, "Expected a missing endpoint name to be told apart from a missing service")
+	_, err = GetServiceEndpointByName(context.Background(), client, opcuaEndpoint.ServiceId, "broken")
+	require.ErrorIs(t, err, types.ErrMalformedResponse)
+	_, err = GetServiceEndpointByName(context.Background(), client, "device-modbus", "opcua")
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+}
@@ -18,19 +18,129 @@
 package registry
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/consul"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/dnssrv"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/etcd"
 	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/keeper"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/kubernetes"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/mdns"
+	"github.com/edgexfoundry/go-mod-registry/v3/internal/pkg/memory"
 	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
 )
 
+// hostOptionalTypes are the registry types which don't need the registry host and port: the Kubernetes API server may
+// come from the kubeconfig file or the in-cluster environment, the DNS server from the system resolver, the mDNS
+// multicast group defaults to the standard one and the memory registry is in-process
+var hostOptionalTypes = map[string]bool{
+	"kubernetes": true,
+	"dns":        true,
+	"mdns":       true,
+	"memory":     true,
+}
+
+// NewRegistryClient creates the Client of the registry type of the configuration, wrapped by the decorators the
+// configuration enables. The endpoint snapshot is saved and the cached services watched in the background for the life
+// of the process, see NewRegistryClientWithContext to stop them.
 func NewRegistryClient(registryConfig types.Config) (Client, error) {
+	return NewRegistryClientWithContext(context.Background(), registryConfig)
+}
+
+// NewRegistryClientWithContext is NewRegistryClient with the background saving of the endpoint snapshot and watching of
+// the cached services running until ctx is done, the snapshot being saved one last time then. Callers needing the
+// last save to be complete before exiting call Save on the SnapshotClient found with As.
+func NewRegistryClientWithContext(ctx context.Context, registryConfig types.Config) (Client, error) {
+	client, err := newBackendClient(registryConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if registryConfig.EndpointSnapshotFile != "" {
+		snapshotInterval, err := registryConfig.GetEndpointSnapshotInterval()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create registry client: %v", err)
+		}
+		var snapshotClient *SnapshotClient
+		if registryConfig.GetEndpointSnapshotKey != nil {
+			key, err := registryConfig.GetEndpointSnapshotKey()
+			if err != nil {
+				return nil, fmt.Errorf("unable to create registry client: unable to get the endpoint snapshot key: %v", err)
+			}
+			if registryConfig.EndpointSnapshotMigrate {
+				if err := EncryptSnapshot(registryConfig.EndpointSnapshotFile, key); err != nil {
+					return nil, fmt.Errorf("unable to create registry client: %v", err)
+				}
+			}
+			snapshotClient, err = NewEncryptedSnapshotClient(client, registryConfig.EndpointSnapshotFile, key)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create registry client: %v", err)
+			}
+		} else {
+			snapshotClient, err = NewSnapshotClient(client, registryConfig.EndpointSnapshotFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create registry client: %v", err)
+			}
+		}
+		snapshotClient.SaveEvery(ctx, snapshotInterval)
+		client = snapshotClient
+	}
+
+	cacheTTL, err := registryConfig.GetEndpointCacheTTL()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create registry client: %v", err)
+	}
+	cacheMaxStale, err := registryConfig.GetEndpointCacheMaxStale()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create registry client: %v", err)
+	}
+	if cacheTTL > 0 {
+		cachingClient := NewCachingClient(client, cacheTTL, cacheMaxStale)
+		if registryConfig.EndpointCacheWatch {
+			cachingClient.WatchChanges(ctx)
+		}
+		client = cachingClient
+	}
+	fallbacks, err := registryConfig.GetFallbackEndpoints()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create registry client: %v", err)
+	}
+	if len(fallbacks) > 0 {
+		client = NewFallbackClient(client, fallbacks)
+	}
+	if registryConfig.EndpointPolicy != nil {
+		client = NewEndpointPolicyClient(client, registryConfig.EndpointPolicy)
+	}
+	if registryConfig.Balancer != nil {
+		client = NewBalancingClient(client, registryConfig.Balancer)
+	}
+	if registryConfig.TracerProvider != nil {
+		client = NewTracingClient(client, registryConfig.TracerProvider, registryConfig.Type, registryConfig.ServiceKey)
+	}
+
+	return client, nil
+}
 
-	if registryConfig.Host == "" || registryConfig.Port == 0 {
+func newBackendClient(registryConfig types.Config) (Client, error) {
+
+	if !hostOptionalTypes[registryConfig.Type] && (registryConfig.Host == "" || registryConfig.Port == 0) {
 		return nil, fmt.Errorf("unable to create ConsulClient: registry host and/or port or serviceKey not set")
 	}
 
+	registryConfig, err := registryConfig.WithTemplate()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create registry client: %v", err)
+	}
+	registryConfig, err = registryConfig.WithServiceHost()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create registry client: %v", err)
+	}
+	registryConfig, err = registryConfig.WithInstanceId()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create registry client: %v", err)
+	}
+
 	switch registryConfig.Type {
 	case "consul":
 		registryClient, err := consul.NewConsulClient(registryConfig)
@@ -38,6 +148,21 @@ func NewRegistryClient(registryConfig types.Config) (Client, error) {
 	case "keeper":
 		registryClient, err := keeper.NewKeeperClient(registryConfig)
 		return registryClient, err
+	case "etcd":
+		registryClient, err := etcd.NewEtcdClient(registryConfig)
+		return registryClient, err
+	case "kubernetes":
+		registryClient, err := kubernetes.NewKubernetesClient(registryConfig)
+		return registryClient, err
+	case "dns":
+		registryClient, err := dnssrv.NewDNSClient(registryConfig)
+		return registryClient, err
+	case "mdns":
+		registryClient, err := mdns.NewMDNSClient(registryConfig)
+		return registryClient, err
+	case "memory":
+		registryClient, err := memory.NewMemoryClient(registryConfig)
+		return registryClient, err
 	default:
 		return nil, fmt.Errorf("unknown registry type '%s' requested", registryConfig.Type)
 	}
@@ -17,9 +17,16 @@
 package registry
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
 )
@@ -44,6 +51,43 @@ func TestNewRegistryClientConsul(t *testing.T) {
 	assert.False(t, client.IsAlive(), "Consul service not expected be running")
 }
 
+func TestNewRegistryClientEtcd(t *testing.T) {
+	config := registryConfig
+	config.Type = "etcd"
+	config.Port = 2379
+
+	client, err := NewRegistryClient(config)
+	if assert.Nil(t, err, "New Registry client failed: ", err) == false {
+		t.Fatal()
+	}
+
+	assert.False(t, client.IsAlive(), "etcd service not expected be running")
+}
+
+func TestNewRegistryClientDNSWithoutHost(t *testing.T) {
+	config := registryConfig
+	config.Type = "dns"
+	config.Host = ""
+	config.Port = 0
+
+	_, err := NewRegistryClient(config)
+	assert.Nil(t, err, "Expected DNS client to use the system resolver without registry host")
+}
+
+func TestNewRegistryClientMemory(t *testing.T) {
+	client, err := NewRegistryClient(types.Config{
+		Type:            "memory",
+		MemoryEndpoints: []types.ServiceEndpoint{{ServiceId: "core-data", Host: "localhost", Port: 59880}},
+	})
+	if assert.Nil(t, err, "New Registry client failed: ", err) == false {
+		t.Fatal()
+	}
+
+	endpoint, err := client.GetServiceEndpoint("core-data")
+	assert.Nil(t, err)
+	assert.Equal(t, 59880, endpoint.Port)
+}
+
 func TestNewRegistryBogusType(t *testing.T) {
 
 	registryConfig.Type = "bogus"
@@ -53,3 +97,126 @@ func TestNewRegistryBogusType(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestNewRegistryUnknownTemplate(t *testing.T) {
+	config := registryConfig
+	config.Type = "keeper"
+	config.Template = "bogus"
+
+	_, err := NewRegistryClient(config)
+	assert.Error(t, err, "Expected unknown registration template error")
+}
+
+func TestNewRegistryClientEndpointPolicy(t *testing.T) {
+	client, err := NewRegistryClient(types.Config{
+		Type:            "memory",
+		MemoryEndpoints: []types.ServiceEndpoint{{ServiceId: "core-data", Host: "203.0.113.7", Port: 59880}},
+		EndpointPolicy:  types.DenyPublicIPs(),
+	})
+	if assert.Nil(t, err, "New Registry client failed: ", err) == false {
+		t.Fatal()
+	}
+
+	_, err = client.GetServiceEndpoint("core-data")
+	assert.Error(t, err, "Expected endpoint rejected by the endpoint policy")
+}
+
+func TestNewRegistryClientBalancer(t *testing.T) {
+	client, err := NewRegistryClient(types.Config{Type: "memory", Balancer: types.RoundRobin()})
+	if assert.Nil(t, err, "New Registry client failed: ", err) == false {
+		t.Fatal()
+	}
+
+	assert.IsType(t, &BalancingClient{}, client)
+}
+
+func TestNewRegistryClientEndpointCache(t *testing.T) {
+	client, err := NewRegistryClient(types.Config{Type: "memory", EndpointCacheTTL: "5s", EndpointCacheMaxStale: "30s", EndpointCacheWatch: true})
+	if assert.Nil(t, err, "New Registry client failed: ", err) == false {
+		t.Fatal()
+	}
+	assert.IsType(t, &CachingClient{}, client)
+
+	_, err = NewRegistryClient(types.Config{Type: "memory", EndpointCacheTTL: "5"})
+	assert.Error(t, err, "Expected invalid endpoint cache TTL error")
+}
+
+func TestNewRegistryClientEndpointSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	client, err := NewRegistryClient(types.Config{Type: "memory", EndpointSnapshotFile: path})
+	if assert.Nil(t, err, "New Registry client failed: ", err) == false {
+		t.Fatal()
+	}
+	assert.IsType(t, &SnapshotClient{}, client)
+
+	_, err = NewRegistryClient(types.Config{Type: "memory", EndpointSnapshotFile: path, EndpointSnapshotInterval: "0s"})
+	assert.Error(t, err, "Expected invalid endpoint snapshot interval error")
+
+	_, err = NewRegistryClient(types.Config{Type: "memory", EndpointSnapshotFile: path, GetEndpointSnapshotKey: func() ([]byte, error) {
+		return []byte("too short"), nil
+	}})
+	assert.Error(t, err, "Expected invalid endpoint snapshot key error")
+
+	key := func() ([]byte, error) { return []byte("0123456789abcdef"), nil }
+	require.NoError(t, os.WriteFile(path, []byte(`{"endpoint":{}}`), 0600))
+	_, err = NewRegistryClient(types.Config{Type: "memory", EndpointSnapshotFile: path, GetEndpointSnapshotKey: key})
+	assert.Error(t, err, "Expected the unencrypted endpoint snapshot to be rejected")
+	client, err = NewRegistryClient(types.Config{Type: "memory", EndpointSnapshotFile: path, GetEndpointSnapshotKey: key, EndpointSnapshotMigrate: true})
+	require.NoError(t, err, "Expected the unencrypted endpoint snapshot to be migrated")
+	assert.IsType(t, &SnapshotClient{}, client)
+}
+
+func TestNewRegistryClientWithContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	client, err := NewRegistryClientWithContext(ctx, types.Config{
+		Type:                     "memory",
+		MemoryEndpoints:          []types.ServiceEndpoint{testEndpoint},
+		EndpointSnapshotFile:     path,
+		EndpointSnapshotInterval: "1h",
+		EndpointCacheTTL:         "5s",
+		EndpointCacheWatch:       true,
+	})
+	require.NoError(t, err)
+	_, err = client.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+
+	caching, ok := As[*CachingClient](client)
+	require.True(t, ok)
+	require.Eventually(t, func() bool {
+		caching.watchLock.Lock()
+		defer caching.watchLock.Unlock()
+		return len(caching.watched) == 1
+	}, time.Second, 10*time.Millisecond, "Expected the cached service to be watched")
+
+	cancel()
+	require.Eventually(t, func() bool {
+		contents, err := os.ReadFile(path)
+		return err == nil && strings.Contains(string(contents), testEndpoint.Host)
+	}, time.Second, 10*time.Millisecond, "Expected the snapshot to be saved once ctx is done")
+	require.Eventually(t, func() bool {
+		caching.watchLock.Lock()
+		defer caching.watchLock.Unlock()
+		return len(caching.watched) == 0
+	}, time.Second, 10*time.Millisecond, "Expected the watch to stop once ctx is done")
+}
+
+func TestNewRegistryClientFallbackEndpoints(t *testing.T) {
+	client, err := NewRegistryClient(types.Config{Type: "memory", FallbackEndpoints: map[string]string{"core-data": "10.1.2.3:59880"}})
+	if assert.Nil(t, err, "New Registry client failed: ", err) == false {
+		t.Fatal()
+	}
+	assert.IsType(t, &FallbackClient{}, client)
+
+	_, err = NewRegistryClient(types.Config{Type: "memory", FallbackEndpoints: map[string]string{"core-data": "10.1.2.3"}})
+	assert.Error(t, err, "Expected invalid fallback endpoint error")
+}
+
+func TestNewRegistryClientTracerProvider(t *testing.T) {
+	client, err := NewRegistryClient(types.Config{Type: "memory", TracerProvider: noop.NewTracerProvider()})
+	if assert.Nil(t, err, "New Registry client failed: ", err) == false {
+		t.Fatal()
+	}
+
+	assert.IsType(t, &TracingClient{}, client)
+}
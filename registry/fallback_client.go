@@ -0,0 +1,90 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// FallbackClient is a Client returning statically configured endpoints from the lookups failing with
+// ErrRegistryUnavailable, so the services keep reaching the services they depend on during Registry outages. Only the
+// lookups of the services with a fallback endpoint succeed then, the others failing as usual. NewRegistryClient wraps
+// the registry client with it when Config.FallbackEndpoints is set.
+type FallbackClient struct {
+	Client
+	fallbacks map[string]types.ServiceEndpoint
+}
+
+// NewFallbackClient wraps the given Client to return the given endpoints, by service key, while the Registry is unavailable
+func NewFallbackClient(client Client, fallbacks map[string]types.ServiceEndpoint) *FallbackClient {
+	return &FallbackClient{
+		Client:    client,
+		fallbacks: fallbacks,
+	}
+}
+
+// Unwrap returns the Client wrapped by the FallbackClient
+func (c *FallbackClient) Unwrap() Client {
+	return c.Client
+}
+
+func (c *FallbackClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *FallbackClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	endpoint, err := c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+	if fallback, found := c.fallback(serviceId, err); found {
+		return fallback, nil
+	}
+	return endpoint, err
+}
+
+func (c *FallbackClient) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceId)
+}
+
+func (c *FallbackClient) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) ([]types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+	if fallback, found := c.fallback(serviceId, err); found {
+		return []types.ServiceEndpoint{fallback}, nil
+	}
+	return endpoints, err
+}
+
+func (c *FallbackClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+// GetAllServiceEndpointsWithContext returns all the fallback endpoints, ordered by service id, while the Registry is
+// unavailable
+func (c *FallbackClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.GetAllServiceEndpointsWithContext(ctx)
+	if len(c.fallbacks) == 0 || !errors.Is(err, types.ErrRegistryUnavailable) {
+		return endpoints, err
+	}
+
+	fallbacks := make([]types.ServiceEndpoint, 0, len(c.fallbacks))
+	for _, fallback := range c.fallbacks {
+		fallbacks = append(fallbacks, fallback)
+	}
+	slices.SortFunc(fallbacks, types.ByServiceId)
+	return fallbacks, nil
+}
+
+// fallback returns the fallback endpoint of the service when the lookup failed for the Registry being unavailable
+func (c *FallbackClient) fallback(serviceId string, err error) (types.ServiceEndpoint, bool) {
+	if !errors.Is(err, types.ErrRegistryUnavailable) {
+		return types.ServiceEndpoint{}, false
+	}
+
+	fallback, found := c.fallbacks[serviceId]
+	return fallback, found
+}
@@ -0,0 +1,61 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestFallbackClientRegistryOutage(t *testing.T) {
+	unavailable := types.Errorf(types.ErrRegistryUnavailable, "connection refused")
+	fallback := types.ServiceEndpoint{ServiceId: "core-data", Host: "10.1.2.3", Port: 59880}
+	command := types.ServiceEndpoint{ServiceId: "core-command", Host: "10.1.2.4", Port: 59882}
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, mock.Anything).Return(types.ServiceEndpoint{}, unavailable)
+	client.On("GetServiceEndpointsWithContext", mock.Anything, mock.Anything).Return(nil, unavailable)
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return(nil, unavailable)
+	fallbackClient := NewFallbackClient(client, map[string]types.ServiceEndpoint{"core-data": fallback, "core-command": command})
+
+	endpoint, err := fallbackClient.GetServiceEndpoint("core-data")
+	require.NoError(t, err)
+	assert.Equal(t, fallback, endpoint)
+
+	endpoints, err := fallbackClient.GetServiceEndpoints("core-data")
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{fallback}, endpoints)
+
+	endpoints, err = fallbackClient.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{command, fallback}, endpoints)
+
+	_, err = fallbackClient.GetServiceEndpoint("core-metadata")
+	assert.ErrorIs(t, err, types.ErrRegistryUnavailable, "Expected the lookups of the services without fallback to fail")
+}
+
+func TestFallbackClientRegistryAvailable(t *testing.T) {
+	registered := types.ServiceEndpoint{ServiceId: "core-data", Host: "10.1.2.7", Port: 59880}
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, "core-data").Return(registered, nil)
+	client.On("GetServiceEndpointWithContext", mock.Anything, "core-command").Return(types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "core-command"))
+	fallbackClient := NewFallbackClient(client, map[string]types.ServiceEndpoint{
+		"core-data":    {ServiceId: "core-data", Host: "10.1.2.3", Port: 59880},
+		"core-command": {ServiceId: "core-command", Host: "10.1.2.4", Port: 59882},
+	})
+
+	endpoint, err := fallbackClient.GetServiceEndpoint("core-data")
+	require.NoError(t, err)
+	assert.Equal(t, registered, endpoint)
+
+	_, err = fallbackClient.GetServiceEndpoint("core-command")
+	assert.ErrorIs(t, err, types.ErrNotRegistered, "Expected no fallback for services the Registry reports unregistered")
+}
@@ -0,0 +1,51 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// FreshnessReporter is implemented by the Clients serving the endpoints of the services from a cache, i.e. the
+// CachingClient, telling how fresh the endpoints they serve are
+type FreshnessReporter interface {
+	// Returns the freshness of the endpoints of the target service served from the cache, false if none are
+	Freshness(serviceKey string) (types.Freshness, bool)
+}
+
+// GetServiceEndpointWithFreshness returns the endpoint GetServiceEndpoint returns along with its freshness, from the
+// cache of the client, or any Client it wraps, being a FreshnessReporter. It's the zero Freshness when the endpoint
+// isn't served from a cache.
+func GetServiceEndpointWithFreshness(ctx context.Context, client Client, serviceKey string) (types.ServiceEndpoint, types.Freshness, error) {
+	endpoint, err := client.GetServiceEndpointWithContext(ctx, serviceKey)
+	if err != nil {
+		return types.ServiceEndpoint{}, types.Freshness{}, err
+	}
+	return endpoint, freshness(client, serviceKey), nil
+}
+
+// GetServiceEndpointsWithFreshness returns the endpoints GetServiceEndpoints returns along with their freshness, like
+// GetServiceEndpointWithFreshness
+func GetServiceEndpointsWithFreshness(ctx context.Context, client Client, serviceKey string) ([]types.ServiceEndpoint, types.Freshness, error) {
+	endpoints, err := client.GetServiceEndpointsWithContext(ctx, serviceKey)
+	if err != nil {
+		return nil, types.Freshness{}, err
+	}
+	return endpoints, freshness(client, serviceKey), nil
+}
+
+// freshness is asked once the lookup returned, rather than looking up through the FreshnessReporter, so the
+// decorators wrapping it still apply to the endpoints returned, i.e. the BalancingClient
+func freshness(client Client, serviceKey string) types.Freshness {
+	reporter, ok := As[FreshnessReporter](client)
+	if !ok {
+		return types.Freshness{}
+	}
+	freshness, _ := reporter.Freshness(serviceKey)
+	return freshness
+}
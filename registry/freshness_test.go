@@ -0,0 +1,51 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestGetServiceEndpointWithFreshness(t *testing.T) {
+	client := &serverClockClient{Client: &mocks.Client{}, now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+	client.On("GetServiceEndpointsWithContext", mock.Anything, testEndpoint.ServiceId).Return([]types.ServiceEndpoint{testEndpoint}, nil)
+	ctx := context.Background()
+
+	_, freshness, err := GetServiceEndpointWithFreshness(ctx, client, testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, types.Freshness{}, freshness, "Expected the endpoint looked up right away without a cache")
+
+	cachingClient := NewCachingClient(client, time.Minute, time.Hour)
+	endpoint, freshness, err := GetServiceEndpointWithFreshness(ctx, NewBalancingClient(cachingClient, types.RoundRobin()), testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpoint, endpoint)
+	assert.Equal(t, types.Freshness{TTL: time.Minute}, freshness, "Expected the freshness to be found behind the decorators")
+
+	client.now = client.now.Add(20 * time.Second)
+	endpoints, freshness, err := GetServiceEndpointsWithFreshness(ctx, cachingClient, testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{testEndpoint}, endpoints)
+	assert.Equal(t, types.Freshness{Age: 20 * time.Second, TTL: 40 * time.Second}, freshness, "Expected the cached endpoints to age by the clock")
+
+	client.now = client.now.Add(time.Minute)
+	freshness, found := cachingClient.Freshness(testEndpoint.ServiceId)
+	require.True(t, found)
+	assert.Equal(t, types.Freshness{Age: 80 * time.Second, Stale: true}, freshness)
+
+	cachingClient.Invalidate(testEndpoint.ServiceId)
+	_, found = cachingClient.Freshness(testEndpoint.ServiceId)
+	assert.False(t, found)
+}
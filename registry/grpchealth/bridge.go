@@ -0,0 +1,74 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package grpchealth exposes the health of the services discovered through the registry client over the standard
+// grpc.health.v1 protocol, so Kubernetes gRPC probes or Envoy health checking can consume the EdgeX Registry state
+// natively. It is a separate package so only the services using it depend on gRPC.
+package grpchealth
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/registry"
+)
+
+// Bridge feeds a grpc.health.v1 health server with the health of the given services as seen by the registry client.
+// Each service is SERVING while the Registry reports it available, i.e. registered and healthy, and NOT_SERVING
+// otherwise, and keeps its last status while its availability is unknown, i.e. the Registry being unavailable. The
+// overall health, queried with an empty service name, is SERVING while the Registry is reachable.
+type Bridge struct {
+	poller   *registry.AvailabilityPoller
+	services []string
+	server   *health.Server
+}
+
+// NewBridge creates the bridge of the given services, which are updated every pollInterval once Run is called. All the
+// services are NOT_SERVING until the first update.
+func NewBridge(client registry.Client, services []string, pollInterval time.Duration) *Bridge {
+	bridge := Bridge{
+		poller:   registry.NewAvailabilityPoller(client, services, pollInterval),
+		services: append([]string(nil), services...),
+		server:   health.NewServer(),
+	}
+
+	bridge.server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	for _, serviceKey := range bridge.services {
+		bridge.server.SetServingStatus(serviceKey, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return &bridge
+}
+
+// Server returns the health server fed by the bridge, to be registered on a gRPC server with
+// healthpb.RegisterHealthServer. Services not bridged are reported as unknown with a NotFound status.
+func (b *Bridge) Server() healthpb.HealthServer {
+	return b.server
+}
+
+// Run updates the health right away, then every pollInterval until ctx is cancelled. All the services are then
+// NOT_SERVING, and watchers notified so.
+func (b *Bridge) Run(ctx context.Context) {
+	b.poller.Run(ctx, b.update)
+	b.server.Shutdown()
+}
+
+func (b *Bridge) update(poll registry.AvailabilityPoll) {
+	b.server.SetServingStatus("", servingStatus(poll.RegistryUp))
+
+	for serviceKey, available := range poll.Available {
+		b.server.SetServingStatus(serviceKey, servingStatus(available))
+	}
+}
+
+func servingStatus(up bool) healthpb.HealthCheckResponse_ServingStatus {
+	if up {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,94 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpchealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+const testPollInterval = 10 * time.Millisecond
+
+func TestBridge(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(true)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(true, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-command").Return(false, errors.New(" core-command service not healthy..."))
+	bridge := NewBridge(client, []string{"core-data", "core-command"}, testPollInterval)
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(t, bridge, "core-data"), "Expected NOT_SERVING before the first update")
+
+	bridge.update(bridge.poller.Poll(context.Background()))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(t, bridge, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(t, bridge, "core-data"))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(t, bridge, "core-command"))
+
+	_, err := bridge.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: "core-metadata"})
+	require.Error(t, err, "Expected services not bridged to be unknown")
+}
+
+func TestBridgeRegistryDown(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(false)
+	bridge := NewBridge(client, []string{"core-data"}, testPollInterval)
+
+	bridge.update(bridge.poller.Poll(context.Background()))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(t, bridge, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(t, bridge, "core-data"), "Expected the status before the Registry went down")
+	client.AssertNotCalled(t, "IsServiceAvailableWithContext", mock.Anything, mock.Anything)
+}
+
+func TestBridgeAvailabilityUnknown(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(true)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(true, nil).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(false, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+	bridge := NewBridge(client, []string{"core-data"}, testPollInterval)
+
+	bridge.update(bridge.poller.Poll(context.Background()))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, check(t, bridge, "core-data"))
+
+	bridge.update(bridge.poller.Poll(context.Background()))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(t, bridge, "core-data"), "Expected the last status while the availability is unknown")
+}
+
+func TestBridgeRun(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAliveWithContext", mock.Anything).Return(true)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(true, nil)
+	bridge := NewBridge(client, []string{"core-data"}, testPollInterval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		bridge.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return check(t, bridge, "core-data") == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, testPollInterval)
+
+	cancel()
+	<-done
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(t, bridge, "core-data"), "Expected NOT_SERVING once stopped")
+}
+
+func check(t *testing.T, bridge *Bridge, service string) healthpb.HealthCheckResponse_ServingStatus {
+	response, err := bridge.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return response.Status
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultHeartbeatInterval = 5 * time.Second
+
+// HeartbeatConfig configures a Heartbeater
+type HeartbeatConfig struct {
+	// Interval between heartbeats, to be shorter than the CheckInterval of the ttl health check so it doesn't expire
+	// in between. Defaults to 5s.
+	Interval time.Duration
+	// Check optionally checks the current service before each heartbeat, which reports it unhealthy with the error
+	// returned, if any, rather than healthy
+	Check func(ctx context.Context) error
+	// OnFailure is optionally called with the error of each failed heartbeat, whether the service failed its Check or
+	// its health couldn't be reported to the Registry
+	OnFailure func(err error)
+}
+
+// Heartbeater pushes the health of the current service to the Registry every Interval, for services registered with
+// the ttl health check type, i.e. the ones behind a firewall the Registry can't check them through.
+type Heartbeater struct {
+	reporter TTLReporter
+	config   HeartbeatConfig
+	lock     sync.Mutex
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewHeartbeater creates the Heartbeater of the current service of the reporter, started with Start. The reporter of a
+// Client returned by NewRegistryClient is found with As[TTLReporter], whichever decorators wrap it.
+func NewHeartbeater(reporter TTLReporter, config HeartbeatConfig) *Heartbeater {
+	if config.Interval <= 0 {
+		config.Interval = defaultHeartbeatInterval
+	}
+	return &Heartbeater{reporter: reporter, config: config}
+}
+
+// Start sends a heartbeat right away, then every Interval in the background until Stop is called. Returns an error if
+// already started.
+func (h *Heartbeater) Start() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.cancel != nil {
+		return errors.New("heartbeater already started")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.done = make(chan struct{})
+	go h.run(ctx, h.done)
+	return nil
+}
+
+// Stop stops sending heartbeats and waits for the one in flight, if any. The service becomes unhealthy once its last
+// heartbeat expires, unless unregistered beforehand. Does nothing if not started.
+func (h *Heartbeater) Stop() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.cancel == nil {
+		return
+	}
+	h.cancel()
+	<-h.done
+	h.cancel = nil
+	h.done = nil
+}
+
+func (h *Heartbeater) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.beat(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// beat reports the health of the current service once, giving up after Interval so heartbeats don't pile up
+func (h *Heartbeater) beat(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, h.config.Interval)
+	defer cancel()
+
+	var err error
+	if checkErr := h.check(ctx); checkErr != nil {
+		err = fmt.Errorf("service failed its health check: %w", checkErr)
+		if reportErr := h.reporter.FailTTL(ctx, checkErr.Error()); reportErr != nil {
+			err = errors.Join(err, fmt.Errorf("unable to report service unhealthy: %w", reportErr))
+		}
+	} else if reportErr := h.reporter.PassTTL(ctx); reportErr != nil {
+		err = fmt.Errorf("unable to report service healthy: %w", reportErr)
+	}
+
+	// Heartbeats interrupted by Stop aren't failures
+	if err != nil && ctx.Err() != context.Canceled && h.config.OnFailure != nil {
+		h.config.OnFailure(err)
+	}
+}
+
+func (h *Heartbeater) check(ctx context.Context) error {
+	if h.config.Check == nil {
+		return nil
+	}
+	return h.config.Check(ctx)
+}
@@ -0,0 +1,111 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingReporter struct {
+	lock    sync.Mutex
+	passes  int
+	fails   []string
+	failErr error
+}
+
+func (r *recordingReporter) PassTTL(_ context.Context) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.passes++
+	return r.failErr
+}
+
+func (r *recordingReporter) FailTTL(_ context.Context, output string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.fails = append(r.fails, output)
+	return r.failErr
+}
+
+func (r *recordingReporter) counts() (int, int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.passes, len(r.fails)
+}
+
+func TestHeartbeaterPasses(t *testing.T) {
+	reporter := &recordingReporter{}
+	heartbeater := NewHeartbeater(reporter, HeartbeatConfig{Interval: testPollInterval})
+
+	require.NoError(t, heartbeater.Start())
+	require.Error(t, heartbeater.Start(), "Expected error starting heartbeater twice")
+	require.Eventually(t, func() bool {
+		passes, _ := reporter.counts()
+		return passes >= 3
+	}, time.Second, testPollInterval)
+	heartbeater.Stop()
+
+	passes, fails := reporter.counts()
+	time.Sleep(3 * testPollInterval)
+	stoppedPasses, _ := reporter.counts()
+	assert.Equal(t, passes, stoppedPasses, "Expected no heartbeat once stopped")
+	assert.Zero(t, fails)
+
+	// Restartable once stopped
+	require.NoError(t, heartbeater.Start())
+	heartbeater.Stop()
+}
+
+func TestHeartbeaterCheckFailure(t *testing.T) {
+	reporter := &recordingReporter{}
+	failures := make(chan error, 10)
+	heartbeater := NewHeartbeater(reporter, HeartbeatConfig{
+		Interval:  time.Hour,
+		Check:     func(ctx context.Context) error { return errors.New("database unreachable") },
+		OnFailure: func(err error) { failures <- err },
+	})
+
+	require.NoError(t, heartbeater.Start())
+	defer heartbeater.Stop()
+
+	err := <-failures
+	require.ErrorContains(t, err, "database unreachable")
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+	assert.Zero(t, reporter.passes)
+	assert.Equal(t, []string{"database unreachable"}, reporter.fails)
+}
+
+func TestHeartbeaterReportFailure(t *testing.T) {
+	registryErr := errors.New("registry unreachable")
+	reporter := &recordingReporter{failErr: registryErr}
+	failures := make(chan error, 10)
+	heartbeater := NewHeartbeater(reporter, HeartbeatConfig{
+		Interval:  time.Hour,
+		OnFailure: func(err error) { failures <- err },
+	})
+
+	require.NoError(t, heartbeater.Start())
+	defer heartbeater.Stop()
+
+	require.ErrorIs(t, <-failures, registryErr)
+}
+
+func TestHeartbeaterStopNotStarted(t *testing.T) {
+	heartbeater := NewHeartbeater(&recordingReporter{}, HeartbeatConfig{})
+	heartbeater.Stop()
+	assert.Equal(t, defaultHeartbeatInterval, heartbeater.config.Interval)
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	defaultInstanceFailureThreshold = 3
+	defaultInstanceCooldown         = 30 * time.Second
+)
+
+// SharedInstanceHealth is the InstanceHealth shared by every BalancingClient of the process, so the instance one
+// component found failing is avoided by all the others rather than each of them rediscovering the failure
+var SharedInstanceHealth = NewInstanceHealth(defaultInstanceFailureThreshold, defaultInstanceCooldown)
+
+// InstanceHealth keeps the circuit state of the instances of the services called, as reported by their callers: an
+// instance failing threshold consecutive calls is left out by the BalancingClient for the cooldown, after which it's
+// selected again, the circuit opening again for another cooldown if the next call fails too.
+type InstanceHealth struct {
+	threshold int
+	cooldown  time.Duration
+	lock      sync.Mutex
+	instances map[string]*instanceCircuit
+}
+
+type instanceCircuit struct {
+	failures    int
+	lastFailure time.Time
+}
+
+// NewInstanceHealth creates an InstanceHealth opening the circuit of an instance for the cooldown once threshold
+// consecutive calls to it failed
+func NewInstanceHealth(threshold int, cooldown time.Duration) *InstanceHealth {
+	return &InstanceHealth{
+		threshold: threshold,
+		cooldown:  cooldown,
+		instances: make(map[string]*instanceCircuit),
+	}
+}
+
+// ReportFailure records a call to the instance of the given endpoint which failed, i.e. which couldn't connect or was
+// answered with a 502, 503 or 504 status code
+func (h *InstanceHealth) ReportFailure(endpoint types.ServiceEndpoint) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	now := time.Now()
+	h.prune(now)
+
+	circuit, found := h.instances[instanceCircuitKey(endpoint)]
+	if !found {
+		circuit = &instanceCircuit{}
+		h.instances[instanceCircuitKey(endpoint)] = circuit
+	}
+	circuit.failures++
+	circuit.lastFailure = now
+}
+
+// ReportSuccess records a call to the instance of the given endpoint which succeeded, closing its circuit
+func (h *InstanceHealth) ReportSuccess(endpoint types.ServiceEndpoint) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	delete(h.instances, instanceCircuitKey(endpoint))
+}
+
+// IsOpen tells whether the circuit of the instance of the given endpoint is open, the instance to be avoided
+func (h *InstanceHealth) IsOpen(endpoint types.ServiceEndpoint) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	circuit, found := h.instances[instanceCircuitKey(endpoint)]
+	return found && circuit.failures >= h.threshold && time.Since(circuit.lastFailure) < h.cooldown
+}
+
+// prune forgets the instances which didn't fail for twice the cooldown, so the instances which went away aren't kept
+// forever
+func (h *InstanceHealth) prune(now time.Time) {
+	for key, circuit := range h.instances {
+		if now.Sub(circuit.lastFailure) >= 2*h.cooldown {
+			delete(h.instances, key)
+		}
+	}
+}
+
+// instanceCircuitKey identifies the instance of an endpoint, also by its address so an instance registering again
+// elsewhere starts with a closed circuit
+func instanceCircuitKey(endpoint types.ServiceEndpoint) string {
+	return endpoint.ServiceId + "/" + endpoint.InstanceId + "/" + net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))
+}
@@ -0,0 +1,63 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestInstanceHealth(t *testing.T) {
+	cooldown := 50 * time.Millisecond
+	health := NewInstanceHealth(2, cooldown)
+	instance := types.ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-0", Host: "10.0.0.1", Port: 59880}
+	moved := instance
+	moved.Host = "10.0.0.2"
+
+	health.ReportFailure(instance)
+	assert.False(t, health.IsOpen(instance), "Expected the circuit to stay closed below the threshold")
+	health.ReportFailure(instance)
+	assert.True(t, health.IsOpen(instance))
+	assert.False(t, health.IsOpen(moved), "Expected the instance registered elsewhere to have its own circuit")
+
+	require.Eventually(t, func() bool { return !health.IsOpen(instance) }, 10*cooldown, cooldown/10, "Expected the circuit to half-open after the cooldown")
+	health.ReportFailure(instance)
+	assert.True(t, health.IsOpen(instance), "Expected the half-open circuit to open again on the next failure")
+
+	health.ReportSuccess(instance)
+	assert.False(t, health.IsOpen(instance))
+}
+
+func TestBalancingClientInstanceHealth(t *testing.T) {
+	replica0 := types.ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-0", Host: "10.0.0.1", Port: 59880}
+	replica1 := types.ServiceEndpoint{ServiceId: "core-data", InstanceId: "core-data-1", Host: "10.0.0.2", Port: 59880}
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointsWithContext", mock.Anything, "core-data").Return([]types.ServiceEndpoint{replica0, replica1}, nil)
+	health := NewInstanceHealth(1, time.Minute)
+	first := NewBalancingClient(client, types.RoundRobin())
+	first.health = health
+	second := NewBalancingClient(client, types.RoundRobin())
+	second.health = health
+
+	health.ReportFailure(replica0)
+	for _, balancingClient := range []*BalancingClient{first, second, first} {
+		endpoint, err := balancingClient.GetServiceEndpoint("core-data")
+		require.NoError(t, err)
+		assert.Equal(t, replica1, endpoint, "Expected every client sharing the health to avoid the failing instance")
+	}
+
+	health.ReportFailure(replica1)
+	endpoint, err := first.GetServiceEndpoint("core-data")
+	require.NoError(t, err, "Expected the failing instances to be tried anyway when all of them are failing")
+	assert.Contains(t, []types.ServiceEndpoint{replica0, replica1}, endpoint)
+}
@@ -17,28 +17,112 @@
 package registry
 
 import (
+	"context"
+
 	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
 )
 
+// Client is mocked for the unit tests of services by mocks.Client, which is to be regenerated whenever the interface
+// changes.
+//
+//go:generate mockery --name=Client --output=./mocks --outpkg=mocks
 type Client interface {
 	// Registers the current service with Registry for discover and health check
 	Register() error
 
+	// Same as Register, but aborts once ctx is done
+	RegisterWithContext(ctx context.Context) error
+
 	// Un-registers the current service with Registry for discover and health check
 	Unregister() error
 
+	// Same as Unregister, but aborts once ctx is done
+	UnregisterWithContext(ctx context.Context) error
+
+	// Permanently retires the target service, i.e. takes it out of service and then removes its registration from the Registry.
+	// DecommissionClient retires it as a single audited operation, draining, tombstoning and notifying it
+	Decommission(ctx context.Context, serviceKey string) error
+
+	// Watches the registration of the current service and notifies when it is modified or deleted by someone else,
+	// until ctx is cancelled
+	WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error)
+
+	// Watches the endpoint of the target service and sends it each time it changes, starting with the current one, until
+	// ctx is cancelled. An empty endpoint is sent when the service isn't registered
+	WatchService(ctx context.Context, serviceId string) (<-chan types.ServiceEndpoint, error)
+
 	// Registers a
 	RegisterCheck(id string, name string, notes string, url string, interval string) error
 
+	// Same as RegisterCheck, but aborts once ctx is done
+	RegisterCheckWithContext(ctx context.Context, id string, name string, notes string, url string, interval string) error
+
+	// Runs the health check of the target service right away instead of waiting for the next scheduled check, and returns its result
+	TriggerHealthCheck(ctx context.Context, serviceId string) (types.HealthCheckResult, error)
+
 	// Simply checks if Registry is up and running at the configured URL
 	IsAlive() bool
 
+	// Same as IsAlive, but gives up once ctx is done
+	IsAliveWithContext(ctx context.Context) bool
+
 	// Gets the service endpoint information for the target ID from the Registry
 	GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error)
 
+	// Same as GetServiceEndpoint, but aborts once ctx is done
+	GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error)
+
+	// Gets the endpoints of all the registered instances of the target service from the Registry, i.e. the replicas of
+	// a horizontally scaled service, each with its InstanceId
+	GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error)
+
+	// Same as GetServiceEndpoints, but aborts once ctx is done
+	GetServiceEndpointsWithContext(ctx context.Context, serviceId string) ([]types.ServiceEndpoint, error)
+
 	// Gets all the service endpoints information from the Registry
 	GetAllServiceEndpoints() ([]types.ServiceEndpoint, error)
 
+	// Same as GetAllServiceEndpoints, but aborts once ctx is done
+	GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error)
+
 	// Checks with the Registry if the target service is available, i.e. registered and healthy
 	IsServiceAvailable(serviceId string) (bool, error)
+
+	// Same as IsServiceAvailable, but aborts once ctx is done
+	IsServiceAvailableWithContext(ctx context.Context, serviceId string) (bool, error)
+}
+
+// TTLReporter is implemented by the Clients of the registry types supporting the ttl health check type, i.e. keeper and
+// consul, for the current service to push its health, i.e. with a Heartbeater. It is found with As behind the Client
+// decorators returned by NewRegistryClient, i.e. the TracingClient when a TracerProvider is set.
+type TTLReporter interface {
+	// Reports the current service healthy for the next CheckInterval
+	PassTTL(ctx context.Context) error
+
+	// Reports the current service unhealthy right away, with the given output where the Registry keeps it
+	FailTTL(ctx context.Context, output string) error
+}
+
+// unwrapper is implemented by the Client decorators, i.e. the CachingClient, returning the Client they wrap
+type unwrapper interface {
+	Unwrap() Client
+}
+
+// As finds the first Client implementing the optional capability T, i.e. TTLReporter, in the chain of decorators of
+// client, starting with client itself then following Unwrap, like errors.As does for wrapped errors. The capability
+// found is used as is, bypassing the decorators in front of it, i.e. without being traced or cached.
+func As[T any](client Client) (T, bool) {
+	for client != nil {
+		if capability, ok := client.(T); ok {
+			return capability, true
+		}
+		wrapper, ok := client.(unwrapper)
+		if !ok {
+			break
+		}
+		client = wrapper.Unwrap()
+	}
+
+	var none T
+	return none, false
 }
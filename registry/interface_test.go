@@ -0,0 +1,46 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+// The published mock must be regenerated whenever the Client interface changes
+var _ Client = (*mocks.Client)(nil)
+
+// ttlReportingClient is a Client of a registry type supporting the ttl health check type
+type ttlReportingClient struct {
+	*mocks.Client
+	recordingReporter
+}
+
+func TestAs(t *testing.T) {
+	backend := &ttlReportingClient{Client: &mocks.Client{}}
+	var client Client = NewCachingClient(NewFallbackClient(backend, nil), time.Minute, 0)
+	client = NewTracingClient(client, sdktrace.NewTracerProvider(), "keeper", "core-data")
+
+	reporter, ok := As[TTLReporter](client)
+	require.True(t, ok, "Expected the TTLReporter to be found behind the decorators")
+	require.NoError(t, reporter.PassTTL(context.Background()))
+	passes, _ := backend.counts()
+	assert.Equal(t, 1, passes)
+
+	caching, ok := As[*CachingClient](client)
+	require.True(t, ok)
+	assert.Same(t, client.(*TracingClient).Client, caching)
+
+	_, ok = As[TTLReporter](NewCachingClient(&mocks.Client{}, time.Minute, 0))
+	assert.False(t, ok, "Expected no TTLReporter for the registry types not supporting it")
+}
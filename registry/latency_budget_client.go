@@ -0,0 +1,114 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// LatencyBudgetClient is a Client bounding the latency of service endpoint lookups on the hot path. A lookup not
+// answered by the Registry within the latency budget returns the endpoint found by the last successful lookup of the
+// service instead, flagged as stale by GetServiceEndpointWithBudget. The live lookup carries on in the background and
+// refreshes the cached endpoint once answered. Concurrent lookups of a service share a single live lookup, run with the
+// context of the client until Close is called.
+type LatencyBudgetClient struct {
+	Client
+	maxWait time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+	lookups singleflight.Group
+	lock    sync.RWMutex
+	cache   map[string]types.ServiceEndpoint
+}
+
+// NewLatencyBudgetClient wraps the given Client to wait at most maxWait for the Registry when a cached endpoint is
+// available. Lookups of services not looked up successfully before wait for the Registry as long as it takes.
+func NewLatencyBudgetClient(client Client, maxWait time.Duration) *LatencyBudgetClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &LatencyBudgetClient{
+		Client:  client,
+		maxWait: maxWait,
+		ctx:     ctx,
+		cancel:  cancel,
+		cache:   make(map[string]types.ServiceEndpoint),
+	}
+}
+
+// Unwrap returns the Client wrapped by the LatencyBudgetClient
+func (c *LatencyBudgetClient) Unwrap() Client {
+	return c.Client
+}
+
+// Close aborts the live lookups in flight, and the ones started afterward
+func (c *LatencyBudgetClient) Close() {
+	c.cancel()
+}
+
+func (c *LatencyBudgetClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *LatencyBudgetClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	endpoint, _, err := c.GetServiceEndpointWithBudget(ctx, serviceId)
+	return endpoint, err
+}
+
+// GetServiceEndpointWithBudget races the live lookup of the target service against the latency budget, returning the
+// cached endpoint flagged as stale if the Registry doesn't answer in time. Errors are only returned once the Registry
+// answered, or ctx is done before a cached endpoint could be returned. The live lookup outlives ctx, being shared with
+// the concurrent lookups of the service. When ctx carries the authentication data of the caller though, i.e. an access
+// token, the Registry is to authorize the caller itself, so the lookup is neither shared nor answered from the cache.
+func (c *LatencyBudgetClient) GetServiceEndpointWithBudget(ctx context.Context, serviceId string) (types.ServiceEndpoint, bool, error) {
+	if hasRequestAuth(ctx) {
+		endpoint, err := c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+		return endpoint, false, err
+	}
+
+	result := c.lookups.DoChan(serviceId, func() (any, error) {
+		endpoint, err := c.Client.GetServiceEndpointWithContext(c.ctx, serviceId)
+		if err == nil {
+			c.lock.Lock()
+			c.cache[serviceId] = endpoint
+			c.lock.Unlock()
+		}
+		return endpoint, err
+	})
+
+	c.lock.RLock()
+	cached, found := c.cache[serviceId]
+	c.lock.RUnlock()
+
+	var budget <-chan time.Time
+	if found {
+		timer := time.NewTimer(c.maxWait)
+		defer timer.Stop()
+		budget = timer.C
+	}
+
+	select {
+	case lookup := <-result:
+		endpoint, _ := lookup.Val.(types.ServiceEndpoint)
+		return endpoint, false, lookup.Err
+	case <-budget:
+		return cached, true, nil
+	case <-ctx.Done():
+		return types.ServiceEndpoint{}, false, ctx.Err()
+	}
+}
+
+// hasRequestAuth tells whether ctx overrides the authentication data of the requests, with WithAccessToken or
+// WithAuthInjector
+func hasRequestAuth(ctx context.Context) bool {
+	_, hasAccessToken := types.AccessTokenFromContext(ctx)
+	_, hasAuthInjector := types.AuthInjectorFromContext(ctx)
+	return hasAccessToken || hasAuthInjector
+}
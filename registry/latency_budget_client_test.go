@@ -0,0 +1,151 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+const testMaxWait = 20 * time.Millisecond
+
+func TestLatencyBudgetClient(t *testing.T) {
+	moved := testEndpoint
+	moved.Host = "edgex-core-data-2"
+
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(moved, nil).After(5 * testMaxWait).Once()
+	budgetClient := NewLatencyBudgetClient(client, testMaxWait)
+
+	endpoint, stale, err := budgetClient.GetServiceEndpointWithBudget(context.Background(), testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpoint, endpoint)
+	assert.False(t, stale)
+
+	start := time.Now()
+	endpoint, stale, err = budgetClient.GetServiceEndpointWithBudget(context.Background(), testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpoint, endpoint)
+	assert.True(t, stale, "Expected cached endpoint once the latency budget is exceeded")
+	assert.Less(t, time.Since(start), 5*testMaxWait)
+
+	require.Eventually(t, func() bool {
+		budgetClient.lock.RLock()
+		defer budgetClient.lock.RUnlock()
+		return budgetClient.cache[testEndpoint.ServiceId].Equal(moved)
+	}, time.Second, testMaxWait, "Expected the slow lookup to refresh the cache in the background")
+}
+
+func TestLatencyBudgetClientNotCached(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).After(2 * testMaxWait).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, "core-command").Return(types.ServiceEndpoint{}, errors.New("no matching service endpoint found"))
+	budgetClient := NewLatencyBudgetClient(client, testMaxWait)
+
+	endpoint, err := budgetClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err, "Expected lookup without cached endpoint to wait for the Registry")
+	assert.Equal(t, testEndpoint, endpoint)
+
+	_, stale, err := budgetClient.GetServiceEndpointWithBudget(context.Background(), "core-command")
+	require.Error(t, err)
+	assert.False(t, stale)
+}
+
+func TestLatencyBudgetClientSharesLookups(t *testing.T) {
+	release := make(chan struct{})
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).
+		Run(func(mock.Arguments) { <-release }).Return(plantEndpoint, nil).Once()
+	budgetClient := NewLatencyBudgetClient(client, testMaxWait)
+	defer budgetClient.Close()
+
+	_, err := budgetClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+
+	// The live lookup is held until all the lookups returned the cached endpoint, so they all share it
+	var lookups sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		lookups.Add(1)
+		go func() {
+			defer lookups.Done()
+			_, stale, err := budgetClient.GetServiceEndpointWithBudget(context.Background(), testEndpoint.ServiceId)
+			assert.NoError(t, err)
+			assert.True(t, stale)
+		}()
+	}
+	lookups.Wait()
+	close(release)
+
+	require.Eventually(t, func() bool {
+		budgetClient.lock.RLock()
+		defer budgetClient.lock.RUnlock()
+		return budgetClient.cache[testEndpoint.ServiceId].Equal(plantEndpoint)
+	}, time.Second, testMaxWait)
+	client.AssertNumberOfCalls(t, "GetServiceEndpointWithContext", 2)
+}
+
+func TestLatencyBudgetClientLookupOutlivesCaller(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).After(2 * testMaxWait).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(types.ServiceEndpoint{}, context.Canceled)
+	budgetClient := NewLatencyBudgetClient(client, testMaxWait)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := budgetClient.GetServiceEndpointWithBudget(ctx, testEndpoint.ServiceId)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Eventually(t, func() bool {
+		budgetClient.lock.RLock()
+		defer budgetClient.lock.RUnlock()
+		return budgetClient.cache[testEndpoint.ServiceId].Equal(testEndpoint)
+	}, time.Second, testMaxWait, "Expected the lookup to complete once its caller is gone")
+
+	endpoint, stale, err := budgetClient.GetServiceEndpointWithBudget(context.Background(), testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.True(t, stale)
+	assert.Equal(t, testEndpoint, endpoint)
+
+	budgetClient.Close()
+	_, err = budgetClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.ErrorIs(t, err, context.Canceled, "Expected Close to abort the lookup in flight")
+	client.AssertExpectations(t)
+}
+
+func TestLatencyBudgetClientRequestAuth(t *testing.T) {
+	ctx := types.WithAccessToken(context.Background(), "caller-token")
+	withCallerToken := mock.MatchedBy(func(lookupCtx context.Context) bool {
+		accessToken, ok := types.AccessTokenFromContext(lookupCtx)
+		return ok && accessToken == "caller-token"
+	})
+
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+	client.On("GetServiceEndpointWithContext", withCallerToken, testEndpoint.ServiceId).
+		Return(types.ServiceEndpoint{}, types.Errorf(types.ErrUnauthorized, "token rejected")).After(5 * testMaxWait).Once()
+	budgetClient := NewLatencyBudgetClient(client, testMaxWait)
+
+	_, err := budgetClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+
+	_, stale, err := budgetClient.GetServiceEndpointWithBudget(ctx, testEndpoint.ServiceId)
+	require.ErrorIs(t, err, types.ErrUnauthorized, "Expected the lookup to be made with the token of the caller")
+	assert.False(t, stale, "Expected the cached endpoint not to be served to the caller with its own token")
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,311 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	// OperationsMetricName is the name of the metrics of the registry operations returned by MetricsClient.GetMetrics,
+	// one per operation tagged with the operation name
+	OperationsMetricName = "RegistryOperations"
+	// CacheMetricName is the name of the metric of the endpoint cache returned by MetricsClient.GetMetrics
+	CacheMetricName = "RegistryEndpointCache"
+
+	defaultMetricsLatencyWindow = 1024
+)
+
+// latencyPercentiles are the percentiles of the latency reported for each operation, by field name
+var latencyPercentiles = []struct {
+	field      string
+	percentile float64
+}{
+	{"latencyP50", 0.50},
+	{"latencyP95", 0.95},
+	{"latencyP99", 0.99},
+}
+
+// transportFailureFields are the fields counting the operations failing to reach the Registry, by kind of failure
+var transportFailureFields = []struct {
+	field   string
+	failure types.TransportFailure
+}{
+	{"dnsNotFoundFailureCount", types.TransportFailureDNSNotFound},
+	{"dnsFailureCount", types.TransportFailureDNS},
+	{"connectionRefusedFailureCount", types.TransportFailureConnectionRefused},
+	{"tlsFailureCount", types.TransportFailureTLS},
+	{"timeoutFailureCount", types.TransportFailureTimeout},
+	{"circuitOpenFailureCount", types.TransportFailureCircuitOpen},
+	{"otherTransportFailureCount", types.TransportFailureOther},
+}
+
+// budgetLookup is implemented by the Clients serving endpoint lookups from a cache, i.e. LatencyBudgetClient
+type budgetLookup interface {
+	GetServiceEndpointWithBudget(ctx context.Context, serviceId string) (types.ServiceEndpoint, bool, error)
+}
+
+// MetricsClient is a Client counting the successes and failures of the Register, Unregister and lookup operations and
+// measuring their latency, and reporting them as EdgeX metrics so services can publish the registry health to the
+// EdgeX metrics pipeline. When wrapping a LatencyBudgetClient, the lookups returning its cached endpoint are counted
+// as cache hits. The notifications of the watches are measured too, from the change being detected until the
+// subscriber receives them, as the WatchSelfNotification and WatchServiceNotification operations, the notifications
+// not received before the watch got cancelled being counted as failures.
+type MetricsClient struct {
+	Client
+	lock       sync.Mutex
+	operations map[string]*operationMetrics
+	cacheHits  uint64
+	cacheMiss  uint64
+}
+
+type operationMetrics struct {
+	successes         uint64
+	failures          uint64
+	transportFailures map[types.TransportFailure]uint64
+	count             uint64
+	total             time.Duration
+	min               time.Duration
+	max               time.Duration
+	recent            []time.Duration
+	next              int
+}
+
+// NewMetricsClient wraps the given Client to measure its operations
+func NewMetricsClient(client Client) *MetricsClient {
+	return &MetricsClient{
+		Client:     client,
+		operations: make(map[string]*operationMetrics),
+	}
+}
+
+// Unwrap returns the Client wrapped by the MetricsClient
+func (c *MetricsClient) Unwrap() Client {
+	return c.Client
+}
+
+// GetMetrics returns the metrics of the operations performed so far, to be published i.e. on the EdgeX telemetry topic.
+// Each operation metric has the successCount, failureCount, latencyMin, latencyMax, latencyMean, latencyP50,
+// latencyP95 and latencyP99 fields, in nanoseconds for the latencies, the percentiles being computed over the 1024 most
+// recent operations. The failures to reach the Registry are also counted by kind of types.TransportFailure, in the
+// dnsNotFoundFailureCount, dnsFailureCount, connectionRefusedFailureCount, tlsFailureCount, timeoutFailureCount and
+// otherTransportFailureCount fields. The cache metric, only returned when wrapping a LatencyBudgetClient, has the hitCount, missCount
+// and hitRate fields.
+func (c *MetricsClient) GetMetrics() []dtos.Metric {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	names := make([]string, 0, len(c.operations))
+	for name := range c.operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metrics := make([]dtos.Metric, 0, len(names)+1)
+	for _, name := range names {
+		metric, err := dtos.NewMetric(OperationsMetricName, c.operations[name].fields(), []dtos.MetricTag{{Name: "operation", Value: name}})
+		if err == nil {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	if _, ok := c.Client.(budgetLookup); ok {
+		hitRate := 0.0
+		if lookups := c.cacheHits + c.cacheMiss; lookups > 0 {
+			hitRate = float64(c.cacheHits) / float64(lookups)
+		}
+		metric, err := dtos.NewMetric(CacheMetricName, []dtos.MetricField{
+			{Name: "hitCount", Value: c.cacheHits},
+			{Name: "missCount", Value: c.cacheMiss},
+			{Name: "hitRate", Value: hitRate},
+		}, nil)
+		if err == nil {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics
+}
+
+func (c *MetricsClient) observe(operation string, start time.Time, err error) {
+	elapsed := time.Since(start)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	metrics, ok := c.operations[operation]
+	if !ok {
+		metrics = &operationMetrics{
+			transportFailures: make(map[types.TransportFailure]uint64),
+			recent:            make([]time.Duration, 0, defaultMetricsLatencyWindow),
+		}
+		c.operations[operation] = metrics
+	}
+	metrics.record(elapsed, err)
+}
+
+func (m *operationMetrics) record(elapsed time.Duration, err error) {
+	if err != nil {
+		m.failures++
+		if failure := types.ClassifyTransportFailure(err); failure != "" {
+			m.transportFailures[failure]++
+		}
+	} else {
+		m.successes++
+	}
+
+	if m.count == 0 || elapsed < m.min {
+		m.min = elapsed
+	}
+	if elapsed > m.max {
+		m.max = elapsed
+	}
+	m.count++
+	m.total += elapsed
+
+	if len(m.recent) < cap(m.recent) {
+		m.recent = append(m.recent, elapsed)
+		return
+	}
+	m.recent[m.next] = elapsed
+	m.next = (m.next + 1) % len(m.recent)
+}
+
+func (m *operationMetrics) fields() []dtos.MetricField {
+	fields := []dtos.MetricField{
+		{Name: "successCount", Value: m.successes},
+		{Name: "failureCount", Value: m.failures},
+	}
+	for _, f := range transportFailureFields {
+		fields = append(fields, dtos.MetricField{Name: f.field, Value: m.transportFailures[f.failure]})
+	}
+	fields = append(fields, []dtos.MetricField{
+		{Name: "latencyMin", Value: m.min.Nanoseconds()},
+		{Name: "latencyMax", Value: m.max.Nanoseconds()},
+		{Name: "latencyMean", Value: (m.total / time.Duration(m.count)).Nanoseconds()},
+	}...)
+
+	sorted := slices.Clone(m.recent)
+	slices.Sort(sorted)
+	for _, p := range latencyPercentiles {
+		index := int(p.percentile * float64(len(sorted)-1))
+		fields = append(fields, dtos.MetricField{Name: p.field, Value: sorted[index].Nanoseconds()})
+	}
+
+	return fields
+}
+
+func (c *MetricsClient) Register() (err error) {
+	defer func(start time.Time) { c.observe("Register", start, err) }(time.Now())
+	return c.Client.Register()
+}
+
+func (c *MetricsClient) RegisterWithContext(ctx context.Context) (err error) {
+	defer func(start time.Time) { c.observe("Register", start, err) }(time.Now())
+	return c.Client.RegisterWithContext(ctx)
+}
+
+func (c *MetricsClient) Unregister() (err error) {
+	defer func(start time.Time) { c.observe("Unregister", start, err) }(time.Now())
+	return c.Client.Unregister()
+}
+
+func (c *MetricsClient) UnregisterWithContext(ctx context.Context) (err error) {
+	defer func(start time.Time) { c.observe("Unregister", start, err) }(time.Now())
+	return c.Client.UnregisterWithContext(ctx)
+}
+
+func (c *MetricsClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *MetricsClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (endpoint types.ServiceEndpoint, err error) {
+	defer func(start time.Time) { c.observe("GetServiceEndpoint", start, err) }(time.Now())
+
+	budgetClient, ok := c.Client.(budgetLookup)
+	if !ok {
+		return c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+	}
+
+	endpoint, stale, err := budgetClient.GetServiceEndpointWithBudget(ctx, serviceId)
+	c.lock.Lock()
+	if stale {
+		c.cacheHits++
+	} else {
+		c.cacheMiss++
+	}
+	c.lock.Unlock()
+	return endpoint, err
+}
+
+func (c *MetricsClient) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceId)
+}
+
+func (c *MetricsClient) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) (endpoints []types.ServiceEndpoint, err error) {
+	defer func(start time.Time) { c.observe("GetServiceEndpoints", start, err) }(time.Now())
+	return c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+}
+
+func (c *MetricsClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+func (c *MetricsClient) GetAllServiceEndpointsWithContext(ctx context.Context) (endpoints []types.ServiceEndpoint, err error) {
+	defer func(start time.Time) { c.observe("GetAllServiceEndpoints", start, err) }(time.Now())
+	return c.Client.GetAllServiceEndpointsWithContext(ctx)
+}
+
+func (c *MetricsClient) IsServiceAvailable(serviceId string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceId)
+}
+
+// IsServiceAvailableWithContext counts lookups of services not available, i.e. unhealthy, as failures
+func (c *MetricsClient) IsServiceAvailableWithContext(ctx context.Context, serviceId string) (available bool, err error) {
+	defer func(start time.Time) { c.observe("IsServiceAvailable", start, err) }(time.Now())
+	return c.Client.IsServiceAvailableWithContext(ctx, serviceId)
+}
+
+func (c *MetricsClient) WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error) {
+	events, err := c.Client.WatchSelf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return relayNotifications(ctx, events, func(types.RegistrationEvent) func(bool) {
+		return c.observeNotification(ctx, "WatchSelfNotification")
+	}), nil
+}
+
+func (c *MetricsClient) WatchService(ctx context.Context, serviceId string) (<-chan types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.WatchService(ctx, serviceId)
+	if err != nil {
+		return nil, err
+	}
+
+	return relayNotifications(ctx, endpoints, func(types.ServiceEndpoint) func(bool) {
+		return c.observeNotification(ctx, "WatchServiceNotification")
+	}), nil
+}
+
+func (c *MetricsClient) observeNotification(ctx context.Context, operation string) func(bool) {
+	start := time.Now()
+	return func(delivered bool) {
+		if delivered {
+			c.observe(operation, start, nil)
+			return
+		}
+		c.observe(operation, start, ctx.Err())
+	}
+}
@@ -0,0 +1,105 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestMetricsClient(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("RegisterWithContext", mock.Anything).Return(nil)
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).After(testMaxWait)
+	client.On("GetServiceEndpointWithContext", mock.Anything, "core-command").Return(types.ServiceEndpoint{}, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+	metricsClient := NewMetricsClient(client)
+
+	require.NoError(t, metricsClient.RegisterWithContext(context.Background()))
+	_, err := metricsClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	_, err = metricsClient.GetServiceEndpoint("core-command")
+	require.Error(t, err)
+
+	metrics := metricsClient.GetMetrics()
+	require.Len(t, metrics, 2, "Expected no cache metric without LatencyBudgetClient")
+
+	assert.Equal(t, OperationsMetricName, metrics[0].Name)
+	assert.Equal(t, []dtos.MetricTag{{Name: "operation", Value: "GetServiceEndpoint"}}, metrics[0].Tags)
+	lookup := metricFields(metrics[0])
+	assert.Equal(t, uint64(1), lookup["successCount"])
+	assert.Equal(t, uint64(1), lookup["failureCount"])
+	assert.Equal(t, uint64(1), lookup["connectionRefusedFailureCount"])
+	assert.Equal(t, uint64(0), lookup["dnsNotFoundFailureCount"])
+	assert.GreaterOrEqual(t, lookup["latencyMax"], testMaxWait.Nanoseconds())
+	assert.LessOrEqual(t, lookup["latencyMin"], lookup["latencyP50"])
+	assert.LessOrEqual(t, lookup["latencyP99"], lookup["latencyMax"])
+
+	assert.Equal(t, []dtos.MetricTag{{Name: "operation", Value: "Register"}}, metrics[1].Tags)
+	assert.Equal(t, uint64(1), metricFields(metrics[1])["successCount"])
+}
+
+func TestMetricsClientCacheHits(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).After(5 * testMaxWait)
+	metricsClient := NewMetricsClient(NewLatencyBudgetClient(client, testMaxWait))
+
+	for i := 0; i < 4; i++ {
+		_, err := metricsClient.GetServiceEndpoint(testEndpoint.ServiceId)
+		require.NoError(t, err)
+	}
+
+	metrics := metricsClient.GetMetrics()
+	require.Len(t, metrics, 2)
+	assert.Equal(t, CacheMetricName, metrics[1].Name)
+	assert.Equal(t, map[string]any{"hitCount": uint64(3), "missCount": uint64(1), "hitRate": 0.75}, metricFields(metrics[1]))
+}
+
+func metricFields(metric dtos.Metric) map[string]any {
+	fields := make(map[string]any, len(metric.Fields))
+	for _, field := range metric.Fields {
+		fields[field.Name] = field.Value
+	}
+	return fields
+}
+
+func TestMetricsClientWatchService(t *testing.T) {
+	endpoints := make(chan types.ServiceEndpoint, 2)
+	endpoints <- testEndpoint
+	endpoints <- types.ServiceEndpoint{}
+	close(endpoints)
+
+	client := &mocks.Client{}
+	client.On("WatchService", mock.Anything, testEndpoint.ServiceId).Return((<-chan types.ServiceEndpoint)(endpoints), nil)
+	metricsClient := NewMetricsClient(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	watched, err := metricsClient.WatchService(ctx, testEndpoint.ServiceId)
+	require.NoError(t, err)
+	for range watched {
+		time.Sleep(testMaxWait)
+	}
+
+	metrics := metricsClient.GetMetrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, []dtos.MetricTag{{Name: "operation", Value: "WatchServiceNotification"}}, metrics[0].Tags)
+	notifications := metricFields(metrics[0])
+	assert.Equal(t, uint64(2), notifications["successCount"])
+	assert.GreaterOrEqual(t, notifications["latencyMax"], testMaxWait.Nanoseconds(), "Expected the second notification to wait for the subscriber")
+}
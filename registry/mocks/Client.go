@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 
 	types "github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
@@ -13,6 +15,20 @@ type Client struct {
 	mock.Mock
 }
 
+// Decommission provides a mock function with given fields: ctx, serviceKey
+func (_m *Client) Decommission(ctx context.Context, serviceKey string) error {
+	ret := _m.Called(ctx, serviceKey)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, serviceKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAllServiceEndpoints provides a mock function with given fields:
 func (_m *Client) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
 	ret := _m.Called()
@@ -36,6 +52,29 @@ func (_m *Client) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
 	return r0, r1
 }
 
+// GetAllServiceEndpointsWithContext provides a mock function with given fields: ctx
+func (_m *Client) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []types.ServiceEndpoint
+	if rf, ok := ret.Get(0).(func(context.Context) []types.ServiceEndpoint); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ServiceEndpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceEndpoint provides a mock function with given fields: serviceId
 func (_m *Client) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
 	ret := _m.Called(serviceId)
@@ -57,6 +96,73 @@ func (_m *Client) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, e
 	return r0, r1
 }
 
+// GetServiceEndpointWithContext provides a mock function with given fields: ctx, serviceId
+func (_m *Client) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	ret := _m.Called(ctx, serviceId)
+
+	var r0 types.ServiceEndpoint
+	if rf, ok := ret.Get(0).(func(context.Context, string) types.ServiceEndpoint); ok {
+		r0 = rf(ctx, serviceId)
+	} else {
+		r0 = ret.Get(0).(types.ServiceEndpoint)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, serviceId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceEndpoints provides a mock function with given fields: serviceId
+func (_m *Client) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	ret := _m.Called(serviceId)
+
+	var r0 []types.ServiceEndpoint
+	if rf, ok := ret.Get(0).(func(string) []types.ServiceEndpoint); ok {
+		r0 = rf(serviceId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ServiceEndpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceEndpointsWithContext provides a mock function with given fields: ctx, serviceId
+func (_m *Client) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) ([]types.ServiceEndpoint, error) {
+	ret := _m.Called(ctx, serviceId)
+
+	var r0 []types.ServiceEndpoint
+	if rf, ok := ret.Get(0).(func(context.Context, string) []types.ServiceEndpoint); ok {
+		r0 = rf(ctx, serviceId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ServiceEndpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, serviceId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsAlive provides a mock function with given fields:
 func (_m *Client) IsAlive() bool {
 	ret := _m.Called()
@@ -71,6 +177,20 @@ func (_m *Client) IsAlive() bool {
 	return r0
 }
 
+// IsAliveWithContext provides a mock function with given fields: ctx
+func (_m *Client) IsAliveWithContext(ctx context.Context) bool {
+	ret := _m.Called(ctx)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context) bool); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // IsServiceAvailable provides a mock function with given fields: serviceId
 func (_m *Client) IsServiceAvailable(serviceId string) (bool, error) {
 	ret := _m.Called(serviceId)
@@ -92,6 +212,27 @@ func (_m *Client) IsServiceAvailable(serviceId string) (bool, error) {
 	return r0, r1
 }
 
+// IsServiceAvailableWithContext provides a mock function with given fields: ctx, serviceId
+func (_m *Client) IsServiceAvailableWithContext(ctx context.Context, serviceId string) (bool, error) {
+	ret := _m.Called(ctx, serviceId)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, serviceId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, serviceId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Register provides a mock function with given fields:
 func (_m *Client) Register() error {
 	ret := _m.Called()
@@ -120,6 +261,55 @@ func (_m *Client) RegisterCheck(id string, name string, notes string, url string
 	return r0
 }
 
+// RegisterCheckWithContext provides a mock function with given fields: ctx, id, name, notes, url, interval
+func (_m *Client) RegisterCheckWithContext(ctx context.Context, id string, name string, notes string, url string, interval string) error {
+	ret := _m.Called(ctx, id, name, notes, url, interval)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string) error); ok {
+		r0 = rf(ctx, id, name, notes, url, interval)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RegisterWithContext provides a mock function with given fields: ctx
+func (_m *Client) RegisterWithContext(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TriggerHealthCheck provides a mock function with given fields: ctx, serviceId
+func (_m *Client) TriggerHealthCheck(ctx context.Context, serviceId string) (types.HealthCheckResult, error) {
+	ret := _m.Called(ctx, serviceId)
+
+	var r0 types.HealthCheckResult
+	if rf, ok := ret.Get(0).(func(context.Context, string) types.HealthCheckResult); ok {
+		r0 = rf(ctx, serviceId)
+	} else {
+		r0 = ret.Get(0).(types.HealthCheckResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, serviceId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Unregister provides a mock function with given fields:
 func (_m *Client) Unregister() error {
 	ret := _m.Called()
@@ -134,6 +324,66 @@ func (_m *Client) Unregister() error {
 	return r0
 }
 
+// UnregisterWithContext provides a mock function with given fields: ctx
+func (_m *Client) UnregisterWithContext(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WatchSelf provides a mock function with given fields: ctx
+func (_m *Client) WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error) {
+	ret := _m.Called(ctx)
+
+	var r0 <-chan types.RegistrationEvent
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan types.RegistrationEvent); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan types.RegistrationEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchService provides a mock function with given fields: ctx, serviceId
+func (_m *Client) WatchService(ctx context.Context, serviceId string) (<-chan types.ServiceEndpoint, error) {
+	ret := _m.Called(ctx, serviceId)
+
+	var r0 <-chan types.ServiceEndpoint
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan types.ServiceEndpoint); ok {
+		r0 = rf(ctx, serviceId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan types.ServiceEndpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, serviceId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewClient interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,131 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// DefaultStatusTopicPrefix is the root of the topic tree the MQTTBridge publishes to when no prefix is given
+const DefaultStatusTopicPrefix = "edgex/registry"
+
+// Publisher publishes a message to a topic of an MQTT broker. It is implemented by wrapping the MQTT client of choice,
+// i.e. Paho's client.Publish(topic, qos, retained, payload).
+type Publisher interface {
+	Publish(topic string, payload []byte, retained bool) error
+}
+
+// ServiceStatusMessage is the payload published to <prefix>/<serviceKey>/status for each registered service
+type ServiceStatusMessage struct {
+	ServiceId string `json:"serviceId"`
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	Available bool   `json:"available"`
+}
+
+// MQTTBridge republishes the registered services and their health to an MQTT topic tree as retained messages, so
+// remote monitoring systems subscribing to <prefix>/+/status get the current topology without polling the Registry.
+// The retained message of a service is cleared once it is no longer registered, and left as is while the Registry is
+// unable to tell whether the service is available.
+type MQTTBridge struct {
+	client       Client
+	publisher    Publisher
+	topicPrefix  string
+	pollInterval time.Duration
+}
+
+// NewMQTTBridge creates an MQTTBridge which checks the Registry for changes every pollInterval and publishes them
+// under topicPrefix, or DefaultStatusTopicPrefix if empty
+func NewMQTTBridge(client Client, publisher Publisher, topicPrefix string, pollInterval time.Duration) *MQTTBridge {
+	if topicPrefix == "" {
+		topicPrefix = DefaultStatusTopicPrefix
+	}
+
+	return &MQTTBridge{
+		client:       client,
+		publisher:    publisher,
+		topicPrefix:  strings.TrimSuffix(topicPrefix, "/"),
+		pollInterval: pollInterval,
+	}
+}
+
+// StatusTopic returns the topic the status of the target service is published to
+func (b *MQTTBridge) StatusTopic(serviceKey string) string {
+	return b.topicPrefix + "/" + serviceKey + "/status"
+}
+
+// Run publishes the status of every registered service, then publishes each change until ctx is cancelled. Statuses
+// which fail to publish are retried on the next poll.
+func (b *MQTTBridge) Run(ctx context.Context) {
+	published := make(map[string]ServiceStatusMessage)
+
+	ticker := time.NewTicker(b.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		b.publishChanges(ctx, published)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *MQTTBridge) publishChanges(ctx context.Context, published map[string]ServiceStatusMessage) {
+	endpoints, err := b.client.GetAllServiceEndpointsWithContext(ctx)
+	if err != nil {
+		// Unable to tell what changed, so try again on the next poll
+		return
+	}
+
+	current := make(map[string]ServiceStatusMessage, len(endpoints))
+	for _, endpoint := range endpoints {
+		available, err := serviceAvailability(b.client.IsServiceAvailableWithContext(ctx, endpoint.ServiceId))
+		if err != nil {
+			// Unable to tell whether the service is available, so leave the status last published, if any, retained
+			if previous, ok := published[endpoint.ServiceId]; ok {
+				current[endpoint.ServiceId] = previous
+			}
+			continue
+		}
+		current[endpoint.ServiceId] = ServiceStatusMessage{
+			ServiceId: endpoint.ServiceId,
+			Host:      endpoint.Host,
+			Port:      endpoint.Port,
+			Available: available,
+		}
+	}
+
+	for serviceKey, status := range current {
+		if previous, ok := published[serviceKey]; ok && previous == status {
+			continue
+		}
+
+		payload, err := json.Marshal(status)
+		if err != nil {
+			continue
+		}
+		if err := b.publisher.Publish(b.StatusTopic(serviceKey), payload, true); err == nil {
+			published[serviceKey] = status
+		}
+	}
+
+	for serviceKey := range published {
+		if _, ok := current[serviceKey]; ok {
+			continue
+		}
+
+		// An empty retained message clears the one previously retained by the broker
+		if err := b.publisher.Publish(b.StatusTopic(serviceKey), []byte{}, true); err == nil {
+			delete(published, serviceKey)
+		}
+	}
+}
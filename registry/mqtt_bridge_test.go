@@ -0,0 +1,130 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+type publishedMessage struct {
+	topic    string
+	payload  []byte
+	retained bool
+}
+
+type fakePublisher struct {
+	messages chan publishedMessage
+	failures int
+}
+
+func (p *fakePublisher) Publish(topic string, payload []byte, retained bool) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unreachable")
+	}
+
+	p.messages <- publishedMessage{topic: topic, payload: payload, retained: retained}
+	return nil
+}
+
+func receiveMessage(t *testing.T, messages <-chan publishedMessage) publishedMessage {
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for published message")
+		return publishedMessage{}
+	}
+}
+
+func requireStatus(t *testing.T, message publishedMessage, expected ServiceStatusMessage) {
+	assert.Equal(t, "edgex/registry/"+expected.ServiceId+"/status", message.topic)
+	assert.True(t, message.retained)
+
+	var actual ServiceStatusMessage
+	require.NoError(t, json.Unmarshal(message.payload, &actual))
+	assert.Equal(t, expected, actual)
+}
+
+func TestMQTTBridge(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{testEndpoint}, nil).Times(3)
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(true, nil).Twice()
+	client.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(false, errors.New("service not healthy"))
+
+	publisher := &fakePublisher{messages: make(chan publishedMessage, 10)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewMQTTBridge(client, publisher, "", testPollInterval).Run(ctx)
+
+	expected := ServiceStatusMessage{ServiceId: testEndpoint.ServiceId, Host: testEndpoint.Host, Port: testEndpoint.Port, Available: true}
+	requireStatus(t, receiveMessage(t, publisher.messages), expected)
+
+	// The unchanged status of the second poll isn't republished
+	expected.Available = false
+	requireStatus(t, receiveMessage(t, publisher.messages), expected)
+
+	cleared := receiveMessage(t, publisher.messages)
+	assert.Equal(t, "edgex/registry/"+testEndpoint.ServiceId+"/status", cleared.topic)
+	assert.True(t, cleared.retained)
+	assert.Empty(t, cleared.payload)
+
+	select {
+	case message := <-publisher.messages:
+		assert.Fail(t, "Unexpected message published", message.topic)
+	case <-time.After(5 * testPollInterval):
+	}
+}
+
+func TestMQTTBridgeRetriesFailedPublish(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{testEndpoint}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(true, nil)
+
+	publisher := &fakePublisher{messages: make(chan publishedMessage, 10), failures: 2}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bridge := NewMQTTBridge(client, publisher, "site/registry/", testPollInterval)
+	go bridge.Run(ctx)
+
+	message := receiveMessage(t, publisher.messages)
+	assert.Equal(t, "site/registry/"+testEndpoint.ServiceId+"/status", message.topic)
+	assert.Equal(t, bridge.StatusTopic(testEndpoint.ServiceId), message.topic)
+}
+
+func TestMQTTBridgeAvailabilityUnknown(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{testEndpoint}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(true, nil).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(false, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+
+	publisher := &fakePublisher{messages: make(chan publishedMessage, 10)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewMQTTBridge(client, publisher, "", testPollInterval).Run(ctx)
+
+	expected := ServiceStatusMessage{ServiceId: testEndpoint.ServiceId, Host: testEndpoint.Host, Port: testEndpoint.Port, Available: true}
+	requireStatus(t, receiveMessage(t, publisher.messages), expected)
+
+	// The status published last stays retained, neither republished unavailable nor cleared
+	select {
+	case message := <-publisher.messages:
+		assert.Fail(t, "Unexpected message published", message.topic)
+	case <-time.After(5 * testPollInterval):
+	}
+}
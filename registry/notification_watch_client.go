@@ -0,0 +1,139 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// DefaultRegistryNotificationTopic is the topic the NotificationWatchClient subscribes to when no topic is given, the
+// system events published by Keeper, i.e. edgex/system-events/core-keeper/registry/update/core-data, followed by the
+// multi-level wildcard of the message bus
+const DefaultRegistryNotificationTopic = common.DefaultBaseTopic + "/" + common.SystemEventPublishTopic + "/" + common.CoreKeeperServiceKey + "/#"
+
+// Subscriber subscribes to a topic of a message bus, i.e. MQTT or Redis. It is implemented by wrapping the message bus
+// client of choice, i.e. the MessageClient of go-mod-messaging, sending the payload of each message received.
+type Subscriber interface {
+	// Subscribes to the topic, which may contain wildcards, until ctx is done, when the returned channel is closed
+	Subscribe(ctx context.Context, topic string) (<-chan []byte, error)
+}
+
+// NotificationWatchClient watches the services with the registry notifications published by Keeper on the message bus
+// rather than polling the Registry, so the changes are sent as soon as they are made
+type NotificationWatchClient struct {
+	Client
+	subscriber Subscriber
+	topic      string
+}
+
+// NewNotificationWatchClient creates a NotificationWatchClient subscribing to topic, or
+// DefaultRegistryNotificationTopic if empty
+func NewNotificationWatchClient(client Client, subscriber Subscriber, topic string) *NotificationWatchClient {
+	if topic == "" {
+		topic = DefaultRegistryNotificationTopic
+	}
+
+	return &NotificationWatchClient{
+		Client:     client,
+		subscriber: subscriber,
+		topic:      topic,
+	}
+}
+
+// Unwrap returns the Client wrapped by the NotificationWatchClient
+func (c *NotificationWatchClient) Unwrap() Client {
+	return c.Client
+}
+
+// WatchService sends the current endpoint of the target service, as watched by the wrapped Client, then the endpoint
+// notified each time its registration is added, updated or deleted, skipping the notifications leaving it unchanged.
+// An empty endpoint is sent when the service isn't registered (anymore). Watching stops and the returned channel is
+// closed once ctx is cancelled or the subscription ends.
+func (c *NotificationWatchClient) WatchService(ctx context.Context, serviceKey string) (<-chan types.ServiceEndpoint, error) {
+	// Subscribing first, so the changes made while the current endpoint is retrieved aren't missed
+	subscriptionCtx, cancelSubscription := context.WithCancel(ctx)
+	messages, err := c.subscriber.Subscribe(subscriptionCtx, c.topic)
+	if err != nil {
+		cancelSubscription()
+		return nil, fmt.Errorf("unable to subscribe to the registry notifications on %s: %w", c.topic, err)
+	}
+
+	pollCtx, cancelPoll := context.WithCancel(ctx)
+	polled, err := c.Client.WatchService(pollCtx, serviceKey)
+	if err != nil {
+		cancelPoll()
+		cancelSubscription()
+		return nil, err
+	}
+
+	endpoints := make(chan types.ServiceEndpoint)
+	go func() {
+		defer close(endpoints)
+		defer cancelSubscription()
+
+		current, ok := <-polled
+		cancelPoll()
+		if !ok {
+			return
+		}
+		select {
+		case endpoints <- current:
+		case <-ctx.Done():
+			return
+		}
+
+		for message := range messages {
+			endpoint, ok := notifiedEndpoint(message, serviceKey)
+			if !ok || endpoint.Equal(current) {
+				continue
+			}
+
+			select {
+			case endpoints <- endpoint:
+				current = endpoint
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return endpoints, nil
+}
+
+// notifiedEndpoint returns the endpoint of the target service notified by the system event of Keeper, empty when the
+// registration got deleted or de-registered, or false when the message isn't about the service or can't be decoded.
+// The system event type of the registrations isn't part of the contracts, so the events are told apart by their
+// source, action and details rather than their type.
+func notifiedEndpoint(message []byte, serviceKey string) (types.ServiceEndpoint, bool) {
+	var event dtos.SystemEvent
+	if err := json.Unmarshal(message, &event); err != nil || event.Source != common.CoreKeeperServiceKey {
+		return types.ServiceEndpoint{}, false
+	}
+	switch event.Action {
+	case common.SystemEventActionAdd, common.SystemEventActionUpdate, common.SystemEventActionDelete:
+	default:
+		return types.ServiceEndpoint{}, false
+	}
+	var registration types.KeeperRegistration
+	if err := event.DecodeDetails(&registration); err != nil || registration.ServiceId != serviceKey {
+		return types.ServiceEndpoint{}, false
+	}
+
+	if event.Action == common.SystemEventActionDelete || types.ParseStatus(registration.Status).IsHalted() {
+		return types.ServiceEndpoint{}, true
+	}
+	return types.ServiceEndpoint{
+		ServiceId: registration.ServiceId,
+		Host:      registration.Host,
+		Port:      registration.Port,
+	}, true
+}
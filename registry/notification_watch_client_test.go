@@ -0,0 +1,127 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+// channelSubscriber delivers the messages sent to its channel to the subscription
+type channelSubscriber struct {
+	messages chan []byte
+	topic    string
+	err      error
+}
+
+func (s *channelSubscriber) Subscribe(ctx context.Context, topic string) (<-chan []byte, error) {
+	s.topic = topic
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	delivered := make(chan []byte)
+	go func() {
+		defer close(delivered)
+		for {
+			select {
+			case message := <-s.messages:
+				select {
+				case delivered <- message:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return delivered, nil
+}
+
+// testRegistrationEventType is the type of the system events of the registrations in the tests, any type being
+// accepted from Keeper
+const testRegistrationEventType = "registration"
+
+func registryEvent(t *testing.T, action string, registration dtos.Registration) []byte {
+	return systemEvent(t, common.CoreKeeperServiceKey, action, registration)
+}
+
+func systemEvent(t *testing.T, source string, action string, registration dtos.Registration) []byte {
+	message, err := json.Marshal(dtos.NewSystemEvent(testRegistrationEventType, action, source, registration.ServiceId, nil, registration))
+	require.NoError(t, err)
+	return message
+}
+
+// receiveEndpoint returns the next endpoint sent, failing the test if the channel is closed or nothing is sent in time
+func receiveEndpoint(t *testing.T, endpoints <-chan types.ServiceEndpoint) types.ServiceEndpoint {
+	select {
+	case endpoint, ok := <-endpoints:
+		require.True(t, ok, "Expected an endpoint before the channel got closed")
+		return endpoint
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for service endpoint")
+		return types.ServiceEndpoint{}
+	}
+}
+
+func TestNotificationWatchClientWatchService(t *testing.T) {
+	polled := make(chan types.ServiceEndpoint, 1)
+	polled <- testEndpoint
+	client := &mocks.Client{}
+	client.On("WatchService", mock.Anything, testEndpoint.ServiceId).Return((<-chan types.ServiceEndpoint)(polled), nil).Once()
+	subscriber := &channelSubscriber{messages: make(chan []byte, 6)}
+	notificationClient := NewNotificationWatchClient(client, subscriber, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	endpoints, err := notificationClient.WatchService(ctx, testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, "edgex/system-events/core-keeper/#", subscriber.topic)
+	assert.Equal(t, testEndpoint, receiveEndpoint(t, endpoints), "Expected the current endpoint first")
+
+	moved := dtos.Registration{ServiceId: testEndpoint.ServiceId, Host: "10.0.0.8", Port: testEndpoint.Port, Status: string(types.StatusUp)}
+	// The notifications about other services, not decoded or leaving the endpoint unchanged are skipped
+	subscriber.messages <- []byte("not a system event")
+	subscriber.messages <- systemEvent(t, common.CoreMetaDataServiceKey, common.SystemEventActionUpdate, dtos.Registration{ServiceId: testEndpoint.ServiceId, Host: "10.0.0.9", Port: 59880})
+	subscriber.messages <- registryEvent(t, common.SystemEventActionUpdate, dtos.Registration{ServiceId: "core-command", Host: "10.0.0.9", Port: 59882})
+	subscriber.messages <- registryEvent(t, common.SystemEventActionUpdate, moved)
+	subscriber.messages <- registryEvent(t, common.SystemEventActionUpdate, moved)
+	subscriber.messages <- registryEvent(t, common.SystemEventActionDelete, moved)
+
+	assert.Equal(t, types.ServiceEndpoint{ServiceId: testEndpoint.ServiceId, Host: "10.0.0.8", Port: testEndpoint.Port}, receiveEndpoint(t, endpoints))
+	assert.True(t, receiveEndpoint(t, endpoints).IsZero(), "Expected an empty endpoint once the registration got deleted")
+
+	cancel()
+	require.Eventually(t, func() bool {
+		_, ok := <-endpoints
+		return !ok
+	}, time.Second, time.Millisecond, "Expected channel to be closed once the context is cancelled")
+	client.AssertExpectations(t)
+}
+
+func TestNotificationWatchClientSubscribeFailure(t *testing.T) {
+	client := &mocks.Client{}
+	notificationClient := NewNotificationWatchClient(client, &channelSubscriber{err: errors.New("broker unreachable")}, "edgex/registry-events")
+
+	_, err := notificationClient.WatchService(context.Background(), testEndpoint.ServiceId)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "edgex/registry-events")
+	client.AssertNotCalled(t, "WatchService", mock.Anything, mock.Anything)
+}
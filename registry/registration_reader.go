@@ -0,0 +1,32 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// RegistrationReader is implemented by the Clients of the registry types storing the complete registrations of the
+// services, i.e. keeper, for monitoring tools to display them as stored rather than just the service endpoints
+type RegistrationReader interface {
+	// Gets the complete registration of the target service, i.e. its health check settings, status and timestamps
+	GetRegistrationWithContext(ctx context.Context, serviceKey string) (types.KeeperRegistration, error)
+}
+
+// GetRegistration returns the complete registration of the target service when the client, or any Client it wraps, is
+// a RegistrationReader, failing with types.ErrNotRegistered when it isn't registered, or otherwise fails as the
+// registry type only provides the service endpoints
+func GetRegistration(ctx context.Context, client Client, serviceKey string) (types.KeeperRegistration, error) {
+	reader, ok := As[RegistrationReader](client)
+	if !ok {
+		return types.KeeperRegistration{}, fmt.Errorf("unable to get the registration of %s: the registry type only provides the service endpoints", serviceKey)
+	}
+
+	return reader.GetRegistrationWithContext(ctx, serviceKey)
+}
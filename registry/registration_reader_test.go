@@ -0,0 +1,44 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+// registrationReadingClient is a Client storing the complete registrations of the services
+type registrationReadingClient struct {
+	*mocks.Client
+	registration types.KeeperRegistration
+}
+
+func (c *registrationReadingClient) GetRegistrationWithContext(context.Context, string) (types.KeeperRegistration, error) {
+	return c.registration, nil
+}
+
+func TestGetRegistration(t *testing.T) {
+	expected := types.KeeperRegistration{Registration: dtos.Registration{ServiceId: "core-data", Status: string(types.StatusUp)}}
+	registration, err := GetRegistration(context.Background(), &registrationReadingClient{Client: &mocks.Client{}, registration: expected}, "core-data")
+	require.NoError(t, err)
+	assert.Equal(t, expected, registration)
+
+	decorated := NewBalancingClient(&registrationReadingClient{Client: &mocks.Client{}, registration: expected}, nil)
+	registration, err = GetRegistration(context.Background(), decorated, "core-data")
+	require.NoError(t, err, "Expected the RegistrationReader to be found behind the decorators")
+	assert.Equal(t, expected, registration)
+
+	_, err = GetRegistration(context.Background(), &mocks.Client{}, "core-data")
+	assert.Error(t, err, "Expected the registry types only providing the endpoints to fail")
+}
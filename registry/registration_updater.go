@@ -0,0 +1,33 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// RegistrationUpdater is implemented by the Clients of the registry types able to update only some fields of an
+// existing registration, i.e. keeper, so the health check interval or the route of a service can be changed without
+// registering it again
+type RegistrationUpdater interface {
+	// Updates only the fields of the patch in the registration of the target service, failing with
+	// types.ErrNotRegistered when it isn't registered
+	UpdateRegistrationWithContext(ctx context.Context, serviceKey string, patch types.RegistrationPatch) error
+}
+
+// UpdateRegistration updates only the fields of the patch in the registration of the target service when the client,
+// or any Client it wraps, is a RegistrationUpdater, otherwise fails as the registry type only replaces whole registrations with Register
+func UpdateRegistration(ctx context.Context, client Client, serviceKey string, patch types.RegistrationPatch) error {
+	updater, ok := As[RegistrationUpdater](client)
+	if !ok {
+		return fmt.Errorf("unable to update the registration of %s: the registry type only replaces whole registrations", serviceKey)
+	}
+
+	return updater.UpdateRegistrationWithContext(ctx, serviceKey, patch)
+}
@@ -0,0 +1,39 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+// registrationUpdatingClient is a Client updating only some fields of the registrations
+type registrationUpdatingClient struct {
+	*mocks.Client
+	patches map[string]types.RegistrationPatch
+}
+
+func (c *registrationUpdatingClient) UpdateRegistrationWithContext(_ context.Context, serviceKey string, patch types.RegistrationPatch) error {
+	c.patches[serviceKey] = patch
+	return nil
+}
+
+func TestUpdateRegistration(t *testing.T) {
+	interval := "30s"
+	patch := types.RegistrationPatch{CheckInterval: &interval}
+	client := &registrationUpdatingClient{Client: &mocks.Client{}, patches: make(map[string]types.RegistrationPatch)}
+	require.NoError(t, UpdateRegistration(context.Background(), client, "core-data", patch))
+	assert.Equal(t, map[string]types.RegistrationPatch{"core-data": patch}, client.patches)
+
+	err := UpdateRegistration(context.Background(), &mocks.Client{}, "core-data", patch)
+	assert.Error(t, err, "Expected the registry types only replacing whole registrations to fail")
+}
@@ -0,0 +1,271 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	defaultShadowTimeout       = 5 * time.Second
+	defaultShadowMaxConcurrent = 32
+)
+
+// The discovery operations whose results are compared by a ShadowClient
+const (
+	ShadowGetServiceEndpoint     = "GetServiceEndpoint"
+	ShadowGetServiceEndpoints    = "GetServiceEndpoints"
+	ShadowGetAllServiceEndpoints = "GetAllServiceEndpoints"
+	ShadowIsServiceAvailable     = "IsServiceAvailable"
+)
+
+// ShadowRead is the result of a discovery operation, i.e. the ServiceEndpoint returned by GetServiceEndpoint
+type ShadowRead struct {
+	Value any
+	Err   error
+}
+
+// ShadowMismatch is reported when the primary and shadow Clients disagree on the result of a discovery operation
+type ShadowMismatch struct {
+	// Operation is the name of the Client method without the WithContext suffix, i.e. ShadowGetServiceEndpoint
+	Operation string
+	// ServiceId is the target service, empty for ShadowGetAllServiceEndpoints
+	ServiceId string
+	Primary   ShadowRead
+	Shadow    ShadowRead
+}
+
+// ShadowStats are the statistics of the shadow reads of a discovery operation
+type ShadowStats struct {
+	// Reads is the number of shadow reads completed
+	Reads uint64
+	// Mismatches is the number of shadow reads whose result differs from the primary one
+	Mismatches uint64
+	// Dropped is the number of shadow reads not issued as MaxConcurrent shadow reads were already in progress
+	Dropped uint64
+}
+
+// ShadowConfig defines how a ShadowClient issues the shadow reads
+type ShadowConfig struct {
+	// Timeout is the time limit of each shadow read, which isn't bound to the context of the primary read. Defaults to
+	// 5s if not set
+	Timeout time.Duration
+	// MaxConcurrent is the maximum number of shadow reads in progress, the shadow reads of the primary reads issued
+	// beyond being dropped and counted as such, so a slow shadow Client can't pile up goroutines. Defaults to 32 if
+	// not set
+	MaxConcurrent int
+	// OnMismatch is optionally called for each mismatch, i.e. to log it
+	OnMismatch func(mismatch ShadowMismatch)
+}
+
+// ShadowClient is a Client serving discovery from the primary Client while issuing the same reads to a shadow Client,
+// i.e. a new backend being evaluated, and comparing their results to de-risk backend migrations with production
+// traffic. Shadow reads run in the background, so they never delay nor fail the primary reads. Service endpoints are
+// compared regardless of their order, and failures are the same result if both or neither are ErrNotRegistered.
+// Registration and health check operations only go to the primary Client.
+type ShadowClient struct {
+	Client
+	shadow Client
+	config ShadowConfig
+	lock   sync.Mutex
+	stats  map[string]ShadowStats
+	wait   sync.WaitGroup
+	// reads holds a token per shadow read in progress
+	reads chan struct{}
+}
+
+// NewShadowClient wraps the given primary Client to issue shadow reads to the given shadow Client
+func NewShadowClient(primary Client, shadow Client, config ShadowConfig) *ShadowClient {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultShadowTimeout
+	}
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = defaultShadowMaxConcurrent
+	}
+
+	return &ShadowClient{
+		Client: primary,
+		shadow: shadow,
+		config: config,
+		stats:  make(map[string]ShadowStats),
+		reads:  make(chan struct{}, config.MaxConcurrent),
+	}
+}
+
+// Unwrap returns the primary Client, the shadow Client only serving the shadow reads
+func (c *ShadowClient) Unwrap() Client {
+	return c.Client
+}
+
+// Stats returns the statistics of the shadow reads of each discovery operation read so far
+func (c *ShadowClient) Stats() map[string]ShadowStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats := make(map[string]ShadowStats, len(c.stats))
+	for operation, operationStats := range c.stats {
+		stats[operation] = operationStats
+	}
+	return stats
+}
+
+// Wait waits for the shadow reads in progress to complete, i.e. before reading the final Stats on shutdown
+func (c *ShadowClient) Wait() {
+	c.wait.Wait()
+}
+
+// ServeHTTP writes the statistics in the Prometheus text exposition format as the registry_shadow_reads_total,
+// registry_shadow_mismatches_total and registry_shadow_dropped_total{operation="<operation>"} counters, to be served on
+// /metrics or appended to the output of an existing metrics endpoint
+func (c *ShadowClient) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	stats := c.Stats()
+	operations := make([]string, 0, len(stats))
+	for operation := range stats {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+
+	var builder strings.Builder
+	builder.WriteString("# HELP registry_shadow_reads_total Number of shadow reads completed.\n")
+	builder.WriteString("# TYPE registry_shadow_reads_total counter\n")
+	for _, operation := range operations {
+		fmt.Fprintf(&builder, "registry_shadow_reads_total{operation=\"%s\"} %d\n", operation, stats[operation].Reads)
+	}
+	builder.WriteString("# HELP registry_shadow_mismatches_total Number of shadow reads whose result differs from the primary one.\n")
+	builder.WriteString("# TYPE registry_shadow_mismatches_total counter\n")
+	for _, operation := range operations {
+		fmt.Fprintf(&builder, "registry_shadow_mismatches_total{operation=\"%s\"} %d\n", operation, stats[operation].Mismatches)
+	}
+	builder.WriteString("# HELP registry_shadow_dropped_total Number of shadow reads dropped as too many were in progress.\n")
+	builder.WriteString("# TYPE registry_shadow_dropped_total counter\n")
+	for _, operation := range operations {
+		fmt.Fprintf(&builder, "registry_shadow_dropped_total{operation=\"%s\"} %d\n", operation, stats[operation].Dropped)
+	}
+
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = writer.Write([]byte(builder.String()))
+}
+
+func (c *ShadowClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *ShadowClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	endpoint, err := c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+	c.compare(ctx, ShadowGetServiceEndpoint, serviceId, ShadowRead{Value: endpoint, Err: err}, func(ctx context.Context) ShadowRead {
+		endpoint, err := c.shadow.GetServiceEndpointWithContext(ctx, serviceId)
+		return ShadowRead{Value: endpoint, Err: err}
+	})
+	return endpoint, err
+}
+
+func (c *ShadowClient) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceId)
+}
+
+func (c *ShadowClient) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) ([]types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+	c.compare(ctx, ShadowGetServiceEndpoints, serviceId, ShadowRead{Value: sortedEndpoints(endpoints), Err: err}, func(ctx context.Context) ShadowRead {
+		endpoints, err := c.shadow.GetServiceEndpointsWithContext(ctx, serviceId)
+		return ShadowRead{Value: sortedEndpoints(endpoints), Err: err}
+	})
+	return endpoints, err
+}
+
+func (c *ShadowClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+func (c *ShadowClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.GetAllServiceEndpointsWithContext(ctx)
+	c.compare(ctx, ShadowGetAllServiceEndpoints, "", ShadowRead{Value: sortedEndpoints(endpoints), Err: err}, func(ctx context.Context) ShadowRead {
+		endpoints, err := c.shadow.GetAllServiceEndpointsWithContext(ctx)
+		return ShadowRead{Value: sortedEndpoints(endpoints), Err: err}
+	})
+	return endpoints, err
+}
+
+func (c *ShadowClient) IsServiceAvailable(serviceId string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceId)
+}
+
+func (c *ShadowClient) IsServiceAvailableWithContext(ctx context.Context, serviceId string) (bool, error) {
+	available, err := c.Client.IsServiceAvailableWithContext(ctx, serviceId)
+	c.compare(ctx, ShadowIsServiceAvailable, serviceId, ShadowRead{Value: available, Err: err}, func(ctx context.Context) ShadowRead {
+		available, err := c.shadow.IsServiceAvailableWithContext(ctx, serviceId)
+		return ShadowRead{Value: available, Err: err}
+	})
+	return available, err
+}
+
+// compare issues the shadow read in the background, then compares its result with the primary one. The shadow read
+// outlives the primary read, so it keeps the values of ctx, i.e. the request authentication, but not its cancellation.
+// The shadow read is dropped when MaxConcurrent shadow reads are already in progress.
+func (c *ShadowClient) compare(ctx context.Context, operation string, serviceId string, primary ShadowRead, read func(ctx context.Context) ShadowRead) {
+	select {
+	case c.reads <- struct{}{}:
+	default:
+		c.lock.Lock()
+		stats := c.stats[operation]
+		stats.Dropped++
+		c.stats[operation] = stats
+		c.lock.Unlock()
+		return
+	}
+
+	c.wait.Add(1)
+	go func() {
+		defer c.wait.Done()
+		defer func() { <-c.reads }()
+
+		shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.config.Timeout)
+		defer cancel()
+		shadow := read(shadowCtx)
+
+		matching := sameRead(primary, shadow)
+		c.lock.Lock()
+		stats := c.stats[operation]
+		stats.Reads++
+		if !matching {
+			stats.Mismatches++
+		}
+		c.stats[operation] = stats
+		c.lock.Unlock()
+
+		if !matching && c.config.OnMismatch != nil {
+			c.config.OnMismatch(ShadowMismatch{Operation: operation, ServiceId: serviceId, Primary: primary, Shadow: shadow})
+		}
+	}()
+}
+
+// sameRead tells whether both reads have the same result. Failures are the same result when both or neither are
+// ErrNotRegistered, as the backends word their errors differently.
+func sameRead(primary ShadowRead, shadow ShadowRead) bool {
+	if primary.Err != nil || shadow.Err != nil {
+		return primary.Err != nil && shadow.Err != nil &&
+			errors.Is(primary.Err, types.ErrNotRegistered) == errors.Is(shadow.Err, types.ErrNotRegistered)
+	}
+	return reflect.DeepEqual(primary.Value, shadow.Value)
+}
+
+// sortedEndpoints returns a sorted copy of the endpoints, so the endpoints of backends returning them in different
+// orders can be compared. Empty and nil endpoints are the same.
+func sortedEndpoints(endpoints []types.ServiceEndpoint) []types.ServiceEndpoint {
+	sorted := make([]types.ServiceEndpoint, len(endpoints))
+	copy(sorted, endpoints)
+	types.SortServiceEndpoints(sorted, types.ByServiceId)
+	return sorted
+}
@@ -0,0 +1,123 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestShadowClient(t *testing.T) {
+	moved := testEndpoint
+	moved.Host = "edgex-core-data-2"
+	command := types.ServiceEndpoint{ServiceId: "core-command", Host: "edgex-core-command", Port: 59882}
+
+	primary := &mocks.Client{}
+	primary.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+	primary.On("GetServiceEndpointWithContext", mock.Anything, "core-metadata").Return(types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "no matching service endpoint found"))
+	primary.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{command, testEndpoint}, nil)
+	primary.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(true, nil)
+
+	shadow := &mocks.Client{}
+	shadow.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(moved, nil)
+	shadow.On("GetServiceEndpointWithContext", mock.Anything, "core-metadata").Return(types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "core-metadata not found"))
+	shadow.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{testEndpoint, command}, nil)
+	shadow.On("IsServiceAvailableWithContext", mock.Anything, testEndpoint.ServiceId).Return(false, errors.New("registry is unavailable"))
+
+	var lock sync.Mutex
+	var mismatches []ShadowMismatch
+	shadowClient := NewShadowClient(primary, shadow, ShadowConfig{OnMismatch: func(mismatch ShadowMismatch) {
+		lock.Lock()
+		defer lock.Unlock()
+		mismatches = append(mismatches, mismatch)
+	}})
+
+	endpoint, err := shadowClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpoint, endpoint, "Expected the primary endpoint")
+	_, err = shadowClient.GetServiceEndpoint("core-metadata")
+	require.Error(t, err)
+	endpoints, err := shadowClient.GetAllServiceEndpoints()
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{command, testEndpoint}, endpoints)
+	available, err := shadowClient.IsServiceAvailable(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.True(t, available)
+	shadowClient.Wait()
+
+	assert.Equal(t, map[string]ShadowStats{
+		ShadowGetServiceEndpoint:     {Reads: 2, Mismatches: 1},
+		ShadowGetAllServiceEndpoints: {Reads: 1},
+		ShadowIsServiceAvailable:     {Reads: 1, Mismatches: 1},
+	}, shadowClient.Stats())
+
+	require.Len(t, mismatches, 2)
+	for _, mismatch := range mismatches {
+		if mismatch.Operation == ShadowGetServiceEndpoint {
+			assert.Equal(t, ShadowMismatch{
+				Operation: ShadowGetServiceEndpoint,
+				ServiceId: testEndpoint.ServiceId,
+				Primary:   ShadowRead{Value: testEndpoint},
+				Shadow:    ShadowRead{Value: moved},
+			}, mismatch)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	shadowClient.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, recorder.Body.String(), "registry_shadow_mismatches_total{operation=\"GetServiceEndpoint\"} 1\n")
+}
+
+func TestShadowClientDoesNotDelayPrimary(t *testing.T) {
+	primary := &mocks.Client{}
+	primary.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+	shadow := &mocks.Client{}
+	shadow.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).After(20 * testPollInterval)
+	shadowClient := NewShadowClient(primary, shadow, ShadowConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	_, err := shadowClient.GetServiceEndpointWithContext(ctx, testEndpoint.ServiceId)
+	cancel()
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 10*testPollInterval, "Expected shadow read to run in the background")
+
+	shadowClient.Wait()
+	assert.Equal(t, ShadowStats{Reads: 1}, shadowClient.Stats()[ShadowGetServiceEndpoint], "Expected shadow read to outlive the primary context")
+}
+
+func TestShadowClientDropsReadsWhenSaturated(t *testing.T) {
+	release := make(chan time.Time)
+	primary := &mocks.Client{}
+	primary.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+	shadow := &mocks.Client{}
+	shadow.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).WaitUntil(release)
+	shadowClient := NewShadowClient(primary, shadow, ShadowConfig{MaxConcurrent: 2})
+
+	for i := 0; i < 5; i++ {
+		_, err := shadowClient.GetServiceEndpoint(testEndpoint.ServiceId)
+		require.NoError(t, err, "Expected the primary read to be served whether the shadow read is dropped or not")
+	}
+	close(release)
+	shadowClient.Wait()
+	assert.Equal(t, ShadowStats{Reads: 2, Dropped: 3}, shadowClient.Stats()[ShadowGetServiceEndpoint])
+
+	recorder := httptest.NewRecorder()
+	shadowClient.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, recorder.Body.String(), `registry_shadow_dropped_total{operation="GetServiceEndpoint"} 3`)
+}
@@ -0,0 +1,266 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+const (
+	defaultSLOWindow        = 100
+	defaultSLOAlertBurnRate = 1
+)
+
+// SLO defines the latency objective of a registry operation
+type SLO struct {
+	// Latency is the duration within which the operation is expected to complete
+	Latency time.Duration
+	// Objective is the fraction of the operations expected to complete within Latency, i.e. 0.99
+	Objective float64
+}
+
+// SLOStats are the statistics of a registry operation against its SLO
+type SLOStats struct {
+	// Total is the number of operations performed
+	Total uint64
+	// Breaches is the number of operations which took longer than the SLO latency
+	Breaches uint64
+	// BurnRate is the rate at which the most recent operations consume the error budget of the SLO, where 1 means the
+	// budget is consumed exactly as fast as the SLO allows
+	BurnRate float64
+}
+
+// SLOBurnAlert is raised once the burn rate of a registry operation reaches the alert burn rate
+type SLOBurnAlert struct {
+	Operation string
+	SLOStats
+}
+
+// SLOConfig defines the SLOs tracked by an SLOClient
+type SLOConfig struct {
+	// Objectives are the SLOs of the tracked registry operations, keyed by the name of the Client method without the
+	// WithContext suffix, i.e. GetServiceEndpoint. Operations without SLO aren't tracked
+	Objectives map[string]SLO
+	// Window is the number of most recent operations the burn rate is computed over. Defaults to 100 if not set
+	Window int
+	// AlertBurnRate is the burn rate at which OnAlert is called. Defaults to 1 if not set
+	AlertBurnRate float64
+	// OnAlert is optionally called each time the burn rate of an operation reaches AlertBurnRate. It is called again
+	// once the burn rate has dropped below AlertBurnRate and reached it again
+	OnAlert func(alert SLOBurnAlert)
+}
+
+// SLOClient is a Client tracking how often registry operations exceed their latency SLO
+type SLOClient struct {
+	Client
+	config   SLOConfig
+	lock     sync.Mutex
+	trackers map[string]*sloTracker
+}
+
+type sloTracker struct {
+	slo            SLO
+	stats          SLOStats
+	recent         []bool
+	next           int
+	filled         int
+	recentBreaches int
+	alerting       bool
+}
+
+// NewSLOClient wraps the given Client to track the latency of its operations against the configured SLOs
+func NewSLOClient(client Client, config SLOConfig) *SLOClient {
+	if config.Window <= 0 {
+		config.Window = defaultSLOWindow
+	}
+	if config.AlertBurnRate <= 0 {
+		config.AlertBurnRate = defaultSLOAlertBurnRate
+	}
+
+	trackers := make(map[string]*sloTracker, len(config.Objectives))
+	for operation, slo := range config.Objectives {
+		trackers[operation] = &sloTracker{slo: slo, recent: make([]bool, config.Window)}
+	}
+
+	return &SLOClient{
+		Client:   client,
+		config:   config,
+		trackers: trackers,
+	}
+}
+
+// Unwrap returns the Client wrapped by the SLOClient
+func (c *SLOClient) Unwrap() Client {
+	return c.Client
+}
+
+// Stats returns the statistics of each tracked operation
+func (c *SLOClient) Stats() map[string]SLOStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats := make(map[string]SLOStats, len(c.trackers))
+	for operation, tracker := range c.trackers {
+		stats[operation] = tracker.stats
+	}
+	return stats
+}
+
+func (c *SLOClient) observe(operation string, start time.Time) {
+	elapsed := time.Since(start)
+
+	c.lock.Lock()
+	tracker, ok := c.trackers[operation]
+	if !ok {
+		c.lock.Unlock()
+		return
+	}
+
+	breached := elapsed > tracker.slo.Latency
+	tracker.record(breached)
+
+	var alert *SLOBurnAlert
+	if tracker.stats.BurnRate >= c.config.AlertBurnRate {
+		if !tracker.alerting {
+			tracker.alerting = true
+			alert = &SLOBurnAlert{Operation: operation, SLOStats: tracker.stats}
+		}
+	} else {
+		tracker.alerting = false
+	}
+	c.lock.Unlock()
+
+	if alert != nil && c.config.OnAlert != nil {
+		c.config.OnAlert(*alert)
+	}
+}
+
+func (t *sloTracker) record(breached bool) {
+	t.stats.Total++
+	if breached {
+		t.stats.Breaches++
+	}
+
+	// Slide the window over the most recent operations
+	if t.filled == len(t.recent) {
+		if t.recent[t.next] {
+			t.recentBreaches--
+		}
+	} else {
+		t.filled++
+	}
+	t.recent[t.next] = breached
+	if breached {
+		t.recentBreaches++
+	}
+	t.next = (t.next + 1) % len(t.recent)
+
+	breachRate := float64(t.recentBreaches) / float64(t.filled)
+	errorBudget := 1 - t.slo.Objective
+	switch {
+	case errorBudget > 0:
+		t.stats.BurnRate = breachRate / errorBudget
+	case breachRate > 0:
+		t.stats.BurnRate = math.Inf(1)
+	default:
+		t.stats.BurnRate = 0
+	}
+}
+
+func (c *SLOClient) Register() error {
+	defer c.observe("Register", time.Now())
+	return c.Client.Register()
+}
+
+func (c *SLOClient) RegisterWithContext(ctx context.Context) error {
+	defer c.observe("Register", time.Now())
+	return c.Client.RegisterWithContext(ctx)
+}
+
+func (c *SLOClient) Unregister() error {
+	defer c.observe("Unregister", time.Now())
+	return c.Client.Unregister()
+}
+
+func (c *SLOClient) UnregisterWithContext(ctx context.Context) error {
+	defer c.observe("Unregister", time.Now())
+	return c.Client.UnregisterWithContext(ctx)
+}
+
+func (c *SLOClient) Decommission(ctx context.Context, serviceKey string) error {
+	defer c.observe("Decommission", time.Now())
+	return c.Client.Decommission(ctx, serviceKey)
+}
+
+func (c *SLOClient) RegisterCheck(id string, name string, notes string, url string, interval string) error {
+	defer c.observe("RegisterCheck", time.Now())
+	return c.Client.RegisterCheck(id, name, notes, url, interval)
+}
+
+func (c *SLOClient) RegisterCheckWithContext(ctx context.Context, id string, name string, notes string, url string, interval string) error {
+	defer c.observe("RegisterCheck", time.Now())
+	return c.Client.RegisterCheckWithContext(ctx, id, name, notes, url, interval)
+}
+
+func (c *SLOClient) TriggerHealthCheck(ctx context.Context, serviceId string) (types.HealthCheckResult, error) {
+	defer c.observe("TriggerHealthCheck", time.Now())
+	return c.Client.TriggerHealthCheck(ctx, serviceId)
+}
+
+func (c *SLOClient) IsAlive() bool {
+	defer c.observe("IsAlive", time.Now())
+	return c.Client.IsAlive()
+}
+
+func (c *SLOClient) IsAliveWithContext(ctx context.Context) bool {
+	defer c.observe("IsAlive", time.Now())
+	return c.Client.IsAliveWithContext(ctx)
+}
+
+func (c *SLOClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	defer c.observe("GetServiceEndpoint", time.Now())
+	return c.Client.GetServiceEndpoint(serviceId)
+}
+
+func (c *SLOClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	defer c.observe("GetServiceEndpoint", time.Now())
+	return c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+}
+
+func (c *SLOClient) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	defer c.observe("GetServiceEndpoints", time.Now())
+	return c.Client.GetServiceEndpoints(serviceId)
+}
+
+func (c *SLOClient) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) ([]types.ServiceEndpoint, error) {
+	defer c.observe("GetServiceEndpoints", time.Now())
+	return c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+}
+
+func (c *SLOClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	defer c.observe("GetAllServiceEndpoints", time.Now())
+	return c.Client.GetAllServiceEndpoints()
+}
+
+func (c *SLOClient) GetAllServiceEndpointsWithContext(ctx context.Context) ([]types.ServiceEndpoint, error) {
+	defer c.observe("GetAllServiceEndpoints", time.Now())
+	return c.Client.GetAllServiceEndpointsWithContext(ctx)
+}
+
+func (c *SLOClient) IsServiceAvailable(serviceId string) (bool, error) {
+	defer c.observe("IsServiceAvailable", time.Now())
+	return c.Client.IsServiceAvailable(serviceId)
+}
+
+func (c *SLOClient) IsServiceAvailableWithContext(ctx context.Context, serviceId string) (bool, error) {
+	defer c.observe("IsServiceAvailable", time.Now())
+	return c.Client.IsServiceAvailableWithContext(ctx, serviceId)
+}
@@ -0,0 +1,77 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+const testSLOLatency = 10 * time.Millisecond
+
+func TestSLOClient(t *testing.T) {
+	// Fast and slow calls, where the burn rate reaches 1 on the 2nd and 8th calls
+	calls := []bool{false, true, true, false, false, false, true, true}
+
+	client := &mocks.Client{}
+	for _, slow := range calls {
+		call := client.On("GetServiceEndpoint", testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+		if slow {
+			call.After(2 * testSLOLatency)
+		}
+	}
+	client.On("IsAlive").Return(true)
+
+	var alerts []SLOBurnAlert
+	sloClient := NewSLOClient(client, SLOConfig{
+		Objectives: map[string]SLO{"GetServiceEndpoint": {Latency: testSLOLatency, Objective: 0.5}},
+		Window:     4,
+		OnAlert: func(alert SLOBurnAlert) {
+			alerts = append(alerts, alert)
+		},
+	})
+
+	for range calls {
+		endpoint, err := sloClient.GetServiceEndpoint(testEndpoint.ServiceId)
+		require.NoError(t, err)
+		require.Equal(t, testEndpoint, endpoint)
+	}
+	assert.True(t, sloClient.IsAlive())
+
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "GetServiceEndpoint", alerts[0].Operation)
+	assert.Equal(t, uint64(2), alerts[0].Total)
+	assert.Equal(t, uint64(8), alerts[1].Total)
+
+	stats := sloClient.Stats()
+	require.Contains(t, stats, "GetServiceEndpoint")
+	assert.NotContains(t, stats, "IsAlive", "Expected operation without SLO not to be tracked")
+	assert.Equal(t, uint64(8), stats["GetServiceEndpoint"].Total)
+	assert.Equal(t, uint64(4), stats["GetServiceEndpoint"].Breaches)
+	assert.InDelta(t, 1.0, stats["GetServiceEndpoint"].BurnRate, 0.001)
+}
+
+func TestSLOClientNoErrorBudget(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsAlive").Return(true).Once()
+	client.On("IsAlive").Return(true).After(2 * testSLOLatency)
+
+	sloClient := NewSLOClient(client, SLOConfig{
+		Objectives: map[string]SLO{"IsAlive": {Latency: testSLOLatency, Objective: 1}},
+	})
+
+	sloClient.IsAlive()
+	assert.Zero(t, sloClient.Stats()["IsAlive"].BurnRate)
+
+	sloClient.IsAlive()
+	assert.True(t, math.IsInf(sloClient.Stats()["IsAlive"].BurnRate, 1))
+}
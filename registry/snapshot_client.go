@@ -0,0 +1,297 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// endpointSnapshot is the content of the snapshot file, the endpoints last looked up by service key
+type endpointSnapshot struct {
+	SavedAt   time.Time                          `json:"savedAt"`
+	Endpoint  map[string]types.ServiceEndpoint   `json:"endpoint"`
+	Endpoints map[string][]types.ServiceEndpoint `json:"endpoints"`
+}
+
+// SnapshotClient is a Client keeping the endpoints last looked up with GetServiceEndpoint and GetServiceEndpoints in a
+// JSON file, returning them from the lookups failing with ErrRegistryUnavailable, so services restarting while the
+// Registry is down still resolve their dependencies from the snapshot saved before. A service found unregistered is
+// left out of the snapshot. NewRegistryClient wraps the registry client with it when Config.EndpointSnapshotFile is
+// set, saving the snapshot every EndpointSnapshotInterval, encrypted with the key of GetEndpointSnapshotKey if set.
+type SnapshotClient struct {
+	Client
+	path string
+	// aead encrypts the snapshot file, nil when it is saved unencrypted
+	aead cipher.AEAD
+	// saveLock serializes the saves, so an older snapshot never replaces a newer one
+	saveLock sync.Mutex
+
+	lock     sync.Mutex
+	snapshot endpointSnapshot
+	// changed is set when endpoints were looked up since the snapshot was last saved
+	changed bool
+}
+
+// NewSnapshotClient wraps the given Client to keep the looked up endpoints in the snapshot file at the given path,
+// loading the snapshot saved there unless the file doesn't exist yet
+func NewSnapshotClient(client Client, path string) (*SnapshotClient, error) {
+	return newSnapshotClient(client, path, nil)
+}
+
+// NewEncryptedSnapshotClient is NewSnapshotClient with the snapshot file encrypted with AES-GCM using the given key, of
+// 16, 24 or 32 bytes. A snapshot saved unencrypted is rejected rather than trusted, as anyone able to write the file
+// could plant endpoints, until migrated with EncryptSnapshot.
+func NewEncryptedSnapshotClient(client Client, path string, key []byte) (*SnapshotClient, error) {
+	aead, err := newSnapshotAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return newSnapshotClient(client, path, aead)
+}
+
+// EncryptSnapshot encrypts the snapshot file at the given path with the key, once, when it was saved unencrypted before
+// encryption was enabled. Does nothing if the file doesn't exist or is already encrypted.
+func EncryptSnapshot(path string, key []byte) error {
+	aead, err := newSnapshotAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to encrypt endpoint snapshot: %v", err)
+	}
+	if !json.Valid(contents) {
+		return nil
+	}
+
+	snapshotClient := &SnapshotClient{aead: aead}
+	if contents, err = snapshotClient.encrypt(contents); err != nil {
+		return fmt.Errorf("unable to encrypt endpoint snapshot %s: %v", path, err)
+	}
+	if err := writeFileAtomically(path, contents); err != nil {
+		return fmt.Errorf("unable to encrypt endpoint snapshot %s: %v", path, err)
+	}
+	return nil
+}
+
+func newSnapshotAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint snapshot key: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint snapshot key: %v", err)
+	}
+	return aead, nil
+}
+
+// Unwrap returns the Client wrapped by the SnapshotClient
+func (c *SnapshotClient) Unwrap() Client {
+	return c.Client
+}
+
+func newSnapshotClient(client Client, path string, aead cipher.AEAD) (*SnapshotClient, error) {
+	snapshotClient := &SnapshotClient{
+		Client: client,
+		path:   path,
+		aead:   aead,
+		snapshot: endpointSnapshot{
+			Endpoint:  make(map[string]types.ServiceEndpoint),
+			Endpoints: make(map[string][]types.ServiceEndpoint),
+		},
+	}
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshotClient, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to load endpoint snapshot: %v", err)
+	}
+	if aead != nil {
+		if json.Valid(contents) {
+			return nil, fmt.Errorf("unable to load endpoint snapshot %s: saved unencrypted, to be migrated with "+
+				"EncryptSnapshot or removed", path)
+		}
+		if contents, err = snapshotClient.decrypt(contents); err != nil {
+			return nil, fmt.Errorf("unable to load endpoint snapshot %s: %v", path, err)
+		}
+	}
+	if err := json.Unmarshal(contents, &snapshotClient.snapshot); err != nil {
+		return nil, fmt.Errorf("unable to load endpoint snapshot %s: %v", path, err)
+	}
+	// Snapshots saved without any lookup of either kind leave its map nil
+	if snapshotClient.snapshot.Endpoint == nil {
+		snapshotClient.snapshot.Endpoint = make(map[string]types.ServiceEndpoint)
+	}
+	if snapshotClient.snapshot.Endpoints == nil {
+		snapshotClient.snapshot.Endpoints = make(map[string][]types.ServiceEndpoint)
+	}
+	return snapshotClient, nil
+}
+
+// encrypt seals the contents of the snapshot, prefixed with the random nonce they are sealed with
+func (c *SnapshotClient) encrypt(contents []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, contents, nil), nil
+}
+
+// decrypt opens the contents of the snapshot sealed by encrypt
+func (c *SnapshotClient) decrypt(contents []byte) ([]byte, error) {
+	if len(contents) < c.aead.NonceSize() {
+		return nil, errors.New("encrypted snapshot too short")
+	}
+	nonce, sealed := contents[:c.aead.NonceSize()], contents[c.aead.NonceSize():]
+	return c.aead.Open(nil, nonce, sealed, nil)
+}
+
+func (c *SnapshotClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *SnapshotClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (types.ServiceEndpoint, error) {
+	endpoint, err := c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if last, found := c.snapshot.Endpoint[serviceId]; found && errors.Is(err, types.ErrRegistryUnavailable) {
+		return last, nil
+	}
+	c.changed = record(c.snapshot.Endpoint, serviceId, endpoint, err) || c.changed
+	return endpoint, err
+}
+
+func (c *SnapshotClient) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceId)
+}
+
+func (c *SnapshotClient) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) ([]types.ServiceEndpoint, error) {
+	endpoints, err := c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if last, found := c.snapshot.Endpoints[serviceId]; found && errors.Is(err, types.ErrRegistryUnavailable) {
+		// The callers may filter the endpoints in place
+		return slices.Clone(last), nil
+	}
+	c.changed = record(c.snapshot.Endpoints, serviceId, slices.Clone(endpoints), err) || c.changed
+	return endpoints, err
+}
+
+// record keeps the looked up value of the service in the snapshot, or forgets it when the service isn't registered
+// anymore, telling whether the snapshot changed
+func record[T any](snapshot map[string]T, serviceId string, value T, err error) bool {
+	switch {
+	case err == nil:
+		snapshot[serviceId] = value
+		return true
+	case errors.Is(err, types.ErrNotRegistered):
+		if _, found := snapshot[serviceId]; found {
+			delete(snapshot, serviceId)
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the snapshot to its file if endpoints were looked up since it was last saved, i.e. before shutting
+// down. The file is replaced at once, so a crash while saving leaves the previous snapshot.
+func (c *SnapshotClient) Save() error {
+	c.saveLock.Lock()
+	defer c.saveLock.Unlock()
+
+	c.lock.Lock()
+	if !c.changed {
+		c.lock.Unlock()
+		return nil
+	}
+	c.snapshot.SavedAt = time.Now().UTC()
+	contents, err := json.Marshal(c.snapshot)
+	c.changed = false
+	c.lock.Unlock()
+	if err == nil && c.aead != nil {
+		contents, err = c.encrypt(contents)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to save endpoint snapshot: %v", err)
+	}
+
+	if err := writeFileAtomically(c.path, contents); err != nil {
+		c.lock.Lock()
+		c.changed = true
+		c.lock.Unlock()
+		return fmt.Errorf("unable to save endpoint snapshot: %v", err)
+	}
+	return nil
+}
+
+// SaveEvery saves the snapshot at the given interval until ctx is done, saving it one last time then. The failures to
+// save are retried at the next interval.
+func (c *SnapshotClient) SaveEvery(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = c.Save()
+			case <-ctx.Done():
+				_ = c.Save()
+				return
+			}
+		}
+	}()
+}
+
+// writeFileAtomically writes the contents to a temporary file in the directory of the target file, only readable by
+// its owner as the endpoints reveal the internal topology, then renames it over the target file
+func writeFileAtomically(path string, contents []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if err := file.Chmod(0600); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if _, err := file.Write(contents); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
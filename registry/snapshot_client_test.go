@@ -0,0 +1,170 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestSnapshotClientOfflineBootstrap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	replica := testEndpoint
+	replica.InstanceId = "core-data-2"
+
+	online := &mocks.Client{}
+	online.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+	online.On("GetServiceEndpointsWithContext", mock.Anything, testEndpoint.ServiceId).Return([]types.ServiceEndpoint{testEndpoint, replica}, nil)
+	snapshotClient, err := NewSnapshotClient(online, path)
+	require.NoError(t, err)
+
+	_, err = snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	_, err = snapshotClient.GetServiceEndpoints(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	require.NoError(t, snapshotClient.Save())
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Expected the snapshot to be only readable by its owner")
+
+	// The service restarts while the Registry is down
+	unavailable := types.Errorf(types.ErrRegistryUnavailable, "connection refused")
+	offline := &mocks.Client{}
+	offline.On("GetServiceEndpointWithContext", mock.Anything, mock.Anything).Return(types.ServiceEndpoint{}, unavailable)
+	offline.On("GetServiceEndpointsWithContext", mock.Anything, mock.Anything).Return(nil, unavailable)
+	snapshotClient, err = NewSnapshotClient(offline, path)
+	require.NoError(t, err)
+
+	endpoint, err := snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpoint, endpoint)
+
+	endpoints, err := snapshotClient.GetServiceEndpoints(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceEndpoint{testEndpoint, replica}, endpoints)
+
+	_, err = snapshotClient.GetServiceEndpoint("core-command")
+	assert.ErrorIs(t, err, types.ErrRegistryUnavailable, "Expected the lookups of the services missing from the snapshot to fail")
+}
+
+func TestSnapshotClientUnregistered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(types.ServiceEndpoint{}, types.Errorf(types.ErrNotRegistered, "%s", testEndpoint.ServiceId)).Once()
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(types.ServiceEndpoint{}, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+	snapshotClient, err := NewSnapshotClient(client, path)
+	require.NoError(t, err)
+
+	_, err = snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	_, err = snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+
+	_, err = snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	assert.ErrorIs(t, err, types.ErrRegistryUnavailable, "Expected the unregistered service to be left out of the snapshot")
+}
+
+func TestSnapshotClientSaveEvery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	client := &mocks.Client{}
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+	snapshotClient, err := NewSnapshotClient(client, path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	snapshotClient.SaveEvery(ctx, 10*time.Millisecond)
+
+	_, err = snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond, "Expected the snapshot to be saved")
+}
+
+func TestNewSnapshotClientCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	require.NoError(t, os.WriteFile(path, []byte("{\"endpoint\":"), 0600))
+
+	_, err := NewSnapshotClient(&mocks.Client{}, path)
+	assert.Error(t, err)
+}
+
+func TestEncryptedSnapshotClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	online := &mocks.Client{}
+	online.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+	snapshotClient, err := NewEncryptedSnapshotClient(online, path, key)
+	require.NoError(t, err)
+	_, err = snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	require.NoError(t, snapshotClient.Save())
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), testEndpoint.Host, "Expected the snapshot to be encrypted")
+
+	offline := &mocks.Client{}
+	offline.On("GetServiceEndpointWithContext", mock.Anything, mock.Anything).Return(types.ServiceEndpoint{}, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+	snapshotClient, err = NewEncryptedSnapshotClient(offline, path, key)
+	require.NoError(t, err)
+	endpoint, err := snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpoint, endpoint)
+
+	_, err = NewEncryptedSnapshotClient(offline, path, []byte("fedcba9876543210fedcba9876543210"))
+	assert.Error(t, err, "Expected the snapshot not to be loaded with another key")
+	_, err = NewSnapshotClient(offline, path)
+	assert.Error(t, err, "Expected the encrypted snapshot not to be loaded without key")
+	_, err = NewEncryptedSnapshotClient(offline, path, []byte("short"))
+	assert.Error(t, err, "Expected invalid key error")
+}
+
+func TestEncryptedSnapshotClientUnencryptedSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	online := &mocks.Client{}
+	online.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Return(testEndpoint, nil)
+	snapshotClient, err := NewSnapshotClient(online, path)
+	require.NoError(t, err)
+	_, err = snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	require.NoError(t, snapshotClient.Save())
+
+	// Encryption is enabled once the snapshot was saved unencrypted
+	key := []byte("0123456789abcdef")
+	_, err = NewEncryptedSnapshotClient(&mocks.Client{}, path, key)
+	require.Error(t, err, "Expected the unencrypted snapshot not to be trusted with encryption enabled")
+
+	require.NoError(t, EncryptSnapshot(path, key))
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), testEndpoint.Host, "Expected the snapshot to be encrypted once migrated")
+	require.NoError(t, EncryptSnapshot(path, key), "Expected the encrypted snapshot to be left as is")
+
+	offline := &mocks.Client{}
+	offline.On("GetServiceEndpointWithContext", mock.Anything, mock.Anything).Return(types.ServiceEndpoint{}, types.Errorf(types.ErrRegistryUnavailable, "connection refused"))
+	snapshotClient, err = NewEncryptedSnapshotClient(offline, path, key)
+	require.NoError(t, err)
+	endpoint, err := snapshotClient.GetServiceEndpoint(testEndpoint.ServiceId)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpoint, endpoint)
+
+	require.NoError(t, EncryptSnapshot(filepath.Join(t.TempDir(), "missing.json"), key))
+}
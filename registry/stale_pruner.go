@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// StalePruner decommissions the registered services which have been unhealthy for longer than a grace period, i.e.
+// crashed device services, for the registries which don't remove them themselves with DeregisterCriticalAfter. As
+// the Registry doesn't tell since when a service is unhealthy, the pruner tracks it across the calls to Prune, to be
+// called periodically, i.e. every few check intervals: a service is pruned once seen unhealthy on each call for the
+// whole grace period.
+type StalePruner struct {
+	client      Client
+	gracePeriod time.Duration
+	lock        sync.Mutex
+	// unhealthySince is when each service was first seen unhealthy since last seen healthy
+	unhealthySince map[string]time.Time
+	now            func() time.Time
+}
+
+// NewStalePruner creates the pruner of the services of the client unhealthy for longer than the grace period
+func NewStalePruner(client Client, gracePeriod time.Duration) *StalePruner {
+	return &StalePruner{
+		client:         client,
+		gracePeriod:    gracePeriod,
+		unhealthySince: make(map[string]time.Time),
+		now:            time.Now,
+	}
+}
+
+// Prune checks the health of all the registered services and decommissions those unhealthy for longer than the grace
+// period. It returns the sorted keys of the services decommissioned. Like UnregisterByPrefix, failing to decommission
+// a service doesn't stop the others from being decommissioned: the returned error then joins all the failures.
+func (p *StalePruner) Prune(ctx context.Context) ([]string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	endpoints, err := p.client.GetAllServiceEndpointsWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get services to prune: %w", err)
+	}
+
+	registered := make(map[string]struct{}, len(endpoints))
+	for _, endpoint := range endpoints {
+		registered[endpoint.ServiceId] = struct{}{}
+	}
+	// Services unregistered in between start over if registered again
+	for serviceKey := range p.unhealthySince {
+		if _, found := registered[serviceKey]; !found {
+			delete(p.unhealthySince, serviceKey)
+		}
+	}
+
+	serviceKeys := make([]string, 0, len(registered))
+	for serviceKey := range registered {
+		serviceKeys = append(serviceKeys, serviceKey)
+	}
+	sort.Strings(serviceKeys)
+
+	var stale []string
+	for _, serviceKey := range serviceKeys {
+		_, err := p.client.IsServiceAvailableWithContext(ctx, serviceKey)
+		switch {
+		case errors.Is(err, types.ErrUnhealthy):
+			since, found := p.unhealthySince[serviceKey]
+			if !found {
+				p.unhealthySince[serviceKey] = p.now()
+				continue
+			}
+			if p.now().Sub(since) >= p.gracePeriod {
+				stale = append(stale, serviceKey)
+			}
+		case errors.Is(err, types.ErrRegistryUnavailable):
+			// Unknown health isn't evidence of the service being unhealthy
+			return nil, fmt.Errorf("unable to check the health of %s: %w", serviceKey, err)
+		default:
+			delete(p.unhealthySince, serviceKey)
+		}
+	}
+
+	var pruned []string
+	var errs []error
+	for _, serviceKey := range stale {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := p.client.Decommission(ctx, serviceKey); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delete(p.unhealthySince, serviceKey)
+		pruned = append(pruned, serviceKey)
+	}
+
+	return pruned, errors.Join(errs...)
+}
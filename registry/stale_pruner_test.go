@@ -0,0 +1,108 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func newPrunerTestClient() *mocks.Client {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{
+		{ServiceId: "core-data", Host: "10.0.0.1", Port: 59880},
+		{ServiceId: "device-modbus", Host: "10.0.0.2", Port: 59901},
+		{ServiceId: "device-onvif-camera", Host: "10.0.0.3", Port: 59984},
+	}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(true, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "device-modbus").
+		Return(false, types.Errorf(types.ErrUnhealthy, "device-modbus service not healthy"))
+	client.On("IsServiceAvailableWithContext", mock.Anything, "device-onvif-camera").
+		Return(false, types.Errorf(types.ErrUnhealthy, "device-onvif-camera service not healthy"))
+	return client
+}
+
+func TestStalePruner(t *testing.T) {
+	client := newPrunerTestClient()
+	client.On("Decommission", mock.Anything, mock.Anything).Return(nil)
+
+	now := time.Now()
+	pruner := NewStalePruner(client, 10*time.Minute)
+	pruner.now = func() time.Time { return now }
+
+	pruned, err := pruner.Prune(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, pruned, "Expected services first seen unhealthy to be left within their grace period")
+
+	now = now.Add(9 * time.Minute)
+	pruned, err = pruner.Prune(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, pruned)
+
+	now = now.Add(time.Minute)
+	pruned, err = pruner.Prune(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"device-modbus", "device-onvif-camera"}, pruned)
+	client.AssertNotCalled(t, "Decommission", mock.Anything, "core-data")
+}
+
+func TestStalePrunerRecovered(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{{ServiceId: "device-modbus"}}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "device-modbus").
+		Return(false, types.Errorf(types.ErrUnhealthy, "device-modbus service not healthy")).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, "device-modbus").Return(true, nil).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, "device-modbus").
+		Return(false, types.Errorf(types.ErrUnhealthy, "device-modbus service not healthy"))
+
+	now := time.Now()
+	pruner := NewStalePruner(client, time.Minute)
+	pruner.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		pruned, err := pruner.Prune(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, pruned, "Expected the grace period to start over once the service recovered")
+		now = now.Add(time.Minute)
+	}
+	client.AssertNotCalled(t, "Decommission", mock.Anything, mock.Anything)
+}
+
+func TestStalePrunerRegistryUnavailable(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{{ServiceId: "device-modbus"}}, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "device-modbus").
+		Return(false, types.Errorf(types.ErrRegistryUnavailable, "keeper unreachable"))
+
+	pruned, err := NewStalePruner(client, 0).Prune(context.Background())
+	require.ErrorIs(t, err, types.ErrRegistryUnavailable)
+	assert.Empty(t, pruned)
+	client.AssertNotCalled(t, "Decommission", mock.Anything, mock.Anything)
+}
+
+func TestStalePrunerDecommissionFailure(t *testing.T) {
+	client := newPrunerTestClient()
+	failure := errors.New("decommission failed")
+	client.On("Decommission", mock.Anything, "device-modbus").Return(failure)
+	client.On("Decommission", mock.Anything, "device-onvif-camera").Return(nil)
+
+	pruner := NewStalePruner(client, 0)
+	_, err := pruner.Prune(context.Background())
+	require.NoError(t, err)
+
+	pruned, err := pruner.Prune(context.Background())
+	require.ErrorIs(t, err, failure)
+	assert.Equal(t, []string{"device-onvif-camera"}, pruned)
+}
@@ -0,0 +1,34 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// StandbyPromoter is implemented by the Clients of the registry types able to update the roles of the instances of a
+// service at once, i.e. etcd, for operators to fail over to a standby instance through the registry
+type StandbyPromoter interface {
+	// Makes the standby instance of the target service active and the active instances standby, all at once, failing
+	// with types.ErrNotRegistered when the instance isn't registered
+	PromoteStandby(ctx context.Context, serviceKey string, instanceId string) error
+}
+
+// PromoteStandby makes the standby instance of the target service, registered with the types.RoleStandby role, active
+// and its active instances standby, so the BalancingClient fails over to it, when the client, or any Client it wraps,
+// is a StandbyPromoter. It otherwise fails with types.ErrNotSupported, as the registry type can't update the roles.
+// The roles last until the instances register again, i.e. after restarting, so the role in their ServiceMetadata is to
+// be updated as well.
+func PromoteStandby(ctx context.Context, client Client, serviceKey string, instanceId string) error {
+	promoter, ok := As[StandbyPromoter](client)
+	if !ok {
+		return types.Errorf(types.ErrNotSupported, "unable to promote the standby instance %s of %s: the registry type can't update the roles of the instances", instanceId, serviceKey)
+	}
+
+	return promoter.PromoteStandby(ctx, serviceKey, instanceId)
+}
@@ -0,0 +1,37 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+// promotingClient is a Client able to promote standby instances
+type promotingClient struct {
+	*mocks.Client
+	promoted []string
+}
+
+func (c *promotingClient) PromoteStandby(_ context.Context, serviceKey string, instanceId string) error {
+	c.promoted = append(c.promoted, serviceKey+"/"+instanceId)
+	return nil
+}
+
+func TestPromoteStandby(t *testing.T) {
+	client := &promotingClient{Client: &mocks.Client{}}
+	require.NoError(t, PromoteStandby(context.Background(), NewBalancingClient(client, types.RoundRobin()), "core-data", "core-data-1"))
+	assert.Equal(t, []string{"core-data/core-data-1"}, client.promoted, "Expected the promoter to be found behind the decorators")
+
+	err := PromoteStandby(context.Background(), &mocks.Client{}, "core-data", "core-data-1")
+	assert.ErrorIs(t, err, types.ErrNotSupported)
+}
@@ -0,0 +1,244 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+)
+
+// TracerName is the name of the OpenTelemetry tracer creating the spans of a TracingClient
+const TracerName = "github.com/edgexfoundry/go-mod-registry/v3/registry"
+
+// The attributes of the spans of a TracingClient
+const (
+	// RegistryTypeAttribute is the registry type, i.e. keeper
+	RegistryTypeAttribute = attribute.Key(types.RegistryTypeLogKey)
+	// ServiceKeyAttribute is the key of the service registered or looked up, absent for GetAllServiceEndpoints
+	ServiceKeyAttribute = attribute.Key(types.ServiceKeyLogKey)
+	// StatusAttribute is either ok or, if the operation failed, the kind of failure: not_registered, unhealthy,
+	// unavailable, unauthorized or error
+	StatusAttribute = attribute.Key("registry.status")
+	// EventTypeAttribute is the type of the registration event notified by WatchSelf, i.e. Modified
+	EventTypeAttribute = attribute.Key("registry.event_type")
+)
+
+// TracingClient is a Client creating an OpenTelemetry span for each registry operation, so the registry latency shows
+// up in the distributed traces alongside the application calls. Spans are children of the span of the operation
+// context, and the context passed to the wrapped Client carries the new span, so the Keeper requests propagate it.
+// Operations without context are traced as new root spans. As watches last for the service lifetime, each notification
+// they send is traced instead, as a new root span linked to the span starting the watch, from the change being detected
+// until the subscriber receives it, to tell how long the topology changes take to propagate.
+type TracingClient struct {
+	Client
+	tracer       trace.Tracer
+	registryType string
+	serviceKey   string
+}
+
+// NewTracingClient wraps the given Client to trace its operations with the tracers of the given TracerProvider. The
+// registry type and the service key of the current service are added to the spans.
+func NewTracingClient(client Client, tracerProvider trace.TracerProvider, registryType string, serviceKey string) *TracingClient {
+	return &TracingClient{
+		Client:       client,
+		tracer:       tracerProvider.Tracer(TracerName),
+		registryType: registryType,
+		serviceKey:   serviceKey,
+	}
+}
+
+// Unwrap returns the Client wrapped by the TracingClient
+func (c *TracingClient) Unwrap() Client {
+	return c.Client
+}
+
+// start starts the span of the operation, named i.e. registry.GetServiceEndpoint
+func (c *TracingClient) start(ctx context.Context, operation string, serviceKey string) (context.Context, trace.Span) {
+	attributes := []attribute.KeyValue{RegistryTypeAttribute.String(c.registryType)}
+	if serviceKey != "" {
+		attributes = append(attributes, ServiceKeyAttribute.String(serviceKey))
+	}
+
+	return c.tracer.Start(ctx, "registry."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+// endSpan records the outcome of the operation and ends its span
+func endSpan(span trace.Span, err error) {
+	if err == nil {
+		span.SetAttributes(StatusAttribute.String("ok"))
+		span.End()
+		return
+	}
+
+	status := "error"
+	switch {
+	case errors.Is(err, types.ErrNotRegistered):
+		status = "not_registered"
+	case errors.Is(err, types.ErrUnhealthy):
+		status = "unhealthy"
+	case errors.Is(err, types.ErrRegistryUnavailable):
+		status = "unavailable"
+	case errors.Is(err, types.ErrUnauthorized):
+		status = "unauthorized"
+	}
+	span.SetAttributes(StatusAttribute.String(status))
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.End()
+}
+
+func (c *TracingClient) Register() error {
+	return c.RegisterWithContext(context.Background())
+}
+
+func (c *TracingClient) RegisterWithContext(ctx context.Context) (err error) {
+	ctx, span := c.start(ctx, "Register", c.serviceKey)
+	defer func() { endSpan(span, err) }()
+	return c.Client.RegisterWithContext(ctx)
+}
+
+func (c *TracingClient) Unregister() error {
+	return c.UnregisterWithContext(context.Background())
+}
+
+func (c *TracingClient) UnregisterWithContext(ctx context.Context) (err error) {
+	ctx, span := c.start(ctx, "Unregister", c.serviceKey)
+	defer func() { endSpan(span, err) }()
+	return c.Client.UnregisterWithContext(ctx)
+}
+
+func (c *TracingClient) Decommission(ctx context.Context, serviceKey string) (err error) {
+	ctx, span := c.start(ctx, "Decommission", serviceKey)
+	defer func() { endSpan(span, err) }()
+	return c.Client.Decommission(ctx, serviceKey)
+}
+
+// WatchSelf traces the start of the watch and then each registration event notified, in a
+// registry.WatchSelf.notification span
+func (c *TracingClient) WatchSelf(ctx context.Context) (<-chan types.RegistrationEvent, error) {
+	watchCtx, span := c.start(ctx, "WatchSelf", c.serviceKey)
+	events, err := c.Client.WatchSelf(watchCtx)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return relayNotifications(ctx, events, func(event types.RegistrationEvent) func(bool) {
+		return c.startNotification(ctx, "WatchSelf", span, c.serviceKey, EventTypeAttribute.String(string(event.Type)))
+	}), nil
+}
+
+// WatchService traces the start of the watch and then each endpoint notified, in a registry.WatchService.notification
+// span
+func (c *TracingClient) WatchService(ctx context.Context, serviceId string) (<-chan types.ServiceEndpoint, error) {
+	watchCtx, span := c.start(ctx, "WatchService", serviceId)
+	endpoints, err := c.Client.WatchService(watchCtx, serviceId)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return relayNotifications(ctx, endpoints, func(types.ServiceEndpoint) func(bool) {
+		return c.startNotification(ctx, "WatchService", span, serviceId)
+	}), nil
+}
+
+// startNotification starts the span of a notification of the watch started in the watch span, and returns the function
+// ending it once delivered to the subscriber, or once the watch got cancelled first
+func (c *TracingClient) startNotification(ctx context.Context, operation string, watch trace.Span, serviceKey string, attributes ...attribute.KeyValue) func(bool) {
+	attributes = append(attributes, RegistryTypeAttribute.String(c.registryType), ServiceKeyAttribute.String(serviceKey))
+	_, span := c.tracer.Start(context.Background(), "registry."+operation+".notification",
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(trace.Link{SpanContext: watch.SpanContext()}),
+		trace.WithAttributes(attributes...))
+
+	return func(delivered bool) {
+		if delivered {
+			endSpan(span, nil)
+			return
+		}
+		endSpan(span, ctx.Err())
+	}
+}
+
+func (c *TracingClient) RegisterCheck(id string, name string, notes string, url string, interval string) error {
+	return c.RegisterCheckWithContext(context.Background(), id, name, notes, url, interval)
+}
+
+func (c *TracingClient) RegisterCheckWithContext(ctx context.Context, id string, name string, notes string, url string, interval string) (err error) {
+	ctx, span := c.start(ctx, "RegisterCheck", c.serviceKey)
+	defer func() { endSpan(span, err) }()
+	return c.Client.RegisterCheckWithContext(ctx, id, name, notes, url, interval)
+}
+
+func (c *TracingClient) TriggerHealthCheck(ctx context.Context, serviceId string) (result types.HealthCheckResult, err error) {
+	ctx, span := c.start(ctx, "TriggerHealthCheck", serviceId)
+	defer func() { endSpan(span, err) }()
+	return c.Client.TriggerHealthCheck(ctx, serviceId)
+}
+
+func (c *TracingClient) IsAlive() bool {
+	return c.IsAliveWithContext(context.Background())
+}
+
+// IsAliveWithContext traces the Registry being down as an unavailable status
+func (c *TracingClient) IsAliveWithContext(ctx context.Context) bool {
+	ctx, span := c.start(ctx, "IsAlive", "")
+	alive := c.Client.IsAliveWithContext(ctx)
+	if alive {
+		endSpan(span, nil)
+	} else {
+		endSpan(span, types.Errorf(types.ErrRegistryUnavailable, "%s registry is not alive", c.registryType))
+	}
+	return alive
+}
+
+func (c *TracingClient) GetServiceEndpoint(serviceId string) (types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointWithContext(context.Background(), serviceId)
+}
+
+func (c *TracingClient) GetServiceEndpointWithContext(ctx context.Context, serviceId string) (endpoint types.ServiceEndpoint, err error) {
+	ctx, span := c.start(ctx, "GetServiceEndpoint", serviceId)
+	defer func() { endSpan(span, err) }()
+	return c.Client.GetServiceEndpointWithContext(ctx, serviceId)
+}
+
+func (c *TracingClient) GetServiceEndpoints(serviceId string) ([]types.ServiceEndpoint, error) {
+	return c.GetServiceEndpointsWithContext(context.Background(), serviceId)
+}
+
+func (c *TracingClient) GetServiceEndpointsWithContext(ctx context.Context, serviceId string) (endpoints []types.ServiceEndpoint, err error) {
+	ctx, span := c.start(ctx, "GetServiceEndpoints", serviceId)
+	defer func() { endSpan(span, err) }()
+	return c.Client.GetServiceEndpointsWithContext(ctx, serviceId)
+}
+
+func (c *TracingClient) GetAllServiceEndpoints() ([]types.ServiceEndpoint, error) {
+	return c.GetAllServiceEndpointsWithContext(context.Background())
+}
+
+func (c *TracingClient) GetAllServiceEndpointsWithContext(ctx context.Context) (endpoints []types.ServiceEndpoint, err error) {
+	ctx, span := c.start(ctx, "GetAllServiceEndpoints", "")
+	defer func() { endSpan(span, err) }()
+	return c.Client.GetAllServiceEndpointsWithContext(ctx)
+}
+
+func (c *TracingClient) IsServiceAvailable(serviceId string) (bool, error) {
+	return c.IsServiceAvailableWithContext(context.Background(), serviceId)
+}
+
+func (c *TracingClient) IsServiceAvailableWithContext(ctx context.Context, serviceId string) (available bool, err error) {
+	ctx, span := c.start(ctx, "IsServiceAvailable", serviceId)
+	defer func() { endSpan(span, err) }()
+	return c.Client.IsServiceAvailableWithContext(ctx, serviceId)
+}
@@ -0,0 +1,141 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestTracingClient(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var lookupSpan trace.SpanContext
+	client := &mocks.Client{}
+	client.On("RegisterWithContext", mock.Anything).Return(nil)
+	client.On("GetServiceEndpointWithContext", mock.Anything, testEndpoint.ServiceId).Run(func(args mock.Arguments) {
+		lookupSpan = trace.SpanContextFromContext(args.Get(0).(context.Context))
+	}).Return(testEndpoint, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-command").Return(false, types.Errorf(types.ErrNotRegistered, "core-command service is not registered"))
+	tracingClient := NewTracingClient(client, tracerProvider, "keeper", "device-virtual")
+
+	require.NoError(t, tracingClient.Register())
+	ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "parent")
+	_, err := tracingClient.GetServiceEndpointWithContext(ctx, testEndpoint.ServiceId)
+	require.NoError(t, err)
+	parent.End()
+	_, err = tracingClient.IsServiceAvailable("core-command")
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	register := spans[0]
+	assert.Equal(t, "registry.Register", register.Name())
+	assert.Equal(t, trace.SpanKindClient, register.SpanKind())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		RegistryTypeAttribute.String("keeper"),
+		ServiceKeyAttribute.String("device-virtual"),
+		StatusAttribute.String("ok"),
+	}, register.Attributes())
+
+	lookup := spans[1]
+	assert.Equal(t, "registry.GetServiceEndpoint", lookup.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), lookup.Parent().SpanID(), "Expected span to be a child of the context span")
+	assert.Equal(t, lookup.SpanContext(), lookupSpan, "Expected the wrapped Client to get the new span")
+	assert.Contains(t, lookup.Attributes(), ServiceKeyAttribute.String(testEndpoint.ServiceId))
+
+	availability := spans[3]
+	assert.Equal(t, "registry.IsServiceAvailable", availability.Name())
+	assert.Contains(t, availability.Attributes(), StatusAttribute.String("not_registered"))
+	assert.Equal(t, codes.Error, availability.Status().Code)
+	require.Len(t, availability.Events(), 1, "Expected the error to be recorded")
+}
+
+func TestTracingClientWatchService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	endpoints := make(chan types.ServiceEndpoint, 2)
+	endpoints <- testEndpoint
+	endpoints <- types.ServiceEndpoint{}
+	close(endpoints)
+
+	client := &mocks.Client{}
+	client.On("WatchService", mock.Anything, testEndpoint.ServiceId).Return((<-chan types.ServiceEndpoint)(endpoints), nil)
+	tracingClient := NewTracingClient(client, tracerProvider, "keeper", "device-virtual")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	watched, err := tracingClient.WatchService(ctx, testEndpoint.ServiceId)
+	require.NoError(t, err)
+
+	var received []types.ServiceEndpoint
+	for endpoint := range watched {
+		time.Sleep(testMaxWait)
+		received = append(received, endpoint)
+	}
+	assert.Equal(t, []types.ServiceEndpoint{testEndpoint, {}}, received)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	watch := spans[0]
+	assert.Equal(t, "registry.WatchService", watch.Name())
+
+	// The second notification waits for the subscriber to handle the first one
+	notification := spans[2]
+	assert.Equal(t, "registry.WatchService.notification", notification.Name())
+	assert.Equal(t, trace.SpanKindConsumer, notification.SpanKind())
+	assert.False(t, notification.Parent().IsValid(), "Expected notification span to be a root span")
+	require.Len(t, notification.Links(), 1)
+	assert.Equal(t, watch.SpanContext(), notification.Links()[0].SpanContext)
+	assert.Contains(t, notification.Attributes(), ServiceKeyAttribute.String(testEndpoint.ServiceId))
+	assert.Contains(t, notification.Attributes(), StatusAttribute.String("ok"))
+	assert.GreaterOrEqual(t, notification.EndTime().Sub(notification.StartTime()), testMaxWait)
+}
+
+func TestTracingClientWatchSelfCancelled(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	events := make(chan types.RegistrationEvent, 1)
+	events <- types.RegistrationEvent{Type: types.RegistrationDeleted}
+
+	client := &mocks.Client{}
+	client.On("WatchSelf", mock.Anything).Return((<-chan types.RegistrationEvent)(events), nil)
+	tracingClient := NewTracingClient(client, tracerProvider, "keeper", "device-virtual")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watched, err := tracingClient.WatchSelf(ctx)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(events) == 0 }, time.Second, testPollInterval)
+	cancel()
+	for range watched {
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	notification := spans[1]
+	assert.Equal(t, "registry.WatchSelf.notification", notification.Name())
+	assert.Contains(t, notification.Attributes(), EventTypeAttribute.String(string(types.RegistrationDeleted)))
+	assert.Equal(t, codes.Error, notification.Status().Code, "Expected notification not received to fail")
+}
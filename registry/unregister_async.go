@@ -0,0 +1,28 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+)
+
+// UnregisterAsync de-registers the current service of the client in the background and returns right away, for the
+// shutdown paths with hard deadlines, i.e. the stop timeout of systemd, so shutting down never blocks on a dead
+// Registry. ctx bounds the attempt, i.e. with a timeout shorter than the time left before the deadline. The callback
+// is optionally called with the result once the attempt completes, so it can be logged. The returned channel is
+// closed once the callback returned, for shutdown paths with time left to wait for it.
+func UnregisterAsync(ctx context.Context, client Client, callback func(err error)) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		err := client.UnregisterWithContext(ctx)
+		if callback != nil {
+			callback(err)
+		}
+	}()
+	return done
+}
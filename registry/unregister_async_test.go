@@ -0,0 +1,68 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestUnregisterAsync(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("UnregisterWithContext", mock.Anything).Return(nil).Once()
+
+	results := make(chan error, 1)
+	done := UnregisterAsync(context.Background(), client, func(err error) {
+		results <- err
+	})
+
+	require.NoError(t, <-results)
+	<-done
+	client.AssertExpectations(t)
+}
+
+func TestUnregisterAsyncDeadRegistry(t *testing.T) {
+	// The Registry doesn't respond before the attempt times out
+	client := &mocks.Client{}
+	client.On("UnregisterWithContext", mock.Anything).Return(func(ctx context.Context) error {
+		<-ctx.Done()
+		return types.Errorf(types.ErrRegistryUnavailable, "%w", ctx.Err())
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var result error
+	done := UnregisterAsync(ctx, client, func(err error) {
+		result = err
+	})
+	assert.Less(t, time.Since(start), 50*time.Millisecond, "Expected UnregisterAsync not to block")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "Timed out waiting for the attempt to be abandoned")
+	}
+	assert.ErrorIs(t, result, types.ErrRegistryUnavailable)
+	assert.ErrorIs(t, result, context.DeadlineExceeded)
+}
+
+func TestUnregisterAsyncWithoutCallback(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("UnregisterWithContext", mock.Anything).Return(nil).Once()
+
+	<-UnregisterAsync(context.Background(), client, nil)
+	client.AssertExpectations(t)
+}
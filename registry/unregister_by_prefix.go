@@ -0,0 +1,74 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnregisterByPrefixOptions controls how UnregisterByPrefix removes the matching registrations
+type UnregisterByPrefixOptions struct {
+	// DryRun only looks up the matching services, without unregistering any of them
+	DryRun bool
+	// Confirm is optionally called with the keys of the matching services before any of them is unregistered. Nothing
+	// is unregistered unless it returns true. It isn't called on a dry run or when no service matches
+	Confirm func(serviceKeys []string) bool
+}
+
+// UnregisterByPrefix decommissions all the services registered with a key starting with prefix, i.e. the per-device
+// registrations with the "device-onvif-" prefix left behind once a device service is reprovisioned. It returns the
+// sorted keys of the services unregistered, or those which would be on a dry run. Failing to unregister a service
+// doesn't stop the others from being unregistered: the returned error then joins all the failures and the keys of the
+// failed services are left out.
+func UnregisterByPrefix(ctx context.Context, client Client, prefix string, options UnregisterByPrefixOptions) ([]string, error) {
+	if prefix == "" {
+		return nil, errors.New("unable to unregister by prefix: a prefix is required to not unregister every service")
+	}
+
+	endpoints, err := client.GetAllServiceEndpointsWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get services with prefix %s: %v", prefix, err)
+	}
+
+	matched := make(map[string]struct{})
+	for _, endpoint := range endpoints {
+		if strings.HasPrefix(endpoint.ServiceId, prefix) {
+			matched[endpoint.ServiceId] = struct{}{}
+		}
+	}
+	serviceKeys := make([]string, 0, len(matched))
+	for serviceKey := range matched {
+		serviceKeys = append(serviceKeys, serviceKey)
+	}
+	sort.Strings(serviceKeys)
+
+	if options.DryRun || len(serviceKeys) == 0 {
+		return serviceKeys, nil
+	}
+	if options.Confirm != nil && !options.Confirm(append([]string(nil), serviceKeys...)) {
+		return nil, nil
+	}
+
+	var unregistered []string
+	var errs []error
+	for _, serviceKey := range serviceKeys {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := client.Decommission(ctx, serviceKey); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		unregistered = append(unregistered, serviceKey)
+	}
+
+	return unregistered, errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func newPrefixTestClient() *mocks.Client {
+	client := &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return([]types.ServiceEndpoint{
+		{ServiceId: "device-onvif-camera-2", Host: "10.0.0.2", Port: 59984},
+		{ServiceId: "core-data", Host: "10.0.0.1", Port: 59880},
+		{ServiceId: "device-onvif-camera-1", Host: "10.0.0.1", Port: 59984},
+		{ServiceId: "device-onvif-camera", Host: "10.0.0.3", Port: 59984},
+	}, nil)
+	return client
+}
+
+func TestUnregisterByPrefix(t *testing.T) {
+	client := newPrefixTestClient()
+	client.On("Decommission", mock.Anything, mock.Anything).Return(nil)
+
+	var confirmed []string
+	unregistered, err := UnregisterByPrefix(context.Background(), client, "device-onvif-camera-", UnregisterByPrefixOptions{
+		Confirm: func(serviceKeys []string) bool {
+			confirmed = serviceKeys
+			return true
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"device-onvif-camera-1", "device-onvif-camera-2"}, unregistered)
+	assert.Equal(t, unregistered, confirmed)
+	client.AssertNumberOfCalls(t, "Decommission", 2)
+	client.AssertCalled(t, "Decommission", mock.Anything, "device-onvif-camera-1")
+	client.AssertCalled(t, "Decommission", mock.Anything, "device-onvif-camera-2")
+}
+
+func TestUnregisterByPrefixDryRun(t *testing.T) {
+	client := newPrefixTestClient()
+
+	matched, err := UnregisterByPrefix(context.Background(), client, "device-onvif-", UnregisterByPrefixOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"device-onvif-camera", "device-onvif-camera-1", "device-onvif-camera-2"}, matched)
+	client.AssertNotCalled(t, "Decommission", mock.Anything, mock.Anything)
+}
+
+func TestUnregisterByPrefixNotConfirmed(t *testing.T) {
+	client := newPrefixTestClient()
+
+	unregistered, err := UnregisterByPrefix(context.Background(), client, "device-onvif-", UnregisterByPrefixOptions{
+		Confirm: func([]string) bool { return false },
+	})
+	require.NoError(t, err)
+	assert.Empty(t, unregistered)
+	client.AssertNotCalled(t, "Decommission", mock.Anything, mock.Anything)
+}
+
+func TestUnregisterByPrefixErrors(t *testing.T) {
+	client := newPrefixTestClient()
+	client.On("Decommission", mock.Anything, "device-onvif-camera-1").Return(errors.New("unable to decommission device-onvif-camera-1"))
+	client.On("Decommission", mock.Anything, mock.Anything).Return(nil)
+
+	unregistered, err := UnregisterByPrefix(context.Background(), client, "device-onvif-camera-", UnregisterByPrefixOptions{})
+	require.EqualError(t, err, "unable to decommission device-onvif-camera-1")
+	assert.Equal(t, []string{"device-onvif-camera-2"}, unregistered, "Expected other services to be unregistered on failure")
+
+	_, err = UnregisterByPrefix(context.Background(), client, "", UnregisterByPrefixOptions{})
+	require.Error(t, err, "Expected error without prefix")
+
+	client = &mocks.Client{}
+	client.On("GetAllServiceEndpointsWithContext", mock.Anything).Return(nil, errors.New("connection refused"))
+	_, err = UnregisterByPrefix(context.Background(), client, "device-onvif-", UnregisterByPrefixOptions{})
+	require.Error(t, err)
+}
@@ -0,0 +1,69 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWaitPollInterval = time.Second
+	// maxWaitBackoff is how many times the poll interval the delay between the checks grows up to
+	maxWaitBackoff = 8
+)
+
+// WaitForService checks with IsServiceAvailable whether the target service is available until it is or ctx is done,
+// i.e. on startup for the services the current one depends on. The first check is immediate, then the delay between
+// checks starts at pollInterval, 1s if not positive, and doubles up to 8 times pollInterval. Once ctx is done, the
+// returned error wraps both ctx.Err() and the error of the last check, if any.
+func WaitForService(ctx context.Context, client Client, serviceKey string, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		pollInterval = defaultWaitPollInterval
+	}
+
+	delay := pollInterval
+	for {
+		available, err := client.IsServiceAvailableWithContext(ctx, serviceKey)
+		if available && err == nil {
+			return nil
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("%s is not available: %w: %w", serviceKey, ctx.Err(), err)
+			}
+			return fmt.Errorf("%s is not available: %w", serviceKey, ctx.Err())
+		}
+		delay = min(2*delay, maxWaitBackoff*pollInterval)
+	}
+}
+
+// WaitForServices waits for the target services at once with WaitForService, returning the result for each of them,
+// nil for the available ones, once all of them are available or ctx is done
+func WaitForServices(ctx context.Context, client Client, serviceKeys []string, pollInterval time.Duration) map[string]error {
+	results := make(map[string]error, len(serviceKeys))
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for _, serviceKey := range serviceKeys {
+		wg.Add(1)
+		go func(serviceKey string) {
+			defer wg.Done()
+
+			err := WaitForService(ctx, client, serviceKey, pollInterval)
+			lock.Lock()
+			results[serviceKey] = err
+			lock.Unlock()
+		}(serviceKey)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,57 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-registry/v3/pkg/types"
+	"github.com/edgexfoundry/go-mod-registry/v3/registry/mocks"
+)
+
+func TestWaitForService(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(false, types.Errorf(types.ErrNotRegistered, "core-data")).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(false, types.Errorf(types.ErrUnhealthy, "core-data")).Once()
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(true, nil).Once()
+
+	require.NoError(t, WaitForService(context.Background(), client, "core-data", time.Millisecond))
+	client.AssertExpectations(t)
+}
+
+func TestWaitForServiceDeadline(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(false, types.Errorf(types.ErrUnhealthy, "core-data"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := WaitForService(ctx, client, "core-data", 5*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, types.ErrUnhealthy, "Expected the error of the last check to be returned")
+
+	// The delay between the checks doubles up to 40ms, so fewer checks than the deadline over the poll interval
+	assert.Less(t, len(client.Calls), 10)
+}
+
+func TestWaitForServices(t *testing.T) {
+	client := &mocks.Client{}
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-data").Return(true, nil)
+	client.On("IsServiceAvailableWithContext", mock.Anything, "core-command").Return(false, types.Errorf(types.ErrNotRegistered, "core-command"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results := WaitForServices(ctx, client, []string{"core-data", "core-command"}, 5*time.Millisecond)
+
+	require.Len(t, results, 2)
+	assert.NoError(t, results["core-data"])
+	assert.ErrorIs(t, results["core-command"], types.ErrNotRegistered)
+}
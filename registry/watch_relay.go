@@ -0,0 +1,34 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+)
+
+// relayNotifications forwards the notifications of a watch to a new channel, calling received as each one is received
+// from the wrapped Client, which is as soon as it detected the change since it blocks until then, and the function it
+// returns once the subscriber received it, or with delivered false if ctx got cancelled first. The new channel is
+// closed once the watch channel is.
+func relayNotifications[T any](ctx context.Context, notifications <-chan T, received func(notification T) func(delivered bool)) <-chan T {
+	relayed := make(chan T)
+	go func() {
+		defer close(relayed)
+
+		for notification := range notifications {
+			done := received(notification)
+			select {
+			case relayed <- notification:
+				done(true)
+			case <-ctx.Done():
+				done(false)
+				return
+			}
+		}
+	}()
+
+	return relayed
+}